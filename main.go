@@ -1,12 +1,14 @@
-/* Copyright 2019-2022 VMware, Inc.
+/* Copyright 2019-2023 VMware, Inc.
    SPDX-License-Identifier: MPL-2.0 */
 
 package main
 
 import (
+	"context"
 	"flag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"log"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/vmware/terraform-provider-vmc/vmc"
 )
 
@@ -15,16 +17,18 @@ func main() {
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{
-		ProviderFunc: func() *schema.Provider {
-			return vmc.Provider()
-		},
+	providerServer, err := vmc.ProviderServer(context.Background())
+	if err != nil {
+		log.Fatal(err)
 	}
 
+	var serveOpts []tf5server.ServeOpt
 	if debugMode {
-		opts.Debug = true
-		opts.ProviderAddr = "vmware/vmc"
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
 	}
 
-	plugin.Serve(opts)
+	err = tf5server.Serve("registry.terraform.io/vmware/vmc", providerServer, serveOpts...)
+	if err != nil {
+		log.Fatal(err)
+	}
 }
@@ -0,0 +1,64 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+)
+
+const (
+	auditOperationCreate = "create"
+	auditOperationUpdate = "update"
+	auditOperationDelete = "delete"
+)
+
+// withAuditLog wraps the create, update and delete functions of the resource, so that each of them
+// writes an entry to the provider audit log, if one is configured.
+func withAuditLog(resourceType string, r *schema.Resource) *schema.Resource {
+	r.CreateContext = auditedContextFunc(resourceType, auditOperationCreate, r.CreateContext)
+	r.UpdateContext = auditedContextFunc(resourceType, auditOperationUpdate, r.UpdateContext)
+	r.DeleteContext = auditedContextFunc(resourceType, auditOperationDelete, r.DeleteContext)
+	return r
+}
+
+func auditedContextFunc(resourceType string, operation string,
+	f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		wrapper, ok := m.(*connector.Wrapper)
+		if !ok {
+			return f(ctx, d, m)
+		}
+		auditedWrapper, writeAuditEntry := wrapper.ForAuditedOperation(resourceType, operation)
+		resourceID := d.Id()
+		diags := f(ctx, d, auditedWrapper)
+		var err error
+		if diags.HasError() {
+			for _, diagnostic := range diags {
+				if diagnostic.Severity == diag.Error {
+					err = fmt.Errorf("%s", diagnostic.Summary)
+					break
+				}
+			}
+		}
+		writeAuditEntry(auditedResourceID(resourceID, d), err)
+		return diags
+	}
+}
+
+// auditedResourceID returns the ID of the resource after the operation, or the one before it
+// when it has been cleared, e.g. by a delete.
+func auditedResourceID(previousID string, d *schema.ResourceData) string {
+	if len(d.Id()) > 0 {
+		return d.Id()
+	}
+	return previousID
+}
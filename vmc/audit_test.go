@@ -0,0 +1,100 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestAuditLogSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	auditLogPath := filepath.Join(t.TempDir(), "audit.log")
+	connectorWrapper.AuditLog = connector.NewAuditLog(auditLogPath)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "audited_sddc"})
+	clustersPath := "/vmc/api/orgs/" + simulator.TestOrgID + "/sddcs/" + sddcID + "/clusters"
+	cluster := withAuditLog("vmc_cluster", resourceCluster())
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	})
+
+	server.InjectError(http.MethodPost, clustersPath, http.StatusInternalServerError)
+	assert.Error(t, diagsErr(cluster.CreateContext(context.Background(), d, connectorWrapper)))
+	server.ClearErrors()
+	assert.NoError(t, diagsErr(cluster.CreateContext(context.Background(), d, connectorWrapper)))
+	clusterID := d.Id()
+	assert.NoError(t, diagsErr(cluster.DeleteContext(context.Background(), d, connectorWrapper)))
+
+	entries := readAuditLog(t, auditLogPath)
+	if !assert.Len(t, entries, 3) {
+		return
+	}
+
+	assert.Equal(t, "vmc_cluster", entries[0].ResourceType)
+	assert.Equal(t, "create", entries[0].Operation)
+	assert.Equal(t, connector.AuditOutcomeFailure, entries[0].Outcome)
+	assert.NotEmpty(t, entries[0].Error)
+	assert.Equal(t, []connector.AuditedRequest{
+		{Method: http.MethodPost, APIPath: clustersPath, StatusCode: http.StatusInternalServerError},
+	}, entries[0].Requests)
+
+	assert.Equal(t, "create", entries[1].Operation)
+	assert.Equal(t, clusterID, entries[1].ResourceID)
+	assert.Equal(t, connector.AuditOutcomeSuccess, entries[1].Outcome)
+	assert.Empty(t, entries[1].Error)
+	if assert.Len(t, entries[1].Requests, 1) {
+		assert.Equal(t, clustersPath, entries[1].Requests[0].APIPath)
+		assert.NotEmpty(t, entries[1].Requests[0].TaskID)
+	}
+
+	assert.Equal(t, "delete", entries[2].Operation)
+	assert.Equal(t, clusterID, entries[2].ResourceID)
+	assert.Equal(t, connector.AuditOutcomeSuccess, entries[2].Outcome)
+	if assert.Len(t, entries[2].Requests, 1) {
+		assert.Equal(t, http.MethodDelete, entries[2].Requests[0].Method)
+		assert.Equal(t, clustersPath+"/"+clusterID, entries[2].Requests[0].APIPath)
+		assert.NotEmpty(t, entries[2].Requests[0].TaskID)
+	}
+}
+
+func TestAuditLogDisabledSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "not_audited_sddc"})
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	})
+
+	assert.NoError(t, diagsErr(withAuditLog("vmc_cluster", resourceCluster()).CreateContext(context.Background(), d, connectorWrapper)))
+	assert.NotEmpty(t, d.Id())
+}
+
+func readAuditLog(t *testing.T, path string) []connector.AuditEntry {
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("error opening audit log: %s", err)
+	}
+	defer file.Close()
+	var entries []connector.AuditEntry
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var entry connector.AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("invalid audit log line %q: %s", scanner.Text(), err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
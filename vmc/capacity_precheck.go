@@ -0,0 +1,195 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+// hostLimitOrgProperty the property of an organization holding the maximum amount of hosts across
+// all its SDDCs, if it has such a quota.
+const hostLimitOrgProperty = "hostLimit"
+
+// capacityPrecheckSchema the schema of the capacity_precheck argument of the resources adding hosts.
+func capacityPrecheckSchema() *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+		Description: "When true, the plan fails if the requested host instance type and amount of hosts are not " +
+			"available in the region, or would exceed the host limit of the organization.",
+	}
+}
+
+// hostCapacityRequest the hosts a plan requests, that checkHostCapacity verifies the availability of.
+type hostCapacityRequest struct {
+	providerType string
+	sddcType     string
+	region       string
+	// instanceType the host instance type in the Schema format, empty for the default one of the region
+	instanceType string
+	// numHosts the amount of hosts of the SDDC or cluster after the change
+	numHosts int
+	// addedHosts the amount of hosts the change adds to the organization
+	addedHosts int
+	// instanceTypeKey and numHostsKey the arguments the errors are reported for
+	instanceTypeKey string
+	numHostsKey     string
+}
+
+// checkHostCapacity verifies against the provision spec of the organization, that the requested host
+// instance type is offered in the region for the requested amount of hosts, and that the added hosts
+// do not exceed the host limit of the organization, as provisioning would fail long after it started
+// otherwise.
+func checkHostCapacity(apiClient *api.Client, request hostCapacityRequest) error {
+	provisionSpec, err := apiClient.ProvisionSpec().Get(apiClient.OrgID())
+	if err != nil {
+		return HandleListError("provision spec for the capacity precheck", err)
+	}
+	sddcType := request.sddcType
+	if len(sddcType) == 0 {
+		sddcType = constants.DefaultSddcType
+	}
+	configSpec, ok := provisionSpec.Provider[request.providerType].SddcTypeConfigSpec[sddcType]
+	if !ok {
+		return fmt.Errorf("no %s SDDCs of type %s are offered to organization %s",
+			request.providerType, sddcType, apiClient.OrgID())
+	}
+	region := strings.ReplaceAll(strings.ToUpper(request.region), "-", "_")
+	instanceTypeConfigs, ok := configSpec.Availability[region]
+	if !ok {
+		return newAttributeError("region", "no hosts are offered in region %s to organization %s",
+			region, apiClient.OrgID())
+	}
+
+	if len(request.instanceType) > 0 {
+		instanceTypeConfig, err := findInstanceTypeConfig(instanceTypeConfigs, request, region)
+		if err != nil {
+			return err
+		}
+		if !hostCountOffered(instanceTypeConfig.Hosts, request.numHosts) {
+			return newAttributeError(request.numHostsKey, "%d %s hosts are not offered in region %s, the offered host counts are %v",
+				request.numHosts, request.instanceType, region, instanceTypeConfig.Hosts)
+		}
+	}
+
+	if request.addedHosts > 0 {
+		return checkOrgHostLimit(apiClient, request)
+	}
+	return nil
+}
+
+// precheckSddcCapacity verifies the availability of the hosts of an SDDC, when it is created or its
+// primary cluster is scaled, if its capacity_precheck is enabled.
+func precheckSddcCapacity(d *schema.ResourceDiff, m interface{}) error {
+	if m == nil || !d.Get("capacity_precheck").(bool) || (d.Id() != "" && !d.HasChange("num_host")) {
+		return nil
+	}
+	for _, key := range []string{"provider_type", "sddc_type", "region", "host_instance_type", "num_host"} {
+		if !d.NewValueKnown(key) {
+			return nil
+		}
+	}
+	oldNumHosts, newNumHosts := d.GetChange("num_host")
+	return checkHostCapacity(api.NewClient(m.(*connector.Wrapper)), hostCapacityRequest{
+		providerType:    d.Get("provider_type").(string),
+		sddcType:        d.Get("sddc_type").(string),
+		region:          d.Get("region").(string),
+		instanceType:    d.Get("host_instance_type").(string),
+		numHosts:        newNumHosts.(int),
+		addedHosts:      newNumHosts.(int) - oldNumHosts.(int),
+		instanceTypeKey: "host_instance_type",
+		numHostsKey:     "num_host",
+	})
+}
+
+// findInstanceTypeConfig returns the config of the requested host instance type, failing if it is not
+// offered in the region or cannot be provisioned currently.
+func findInstanceTypeConfig(instanceTypeConfigs []model.InstanceTypeConfig, request hostCapacityRequest,
+	region string) (*model.InstanceTypeConfig, error) {
+	available := []string{}
+	for i := range instanceTypeConfigs {
+		instanceTypeConfig := &instanceTypeConfigs[i]
+		if instanceTypeConfig.InstanceType == nil {
+			continue
+		}
+		instanceType := fromHostInstanceType(*instanceTypeConfig.InstanceType)
+		if instanceType == fromHostInstanceType(request.instanceType) {
+			if instanceTypeConfig.InstanceProvisioningErrorCause != nil &&
+				len(*instanceTypeConfig.InstanceProvisioningErrorCause) > 0 {
+				return nil, newAttributeError(request.instanceTypeKey, "%s hosts cannot be provisioned in region %s: %s",
+					request.instanceType, region, *instanceTypeConfig.InstanceProvisioningErrorCause)
+			}
+			return instanceTypeConfig, nil
+		}
+		if instanceTypeConfig.InstanceProvisioningErrorCause == nil || len(*instanceTypeConfig.InstanceProvisioningErrorCause) == 0 {
+			available = append(available, instanceType)
+		}
+	}
+	sort.Strings(available)
+	return nil, newAttributeError(request.instanceTypeKey, "%s hosts are not offered in region %s, the available host instance types are %v",
+		request.instanceType, region, available)
+}
+
+func hostCountOffered(hostCounts []int64, numHosts int) bool {
+	if len(hostCounts) == 0 {
+		return true
+	}
+	for _, hostCount := range hostCounts {
+		if hostCount == int64(numHosts) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkOrgHostLimit fails if adding the requested hosts to the SDDCs of the organization would exceed
+// its host limit. Organizations without a host limit are not checked.
+func checkOrgHostLimit(apiClient *api.Client, request hostCapacityRequest) error {
+	org, err := apiClient.Orgs().Get(apiClient.OrgID())
+	if err != nil {
+		return HandleListError("organization for the capacity precheck", err)
+	}
+	if org.Properties == nil {
+		return nil
+	}
+	hostLimitValue, ok := org.Properties.Values[hostLimitOrgProperty]
+	if !ok {
+		return nil
+	}
+	hostLimit, err := strconv.Atoi(hostLimitValue)
+	if err != nil {
+		return fmt.Errorf("invalid host limit %q of organization %s: %v", hostLimitValue, apiClient.OrgID(), err)
+	}
+	sddcs, err := apiClient.Sddcs().List(apiClient.OrgID(), nil)
+	if err != nil {
+		return HandleListError("SDDCs for the capacity precheck", err)
+	}
+	usedHosts := 0
+	for _, sddc := range sddcs {
+		if sddc.SddcState != nil && *sddc.SddcState == model.Sddc_SDDC_STATE_DELETED {
+			continue
+		}
+		if sddc.ResourceConfig == nil {
+			continue
+		}
+		for _, cluster := range sddc.ResourceConfig.Clusters {
+			usedHosts += len(cluster.EsxHostList)
+		}
+	}
+	if usedHosts+request.addedHosts > hostLimit {
+		return newAttributeError(request.numHostsKey, "adding %d hosts would exceed the host limit of organization %s, "+
+			"%d of its %d hosts are in use", request.addedHosts, apiClient.OrgID(), usedHosts, hostLimit)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestSddcCapacityPrecheckSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.AddSddc(simulator.SddcConfig{Name: "existing_sddc", NumHosts: 4, Region: "US_WEST_2"})
+
+	testCases := []struct {
+		config      map[string]interface{}
+		expectedErr string
+	}{
+		{config: map[string]interface{}{"host_instance_type": "I3EN_METAL", "num_host": 3}},
+		{config: map[string]interface{}{"host_instance_type": "i4i.metal", "num_host": 16}},
+		{config: map[string]interface{}{"num_host": 2}},
+		{config: map[string]interface{}{"num_host": 2, "region": "ap-south-1"},
+			expectedErr: "no hosts are offered in region AP_SOUTH_1"},
+		{config: map[string]interface{}{"host_instance_type": "I3_METAL", "num_host": 3},
+			expectedErr: "I3_METAL hosts cannot be provisioned in region US_WEST_2"},
+		{config: map[string]interface{}{"host_instance_type": "I4I_METAL", "num_host": 3, "region": "EU_CENTRAL_1"},
+			expectedErr: "the available host instance types are [I3EN_METAL]"},
+		{config: map[string]interface{}{"host_instance_type": "I3EN_METAL", "num_host": 17},
+			expectedErr: "17 I3EN_METAL hosts are not offered in region US_WEST_2"},
+		{config: map[string]interface{}{"host_instance_type": "I3EN_METAL", "num_host": 17, "capacity_precheck": false}},
+	}
+	for _, testCase := range testCases {
+		rawConfig := map[string]interface{}{
+			"sddc_name":         "sddc",
+			"region":            "US_WEST_2",
+			"capacity_precheck": true,
+		}
+		for key, value := range testCase.config {
+			rawConfig[key] = value
+		}
+		_, err := resourceSddc().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+		if len(testCase.expectedErr) == 0 {
+			assert.NoError(t, err, "config: %v", testCase.config)
+		} else {
+			assert.ErrorContains(t, err, testCase.expectedErr, "config: %v", testCase.config)
+		}
+	}
+
+	// 4 of the 10 hosts of the organization are used by the existing SDDC
+	server.SetOrgProperty(simulator.TestOrgID, hostLimitOrgProperty, "10")
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc", NumHosts: 3, Region: "US_WEST_2"})
+	rawConfig := map[string]interface{}{
+		"sddc_name":         "sddc",
+		"region":            "US_WEST_2",
+		"num_host":          3,
+		"capacity_precheck": true,
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	state := d.State()
+	for numHost, expectedErr := range map[int]string{
+		6: "",
+		7: "adding 4 hosts would exceed the host limit of organization " + simulator.TestOrgID + ", 7 of its 10 hosts are in use",
+		2: "",
+	} {
+		rawConfig["num_host"] = numHost
+		_, err := resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+		if len(expectedErr) == 0 {
+			assert.NoError(t, err, "num_host: %d", numHost)
+		} else {
+			assert.ErrorContains(t, err, expectedErr, "num_host: %d", numHost)
+		}
+	}
+}
+
+func TestClusterCapacityPrecheckSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc", NumHosts: 4, Region: "EU_CENTRAL_1"})
+	server.SetOrgProperty(simulator.TestOrgID, hostLimitOrgProperty, "8")
+	newClusterConfig := func(numHosts int, hostInstanceType string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"sddc_id":            sddcID,
+			"num_hosts":          numHosts,
+			"host_instance_type": hostInstanceType,
+			"capacity_precheck":  true,
+		})
+	}
+
+	_, err := resourceCluster().Diff(context.Background(), nil, newClusterConfig(4, "I3EN_METAL"), connectorWrapper)
+	assert.NoError(t, err)
+	_, err = resourceCluster().Diff(context.Background(), nil, newClusterConfig(4, "I4I_METAL"), connectorWrapper)
+	assert.ErrorContains(t, err, "I4I_METAL hosts are not offered in region EU_CENTRAL_1")
+	_, err = resourceCluster().Diff(context.Background(), nil, newClusterConfig(5, "I3EN_METAL"), connectorWrapper)
+	assert.ErrorContains(t, err, "would exceed the host limit")
+}
@@ -0,0 +1,105 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+// Package clusterconversion provides a client of the VMC API, that converts the hosts of an
+// existing cluster to another host instance type, e.g. from i3.metal to i4i.metal. This API is
+// not covered by the VMC SDK.
+package clusterconversion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
+)
+
+type Client interface {
+	connector.Authenticator
+	Convert(sddcID string, clusterID string, hostInstanceType string) (taskID string, err error)
+}
+
+// ConversionRequest the body of a cluster conversion request.
+type ConversionRequest struct {
+	// HostInstanceType the host instance type, in the VMC API format, to convert the hosts of
+	// the cluster to.
+	HostInstanceType string `json:"host_instance_type"`
+}
+
+// Task the part of the VMC task, returned by the conversion API, that is needed to track it.
+type Task struct {
+	ID string `json:"id"`
+}
+
+type ClientImpl struct {
+	connector  connector.Wrapper
+	httpClient connector.HTTPClient
+}
+
+func NewConversionClient(wrapper connector.Wrapper) *ClientImpl {
+	copyWrapper := connector.CopyWrapper(wrapper)
+	return &ClientImpl{
+		connector:  *copyWrapper,
+		httpClient: copyWrapper.HTTPClient(),
+	}
+}
+
+// newTestConversionClient intended for injecting dummy accessToken and stubbed httpClient for
+// testing purposes.
+func newTestConversionClient(vmcURL string, orgID string, accessToken string, httpClient connector.HTTPClient) *ClientImpl {
+	testConnector := connector.Wrapper{
+		VmcURL: vmcURL,
+		OrgID:  orgID,
+	}
+	// Create a dummy connector to house the access token in a security context
+	testConnector.Connector = client.NewConnector("", client.WithHttpClient(&http.Client{}),
+		client.WithSecurityContext(security.NewOauthSecurityContext(accessToken)))
+	return &ClientImpl{
+		connector:  testConnector,
+		httpClient: httpClient,
+	}
+}
+
+// Authenticate grab an access token and set it into the Client instance for later use
+func (client *ClientImpl) Authenticate() error {
+	return client.connector.Authenticate()
+}
+
+// Convert starts the conversion of the hosts of the specified cluster to the provided host
+// instance type and returns the ID of the VMC task tracking it.
+func (client *ClientImpl) Convert(sddcID string, clusterID string, hostInstanceType string) (string, error) {
+	requestPayload, err := json.Marshal(ConversionRequest{HostInstanceType: hostInstanceType})
+	if err != nil {
+		return "", err
+	}
+	conversionURL := client.getBaseURL() + fmt.Sprintf("/orgs/%s/sddcs/%s/clusters/%s/convert",
+		client.connector.OrgID, sddcID, clusterID)
+	req := client.connector.NewRequest(http.MethodPost, conversionURL, bytes.NewBuffer(requestPayload))
+
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
+	if err != nil {
+		return "", err
+	}
+	if statusCode == http.StatusNotFound {
+		return "", fmt.Errorf("cluster %s of SDDC %s not found", clusterID, sddcID)
+	}
+	if statusCode != http.StatusOK && statusCode != http.StatusAccepted && statusCode != http.StatusCreated {
+		return "", fmt.Errorf("cluster conversion response code: %d body: %s", statusCode, string(*rawResponse))
+	}
+	var result Task
+	err = json.NewDecoder(bytes.NewReader(*rawResponse)).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+	if len(result.ID) == 0 {
+		return "", fmt.Errorf("cluster conversion response does not contain a task ID")
+	}
+	return result.ID, nil
+}
+
+func (client *ClientImpl) getBaseURL() string {
+	return client.connector.VmcURL + "/vmc/api"
+}
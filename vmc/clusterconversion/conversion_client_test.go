@@ -0,0 +1,82 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package clusterconversion
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+)
+
+const testAccessToken = "testAccessToken"
+const testOrgID = "testOrgID"
+const testVmcURL = "https://test.vmc.vmware.com"
+const testConversionURL = testVmcURL + "/vmc/api/orgs/testOrgID/sddcs/sddcId/clusters/clusterId/convert"
+
+type HTTPClientStub struct {
+	expectedJSON   string
+	expectedURL    string
+	responseJSON   string
+	responseCode   int
+	requestsServed int
+	t              *testing.T
+}
+
+func (stub *HTTPClientStub) Do(req *http.Request) (*http.Response, error) {
+	stub.requestsServed++
+	bodyBytes, _ := io.ReadAll(req.Body)
+	assert.Equal(stub.t, stub.expectedJSON, string(bodyBytes))
+	assert.Equal(stub.t, stub.expectedURL, req.URL.String())
+	assert.Equal(stub.t, http.MethodPost, req.Method)
+	assert.Equal(stub.t, testAccessToken, req.Header.Get(connector.AuthnHeader))
+	return &http.Response{
+		StatusCode: stub.responseCode,
+		Body:       io.NopCloser(strings.NewReader(stub.responseJSON)),
+	}, nil
+}
+
+func TestConvert(t *testing.T) {
+	stub := &HTTPClientStub{
+		expectedJSON: `{"host_instance_type":"i4i.metal"}`,
+		expectedURL:  testConversionURL,
+		responseJSON: `{"id": "taskId", "status": "STARTED"}`,
+		responseCode: http.StatusAccepted,
+		t:            t,
+	}
+	taskID, err := newTestConversionClient(testVmcURL, testOrgID, testAccessToken, stub).
+		Convert("sddcId", "clusterId", "i4i.metal")
+	assert.NoError(t, err)
+	assert.Equal(t, "taskId", taskID)
+	assert.Equal(t, 1, stub.requestsServed)
+}
+
+func TestConvertErrors(t *testing.T) {
+	testCases := []struct {
+		responseCode  int
+		responseJSON  string
+		expectedError string
+	}{
+		{responseCode: http.StatusNotFound, expectedError: "cluster clusterId of SDDC sddcId not found"},
+		{responseCode: http.StatusBadRequest, responseJSON: `{"error_messages": ["conversion not supported"]}`,
+			expectedError: "conversion not supported"},
+		{responseCode: http.StatusForbidden, expectedError: "Unauthorized request"},
+		{responseCode: http.StatusOK, responseJSON: `{}`, expectedError: "does not contain a task ID"},
+	}
+	for _, testCase := range testCases {
+		stub := &HTTPClientStub{
+			expectedJSON: `{"host_instance_type":"i4i.metal"}`,
+			expectedURL:  testConversionURL,
+			responseJSON: testCase.responseJSON,
+			responseCode: testCase.responseCode,
+			t:            t,
+		}
+		_, err := newTestConversionClient(testVmcURL, testOrgID, testAccessToken, stub).
+			Convert("sddcId", "clusterId", "i4i.metal")
+		assert.ErrorContains(t, err, testCase.expectedError)
+	}
+}
@@ -0,0 +1,176 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// AuditLog appends a JSON line for each mutating operation performed by the provider
+// to a local file, to serve as change-control evidence.
+type AuditLog struct {
+	path  string
+	mutex sync.Mutex
+}
+
+// AuditEntry a single line of the audit log.
+type AuditEntry struct {
+	Timestamp    time.Time        `json:"timestamp"`
+	ResourceType string           `json:"resource_type"`
+	ResourceID   string           `json:"resource_id"`
+	Operation    string           `json:"operation"`
+	Requests     []AuditedRequest `json:"requests"`
+	Outcome      string           `json:"outcome"`
+	Error        string           `json:"error,omitempty"`
+}
+
+// AuditedRequest a mutating API request issued during an audited operation.
+type AuditedRequest struct {
+	Method     string `json:"method"`
+	APIPath    string `json:"api_path"`
+	StatusCode int    `json:"status_code"`
+	TaskID     string `json:"task_id,omitempty"`
+}
+
+const (
+	AuditOutcomeSuccess = "success"
+	AuditOutcomeFailure = "failure"
+)
+
+// NewAuditLog returns an AuditLog writing to the file with the provided path.
+func NewAuditLog(path string) *AuditLog {
+	return &AuditLog{path: path}
+}
+
+// Write appends the provided entry to the audit log file, creating the file if necessary.
+func (auditLog *AuditLog) Write(entry AuditEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	auditLog.mutex.Lock()
+	defer auditLog.mutex.Unlock()
+	file, err := os.OpenFile(auditLog.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+// auditRecorder collects the mutating requests issued during a single audited operation.
+type auditRecorder struct {
+	mutex    sync.Mutex
+	requests []AuditedRequest
+}
+
+func (recorder *auditRecorder) record(request AuditedRequest) {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	recorder.requests = append(recorder.requests, request)
+}
+
+func (recorder *auditRecorder) recorded() []AuditedRequest {
+	recorder.mutex.Lock()
+	defer recorder.mutex.Unlock()
+	return append([]AuditedRequest{}, recorder.requests...)
+}
+
+// ForAuditedOperation returns a copy of the wrapper, that records the mutating requests issued
+// through it, together with a function that writes the audit log entry of the operation once it
+// is done. The wrapper itself is returned if audit logging is not enabled.
+func (c *Wrapper) ForAuditedOperation(resourceType string, operation string) (*Wrapper, func(resourceID string, err error)) {
+	if c.AuditLog == nil {
+		return c, func(string, error) {}
+	}
+	copyWrapper := CopyWrapper(*c)
+	copyWrapper.auditRecorder = &auditRecorder{}
+	copyWrapper.reconnect()
+	return copyWrapper, func(resourceID string, err error) {
+		entry := AuditEntry{
+			Timestamp:    time.Now().UTC(),
+			ResourceType: resourceType,
+			ResourceID:   resourceID,
+			Operation:    operation,
+			Requests:     copyWrapper.auditRecorder.recorded(),
+			Outcome:      AuditOutcomeSuccess,
+		}
+		if err != nil {
+			entry.Outcome = AuditOutcomeFailure
+			entry.Error = err.Error()
+		}
+		if writeErr := c.AuditLog.Write(entry); writeErr != nil {
+			log.Printf("[ERROR] Unable to write to audit log %s: %v", c.AuditLog.path, writeErr)
+		}
+	}
+}
+
+// auditTransport is a http.RoundTripper that records the mutating requests passing through it.
+type auditTransport struct {
+	recorder *auditRecorder
+	base     http.RoundTripper
+}
+
+func (t *auditTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if !isMutatingMethod(req.Method) || strings.HasPrefix(req.URL.Path, "/csp/gateway/") {
+		return res, err
+	}
+	auditedRequest := AuditedRequest{
+		Method:  req.Method,
+		APIPath: req.URL.Path,
+	}
+	if err == nil {
+		auditedRequest.StatusCode = res.StatusCode
+		auditedRequest.TaskID = readTaskID(res)
+	}
+	t.recorder.record(auditedRequest)
+	return res, err
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// readTaskID extracts the ID of the task, that tracks the request, from the response body
+// without consuming it.
+func readTaskID(res *http.Response) string {
+	if res.Body == nil || !strings.Contains(res.Header.Get("Content-Type"), "json") {
+		return ""
+	}
+	body, err := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	if err != nil {
+		return ""
+	}
+	var task struct {
+		ID       string `json:"id"`
+		TaskType string `json:"task_type"`
+		Status   string `json:"status"`
+	}
+	if json.Unmarshal(body, &task) != nil {
+		return ""
+	}
+	// Responses of some APIs are the created resource rather than a task
+	if len(task.TaskType) == 0 && len(task.Status) == 0 {
+		return ""
+	}
+	return task.ID
+}
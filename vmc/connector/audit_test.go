@@ -0,0 +1,79 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditedOperationRecordsMutatingRequests(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/task":
+			_, _ = w.Write([]byte(`{"id": "task-1", "status": "STARTED"}`))
+		default:
+			_, _ = w.Write([]byte(`{"id": "resource-1", "name": "resource"}`))
+		}
+	}))
+	defer server.Close()
+
+	auditLogPath := filepath.Join(t.TempDir(), "audit.log")
+	wrapper := &Wrapper{AuditLog: NewAuditLog(auditLogPath)}
+	auditedWrapper, writeAuditEntry := wrapper.ForAuditedOperation("vmc_test", "create")
+	httpClient := auditedWrapper.HTTPClient()
+	for _, request := range []struct{ method, path string }{
+		{http.MethodGet, "/read"},
+		{http.MethodPost, "/csp/gateway/am/api/auth/api-tokens/authorize"},
+		{http.MethodPost, "/task"},
+		{http.MethodPatch, "/resource"},
+	} {
+		req, _ := http.NewRequest(request.method, server.URL+request.path, nil)
+		res, err := httpClient.Do(req)
+		assert.NoError(t, err)
+		// The body remains readable after the task ID has been extracted from it
+		body, _ := io.ReadAll(res.Body)
+		assert.Contains(t, string(body), `"id"`)
+		_ = res.Body.Close()
+	}
+	writeAuditEntry("resource-1", nil)
+	writeAuditEntry("resource-1", errors.New("failed"))
+
+	content, err := os.ReadFile(auditLogPath)
+	assert.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	assert.Len(t, lines, 2)
+	var entry AuditEntry
+	assert.NoError(t, json.Unmarshal([]byte(lines[0]), &entry))
+	assert.Equal(t, "vmc_test", entry.ResourceType)
+	assert.Equal(t, "resource-1", entry.ResourceID)
+	assert.Equal(t, "create", entry.Operation)
+	assert.Equal(t, AuditOutcomeSuccess, entry.Outcome)
+	assert.Equal(t, []AuditedRequest{
+		{Method: http.MethodPost, APIPath: "/task", StatusCode: http.StatusOK, TaskID: "task-1"},
+		{Method: http.MethodPatch, APIPath: "/resource", StatusCode: http.StatusOK},
+	}, entry.Requests)
+	assert.NoError(t, json.Unmarshal([]byte(lines[1]), &entry))
+	assert.Equal(t, AuditOutcomeFailure, entry.Outcome)
+	assert.Equal(t, "failed", entry.Error)
+
+	// The original wrapper does not record requests
+	assert.Nil(t, wrapper.auditRecorder)
+}
+
+func TestAuditedOperationWithoutAuditLog(t *testing.T) {
+	wrapper := &Wrapper{}
+	auditedWrapper, writeAuditEntry := wrapper.ForAuditedOperation("vmc_test", "delete")
+	assert.Same(t, wrapper, auditedWrapper)
+	writeAuditEntry("resource-1", nil)
+}
@@ -10,11 +10,13 @@ import (
 	"errors"
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/clientcredentials"
 	"io"
 	"net/http"
 	"reflect"
 	"strings"
+	"time"
 
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/core"
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
@@ -33,124 +35,171 @@ type Wrapper struct {
 	OrgID        string
 	VmcURL       string
 	CspURL       string
+	// ExtraHeaders are added to every outbound request, including the ones to the Cloud Service Provider.
+	ExtraHeaders map[string]string
+	// Retry configures the retries of rate limited requests and requests failing with a transient error.
+	Retry RetryConfig
+	// TaskPoll configures how often the tasks started through the wrapper are polled.
+	TaskPoll TaskPollConfig
+	// Transport opens the connections of the requests, see NewTransport. http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+	// AuditLog records the mutating operations performed through the wrapper, if set.
+	AuditLog      *AuditLog
+	auditRecorder *auditRecorder
+	// tokens hands out the access token of the wrapper, refreshing it when it expires.
+	tokens *tokenSource
+	// logContext carries the Terraform logger the requests of the wrapper are logged with.
+	logContext context.Context
 }
 
 func CopyWrapper(original Wrapper) *Wrapper {
 	return &original
 }
 
-func (c *Wrapper) Authenticate() error {
-	var err error
-	httpClient := http.Client{}
-	if len(c.RefreshToken) > 0 {
-		c.Connector, err = newClientConnectorByRefreshToken(c.RefreshToken, c.VmcURL, c.CspURL, &httpClient)
-		if err != nil {
-			return err
+// HTTPClient returns a http.Client whose transport adds the configured ExtraHeaders
+// to each request. It is meant to be shared by all clients talking to VMC services.
+// When the wrapper is used for an audited operation the transport also records the mutating requests.
+// Once the wrapper is authenticated the transport also keeps the access token of the requests fresh.
+// Rate limited requests are retried according to the Retry configuration.
+// Each request sent, including each retry, is logged with the Terraform logger of the wrapper.
+func (c *Wrapper) HTTPClient() *http.Client {
+	base := c.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	httpClient := &http.Client{}
+	var transport http.RoundTripper = &loggingTransport{
+		ctx:  c.logContext,
+		base: base,
+	}
+	if c.Retry.MaxRetries > 0 {
+		transport = &retryTransport{
+			config: c.Retry,
+			base:   transport,
 		}
-		return nil
 	}
-	if len(c.ClientID) > 0 && len(c.ClientSecret) > 0 {
-		c.Connector, err = newClientConnectorByClientID(c.ClientID, c.ClientSecret, c.VmcURL, c.CspURL, &httpClient)
-		if err != nil {
-			return err
+	if c.tokens != nil {
+		transport = &tokenRefreshTransport{
+			tokens:     c.tokens,
+			httpClient: httpClient,
+			base:       transport,
 		}
-		return nil
 	}
-	return fmt.Errorf("no refreshToken or ClientID/ClientSecret provided")
+	if len(c.ExtraHeaders) > 0 {
+		transport = &headerTransport{
+			headers: c.ExtraHeaders,
+			base:    transport,
+		}
+	}
+	if c.auditRecorder != nil {
+		transport = &auditTransport{
+			recorder: c.auditRecorder,
+			base:     transport,
+		}
+	}
+	httpClient.Transport = transport
+	return httpClient
 }
 
-// newClientConnectorByRefreshToken returns client connector to any VMC service by using OAuth authentication using Refresh Token.
-func newClientConnectorByRefreshToken(refreshToken, serviceURL, cspURL string,
-	httpClient *http.Client) (client.Connector, error) {
-
-	if len(serviceURL) <= 0 {
-		serviceURL = constants.DefaultVmcURL
+// reconnect replaces the connector of the wrapper with one, that sends the requests through
+// HTTPClient, keeping its address and security context.
+func (c *Wrapper) reconnect() {
+	if c.Connector == nil {
+		return
 	}
+	c.Connector = client.NewConnector(c.Connector.Address(), client.UsingRest(nil),
+		client.WithHttpClient(c.HTTPClient()), client.WithSecurityContext(c.Connector.SecurityContext()),
+		client.WithApplicationContext(core.NewApplicationContext(nil)))
+}
 
-	if len(cspURL) <= 0 {
-		cspURL = constants.DefaultCspURL +
-			constants.CspRefreshURLSuffix
+func (c *Wrapper) Authenticate() error {
+	var fetch func(httpClient *http.Client) (accessToken, error)
+	var cacheKey string
+	if len(c.RefreshToken) > 0 {
+		refreshToken, cspURL := c.RefreshToken, cspEndpoint(c.CspURL, constants.CspRefreshURLSuffix)
+		fetch = func(httpClient *http.Client) (accessToken, error) {
+			return accessTokenByRefreshToken(refreshToken, cspURL, httpClient)
+		}
+		cacheKey = tokenCacheKey(c.ExtraHeaders, cspURL, refreshToken)
+	} else if len(c.ClientID) > 0 && len(c.ClientSecret) > 0 {
+		clientID, clientSecret, cspURL := c.ClientID, c.ClientSecret, cspEndpoint(c.CspURL, constants.CspTokenURLSuffix)
+		fetch = func(httpClient *http.Client) (accessToken, error) {
+			return accessTokenByClientID(clientID, clientSecret, cspURL, httpClient)
+		}
+		cacheKey = tokenCacheKey(c.ExtraHeaders, cspURL, clientID, clientSecret)
 	} else {
-		cspURL = cspURL +
-			constants.CspRefreshURLSuffix
+		return fmt.Errorf("no refreshToken or ClientID/ClientSecret provided")
 	}
-
-	securityCtx, err := securityContextByRefreshToken(refreshToken, cspURL)
+	// Wrappers with the same credentials, e.g. the ones of provider aliases for multiple regions,
+	// share the access token, instead of each of them exchanging the credentials for a new one.
+	// The tokens are fetched through the HTTP client of the wrapper requesting them, so that each
+	// wrapper reaches the Cloud Service Provider with its own transport settings.
+	tokens := sharedTokenSource(cacheKey, func(tokens *tokenSource) {
+		tokens.fetch = fetch
+	})
+	c.tokens = tokens
+	httpClient := c.HTTPClient()
+	token, err := tokens.token(httpClient)
 	if err != nil {
-		return nil, err
+		c.tokens = nil
+		return err
 	}
 
-	connector := client.NewConnector(serviceURL, client.UsingRest(nil),
-		client.WithHttpClient(httpClient), client.WithSecurityContext(securityCtx))
+	serviceURL := c.VmcURL
+	if len(serviceURL) <= 0 {
+		serviceURL = constants.DefaultVmcURL
+	}
+	// The application context is set upfront, as the connector lazily initializes it
+	// otherwise, which races when the connector is used by concurrent requests.
+	c.Connector = client.NewConnector(serviceURL, client.UsingRest(nil),
+		client.WithHttpClient(httpClient), client.WithSecurityContext(security.NewOauthSecurityContext(token)),
+		client.WithApplicationContext(core.NewApplicationContext(nil)))
+	return nil
+}
 
-	return connector, nil
+func cspEndpoint(cspURL string, suffix string) string {
+	if len(cspURL) <= 0 {
+		return constants.DefaultCspURL + suffix
+	}
+	return cspURL + suffix
 }
 
-// SecurityContextByRefreshToken returns Security Context with access token that is received from Cloud Service Provider using Refresh Token by OAuth authentication scheme.
-func securityContextByRefreshToken(refreshToken string, cspURL string) (core.SecurityContext, error) {
+// accessTokenByRefreshToken returns an access token, that is received from Cloud Service Provider using Refresh Token by OAuth authentication scheme.
+func accessTokenByRefreshToken(refreshToken string, cspURL string, httpClient *http.Client) (accessToken, error) {
 	payload := strings.NewReader("refresh_token=" + refreshToken)
 
 	req, _ := http.NewRequest("POST", cspURL, payload)
 
 	req.Header.Add("content-type", "application/x-www-form-urlencoded")
 
-	res, err := http.DefaultClient.Do(req)
-
-	if err != nil {
-		return nil, err
-	}
-
-	securityCtx, err := parseAuthnResponse(res)
-	if err != nil {
-		return nil, err
-	}
-	return securityCtx, nil
-}
-
-// newClientConnectorByClientID returns client connector to any VMC service by using OAuth authentication using clientId and secret.
-func newClientConnectorByClientID(clientID, clientSecret, serviceURL, cspURL string,
-	httpClient *http.Client) (client.Connector, error) {
-
-	if len(serviceURL) <= 0 {
-		serviceURL = constants.DefaultVmcURL
-	}
-
-	if len(cspURL) <= 0 {
-		cspURL = constants.DefaultCspURL +
-			constants.CspTokenURLSuffix
-	} else {
-		cspURL = cspURL +
-			constants.CspTokenURLSuffix
-	}
+	res, err := httpClient.Do(req)
 
-	securityCtx, err := securityContextByClientID(clientID, clientSecret, cspURL)
 	if err != nil {
-		return nil, err
+		return accessToken{}, err
 	}
 
-	connector := client.NewConnector(serviceURL, client.UsingRest(nil),
-		client.WithHttpClient(httpClient), client.WithSecurityContext(securityCtx))
-
-	return connector, nil
+	return parseAuthnResponse(res)
 }
 
-func securityContextByClientID(clientID string, clientSecret string, cspTokenEndpointURL string) (core.SecurityContext, error) {
+func accessTokenByClientID(clientID string, clientSecret string, cspTokenEndpointURL string,
+	httpClient *http.Client) (accessToken, error) {
 	oauth2Config := clientcredentials.Config{
 		ClientID:     clientID,
 		ClientSecret: clientSecret,
 		TokenURL:     cspTokenEndpointURL,
 	}
-	token, err := oauth2Config.Token(context.TODO())
+	ctx := context.WithValue(context.TODO(), oauth2.HTTPClient, httpClient)
+	token, err := oauth2Config.Token(ctx)
 	if err != nil {
-		return nil, err
+		return accessToken{}, err
 	}
-	return security.NewOauthSecurityContext(token.AccessToken), nil
+	return accessToken{value: token.AccessToken, expiry: token.Expiry}, nil
 }
 
-func parseAuthnResponse(response *http.Response) (*security.OauthSecurityContext, error) {
+func parseAuthnResponse(response *http.Response) (accessToken, error) {
 	if response.StatusCode != 200 {
 		b, _ := io.ReadAll(response.Body)
-		return nil, fmt.Errorf("response from Cloud Service Provider contains status code %d : %s", response.StatusCode, string(b))
+		return accessToken{}, fmt.Errorf("response from Cloud Service Provider contains status code %d : %s", response.StatusCode, string(b))
 	}
 
 	defer func(Body io.ReadCloser) {
@@ -163,21 +212,41 @@ func parseAuthnResponse(response *http.Response) (*security.OauthSecurityContext
 	var jsondata map[string]interface{}
 	err := json.NewDecoder(response.Body).Decode(&jsondata)
 	if err != nil {
-		return nil, fmt.Errorf("error decoding response : %v", err)
+		return accessToken{}, fmt.Errorf("error decoding response : %v", err)
 	}
 
-	var accessToken string
-	if token, ok := jsondata["access_token"]; ok {
-		if accessTokenStr, ok := token.(string); ok {
-			accessToken = accessTokenStr
+	var token accessToken
+	if value, ok := jsondata["access_token"]; ok {
+		if accessTokenStr, ok := value.(string); ok {
+			token.value = accessTokenStr
 		} else {
-			errMsg := fmt.Sprintf("Invalid type for access_token, expected string, actual %s", reflect.TypeOf(token).String())
-			return nil, errors.New(errMsg)
+			errMsg := fmt.Sprintf("Invalid type for access_token, expected string, actual %s", reflect.TypeOf(value).String())
+			return accessToken{}, errors.New(errMsg)
 		}
 	} else {
-		return nil, errors.New("cloud Service Provider authentication response does not contain access token")
+		return accessToken{}, errors.New("cloud Service Provider authentication response does not contain access token")
 	}
+	// Without an expiry the token is only refreshed, once it gets rejected
+	if expiresIn, ok := jsondata["expires_in"].(float64); ok && expiresIn > 0 {
+		token.expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+	return token, nil
+}
+
+// headerTransport is a http.RoundTripper that adds a fixed set of headers to each request,
+// without overriding the ones already set by the caller (e.g. authentication headers).
+type headerTransport struct {
+	headers map[string]string
+	base    http.RoundTripper
+}
 
-	securityCtx := security.NewOauthSecurityContext(accessToken)
-	return securityCtx, nil
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// A RoundTripper must not modify the original request
+	req = req.Clone(req.Context())
+	for name, value := range t.headers {
+		if len(req.Header.Get(name)) == 0 {
+			req.Header.Set(name, value)
+		}
+	}
+	return t.base.RoundTrip(req)
 }
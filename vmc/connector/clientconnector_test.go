@@ -0,0 +1,220 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
+)
+
+func TestHTTPClientExtraHeaders(t *testing.T) {
+	var received http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	wrapper := Wrapper{
+		ExtraHeaders: map[string]string{
+			"X-Gateway-Token": "gateway",
+			"Csp-Auth-Token":  "overridden",
+		},
+	}
+	req, _ := http.NewRequest(http.MethodGet, server.URL, nil)
+	req.Header.Set("csp-auth-token", "token")
+	res, err := wrapper.HTTPClient().Do(req)
+	assert.NoError(t, err)
+	_ = res.Body.Close()
+
+	assert.Equal(t, "gateway", received.Get("X-Gateway-Token"))
+	assert.Equal(t, "token", received.Get("csp-auth-token"))
+	// The original request must remain untouched
+	assert.Empty(t, req.Header.Get("X-Gateway-Token"))
+}
+
+func TestAuthenticateSendsExtraHeaders(t *testing.T) {
+	var gatewayHeaders []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gatewayHeaders = append(gatewayHeaders, r.Header.Get("X-Gateway-Token"))
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer"}`))
+	}))
+	defer server.Close()
+
+	for _, wrapper := range []Wrapper{
+		{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL},
+		{ClientID: "id", ClientSecret: "secret", CspURL: server.URL, VmcURL: server.URL},
+	} {
+		gatewayHeaders = nil
+		wrapper.ExtraHeaders = map[string]string{"X-Gateway-Token": "gateway"}
+		assert.NoError(t, wrapper.Authenticate())
+		assert.Equal(t, []string{"gateway"}, gatewayHeaders)
+	}
+}
+
+// newTokenTestServer returns a server, that hands out numbered access tokens and accepts only
+// the latest one, together with the number of tokens issued so far.
+func newTokenTestServer(t *testing.T, expiresIn int) (*httptest.Server, *int) {
+	issued := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/csp/gateway/") {
+			issued++
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"access_token": "token-%d", "expires_in": %d}`, issued, expiresIn)
+			return
+		}
+		if r.Header.Get("csp-auth-token") != fmt.Sprintf("token-%d", issued) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &issued
+}
+
+func sendAuthenticated(t *testing.T, wrapper *Wrapper, body string) *http.Response {
+	req, _ := http.NewRequest(http.MethodPost, wrapper.VmcURL+"/vmc/api/orgs", strings.NewReader(body))
+	req.Header.Set("csp-auth-token", wrapper.Connector.SecurityContext().Property(security.ACCESS_TOKEN).(string))
+	res, err := wrapper.HTTPClient().Do(req)
+	assert.NoError(t, err)
+	return res
+}
+
+func TestHTTPClientRetriesWithRefreshedToken(t *testing.T) {
+	server, issued := newTokenTestServer(t, 1799)
+	wrapper := &Wrapper{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL}
+	assert.NoError(t, wrapper.Authenticate())
+	assert.Equal(t, 1, *issued)
+
+	// The token gets rejected, as if it was revoked or expired early
+	*issued++
+	res := sendAuthenticated(t, wrapper, "payload")
+	body, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "payload", string(body), "the body must be sent again")
+	assert.Equal(t, 3, *issued)
+
+	// The refreshed token is reused
+	res = sendAuthenticated(t, wrapper, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, *issued)
+}
+
+func TestHTTPClientRetriesOnlyOnce(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/csp/gateway/") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"access_token": "token", "token_type": "bearer"}`))
+			return
+		}
+		requests++
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer server.Close()
+	wrapper := &Wrapper{ClientID: "id", ClientSecret: "secret", CspURL: server.URL, VmcURL: server.URL}
+	assert.NoError(t, wrapper.Authenticate())
+
+	res := sendAuthenticated(t, wrapper, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, res.StatusCode)
+	assert.Equal(t, 2, requests)
+}
+
+func TestHTTPClientRefreshesExpiringToken(t *testing.T) {
+	// The token expires within tokenRefreshMargin, so every request needs a new one
+	server, issued := newTokenTestServer(t, 60)
+	wrapper := &Wrapper{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL}
+	assert.NoError(t, wrapper.Authenticate())
+	assert.Equal(t, 1, *issued)
+
+	res := sendAuthenticated(t, wrapper, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, *issued, "the token must be refreshed before sending the request")
+
+	// Copies of the wrapper share the token
+	copyWrapper := CopyWrapper(*wrapper)
+	res = sendAuthenticated(t, copyWrapper, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, *issued)
+}
+
+func TestAuthenticateSharesTokenAcrossWrappers(t *testing.T) {
+	server, issued := newTokenTestServer(t, 1799)
+	wrapper := &Wrapper{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL}
+	assert.NoError(t, wrapper.Authenticate())
+	assert.Equal(t, 1, *issued)
+
+	// Wrappers with the same credentials, e.g. the ones of provider aliases, reuse the access token
+	aliasWrapper := &Wrapper{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL, OrgID: "other"}
+	assert.NoError(t, aliasWrapper.Authenticate())
+	assert.Equal(t, 1, *issued)
+
+	// A token refreshed by one of the wrappers is picked up by the other one
+	*issued++
+	res := sendAuthenticated(t, aliasWrapper, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, *issued)
+	res = sendAuthenticated(t, wrapper, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 3, *issued)
+
+	// Other credentials or extra headers get their own access token
+	for _, otherWrapper := range []*Wrapper{
+		{RefreshToken: "other-refresh", CspURL: server.URL, VmcURL: server.URL},
+		{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL, ExtraHeaders: map[string]string{"X-Gateway-Token": "gateway"}},
+	} {
+		issuedBefore := *issued
+		assert.NoError(t, otherWrapper.Authenticate())
+		assert.Equal(t, issuedBefore+1, *issued)
+	}
+}
+
+// countingTransport counts the requests to the Cloud Service Provider sent through it.
+type countingTransport struct {
+	cspRequests int
+}
+
+func (t *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if strings.HasPrefix(req.URL.Path, "/csp/gateway/") {
+		t.cspRequests++
+	}
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestSharedTokenIsFetchedThroughOwnTransport(t *testing.T) {
+	server, issued := newTokenTestServer(t, 1799)
+	transport, aliasTransport := &countingTransport{}, &countingTransport{}
+	wrapper := &Wrapper{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL, Transport: transport}
+	assert.NoError(t, wrapper.Authenticate())
+	aliasWrapper := &Wrapper{RefreshToken: "refresh", CspURL: server.URL, VmcURL: server.URL, Transport: aliasTransport}
+	assert.NoError(t, aliasWrapper.Authenticate())
+	assert.Equal(t, 1, *issued)
+	assert.Equal(t, 1, transport.cspRequests)
+
+	// A token refreshed for a request of the alias is fetched with the transport of the alias,
+	// e.g. its proxy and CAs, rather than with the one of the wrapper, that created the token source
+	*issued++
+	res := sendAuthenticated(t, aliasWrapper, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 1, transport.cspRequests)
+	assert.Equal(t, 1, aliasTransport.cspRequests)
+}
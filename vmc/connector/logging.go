@@ -0,0 +1,123 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// opIDHeader the header the vAPI runtime sends the operation ID of each request in.
+	opIDHeader = "Vapi-Ctx-Opid"
+	// requestIDHeader the header VMware Cloud Services return the ID of each request in.
+	requestIDHeader = "X-Request-Id"
+	// sessionIDHeader the header carrying the vAPI session of a request, if it has one.
+	sessionIDHeader = "Vmware-Api-Session-Id"
+)
+
+// sensitiveHeaders the headers, whose values are redacted from the logged requests and responses.
+var sensitiveHeaders = map[string]bool{
+	"Authorization":  true,
+	"Csp-Auth-Token": true,
+	"Cookie":         true,
+	"Set-Cookie":     true,
+	sessionIDHeader:  true,
+}
+
+// WithLogContext returns a copy of the wrapper, whose requests are logged with the Terraform
+// logger carried by the provided context, e.g. the one of the resource operation issuing them.
+func (c *Wrapper) WithLogContext(ctx context.Context) *Wrapper {
+	copyWrapper := CopyWrapper(*c)
+	copyWrapper.logContext = ctx
+	copyWrapper.reconnect()
+	return copyWrapper
+}
+
+// LogContext returns the context carrying the Terraform logger of the wrapper, see WithLogContext.
+func (c *Wrapper) LogContext() context.Context {
+	if c.logContext == nil {
+		return context.Background()
+	}
+	return c.logContext
+}
+
+// loggingTransport is a http.RoundTripper that logs each request sent to VMware Cloud Services,
+// together with the IDs VMware support needs to correlate it with the service logs. The request
+// and response headers are logged at TRACE level, with the credentials they carry redacted.
+type loggingTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t *loggingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := t.ctx
+	if ctx == nil {
+		ctx = req.Context()
+	}
+	fields := map[string]interface{}{
+		"http_method": req.Method,
+		"http_host":   req.URL.Host,
+		"http_path":   req.URL.Path,
+	}
+	addCorrelationIDs(fields, req.Header)
+	tflog.Trace(ctx, "Sending request to VMware Cloud Services", fields, map[string]interface{}{
+		"http_request_headers": redactHeaders(req.Header),
+	})
+
+	start := time.Now()
+	res, err := t.base.RoundTrip(req)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	if err != nil {
+		fields["error"] = err.Error()
+		tflog.Debug(ctx, "Request to VMware Cloud Services failed", fields)
+		return res, err
+	}
+	fields["http_status_code"] = res.StatusCode
+	addCorrelationIDs(fields, res.Header)
+	tflog.Debug(ctx, "Received response from VMware Cloud Services", fields)
+	tflog.Trace(ctx, "Response headers from VMware Cloud Services", fields, map[string]interface{}{
+		"http_response_headers": redactHeaders(res.Header),
+	})
+	return res, err
+}
+
+// addCorrelationIDs adds the operation, request and session IDs found in the headers to the fields.
+// The session ID is a credential, only a fingerprint of it is logged, which still allows to correlate
+// the requests sharing a session.
+func addCorrelationIDs(fields map[string]interface{}, header http.Header) {
+	if opID := header.Get(opIDHeader); len(opID) > 0 {
+		fields["op_id"] = opID
+	}
+	if requestID := header.Get(requestIDHeader); len(requestID) > 0 {
+		fields["request_id"] = requestID
+	}
+	if sessionID := header.Get(sessionIDHeader); len(sessionID) > 0 {
+		fields["session_fingerprint"] = fingerprint(sessionID)
+	}
+}
+
+// redactHeaders returns the headers as a map, with the values of the sensitive ones redacted.
+func redactHeaders(header http.Header) map[string]string {
+	redacted := make(map[string]string, len(header))
+	for name, values := range header {
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			redacted[name] = "REDACTED"
+			continue
+		}
+		redacted[name] = strings.Join(values, ", ")
+	}
+	return redacted
+}
+
+func fingerprint(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])[:12]
+}
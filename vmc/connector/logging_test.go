@@ -0,0 +1,66 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestsAreLoggedWithCorrelationIDs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Request-Id", "request-1")
+		w.Header().Set("Set-Cookie", "session=secret")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	var output bytes.Buffer
+	wrapper := (&Wrapper{}).WithLogContext(tflogtest.RootLogger(context.Background(), &output))
+	req, _ := http.NewRequest(http.MethodPost, server.URL+"/vmc/api/orgs/org-1/sddcs", nil)
+	req.Header.Set("csp-auth-token", "secret-token")
+	req.Header.Set("vapi-ctx-opid", "op-1")
+	res, err := wrapper.HTTPClient().Do(req)
+	assert.NoError(t, err)
+	_ = res.Body.Close()
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	assert.NoError(t, err)
+	assert.NotContains(t, output.String(), "secret")
+	var response map[string]interface{}
+	for _, entry := range entries {
+		if entry["@message"] == "Received response from VMware Cloud Services" {
+			response = entry
+		}
+	}
+	if !assert.NotNil(t, response, "no response logged in %v", entries) {
+		return
+	}
+	assert.Equal(t, "debug", response["@level"])
+	assert.Equal(t, http.MethodPost, response["http_method"])
+	assert.Equal(t, "/vmc/api/orgs/org-1/sddcs", response["http_path"])
+	assert.Equal(t, float64(http.StatusAccepted), response["http_status_code"])
+	assert.Equal(t, "op-1", response["op_id"])
+	assert.Equal(t, "request-1", response["request_id"])
+	assert.Contains(t, response, "duration_ms")
+	assert.Len(t, entries, 3)
+}
+
+func TestRedactHeaders(t *testing.T) {
+	header := http.Header{}
+	header.Set("Authorization", "Bearer token")
+	header.Set("vmware-api-session-id", "session")
+	header.Set("Content-Type", "application/json")
+	assert.Equal(t, map[string]string{
+		"Authorization":         "REDACTED",
+		"Vmware-Api-Session-Id": "REDACTED",
+		"Content-Type":          "application/json",
+	}, redactHeaders(header))
+}
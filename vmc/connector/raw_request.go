@@ -0,0 +1,62 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
+)
+
+// AuthnHeader the header carrying the access token of the requests to the VMC APIs, that are not
+// covered by the VMC SDK.
+const AuthnHeader = "csp-auth-token"
+
+// HTTPClient an interface, that is implemented by the http.DefaultClient,
+// intended to enable stubbing for testing purposes
+type HTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// NewRequest creates a request to a VMC API, that is not covered by the VMC SDK, authenticated with
+// the access token of the wrapper. The body, if any, is sent as JSON.
+func (c *Wrapper) NewRequest(method string, URL string, body io.Reader) *http.Request {
+	req, _ := http.NewRequest(method, URL, body)
+	req.Header.Add(AuthnHeader, c.Connector.SecurityContext().Property(security.ACCESS_TOKEN).(string))
+	if body != nil {
+		req.Header.Add("content-type", "application/json")
+	}
+	return req
+}
+
+// ExecuteRequest sends the request with the provided client. Returns the body of the response as
+// byte array pointer, the status code or any error that may have occurred during the Http communication.
+func ExecuteRequest(httpClient HTTPClient, request *http.Request) (responseBody *[]byte, statusCode int, err error) {
+	response, err := httpClient.Do(request)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			log.Printf("[WARN] Error closing body of http response: %v", err)
+		}
+	}(response.Body)
+
+	if response.StatusCode == http.StatusUnauthorized {
+		return nil, response.StatusCode, fmt.Errorf("Unauthenticated request")
+	}
+	if response.StatusCode == http.StatusForbidden {
+		return nil, response.StatusCode, fmt.Errorf("Unauthorized request")
+	}
+	result, err := io.ReadAll(response.Body)
+	if err != nil {
+		return nil, response.StatusCode, err
+	}
+
+	return &result, response.StatusCode, nil
+}
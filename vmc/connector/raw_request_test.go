@@ -0,0 +1,77 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
+)
+
+type httpClientStub struct {
+	responseCode int
+	responseBody string
+	err          error
+}
+
+func (stub *httpClientStub) Do(_ *http.Request) (*http.Response, error) {
+	if stub.err != nil {
+		return nil, stub.err
+	}
+	return &http.Response{
+		StatusCode: stub.responseCode,
+		Body:       io.NopCloser(strings.NewReader(stub.responseBody)),
+	}, nil
+}
+
+func TestNewRequest(t *testing.T) {
+	wrapper := &Wrapper{}
+	wrapper.Connector = client.NewConnector("", client.WithHttpClient(&http.Client{}),
+		client.WithSecurityContext(security.NewOauthSecurityContext("token")))
+
+	req := wrapper.NewRequest(http.MethodPost, "https://test.vmc.vmware.com/api", strings.NewReader("{}"))
+	assert.Equal(t, "token", req.Header.Get(AuthnHeader))
+	assert.Equal(t, "application/json", req.Header.Get("content-type"))
+
+	req = wrapper.NewRequest(http.MethodGet, "https://test.vmc.vmware.com/api", nil)
+	assert.Equal(t, "token", req.Header.Get(AuthnHeader))
+	assert.Empty(t, req.Header.Get("content-type"))
+}
+
+func TestExecuteRequest(t *testing.T) {
+	testCases := []struct {
+		stub          *httpClientStub
+		expectedCode  int
+		expectedBody  string
+		expectedError string
+	}{
+		{stub: &httpClientStub{responseCode: http.StatusOK, responseBody: `{"id":"task"}`},
+			expectedCode: http.StatusOK, expectedBody: `{"id":"task"}`},
+		{stub: &httpClientStub{responseCode: http.StatusNotFound, responseBody: "not found"},
+			expectedCode: http.StatusNotFound, expectedBody: "not found"},
+		{stub: &httpClientStub{responseCode: http.StatusUnauthorized},
+			expectedCode: http.StatusUnauthorized, expectedError: "Unauthenticated request"},
+		{stub: &httpClientStub{responseCode: http.StatusForbidden},
+			expectedCode: http.StatusForbidden, expectedError: "Unauthorized request"},
+		{stub: &httpClientStub{err: fmt.Errorf("connection refused")}, expectedError: "connection refused"},
+	}
+	for _, testCase := range testCases {
+		req, _ := http.NewRequest(http.MethodGet, "https://test.vmc.vmware.com/api", nil)
+		body, statusCode, err := ExecuteRequest(testCase.stub, req)
+		assert.Equal(t, testCase.expectedCode, statusCode)
+		if len(testCase.expectedError) > 0 {
+			assert.EqualError(t, err, testCase.expectedError)
+			assert.Nil(t, body)
+		} else {
+			assert.NoError(t, err)
+			assert.Equal(t, testCase.expectedBody, string(*body))
+		}
+	}
+}
@@ -0,0 +1,119 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"io"
+	"log"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RetryConfig configures the retries of requests, that are rate limited or fail with a transient error.
+type RetryConfig struct {
+	// MaxRetries the maximum amount of times a request is retried, requests are not retried when 0.
+	MaxRetries int
+	// MinDelay the delay before the first retry, doubled for each subsequent one.
+	MinDelay time.Duration
+	// MaxDelay the upper bound of the delay between retries.
+	MaxDelay time.Duration
+}
+
+// retryTransport is a http.RoundTripper that retries requests rejected with 429 Too Many Requests
+// or 503 Service Unavailable, honoring the Retry-After header of the response. Requests failing
+// with 502 Bad Gateway or 504 Gateway Timeout are retried only if their method is idempotent,
+// as the request may have been processed regardless.
+type retryTransport struct {
+	config RetryConfig
+	base   http.RoundTripper
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	getBody, err := replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+	for attempt := 0; ; attempt++ {
+		res, err := t.base.RoundTrip(withBody(req, getBody))
+		if err != nil || attempt >= t.config.MaxRetries || !isRetryable(req.Method, res.StatusCode) {
+			return res, err
+		}
+		delay, ok := t.delay(attempt, res)
+		if !ok {
+			return res, nil
+		}
+		_, _ = io.Copy(io.Discard, res.Body)
+		_ = res.Body.Close()
+		log.Printf("[DEBUG] %s %s failed with status code %d, retrying in %s", req.Method, req.URL.Path,
+			res.StatusCode, delay)
+		timer := time.NewTimer(delay)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// delay returns how long to wait before the specified retry. It returns false, if the server
+// asks to wait longer than the configured maximum delay.
+func (t *retryTransport) delay(attempt int, res *http.Response) (time.Duration, bool) {
+	if retryAfter, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+		return retryAfter, retryAfter <= t.config.MaxDelay
+	}
+	delay := t.config.MinDelay << attempt
+	if delay <= 0 || delay > t.config.MaxDelay {
+		delay = t.config.MaxDelay
+	}
+	// Jitter spreads the retries of requests, that were rate limited at the same time
+	jitter := time.Duration(0)
+	if delay > 1 {
+		jitter = time.Duration(rand.Int63n(int64(delay / 2)))
+	}
+	return delay/2 + jitter, true
+}
+
+func isRetryable(method string, statusCode int) bool {
+	switch statusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return !isMutatingMethod(method) || method == http.MethodPut || method == http.MethodDelete
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses the value of a Retry-After header, which is either an amount of
+// seconds or an HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if len(value) == 0 {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		delay := time.Until(date)
+		if delay < 0 {
+			delay = 0
+		}
+		return delay, true
+	}
+	return 0, false
+}
+
+func withBody(req *http.Request, getBody func() (io.ReadCloser, error)) *http.Request {
+	if getBody == nil {
+		return req
+	}
+	// A RoundTripper must not modify the original request
+	attempt := req.Clone(req.Context())
+	attempt.Body, _ = getBody()
+	attempt.GetBody = getBody
+	return attempt
+}
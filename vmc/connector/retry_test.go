@@ -0,0 +1,108 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// newFlakyTestServer returns a server, that fails the first failures requests with the specified
+// status code and Retry-After header, and echoes the body of the request afterwards.
+func newFlakyTestServer(t *testing.T, failures int, statusCode int, retryAfter string) (*httptest.Server, *int) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests <= failures {
+			if len(retryAfter) > 0 {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+			w.WriteHeader(statusCode)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		_, _ = w.Write(body)
+	}))
+	t.Cleanup(server.Close)
+	return server, &requests
+}
+
+func sendRetried(t *testing.T, method string, url string, body string) *http.Response {
+	wrapper := Wrapper{Retry: RetryConfig{MaxRetries: 2, MinDelay: time.Millisecond, MaxDelay: time.Second}}
+	req, _ := http.NewRequest(method, url, strings.NewReader(body))
+	res, err := wrapper.HTTPClient().Do(req)
+	assert.NoError(t, err)
+	return res
+}
+
+func TestHTTPClientRetriesRateLimitedRequests(t *testing.T) {
+	server, requests := newFlakyTestServer(t, 2, http.StatusTooManyRequests, "0")
+	res := sendRetried(t, http.MethodPost, server.URL, "payload")
+	body, _ := io.ReadAll(res.Body)
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "payload", string(body), "the body must be sent again")
+	assert.Equal(t, 3, *requests)
+
+	// The last response is returned, once the retries are exhausted
+	server, requests = newFlakyTestServer(t, 3, http.StatusTooManyRequests, "")
+	res = sendRetried(t, http.MethodGet, server.URL, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, 3, *requests)
+}
+
+func TestHTTPClientRetryAfterExceedsMaxDelay(t *testing.T) {
+	server, requests := newFlakyTestServer(t, 1, http.StatusTooManyRequests, "3600")
+	res := sendRetried(t, http.MethodGet, server.URL, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusTooManyRequests, res.StatusCode)
+	assert.Equal(t, 1, *requests)
+}
+
+func TestHTTPClientRetriesGatewayErrorsOfIdempotentRequests(t *testing.T) {
+	server, requests := newFlakyTestServer(t, 1, http.StatusBadGateway, "")
+	res := sendRetried(t, http.MethodGet, server.URL, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, 2, *requests)
+
+	// The request may have been processed, so it is not sent again
+	server, requests = newFlakyTestServer(t, 1, http.StatusGatewayTimeout, "")
+	res = sendRetried(t, http.MethodPost, server.URL, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusGatewayTimeout, res.StatusCode)
+	assert.Equal(t, 1, *requests)
+
+	server, requests = newFlakyTestServer(t, 1, http.StatusInternalServerError, "")
+	res = sendRetried(t, http.MethodGet, server.URL, "")
+	_ = res.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, res.StatusCode)
+	assert.Equal(t, 1, *requests)
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	delay, ok := parseRetryAfter("120")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, delay)
+
+	delay, ok = parseRetryAfter(time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.Equal(t, time.Duration(0), delay)
+
+	delay, ok = parseRetryAfter(time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, time.Hour, delay, float64(2*time.Second))
+
+	for _, value := range []string{"", "-1", "soon"} {
+		_, ok = parseRetryAfter(value)
+		assert.False(t, ok, value)
+	}
+}
@@ -0,0 +1,45 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+)
+
+// TaskPollConfig configures how often the tasks started by the provider are polled until they finish.
+type TaskPollConfig struct {
+	// Interval the delay before the second poll of a task, doubled for each subsequent poll.
+	Interval time.Duration
+	// MaxInterval the upper bound of the delay between the polls of a task.
+	MaxInterval time.Duration
+}
+
+// Delay returns how long to wait after the specified poll of a task, starting at 0 for the first
+// one. The delay is shortened by a random jitter of up to 20%, so that the tasks of a large parallel
+// apply are not polled in lockstep. Unset intervals fall back to the defaults of the provider.
+func (c TaskPollConfig) Delay(poll int) time.Duration {
+	interval, maxInterval := c.Interval, c.MaxInterval
+	if interval <= 0 {
+		interval = constants.DefaultTaskPollInterval * time.Second
+	}
+	if maxInterval <= 0 {
+		maxInterval = constants.DefaultTaskPollMaxInterval * time.Second
+	}
+	if maxInterval < interval {
+		maxInterval = interval
+	}
+	delay := maxInterval
+	// Avoid overflowing the shift for long-running tasks
+	if poll < 32 && interval<<poll > 0 && interval<<poll < maxInterval {
+		delay = interval << poll
+	}
+	jitter := time.Duration(0)
+	if delay/5 > 0 {
+		jitter = time.Duration(rand.Int63n(int64(delay / 5)))
+	}
+	return delay - jitter
+}
@@ -0,0 +1,31 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+)
+
+func TestTaskPollDelay(t *testing.T) {
+	config := TaskPollConfig{Interval: 10 * time.Second, MaxInterval: time.Minute}
+	for poll, expected := range map[int]time.Duration{
+		0:   10 * time.Second,
+		1:   20 * time.Second,
+		2:   40 * time.Second,
+		3:   time.Minute,
+		100: time.Minute,
+	} {
+		delay := config.Delay(poll)
+		assert.LessOrEqual(t, delay, expected, "poll %d", poll)
+		assert.Greater(t, delay, expected*4/5, "poll %d", poll)
+	}
+
+	delay := TaskPollConfig{}.Delay(0)
+	assert.LessOrEqual(t, delay, constants.DefaultTaskPollInterval*time.Second)
+	assert.Greater(t, delay, constants.DefaultTaskPollInterval*time.Second*4/5)
+}
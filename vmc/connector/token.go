@@ -0,0 +1,185 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
+)
+
+// tokenRefreshMargin how long before its expiry an access token is refreshed, so that it
+// does not expire while a request is in flight.
+const tokenRefreshMargin = 5 * time.Minute
+
+type accessToken struct {
+	value string
+	// expiry is zero, when the Cloud Service Provider does not report it
+	expiry time.Time
+}
+
+func (token accessToken) expiresSoon() bool {
+	return !token.expiry.IsZero() && time.Now().Add(tokenRefreshMargin).After(token.expiry)
+}
+
+// tokenSource hands out a valid access token to concurrent requests, fetching a new one
+// from the Cloud Service Provider when the current one expires or gets rejected. The token sources
+// are shared by wrappers, whose transports may differ, so the token is fetched through the HTTP client
+// of the wrapper, whose request needs it.
+type tokenSource struct {
+	mutex sync.Mutex
+	fetch func(httpClient *http.Client) (accessToken, error)
+	// current is empty until the first token is fetched
+	current accessToken
+}
+
+// token returns the current access token, refreshing it if it is about to expire.
+func (s *tokenSource) token(httpClient *http.Client) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if len(s.current.value) > 0 && !s.current.expiresSoon() {
+		return s.current.value, nil
+	}
+	return s.refreshLocked(httpClient)
+}
+
+// refresh fetches a new access token, in place of the rejected one. Concurrent requests
+// rejected with the same token result in a single refresh.
+func (s *tokenSource) refresh(rejected string, httpClient *http.Client) (string, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.current.value != rejected && !s.current.expiresSoon() {
+		return s.current.value, nil
+	}
+	return s.refreshLocked(httpClient)
+}
+
+func (s *tokenSource) refreshLocked(httpClient *http.Client) (string, error) {
+	token, err := s.fetch(httpClient)
+	if err != nil {
+		return "", err
+	}
+	s.current = token
+	return token.value, nil
+}
+
+// sharedTokenSources the token sources of the process, keyed by the credentials and the Cloud Service
+// Provider endpoint they are exchanged with, see tokenCacheKey.
+var sharedTokenSources = struct {
+	mutex   sync.Mutex
+	sources map[string]*tokenSource
+}{sources: map[string]*tokenSource{}}
+
+// sharedTokenSource returns the token source with the specified key, creating it with init on first use.
+func sharedTokenSource(key string, init func(tokens *tokenSource)) *tokenSource {
+	sharedTokenSources.mutex.Lock()
+	defer sharedTokenSources.mutex.Unlock()
+	tokens, ok := sharedTokenSources.sources[key]
+	if !ok {
+		tokens = &tokenSource{}
+		init(tokens)
+		sharedTokenSources.sources[key] = tokens
+	}
+	return tokens
+}
+
+// tokenCacheKey returns the key of the token source for the specified credentials and Cloud Service
+// Provider endpoint. The extra headers are part of the key, as they may be needed to reach the endpoint.
+// The credentials are hashed, so that they are not kept around in plain text.
+func tokenCacheKey(extraHeaders map[string]string, cspURL string, credentials ...string) string {
+	hash := sha256.New()
+	names := make([]string, 0, len(extraHeaders))
+	for name := range extraHeaders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		credentials = append(credentials, name+": "+extraHeaders[name])
+	}
+	for _, part := range append([]string{cspURL}, credentials...) {
+		hash.Write([]byte(part))
+		// Separates the parts, so that their boundaries are part of the key
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// tokenRefreshTransport is a http.RoundTripper that replaces the access token of authenticated
+// requests with the current one, and retries a request once with a new access token, when
+// the Cloud Service Provider rejects its token. This keeps long-running operations, like SDDC
+// creation, going past the lifetime of the access token obtained when the provider was configured.
+type tokenRefreshTransport struct {
+	tokens *tokenSource
+	// httpClient the client of the wrapper, that the tokens are fetched through
+	httpClient *http.Client
+	base       http.RoundTripper
+}
+
+func (t *tokenRefreshTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Requests to the Cloud Service Provider itself are the ones fetching the tokens
+	if len(req.Header.Get(security.CSP_AUTH_TOKEN_KEY)) == 0 || strings.HasPrefix(req.URL.Path, "/csp/gateway/") {
+		return t.base.RoundTrip(req)
+	}
+	token, err := t.tokens.token(t.httpClient)
+	if err != nil {
+		return nil, err
+	}
+	getBody, err := replayableBody(req)
+	if err != nil {
+		return nil, err
+	}
+	res, err := t.base.RoundTrip(withAccessToken(req, token, getBody))
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	newToken, err := t.tokens.refresh(token, t.httpClient)
+	if err != nil {
+		log.Printf("[WARN] Unable to refresh the access token: %v", err)
+		return res, nil
+	}
+	_, _ = io.Copy(io.Discard, res.Body)
+	_ = res.Body.Close()
+	log.Printf("[DEBUG] Retrying %s %s with a refreshed access token", req.Method, req.URL.Path)
+	return t.base.RoundTrip(withAccessToken(req, newToken, getBody))
+}
+
+// replayableBody returns a function, that returns a fresh copy of the body of the request,
+// so that it can be sent again.
+func replayableBody(req *http.Request) (func() (io.ReadCloser, error), error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+	// Each attempt sends its own copy of the body, so the original one is not needed
+	defer req.Body.Close()
+	if req.GetBody != nil {
+		return req.GetBody, nil
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}, nil
+}
+
+func withAccessToken(req *http.Request, token string, getBody func() (io.ReadCloser, error)) *http.Request {
+	// A RoundTripper must not modify the original request
+	attempt := req.Clone(req.Context())
+	attempt.Header.Set(security.CSP_AUTH_TOKEN_KEY, token)
+	if getBody != nil {
+		attempt.Body, _ = getBody()
+		attempt.GetBody = getBody
+	}
+	return attempt
+}
@@ -0,0 +1,78 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// TransportConfig configures the connections to VMware Cloud Services, e.g. for runners behind a
+// TLS-intercepting proxy.
+type TransportConfig struct {
+	// ProxyURL the proxy all requests are sent through. The proxy of the HTTPS_PROXY and
+	// NO_PROXY environment variables is used, if it is empty.
+	ProxyURL string
+	// CAFile the path of a PEM bundle with the certificates of the CAs, that are trusted in
+	// addition to the ones of the system.
+	CAFile string
+	// InsecureSkipVerify disables the verification of the server certificates.
+	InsecureSkipVerify bool
+}
+
+// NewTransport returns the http.RoundTripper, that opens the connections of the provided config.
+// http.DefaultTransport is returned, if the config does not differ from its defaults, so that
+// its connections are reused.
+func NewTransport(config TransportConfig) (http.RoundTripper, error) {
+	if config == (TransportConfig{}) {
+		return http.DefaultTransport, nil
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if len(config.ProxyURL) > 0 {
+		proxyURL, err := url.Parse(config.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy_url %q: %v", config.ProxyURL, err)
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return nil, fmt.Errorf("invalid proxy_url %q: expected an http, https or socks5 URL", config.ProxyURL)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// Only disabled when explicitly requested with allow_unverified_ssl
+		InsecureSkipVerify: config.InsecureSkipVerify,
+	}
+	if len(config.CAFile) > 0 {
+		rootCAs, err := loadCAFile(config.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = rootCAs
+	}
+	transport.TLSClientConfig = tlsConfig
+	return transport, nil
+}
+
+// loadCAFile returns the CAs of the system, extended with the ones in the provided PEM bundle.
+func loadCAFile(path string) (*x509.CertPool, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading ca_file: %v", err)
+	}
+	rootCAs, err := x509.SystemCertPool()
+	if err != nil || rootCAs == nil {
+		rootCAs = x509.NewCertPool()
+	}
+	if !rootCAs.AppendCertsFromPEM(content) {
+		return nil, fmt.Errorf("invalid ca_file %s: no PEM encoded certificates found", path)
+	}
+	return rootCAs, nil
+}
@@ -0,0 +1,77 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package connector
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTransportDefaults(t *testing.T) {
+	transport, err := NewTransport(TransportConfig{})
+	assert.NoError(t, err)
+	assert.Same(t, http.DefaultTransport, transport)
+}
+
+func TestNewTransportCAFile(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	certificate := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	assert.NoError(t, os.WriteFile(caFile, certificate, 0600))
+
+	for _, test := range []struct {
+		config      TransportConfig
+		expectError bool
+	}{
+		{config: TransportConfig{}, expectError: true},
+		{config: TransportConfig{CAFile: caFile}},
+		{config: TransportConfig{InsecureSkipVerify: true}},
+	} {
+		transport, err := NewTransport(test.config)
+		assert.NoError(t, err)
+		wrapper := &Wrapper{Transport: transport}
+		res, err := wrapper.HTTPClient().Get(server.URL)
+		if test.expectError {
+			assert.ErrorContains(t, err, "certificate")
+			continue
+		}
+		assert.NoError(t, err)
+		_ = res.Body.Close()
+	}
+
+	_, err := NewTransport(TransportConfig{CAFile: filepath.Join(t.TempDir(), "missing.pem")})
+	assert.ErrorContains(t, err, "error reading ca_file")
+	assert.NoError(t, os.WriteFile(caFile, []byte("not a certificate"), 0600))
+	_, err = NewTransport(TransportConfig{CAFile: caFile})
+	assert.ErrorContains(t, err, "no PEM encoded certificates found")
+}
+
+func TestNewTransportProxyURL(t *testing.T) {
+	var proxiedURL string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests for plain HTTP URLs are sent to the proxy with the absolute URL
+		proxiedURL = r.URL.String()
+	}))
+	defer proxy.Close()
+
+	transport, err := NewTransport(TransportConfig{ProxyURL: proxy.URL})
+	assert.NoError(t, err)
+	wrapper := &Wrapper{Transport: transport}
+	res, err := wrapper.HTTPClient().Get("http://vmc.example.com/vmc/api/orgs")
+	assert.NoError(t, err)
+	_ = res.Body.Close()
+	assert.Equal(t, "http://vmc.example.com/vmc/api/orgs", proxiedURL)
+
+	for _, proxyURL := range []string{"ftp://proxy.example.com", "://proxy"} {
+		_, err = NewTransport(TransportConfig{ProxyURL: proxyURL})
+		assert.ErrorContains(t, err, "invalid proxy_url", proxyURL)
+	}
+}
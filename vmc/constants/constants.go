@@ -10,6 +10,19 @@ const (
 	// DefaultCspURL defines the default URL for CSP.
 	DefaultCspURL string = "https://console.cloud.vmware.com"
 
+	// VMC environments, that can be selected with the environment provider argument
+	CommercialEnvironment = "commercial"
+	GovCloudEnvironment   = "govcloud"
+	StagingEnvironment    = "staging"
+
+	// GovCloudVmcURL and GovCloudCspURL the URLs of VMware Cloud on AWS GovCloud (US).
+	GovCloudVmcURL string = "https://www.vmc-us-gov.vmware.com"
+	GovCloudCspURL string = "https://console.cloud-us-gov.vmware.com"
+
+	// StagingVmcURL and StagingCspURL the URLs of the VMC staging environment.
+	StagingVmcURL string = "https://stg.skyscraper.vmware.com"
+	StagingCspURL string = "https://console-stg.cloud.vmware.com"
+
 	// CspRefreshURLSuffix defines the CSP Refresh Token API endpoint.
 	CspRefreshURLSuffix string = "/csp/gateway/am/api/auth/api-tokens/authorize"
 
@@ -38,6 +51,11 @@ const (
 	SrmPrefix          = "srm-"
 	SddcSuffix         = ".sddc-"
 
+	// SrmApplianceUIPort the port of the SRM appliance management UI
+	SrmApplianceUIPort = 5480
+	// SrmRestAPIPath the base path of the SRM REST API, served by the SRM appliance
+	SrmRestAPIPath = "/api/rest/srm/v1"
+
 	// EDRS Policy types
 	CostPolicyType           = "cost"
 	PerformancePolicyType    = "performance"
@@ -52,6 +70,7 @@ const (
 
 	// SDDC Type
 	OneNodeSddcType = "1NODE"
+	DefaultSddcType = "DEFAULT"
 
 	// Provider Types
 	AwsProviderType       = "AWS"
@@ -65,6 +84,66 @@ const (
 	MinHosts = 2
 	MaxHosts = 16
 
+	// MaxAdditionalSrmNodes the maximum amount of SRM nodes per SDDC, on top of the one
+	// deployed upon site recovery activation.
+	MaxAdditionalSrmNodes = 9
+
+	// MaxConcurrentPublicIPRequests the maximum amount of concurrent NSX requests the
+	// vmc_public_ips resource sends, when allocating or releasing public IPs.
+	MaxConcurrentPublicIPRequests = 5
+
+	// Types of the VMC tasks, that the provider resumes waiting for after an interrupted apply
+	SddcProvisionTaskType     = "SDDC-PROVISION"
+	SddcDeleteTaskType        = "SDDC-DELETE"
+	SddcUpsizeTaskType        = "SDDC-UPSIZE"
+	ClusterProvisionTaskType  = "CLUSTER-PROVISION"
+	ClusterDeleteTaskType     = "CLUSTER-DELETE"
+	ClusterConversionTaskType = "CLUSTER-CONVERSION"
+
+	// DefaultClusterConversionTimeout the default time to wait for the conversion of the hosts of a
+	// cluster to another host instance type, which replaces the hosts one at a time.
+	DefaultClusterConversionTimeout = "12h"
+
+	// Types of the external attachments of the transit gateway of an SDDC group, managed
+	// by the vmc_sddc_group_external_attachment resource
+	VpcExternalAttachmentType = "VPC"
+	TgwExternalAttachmentType = "TGW"
+
+	// Services, whose tasks can be looked up by the vmc_task data source and vmc_task_wait resource
+	VmcTaskService   = "vmc"
+	DraasTaskService = "draas"
+
+	// Defaults of the provider arguments configuring the retries of rate limited and transient failed requests
+	DefaultMaxRetries    = 4
+	DefaultRetryMinDelay = 1
+	DefaultRetryMaxDelay = 30
+
+	// Defaults of the provider arguments configuring the polling of tasks, in seconds
+	DefaultTaskPollInterval    = 10
+	DefaultTaskPollMaxInterval = 60
+
+	// Environment Env variable with the VMC environment the provider talks to
+	Environment string = "VMC_ENVIRONMENT"
+
+	// AuditLogPath Env variable with the path of the provider audit log file
+	AuditLogPath string = "VMC_AUDIT_LOG_PATH"
+
+	// CredentialFile Env variable with the path of the file the provider credentials are read from
+	CredentialFile string = "VMC_CREDENTIAL_FILE"
+
+	// ProxyURL Env variable with the URL of the proxy the provider requests are sent through
+	ProxyURL string = "VMC_PROXY_URL"
+
+	// CAFile Env variable with the path of a PEM bundle with additionally trusted CAs
+	CAFile string = "VMC_CA_FILE"
+
+	// AllowUnverifiedSSL Env variable disabling the verification of the server certificates
+	AllowUnverifiedSSL string = "VMC_ALLOW_UNVERIFIED_SSL"
+
+	// CredentialProcessTimeout the seconds the command of the credential_process provider argument has
+	// to print the credentials
+	CredentialProcessTimeout = 120
+
 	// Env variables used in acceptance tests
 	VmcURL         string = "VMC_URL"
 	CspURL         string = "CSP_URL"
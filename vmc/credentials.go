@@ -0,0 +1,122 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+)
+
+// providerCredentials the credentials read from the credential_file or printed by the
+// credential_process of the provider. Either RefreshToken, or ClientID and ClientSecret are set.
+type providerCredentials struct {
+	RefreshToken string `json:"refresh_token"`
+	ClientID     string `json:"client_id"`
+	ClientSecret string `json:"client_secret"`
+}
+
+// resolveCredentials replaces the credentials of the provider config with the ones read from its
+// credential file or printed by its credential process, if either is configured, so that the
+// credentials do not have to be part of the Terraform configuration.
+func resolveCredentials(config *providerConfig) error {
+	var credentials *providerCredentials
+	var err error
+	switch {
+	case len(config.CredentialFile) > 0 && len(config.CredentialProcess) > 0:
+		return fmt.Errorf("only one of credential_file and credential_process can be specified")
+	case len(config.CredentialFile) > 0:
+		credentials, err = readCredentialFile(config.CredentialFile)
+	case len(config.CredentialProcess) > 0:
+		credentials, err = runCredentialProcess(config.CredentialProcess)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	config.RefreshToken = credentials.RefreshToken
+	config.ClientID = credentials.ClientID
+	config.ClientSecret = credentials.ClientSecret
+	return nil
+}
+
+// readCredentialFile reads the credentials from a file, holding either an API token or the JSON
+// format of providerCredentials.
+func readCredentialFile(path string) (*providerCredentials, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credential_file: %v", err)
+	}
+	credentials, err := parseCredentials(content)
+	if err != nil {
+		return nil, fmt.Errorf("invalid credential_file %s: %v", path, err)
+	}
+	return credentials, nil
+}
+
+// runCredentialProcess runs the command of the credential_process argument, with its arguments, and
+// parses the credentials it prints to its standard output. The output is not logged, and neither
+// is it part of the returned errors.
+func runCredentialProcess(command []string) (*providerCredentials, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), constants.CredentialProcessTimeout*time.Second)
+	defer cancel()
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return nil, fmt.Errorf("credential_process %s did not finish within %ds", command[0],
+				constants.CredentialProcessTimeout)
+		}
+		return nil, fmt.Errorf("credential_process %s failed: %v: %s", command[0], err,
+			strings.TrimSpace(stderr.String()))
+	}
+	credentials, err := parseCredentials(stdout.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("invalid output of credential_process %s: %v", command[0], err)
+	}
+	return credentials, nil
+}
+
+// parseCredentials parses either a JSON object in the providerCredentials format, or a bare API
+// token, e.g. one mounted from a secret store.
+func parseCredentials(content []byte) (*providerCredentials, error) {
+	trimmed := strings.TrimSpace(string(content))
+	if len(trimmed) == 0 {
+		return nil, fmt.Errorf("no credentials found")
+	}
+	if !strings.HasPrefix(trimmed, "{") {
+		if strings.ContainsAny(trimmed, " \t\r\n") {
+			return nil, fmt.Errorf("expected an API token or a JSON object with refresh_token or client_id and client_secret")
+		}
+		return &providerCredentials{RefreshToken: trimmed}, nil
+	}
+	credentials := &providerCredentials{}
+	decoder := json.NewDecoder(strings.NewReader(trimmed))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(credentials); err != nil {
+		// The JSON errors do not include the values, so the credentials are not leaked
+		return nil, fmt.Errorf("invalid JSON: %v", err)
+	}
+	hasRefreshToken := len(credentials.RefreshToken) > 0
+	hasClientCredentials := len(credentials.ClientID) > 0 || len(credentials.ClientSecret) > 0
+	switch {
+	case hasRefreshToken && hasClientCredentials:
+		return nil, fmt.Errorf("refresh_token conflicts with client_id and client_secret")
+	case !hasRefreshToken && !hasClientCredentials:
+		return nil, fmt.Errorf("expected refresh_token or client_id and client_secret")
+	case hasClientCredentials && (len(credentials.ClientID) == 0 || len(credentials.ClientSecret) == 0):
+		return nil, fmt.Errorf("client_id and client_secret have to be specified together")
+	}
+	return credentials, nil
+}
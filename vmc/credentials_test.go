@@ -0,0 +1,109 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+// TestCredentialProcessHelper is not a test, it is the credential process run by the credential_process
+// tests, printing the output passed in the environment.
+func TestCredentialProcessHelper(_ *testing.T) {
+	output, ok := os.LookupEnv("VMC_TEST_CREDENTIAL_PROCESS_OUTPUT")
+	if !ok {
+		return
+	}
+	// Each run is recorded as a line of the runs file, if there is one
+	if runsFile, ok := os.LookupEnv("VMC_TEST_CREDENTIAL_PROCESS_RUNS"); ok {
+		file, err := os.OpenFile(runsFile, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err == nil {
+			fmt.Fprintln(file, "run")
+			_ = file.Close()
+		}
+	}
+	if output == "fail" {
+		fmt.Fprint(os.Stderr, "not logged in")
+		os.Exit(1)
+	}
+	fmt.Print(output)
+	os.Exit(0)
+}
+
+func credentialProcessHelper(t *testing.T, output string) []string {
+	t.Setenv("VMC_TEST_CREDENTIAL_PROCESS_OUTPUT", output)
+	return []string{os.Args[0], "-test.run=TestCredentialProcessHelper"}
+}
+
+func TestParseCredentials(t *testing.T) {
+	credentials, err := parseCredentials([]byte("api-token\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, providerCredentials{RefreshToken: "api-token"}, *credentials)
+
+	credentials, err = parseCredentials([]byte(`{"client_id": "id", "client_secret": "secret"}`))
+	assert.NoError(t, err)
+	assert.Equal(t, providerCredentials{ClientID: "id", ClientSecret: "secret"}, *credentials)
+
+	for content, expectedError := range map[string]string{
+		"":                       "no credentials found",
+		"two tokens":             "expected an API token or a JSON object",
+		`{"api_token": "token"}`: "unknown field",
+		`{}`:                     "expected refresh_token or client_id and client_secret",
+		`{"client_id": "id"}`:    "have to be specified together",
+		`{"refresh_token": "t", "client_id": "i"}`: "conflicts with client_id",
+	} {
+		_, err := parseCredentials([]byte(content))
+		assert.ErrorContains(t, err, expectedError, content)
+	}
+}
+
+func TestResolveCredentials(t *testing.T) {
+	credentialFile := filepath.Join(t.TempDir(), "credentials")
+	assert.NoError(t, os.WriteFile(credentialFile, []byte(`{"refresh_token": "file-token"}`), 0600))
+	config := providerConfig{ClientID: "id", ClientSecret: "secret", CredentialFile: credentialFile}
+	assert.NoError(t, resolveCredentials(&config))
+	assert.Equal(t, "file-token", config.RefreshToken)
+	assert.Equal(t, "", config.ClientID, "the credential file replaces the configured credentials")
+
+	config = providerConfig{CredentialFile: filepath.Join(t.TempDir(), "missing")}
+	assert.ErrorContains(t, resolveCredentials(&config), "error reading credential_file")
+
+	config = providerConfig{CredentialProcess: credentialProcessHelper(t, `{"client_id": "id", "client_secret": "secret"}`)}
+	assert.NoError(t, resolveCredentials(&config))
+	assert.Equal(t, "id", config.ClientID)
+	assert.Equal(t, "secret", config.ClientSecret)
+
+	config = providerConfig{CredentialProcess: credentialProcessHelper(t, "fail")}
+	assert.ErrorContains(t, resolveCredentials(&config), "not logged in")
+
+	config = providerConfig{RefreshToken: "token"}
+	assert.NoError(t, resolveCredentials(&config))
+	assert.Equal(t, "token", config.RefreshToken)
+}
+
+func TestProviderConfigureCredentialFileSimulator(t *testing.T) {
+	server := simulator.NewServer()
+	defer server.Close()
+	t.Setenv(constants.APIToken, "")
+	credentialFile := filepath.Join(t.TempDir(), "api-token")
+	assert.NoError(t, os.WriteFile(credentialFile, []byte("refresh-token\n"), 0600))
+	t.Setenv(constants.CredentialFile, credentialFile)
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"org_id":  simulator.TestOrgID,
+		"vmc_url": server.URL,
+		"csp_url": server.URL,
+	})
+	connectorWrapper, err := providerConfigure(d)
+	assert.NoError(t, err)
+	assert.Equal(t, "refresh-token", connectorWrapper.(*connector.Wrapper).RefreshToken)
+}
@@ -4,16 +4,21 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
-	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"sort"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/account_link"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
 )
 
 func dataSourceVmcConnectedAccounts() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVmcConnectedAccountsRead,
+		ReadContext: dataSourceVmcConnectedAccountsRead,
 
 		Schema: map[string]*schema.Schema{
 			"provider_type": {
@@ -24,45 +29,175 @@ func dataSourceVmcConnectedAccounts() *schema.Resource {
 			},
 			"account_number": {
 				Type:        schema.TypeString,
-				Description: "AWS account number.",
-				Required:    true,
+				Description: "AWS account number. When specified, only the connected account with this number is returned.",
+				Optional:    true,
+			},
+			"state": {
+				Type:        schema.TypeString,
+				Description: "Only return the connected accounts in this state (ACTIVE, BROKEN or DELETED).",
+				Optional:    true,
+				ValidateFunc: validation.StringInSlice([]string{
+					model.AwsCustomerConnectedAccount_STATE_ACTIVE,
+					model.AwsCustomerConnectedAccount_STATE_BROKEN,
+					model.AwsCustomerConnectedAccount_STATE_DELETED}, false),
+			},
+			"include_linked_vpcs": {
+				Type:        schema.TypeBool,
+				Description: "When true, the compatible VPCs of the active connected accounts are looked up in each of their regions.",
+				Optional:    true,
+				Default:     false,
 			},
 			"id": {
 				Type:        schema.TypeString,
 				Description: "The corresponding connected (customer) account UUID this connection is attached to.",
 				Computed:    true,
 			},
+			"ids": {
+				Type:        schema.TypeList,
+				Description: "The IDs of the matching connected accounts, in the same order as accounts.",
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+			},
+			"accounts": {
+				Type:        schema.TypeList,
+				Description: "The matching connected accounts, sorted by account number.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"account_number": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cf_stack_name": {
+							Type:        schema.TypeString,
+							Description: "Name of the AWS CloudFormation stack, that linked the account.",
+							Computed:    true,
+						},
+						"regions": {
+							Type:        schema.TypeList,
+							Description: "The AWS regions, the account is linked in.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+						"linked_vpc_ids": {
+							Type:        schema.TypeList,
+							Description: "The IDs of the compatible VPCs of the account, looked up only if include_linked_vpcs is true.",
+							Computed:    true,
+							Elem:        &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func dataSourceVmcConnectedAccountsRead(d *schema.ResourceData, m interface{}) error {
-	orgID := (m.(*connector.Wrapper)).OrgID
+func dataSourceVmcConnectedAccountsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
 	providerType := d.Get("provider_type").(string)
 	accountNumber := d.Get("account_number").(string)
+	state := d.Get("state").(string)
 
-	connectorWrapper := (m.(*connector.Wrapper)).Connector
-	defaultConnectedAccountsClient := account_link.NewConnectedAccountsClient(connectorWrapper)
-	accounts, err := defaultConnectedAccountsClient.Get(orgID, &providerType)
-
-	if accountNumber == "" {
-		return fmt.Errorf("account number is a required parameter and cannot be empty")
+	accounts, err := apiClient.ConnectedAccounts().Get(apiClient.OrgID(), &providerType)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Connected Accounts", err))
 	}
-	id := ""
+
+	var matches []model.AwsCustomerConnectedAccount
 	for _, account := range accounts {
-		if *account.AccountNumber == accountNumber {
-			id = account.Id
+		if len(accountNumber) > 0 && (account.AccountNumber == nil || *account.AccountNumber != accountNumber) {
+			continue
+		}
+		if len(state) > 0 && (account.State == nil || *account.State != state) {
+			continue
 		}
+		matches = append(matches, account)
 	}
-
-	if err != nil {
-		return HandleDataSourceReadError("Connected Accounts", err)
+	if len(accountNumber) > 0 && len(matches) == 0 {
+		return toDiagnostics(newAttributeError("account_number", "no connected account found with the account number : %q ", accountNumber))
 	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].AccountNumber == nil || matches[j].AccountNumber == nil {
+			return matches[j].AccountNumber == nil && matches[i].AccountNumber != nil
+		}
+		return *matches[i].AccountNumber < *matches[j].AccountNumber
+	})
 
-	if id == "" {
-		return fmt.Errorf("no connected account found with the account number : %q ", accountNumber)
+	ids := []string{}
+	accountList := []map[string]interface{}{}
+	for _, account := range matches {
+		accountMap, err := flattenConnectedAccount(apiClient, account, d.Get("include_linked_vpcs").(bool))
+		if err != nil {
+			return toDiagnostics(err)
+		}
+		ids = append(ids, account.Id)
+		accountList = append(accountList, accountMap)
 	}
 
-	d.SetId(id)
+	if len(accountNumber) > 0 {
+		d.SetId(matches[0].Id)
+	} else {
+		d.SetId(fmt.Sprintf("%s,%s", apiClient.OrgID(), providerType))
+	}
+	d.Set("ids", ids)
+	d.Set("accounts", accountList)
 	return nil
 }
+
+// flattenConnectedAccount converts a connected account to the map of its accounts block, looking up
+// the compatible VPCs of the active accounts in each of their regions, if includeLinkedVpcs is true.
+func flattenConnectedAccount(apiClient *api.Client, account model.AwsCustomerConnectedAccount,
+	includeLinkedVpcs bool) (map[string]interface{}, error) {
+	regions := make([]string, 0, len(account.RegionToAzToShadowMapping))
+	for region := range account.RegionToAzToShadowMapping {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+
+	linkedVpcIDs := []string{}
+	active := account.State != nil && *account.State == model.AwsCustomerConnectedAccount_STATE_ACTIVE
+	if includeLinkedVpcs && active {
+		vpcIDs := map[string]bool{}
+		for _, region := range regions {
+			region := region
+			forceRefresh := false
+			compatibleSubnets, err := apiClient.CompatibleSubnets().Get(apiClient.OrgID(), account.Id, &region,
+				nil, &forceRefresh, nil, nil, nil)
+			if err != nil {
+				return nil, HandleDataSourceReadError(
+					fmt.Sprintf("VPCs of connected account %s in region %s", account.Id, region), err)
+			}
+			for vpcID := range compatibleSubnets.VpcMap {
+				vpcIDs[vpcID] = true
+			}
+		}
+		for vpcID := range vpcIDs {
+			linkedVpcIDs = append(linkedVpcIDs, vpcID)
+		}
+		sort.Strings(linkedVpcIDs)
+	}
+
+	accountMap := map[string]interface{}{
+		"id":             account.Id,
+		"regions":        regions,
+		"linked_vpc_ids": linkedVpcIDs,
+	}
+	for key, value := range map[string]*string{
+		"account_number": account.AccountNumber,
+		"state":          account.State,
+		"cf_stack_name":  account.CfStackName,
+	} {
+		if value != nil {
+			accountMap[key] = *value
+		}
+	}
+	return accountMap, nil
+}
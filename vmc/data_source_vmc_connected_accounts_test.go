@@ -4,12 +4,16 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
 	"os"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
 )
 
 func TestAccDataSourceVmcConnectedAccountsBasic(t *testing.T) {
@@ -36,3 +40,44 @@ data "vmc_connected_accounts" "my_accounts" {
 		os.Getenv(constants.AwsAccountNumber),
 	)
 }
+
+func TestDataSourceVmcConnectedAccountsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.AddCustomerSubnet("vpc-1", "subnet-1a", "10.1.0.0/24", "us-west-2a", "usw2-az1")
+	server.AddCustomerSubnet("vpc-2", "subnet-2a", "10.2.0.0/24", "eu-central-1a", "euc1-az2")
+	server.AddCustomerSubnet("vpc-3", "subnet-3a", "10.3.0.0/24", "ap-south-1a", "aps1-az1")
+	activeID := server.AddConnectedAccount("222222222222", model.AwsCustomerConnectedAccount_STATE_ACTIVE,
+		"us-west-2", "eu-central-1")
+	brokenID := server.AddConnectedAccount("111111111111", model.AwsCustomerConnectedAccount_STATE_BROKEN, "us-west-2")
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcConnectedAccounts().Schema, map[string]interface{}{
+		"account_number": "222222222222",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcConnectedAccountsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, activeID, d.Id())
+	assert.Equal(t, []interface{}{activeID}, d.Get("ids"))
+	assert.Equal(t, "vmware-sddc-formation-222222222222", d.Get("accounts.0.cf_stack_name"))
+	assert.Equal(t, []interface{}{"eu-central-1", "us-west-2"}, d.Get("accounts.0.regions"))
+	assert.Empty(t, d.Get("accounts.0.linked_vpc_ids"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcConnectedAccounts().Schema, map[string]interface{}{
+		"include_linked_vpcs": true,
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcConnectedAccountsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, []interface{}{brokenID, activeID}, d.Get("ids"))
+	assert.Equal(t, "BROKEN", d.Get("accounts.0.state"))
+	assert.Empty(t, d.Get("accounts.0.linked_vpc_ids"))
+	assert.Equal(t, []interface{}{"vpc-1", "vpc-2"}, d.Get("accounts.1.linked_vpc_ids"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcConnectedAccounts().Schema, map[string]interface{}{
+		"state": "ACTIVE",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcConnectedAccountsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, []interface{}{activeID}, d.Get("ids"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcConnectedAccounts().Schema, map[string]interface{}{
+		"account_number": "333333333333",
+	})
+	assert.ErrorContains(t, diagsErr(dataSourceVmcConnectedAccountsRead(context.Background(), d, connectorWrapper)),
+		"no connected account found")
+}
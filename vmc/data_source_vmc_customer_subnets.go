@@ -4,19 +4,24 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
 	"log"
+	"net"
+	"sort"
 	"strings"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/account_link"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
 )
 
 func dataSourceVmcCustomerSubnets() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVmcCustomerSubnetsRead,
+		ReadContext: dataSourceVmcCustomerSubnetsRead,
 
 		Schema: map[string]*schema.Schema{
 			"connected_account_id": {
@@ -70,17 +75,77 @@ func dataSourceVmcCustomerSubnets() *schema.Resource {
 				Type:     schema.TypeMap,
 				Computed: true,
 			},
+			"availability_zone": {
+				Type:        schema.TypeString,
+				Description: "Only return the subnets in this AWS availability zone, specified either by name (e.g. us-west-2a) or by ID (e.g. usw2-az1).",
+				Optional:    true,
+			},
+			"vpc_id": {
+				Type:        schema.TypeString,
+				Description: "Only return the subnets of this AWS VPC.",
+				Optional:    true,
+			},
 			"ids": {
 				Type:        schema.TypeList,
 				Description: "A list of AWS subnet IDs to create links to in the customer's account.",
 				Computed:    true,
 				Elem:        &schema.Schema{Type: schema.TypeString},
 			},
+			"subnets": {
+				Type:        schema.TypeList,
+				Description: "The matching AWS subnets, in the same order as ids.",
+				Computed:    true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"subnet_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"cidr_block": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"availability_zone": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"availability_zone_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vpc_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vpc_cidr_block": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"compatible": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"note": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"usable_ip_count": {
+							Type:        schema.TypeInt,
+							Description: "The number of IP addresses of the subnet, that are not reserved by AWS.",
+							Computed:    true,
+						},
+					},
+				},
+			},
 		},
 	}
 }
 
-func dataSourceVmcCustomerSubnetsRead(d *schema.ResourceData, m interface{}) error {
+func dataSourceVmcCustomerSubnetsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	orgID := m.(*connector.Wrapper).OrgID
 	accountID := d.Get("connected_account_id").(string)
 	sddcID := d.Get("sddc_id").(string)
@@ -109,23 +174,96 @@ func dataSourceVmcCustomerSubnetsRead(d *schema.ResourceData, m interface{}) err
 
 	forceRefresh := d.Get("force_refresh").(bool)
 
-	connectorWrapper := (m.(*connector.Wrapper)).Connector
-	compatibleSubnetsClient := account_link.NewCompatibleSubnetsClient(connectorWrapper)
+	compatibleSubnetsClient := api.NewClient(m.(*connector.Wrapper)).CompatibleSubnets()
 	compatibleSubnets, err := compatibleSubnetsClient.Get(orgID, accountID, &region, &sddcID, &forceRefresh, instanceType, sddcType, &numHosts)
-	ids := []string{}
-	for _, value := range compatibleSubnets.VpcMap {
-		for _, subnet := range value.Subnets {
-			ids = append(ids, *subnet.SubnetId)
-		}
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Customer Subnets", err))
 	}
-	log.Printf("[DEBUG] Subnet IDs are %v\n", ids)
 
-	if err != nil {
-		return HandleDataSourceReadError("Customer Subnets", err)
+	availabilityZone := d.Get("availability_zone").(string)
+	vpcID := d.Get("vpc_id").(string)
+	subnets := []map[string]interface{}{}
+	for mapVpcID, vpc := range compatibleSubnets.VpcMap {
+		for _, subnet := range vpc.Subnets {
+			if subnet.SubnetId == nil {
+				continue
+			}
+			subnetMap := flattenCustomerSubnet(subnet)
+			// The subnets do not necessarily repeat the ID of their VPC
+			if subnet.VpcId == nil {
+				subnetMap["vpc_id"] = mapVpcID
+			}
+			if matchesCustomerSubnetFilters(subnetMap, availabilityZone, vpcID) {
+				subnets = append(subnets, subnetMap)
+			}
+		}
 	}
+	// The VPC map has no order, sort the subnets by VPC so that the order of ids is stable.
+	// The subnets of a VPC keep the order they are returned in.
+	sort.SliceStable(subnets, func(i, j int) bool {
+		return subnets[i]["vpc_id"].(string) < subnets[j]["vpc_id"].(string)
+	})
+	ids := []string{}
+	for _, subnetMap := range subnets {
+		ids = append(ids, subnetMap["subnet_id"].(string))
+	}
+	log.Printf("[DEBUG] Subnet IDs are %v\n", ids)
 
 	d.Set("ids", ids)
+	d.Set("subnets", subnets)
 	d.Set("customer_available_zones", compatibleSubnets.CustomerAvailableZones)
 	d.SetId(fmt.Sprintf("%s-%s", orgID, accountID))
 	return nil
 }
+
+// flattenCustomerSubnet converts a compatible subnet into the schema format of the subnets attribute.
+func flattenCustomerSubnet(subnet model.SubnetInfo) map[string]interface{} {
+	subnetMap := map[string]interface{}{}
+	for key, value := range map[string]*string{
+		"subnet_id":            subnet.SubnetId,
+		"name":                 subnet.Name,
+		"cidr_block":           subnet.SubnetCidrBlock,
+		"availability_zone":    subnet.AvailabilityZone,
+		"availability_zone_id": subnet.AvailabilityZoneId,
+		"vpc_id":               subnet.VpcId,
+		"vpc_cidr_block":       subnet.VpcCidrBlock,
+		"note":                 subnet.Note,
+	} {
+		if value != nil {
+			subnetMap[key] = *value
+		}
+	}
+	if subnet.Compatible != nil {
+		subnetMap["compatible"] = *subnet.Compatible
+	}
+	if subnet.SubnetCidrBlock != nil {
+		subnetMap["usable_ip_count"] = usableSubnetIPCount(*subnet.SubnetCidrBlock)
+	}
+	return subnetMap
+}
+
+// matchesCustomerSubnetFilters checks whether a flattened subnet is in the specified availability
+// zone, matched either by name or ID, and VPC. Empty filters match all subnets.
+func matchesCustomerSubnetFilters(subnetMap map[string]interface{}, availabilityZone string, vpcID string) bool {
+	if len(availabilityZone) > 0 &&
+		subnetMap["availability_zone"] != availabilityZone &&
+		subnetMap["availability_zone_id"] != availabilityZone {
+		return false
+	}
+	return len(vpcID) == 0 || subnetMap["vpc_id"] == vpcID
+}
+
+// usableSubnetIPCount returns the number of IP addresses of an AWS subnet, that can be assigned,
+// as AWS reserves the first four and the last IP address of every subnet. It returns 0 if the
+// CIDR block is not a valid IPv4 one.
+func usableSubnetIPCount(cidrBlock string) int {
+	_, network, err := net.ParseCIDR(cidrBlock)
+	if err != nil {
+		return 0
+	}
+	ones, bits := network.Mask.Size()
+	if bits != 32 || bits-ones < 3 {
+		return 0
+	}
+	return (1 << (bits - ones)) - 5
+}
@@ -4,8 +4,11 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
 	"os"
 	"testing"
@@ -86,3 +89,53 @@ data "vmc_customer_subnets" "my_subnets" {
 `,
 		os.Getenv(constants.AwsAccountNumber))
 }
+
+func TestDataSourceVmcCustomerSubnetsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.AddCustomerSubnet("vpc-2", "subnet-2b", "10.2.1.0/24", "us-west-2b", "usw2-az2")
+	server.AddCustomerSubnet("vpc-1", "subnet-1b", "10.1.1.0/28", "us-west-2b", "usw2-az2")
+	server.AddCustomerSubnet("vpc-1", "subnet-1a", "10.1.0.0/24", "us-west-2a", "usw2-az1")
+
+	testCases := []struct {
+		filters     map[string]interface{}
+		expectedIDs []string
+	}{
+		{filters: map[string]interface{}{}, expectedIDs: []string{"subnet-1b", "subnet-1a", "subnet-2b"}},
+		{filters: map[string]interface{}{"availability_zone": "us-west-2b"}, expectedIDs: []string{"subnet-1b", "subnet-2b"}},
+		{filters: map[string]interface{}{"availability_zone": "usw2-az1"}, expectedIDs: []string{"subnet-1a"}},
+		{filters: map[string]interface{}{"vpc_id": "vpc-2"}, expectedIDs: []string{"subnet-2b"}},
+		{filters: map[string]interface{}{"vpc_id": "vpc-2", "availability_zone": "usw2-az1"}, expectedIDs: []string{}},
+	}
+	for _, testCase := range testCases {
+		testCase.filters["region"] = "US_WEST_2"
+		d := schema.TestResourceDataRaw(t, dataSourceVmcCustomerSubnets().Schema, testCase.filters)
+		assert.NoError(t, diagsErr(dataSourceVmcCustomerSubnetsRead(context.Background(), d, connectorWrapper)))
+		ids := []string{}
+		for _, id := range d.Get("ids").([]interface{}) {
+			ids = append(ids, id.(string))
+		}
+		assert.Equal(t, testCase.expectedIDs, ids, "filters: %v", testCase.filters)
+		assert.Len(t, d.Get("subnets").([]interface{}), len(testCase.expectedIDs))
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcCustomerSubnets().Schema, map[string]interface{}{
+		"region":            "US_WEST_2",
+		"availability_zone": "usw2-az1",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcCustomerSubnetsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "subnet-1a", d.Get("subnets.0.subnet_id"))
+	assert.Equal(t, "10.1.0.0/24", d.Get("subnets.0.cidr_block"))
+	assert.Equal(t, "us-west-2a", d.Get("subnets.0.availability_zone"))
+	assert.Equal(t, "usw2-az1", d.Get("subnets.0.availability_zone_id"))
+	assert.Equal(t, "vpc-1", d.Get("subnets.0.vpc_id"))
+	assert.Equal(t, true, d.Get("subnets.0.compatible"))
+	assert.Equal(t, 251, d.Get("subnets.0.usable_ip_count"))
+	assert.ElementsMatch(t, []interface{}{"us-west-2a", "us-west-2b"}, d.Get("customer_available_zones"))
+}
+
+func TestUsableSubnetIPCount(t *testing.T) {
+	assert.Equal(t, 251, usableSubnetIPCount("10.0.0.0/24"))
+	assert.Equal(t, 11, usableSubnetIPCount("10.0.0.0/28"))
+	assert.Equal(t, 0, usableSubnetIPCount("2001:db8::/64"))
+	assert.Equal(t, 0, usableSubnetIPCount("invalid"))
+}
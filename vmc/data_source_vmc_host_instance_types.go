@@ -0,0 +1,198 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func dataSourceVmcHostInstanceTypes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcHostInstanceTypesRead,
+
+		Schema: map[string]*schema.Schema{
+			"region": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "The region to list the host instance types of, e.g. US_WEST_2 or us-west-2.",
+			},
+			"provider_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     constants.AwsProviderType,
+				Description: "The cloud provider to list the host instance types of (AWS or ZEROCLOUD). Default: AWS.",
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.AwsProviderType, constants.ZeroCloudProviderType}, false),
+			},
+			"sddc_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     constants.DefaultSddcType,
+				Description: "The type of the SDDC to list the host instance types of (DEFAULT or 1NODE). Default: DEFAULT.",
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.DefaultSddcType, constants.OneNodeSddcType}, false),
+			},
+			"host_instance_types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The host instance types, that can be provisioned in the region, in the format of the host_instance_type argument.",
+			},
+			"instance_types": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "All host instance types offered in the region, including the ones, that cannot be provisioned.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host_instance_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"description": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_counts": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"cpu_cores": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeInt},
+						},
+						"hyper_threading_supported": {
+							Type:     schema.TypeBool,
+							Computed: true,
+						},
+						"storage_capacity_gib": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"memory_capacity_gib": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"total_number_of_cores": {
+							Type:     schema.TypeInt,
+							Computed: true,
+						},
+						"provisioning_error": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVmcHostInstanceTypesRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	provisionSpec, err := apiClient.ProvisionSpec().Get(apiClient.OrgID())
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Host instance types", err))
+	}
+	providerType := d.Get("provider_type").(string)
+	sddcType := d.Get("sddc_type").(string)
+	region := strings.ReplaceAll(strings.ToUpper(d.Get("region").(string)), "-", "_")
+
+	configSpec, ok := provisionSpec.Provider[providerType].SddcTypeConfigSpec[sddcType]
+	if !ok {
+		return toDiagnostics(fmt.Errorf("no %s SDDCs of type %s are offered to organization %s",
+			providerType, sddcType, apiClient.OrgID()))
+	}
+	instanceTypeConfigs, ok := configSpec.Availability[region]
+	if !ok {
+		regions := make([]string, 0, len(configSpec.Availability))
+		for availableRegion := range configSpec.Availability {
+			regions = append(regions, availableRegion)
+		}
+		sort.Strings(regions)
+		return toDiagnostics(newAttributeError("region", "no host instance types are offered in region %s, "+
+			"the available regions are %v", region, regions))
+	}
+
+	hostInstanceTypes := []string{}
+	instanceTypes := []map[string]interface{}{}
+	for _, instanceTypeConfig := range instanceTypeConfigs {
+		instanceTypeMap := flattenInstanceTypeConfig(instanceTypeConfig)
+		if len(instanceTypeMap["provisioning_error"].(string)) == 0 {
+			hostInstanceTypes = append(hostInstanceTypes, instanceTypeMap["host_instance_type"].(string))
+		}
+		instanceTypes = append(instanceTypes, instanceTypeMap)
+	}
+
+	d.SetId(strings.Join([]string{apiClient.OrgID(), providerType, sddcType, region}, ","))
+	d.Set("host_instance_types", hostInstanceTypes)
+	d.Set("instance_types", instanceTypes)
+	return nil
+}
+
+// flattenInstanceTypeConfig converts the attributes of a host instance type, that the
+// vmc_host_instance_types data source exports, into their schema format.
+func flattenInstanceTypeConfig(instanceTypeConfig model.InstanceTypeConfig) map[string]interface{} {
+	instanceTypeMap := map[string]interface{}{
+		"host_counts":        toIntList(instanceTypeConfig.Hosts),
+		"cpu_cores":          toIntList(instanceTypeConfig.CpuCores),
+		"provisioning_error": "",
+	}
+	if instanceTypeConfig.InstanceType != nil {
+		instanceTypeMap["name"] = *instanceTypeConfig.InstanceType
+		instanceTypeMap["host_instance_type"] = fromHostInstanceType(*instanceTypeConfig.InstanceType)
+	}
+	if instanceTypeConfig.DisplayName != nil {
+		instanceTypeMap["display_name"] = *instanceTypeConfig.DisplayName
+	}
+	if instanceTypeConfig.Description != nil {
+		instanceTypeMap["description"] = *instanceTypeConfig.Description
+	}
+	if instanceTypeConfig.HyperThreadingSupported != nil {
+		instanceTypeMap["hyper_threading_supported"] = *instanceTypeConfig.HyperThreadingSupported
+	}
+	if instanceTypeConfig.InstanceProvisioningErrorCause != nil {
+		instanceTypeMap["provisioning_error"] = *instanceTypeConfig.InstanceProvisioningErrorCause
+	}
+	if capacity := instanceTypeConfig.EntityCapacity; capacity != nil {
+		if capacity.StorageCapacityGib != nil {
+			instanceTypeMap["storage_capacity_gib"] = int(*capacity.StorageCapacityGib)
+		}
+		if capacity.MemoryCapacityGib != nil {
+			instanceTypeMap["memory_capacity_gib"] = int(*capacity.MemoryCapacityGib)
+		}
+		if capacity.TotalNumberOfCores != nil {
+			instanceTypeMap["total_number_of_cores"] = int(*capacity.TotalNumberOfCores)
+		}
+	}
+	return instanceTypeMap
+}
+
+func toIntList(values []int64) []int {
+	ints := make([]int, 0, len(values))
+	for _, value := range values {
+		ints = append(ints, int(value))
+	}
+	return ints
+}
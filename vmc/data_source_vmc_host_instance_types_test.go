@@ -0,0 +1,51 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestDataSourceVmcHostInstanceTypesSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcHostInstanceTypes().Schema, map[string]interface{}{
+		"region": "us-west-2",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcHostInstanceTypesRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, []interface{}{"I3EN_METAL", "I4I_METAL"}, d.Get("host_instance_types"))
+	assert.Len(t, d.Get("instance_types").([]interface{}), 3)
+	assert.Equal(t, "i3en.metal", d.Get("instance_types.0.name"))
+	assert.Equal(t, 768, d.Get("instance_types.0.memory_capacity_gib"))
+	assert.Equal(t, 96, d.Get("instance_types.0.cpu_cores.0"))
+	assert.Empty(t, d.Get("instance_types.0.provisioning_error"))
+	assert.Equal(t, "I3_METAL", d.Get("instance_types.2.host_instance_type"))
+	assert.NotEmpty(t, d.Get("instance_types.2.provisioning_error"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcHostInstanceTypes().Schema, map[string]interface{}{
+		"region":    "US_WEST_2",
+		"sddc_type": "1NODE",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcHostInstanceTypesRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, []interface{}{"I3EN_METAL"}, d.Get("host_instance_types"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcHostInstanceTypes().Schema, map[string]interface{}{
+		"region": "ap-south-1",
+	})
+	err := diagsErr(dataSourceVmcHostInstanceTypesRead(context.Background(), d, connectorWrapper))
+	assert.ErrorContains(t, err, "AP_SOUTH_1")
+	assert.ErrorContains(t, err, "[EU_CENTRAL_1 US_WEST_2]")
+
+	server.InjectError(http.MethodGet, "/vmc/api/orgs/"+simulator.TestOrgID+"/sddcs/provision-spec", http.StatusInternalServerError)
+	d = schema.TestResourceDataRaw(t, dataSourceVmcHostInstanceTypes().Schema, map[string]interface{}{
+		"region": "us-west-2",
+	})
+	assert.Error(t, diagsErr(dataSourceVmcHostInstanceTypesRead(context.Background(), d, connectorWrapper)))
+}
@@ -0,0 +1,44 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+)
+
+func dataSourceVmcIntranetMtu() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcIntranetMtuRead,
+
+		Schema: intranetMtuSchema(map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "SDDC identifier",
+			},
+			"mtu": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "Uplink MTU of the direct connect, SDDC grouping and outposts traffic of the SDDC.",
+			},
+		}),
+	}
+}
+
+func dataSourceVmcIntranetMtuRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Get("sddc_id").(string)
+	nsxClient, err := getIntranetMtuNsxClient(m.(*connector.Wrapper), sddcID)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Intranet MTU uplink", err))
+	}
+	err = setIntranetMtuAttributes(d, nsxClient)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Intranet MTU uplink", err))
+	}
+	d.SetId(sddcID)
+	return nil
+}
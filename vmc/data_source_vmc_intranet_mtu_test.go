@@ -0,0 +1,33 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestDataSourceVmcIntranetMtuWithoutDirectConnectSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc"})
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcIntranetMtu().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcIntranetMtuRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, constants.MinIntranetMtuLink, d.Get("mtu"))
+	assert.Equal(t, "", d.Get("bgp_asn"))
+	assert.Empty(t, d.Get("advertised_prefixes"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcIntranetMtu().Schema, map[string]interface{}{
+		"sddc_id": "missing-sddc",
+	})
+	assert.Error(t, diagsErr(dataSourceVmcIntranetMtuRead(context.Background(), d, connectorWrapper)))
+}
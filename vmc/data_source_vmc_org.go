@@ -1,31 +1,56 @@
-/* Copyright 2019-2022 VMware, Inc.
+/* Copyright 2019-2023 VMware, Inc.
    SPDX-License-Identifier: MPL-2.0 */
 
 package vmc
 
 import (
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
 )
 
-func dataSourceVmcOrg() *schema.Resource {
-	return &schema.Resource{
-		Read: dataSourceVmcOrgRead,
+// orgDataSource the vmc_org data source, the first one served by the framework provider.
+type orgDataSource struct {
+	connectorWrapper *connector.Wrapper
+}
+
+type orgDataSourceModel struct {
+	ID          types.String `tfsdk:"id"`
+	OrgID       types.String `tfsdk:"org_id"`
+	DisplayName types.String `tfsdk:"display_name"`
+	Name        types.String `tfsdk:"name"`
+}
+
+func newOrgDataSource() datasource.DataSource {
+	return &orgDataSource{}
+}
+
+func (o *orgDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_org"
+}
 
-		Schema: map[string]*schema.Schema{
-			"id": {
-				Type:        schema.TypeString,
+func (o *orgDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
 				Description: "Organization identifier.",
 				Computed:    true,
 			},
-			"display_name": {
-				Type:        schema.TypeString,
+			"org_id": schema.StringAttribute{
+				Description: "ID of the organization to read. Defaults to the organization the provider is configured with.",
+				Optional:    true,
+				Computed:    true,
+			},
+			"display_name": schema.StringAttribute{
 				Description: "The display name of this resource",
 				Computed:    true,
 			},
-			"name": {
-				Type:        schema.TypeString,
+			"name": schema.StringAttribute{
 				Description: "The Name of this resource",
 				Computed:    true,
 			},
@@ -33,17 +58,50 @@ func dataSourceVmcOrg() *schema.Resource {
 	}
 }
 
-func dataSourceVmcOrgRead(d *schema.ResourceData, m interface{}) error {
-	orgID := (m.(*connector.Wrapper)).OrgID
-	connectorWrapper := (m.(*connector.Wrapper)).Connector
-	orgClient := vmc.NewOrgsClient(connectorWrapper)
+func (o *orgDataSource) Configure(_ context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	// The provider is not configured yet during validation
+	if req.ProviderData == nil {
+		return
+	}
+	connectorWrapper, ok := req.ProviderData.(*connector.Wrapper)
+	if !ok {
+		resp.Diagnostics.AddError("Unexpected provider data",
+			fmt.Sprintf("expected *connector.Wrapper, got %T", req.ProviderData))
+		return
+	}
+	o.connectorWrapper = connectorWrapper
+}
+
+func (o *orgDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	orgID := o.connectorWrapper.OrgID
+	if !req.Config.Raw.IsNull() {
+		var config orgDataSourceModel
+		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if len(config.OrgID.ValueString()) > 0 {
+			orgID = config.OrgID.ValueString()
+		}
+	}
+	orgClient := api.NewClient(o.connectorWrapper.WithLogContext(ctx)).Orgs()
 	org, err := orgClient.Get(orgID)
 	if err != nil {
-		return HandleDataSourceReadError("VMC Organization", err)
+		resp.Diagnostics.AddError("Failed to read VMC Organization",
+			HandleDataSourceReadError("VMC Organization", err).Error())
+		return
 	}
-	d.SetId(orgID)
-	d.Set("display_name", org.DisplayName)
-	d.Set("name", org.Name)
-
-	return nil
+	state := orgDataSourceModel{
+		ID:          types.StringValue(orgID),
+		OrgID:       types.StringValue(orgID),
+		DisplayName: types.StringNull(),
+		Name:        types.StringNull(),
+	}
+	if org.DisplayName != nil {
+		state.DisplayName = types.StringValue(*org.DisplayName)
+	}
+	if org.Name != nil {
+		state.Name = types.StringValue(*org.Name)
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, &state)...)
 }
@@ -1,20 +1,26 @@
-/* Copyright 2019-2022 VMware, Inc.
+/* Copyright 2019-2023 VMware, Inc.
    SPDX-License-Identifier: MPL-2.0 */
 
 package vmc
 
 import (
+	"context"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
 	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestAccDataSourceVmcOrgBasic(t *testing.T) {
 	resource.Test(t, resource.TestCase{
-		PreCheck:  func() { testAccPreCheckZerocloud(t) },
-		Providers: testAccProviders,
+		PreCheck:                 func() { testAccPreCheckZerocloud(t) },
+		ProtoV5ProviderFactories: testAccProtoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
 				Config: testAccDataSourceVmcOrgConfig(),
@@ -29,3 +35,43 @@ func TestAccDataSourceVmcOrgBasic(t *testing.T) {
 func testAccDataSourceVmcOrgConfig() string {
 	return `data "vmc_org" "my_org" {}`
 }
+
+func TestDataSourceVmcOrgSimulator(t *testing.T) {
+	ctx := context.Background()
+	server, connectorWrapper := newTestSimulator(t)
+	orgDataSource := newOrgDataSource().(*orgDataSource)
+	configureResp := &datasource.ConfigureResponse{}
+	orgDataSource.Configure(ctx, datasource.ConfigureRequest{ProviderData: connectorWrapper}, configureResp)
+	assert.False(t, configureResp.Diagnostics.HasError())
+
+	schemaResp := &datasource.SchemaResponse{}
+	orgDataSource.Schema(ctx, datasource.SchemaRequest{}, schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx)
+	readResp := &datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	orgDataSource.Read(ctx, datasource.ReadRequest{}, readResp)
+	assert.False(t, readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+
+	var state orgDataSourceModel
+	readResp.State.Get(ctx, &state)
+	assert.Equal(t, simulator.TestOrgID, state.ID.ValueString())
+	assert.NotEmpty(t, state.DisplayName.ValueString())
+
+	// Another organization, that the token has access to, is read when specified
+	drOrgID := server.AddOrg("Disaster recovery", "dr-org", nil)
+	config := tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, map[string]tftypes.Value{
+		"id":           tftypes.NewValue(tftypes.String, nil),
+		"org_id":       tftypes.NewValue(tftypes.String, drOrgID),
+		"display_name": tftypes.NewValue(tftypes.String, nil),
+		"name":         tftypes.NewValue(tftypes.String, nil),
+	})}
+	readResp = &datasource.ReadResponse{
+		State: tfsdk.State{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, nil)},
+	}
+	orgDataSource.Read(ctx, datasource.ReadRequest{Config: config}, readResp)
+	assert.False(t, readResp.Diagnostics.HasError(), "%v", readResp.Diagnostics)
+	readResp.State.Get(ctx, &state)
+	assert.Equal(t, drOrgID, state.ID.ValueString())
+	assert.Equal(t, "Disaster recovery", state.DisplayName.ValueString())
+}
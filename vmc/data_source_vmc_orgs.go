@@ -0,0 +1,128 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func dataSourceVmcOrgs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcOrgsRead,
+
+		Schema: map[string]*schema.Schema{
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the organizations with this display name.",
+			},
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the organizations with this name.",
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the matching organizations.",
+			},
+			"orgs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching organizations.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"tags": {
+							Type:     schema.TypeMap,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVmcOrgsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	orgList, err := apiClient.Orgs().List()
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Organizations", err))
+	}
+
+	filters := map[string]string{}
+	for _, key := range []string{"display_name", "name"} {
+		if value, ok := d.GetOk(key); ok {
+			filters[key] = value.(string)
+		}
+	}
+	ids := []string{}
+	orgs := []map[string]interface{}{}
+	for _, org := range orgList {
+		orgMap := flattenOrgSummary(org)
+		if !matchesOrgFilters(orgMap, filters) {
+			continue
+		}
+		ids = append(ids, org.Id)
+		orgs = append(orgs, orgMap)
+	}
+
+	d.SetId(apiClient.OrgID())
+	d.Set("ids", ids)
+	d.Set("orgs", orgs)
+	return nil
+}
+
+// flattenOrgSummary converts the attributes of an organization, that the vmc_orgs data source
+// exports, into their schema format. The properties of the organization are exported as its tags.
+func flattenOrgSummary(org model.Organization) map[string]interface{} {
+	orgMap := map[string]interface{}{
+		"id": org.Id,
+	}
+	if org.DisplayName != nil {
+		orgMap["display_name"] = *org.DisplayName
+	}
+	if org.Name != nil {
+		orgMap["name"] = *org.Name
+	}
+	tags := map[string]interface{}{}
+	if org.Properties != nil {
+		for key, value := range org.Properties.Values {
+			tags[key] = value
+		}
+	}
+	orgMap["tags"] = tags
+	return orgMap
+}
+
+// matchesOrgFilters checks whether a flattened organization matches all the provided filters.
+func matchesOrgFilters(orgMap map[string]interface{}, filters map[string]string) bool {
+	for key, expected := range filters {
+		actual, _ := orgMap[key].(string)
+		if actual != expected {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,51 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestDataSourceVmcOrgsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	productionID := server.AddOrg("Production", "production-org", map[string]string{"environment": "production"})
+	stagingID := server.AddOrg("Staging", "staging-org", nil)
+
+	testCases := []struct {
+		filters     map[string]interface{}
+		expectedIDs []string
+	}{
+		{filters: map[string]interface{}{}, expectedIDs: []string{simulator.TestOrgID, productionID, stagingID}},
+		{filters: map[string]interface{}{"display_name": "Production"}, expectedIDs: []string{productionID}},
+		{filters: map[string]interface{}{"name": "staging-org"}, expectedIDs: []string{stagingID}},
+		{filters: map[string]interface{}{"display_name": "Staging", "name": "production-org"}, expectedIDs: []string{}},
+		{filters: map[string]interface{}{"display_name": "Unknown"}, expectedIDs: []string{}},
+	}
+	for _, testCase := range testCases {
+		d := schema.TestResourceDataRaw(t, dataSourceVmcOrgs().Schema, testCase.filters)
+		assert.NoError(t, diagsErr(dataSourceVmcOrgsRead(context.Background(), d, connectorWrapper)))
+		ids := []string{}
+		for _, id := range d.Get("ids").([]interface{}) {
+			ids = append(ids, id.(string))
+		}
+		assert.ElementsMatch(t, testCase.expectedIDs, ids, "filters: %v", testCase.filters)
+		assert.Len(t, d.Get("orgs").([]interface{}), len(testCase.expectedIDs))
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcOrgs().Schema, map[string]interface{}{"display_name": "Production"})
+	assert.NoError(t, diagsErr(dataSourceVmcOrgsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, productionID, d.Get("orgs.0.id"))
+	assert.Equal(t, "production-org", d.Get("orgs.0.name"))
+	assert.Equal(t, map[string]interface{}{"environment": "production"}, d.Get("orgs.0.tags"))
+
+	server.InjectError(http.MethodGet, "/vmc/api/orgs", http.StatusInternalServerError)
+	d = schema.TestResourceDataRaw(t, dataSourceVmcOrgs().Schema, map[string]interface{}{})
+	assert.Error(t, diagsErr(dataSourceVmcOrgsRead(context.Background(), d, connectorWrapper)))
+}
@@ -0,0 +1,106 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	nsxmodel "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
+)
+
+func dataSourceVmcPublicIP() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcPublicIPRead,
+
+		Schema: map[string]*schema.Schema{
+			"nsxt_reverse_proxy_url": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"nsxt_reverse_proxy_url", "sddc_id"},
+				Description:  "NSX API public endpoint url of the SDDC, the public IP is allocated for.",
+			},
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Identifier of the SDDC, the public IP is allocated for.",
+			},
+			"ip": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Computed:     true,
+				ExactlyOneOf: []string{"ip", "display_name"},
+				Description:  "The public IP address to look up.",
+			},
+			"display_name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The display name of the public IP to look up. It has to be unique among the public IPs of the SDDC.",
+			},
+		},
+	}
+}
+
+func dataSourceVmcPublicIPRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
+	if sddcID := d.Get("sddc_id").(string); len(sddcID) > 0 {
+		sddc, err := apiClient.Sddcs().Get(apiClient.OrgID(), sddcID)
+		if err != nil {
+			return toDiagnostics(HandleDataSourceReadError("SDDC", err))
+		}
+		if sddc.ResourceConfig == nil || sddc.ResourceConfig.NsxApiPublicEndpointUrl == nil {
+			return toDiagnostics(newAttributeError("sddc_id", "NSX reverse proxy URL of SDDC %s is not available", sddcID))
+		}
+		nsxtReverseProxyURL = *sddc.ResourceConfig.NsxApiPublicEndpointUrl
+	}
+	nsxClient, err := apiClient.ForNsx(nsxtReverseProxyURL)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("NSXT reverse proxy URL connector", err))
+	}
+	publicIPs, err := listPublicIPs(nsxClient)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Public IPs", err))
+	}
+
+	ip := d.Get("ip").(string)
+	displayName := d.Get("display_name").(string)
+	var matches []nsxmodel.PublicIp
+	for _, publicIP := range publicIPs {
+		if len(ip) > 0 && publicIP.Ip != nil && *publicIP.Ip == ip {
+			matches = append(matches, publicIP)
+		} else if len(ip) == 0 && publicIP.DisplayName != nil && *publicIP.DisplayName == displayName {
+			matches = append(matches, publicIP)
+		}
+	}
+	if len(matches) == 0 {
+		if len(ip) > 0 {
+			return toDiagnostics(newAttributeError("ip", "no public IP %s is allocated at %s", ip, nsxtReverseProxyURL))
+		}
+		return toDiagnostics(newAttributeError("display_name", "no public IP named %q is allocated at %s",
+			displayName, nsxtReverseProxyURL))
+	}
+	if len(matches) > 1 {
+		ips := make([]string, 0, len(matches))
+		for _, publicIP := range matches {
+			if publicIP.Ip != nil {
+				ips = append(ips, *publicIP.Ip)
+			}
+		}
+		return toDiagnostics(newAttributeError("display_name", "%d public IPs named %q are allocated at %s: %v, "+
+			"look up the public IP by its ip instead", len(matches), displayName, nsxtReverseProxyURL, ips))
+	}
+
+	publicIP := matches[0]
+	d.SetId(*publicIP.Id)
+	d.Set("nsxt_reverse_proxy_url", nsxtReverseProxyURL)
+	d.Set("ip", publicIP.Ip)
+	d.Set("display_name", publicIP.DisplayName)
+	return nil
+}
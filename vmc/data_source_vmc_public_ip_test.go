@@ -0,0 +1,61 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestDataSourceVmcPublicIPSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "public_ip_sddc"})
+	nsxtReverseProxyURL := server.NsxtReverseProxyURL(sddcID)
+	publicIPs := map[string]*schema.ResourceData{}
+	for _, displayName := range []string{"web", "app", "app"} {
+		publicIP := schema.TestResourceDataRaw(t, resourcePublicIP().Schema, map[string]interface{}{
+			"nsxt_reverse_proxy_url": nsxtReverseProxyURL,
+			"display_name":           displayName,
+		})
+		assert.NoError(t, diagsErr(resourcePublicIPCreate(context.Background(), publicIP, connectorWrapper)))
+		publicIPs[displayName] = publicIP
+	}
+
+	// By display name, on the SDDC
+	d := schema.TestResourceDataRaw(t, dataSourceVmcPublicIP().Schema, map[string]interface{}{
+		"sddc_id":      sddcID,
+		"display_name": "web",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcPublicIPRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, publicIPs["web"].Id(), d.Id())
+	assert.Equal(t, publicIPs["web"].Get("ip"), d.Get("ip"))
+	assert.Equal(t, nsxtReverseProxyURL, d.Get("nsxt_reverse_proxy_url"))
+
+	// By IP address, on the NSX manager
+	d = schema.TestResourceDataRaw(t, dataSourceVmcPublicIP().Schema, map[string]interface{}{
+		"nsxt_reverse_proxy_url": nsxtReverseProxyURL,
+		"ip":                     publicIPs["app"].Get("ip"),
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcPublicIPRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, publicIPs["app"].Id(), d.Id())
+	assert.Equal(t, "app", d.Get("display_name"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcPublicIP().Schema, map[string]interface{}{
+		"nsxt_reverse_proxy_url": nsxtReverseProxyURL,
+		"display_name":           "app",
+	})
+	assert.ErrorContains(t, diagsErr(dataSourceVmcPublicIPRead(context.Background(), d, connectorWrapper)),
+		"2 public IPs named \"app\"")
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcPublicIP().Schema, map[string]interface{}{
+		"nsxt_reverse_proxy_url": nsxtReverseProxyURL,
+		"ip":                     "203.0.113.1",
+	})
+	assert.ErrorContains(t, diagsErr(dataSourceVmcPublicIPRead(context.Background(), d, connectorWrapper)),
+		"no public IP 203.0.113.1")
+}
@@ -0,0 +1,151 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func dataSourceVmcRegions() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcRegionsRead,
+
+		Schema: map[string]*schema.Schema{
+			"provider_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Default:     constants.AwsProviderType,
+				Description: "The cloud provider to list the regions of (AWS or ZEROCLOUD). Default: AWS.",
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.AwsProviderType, constants.ZeroCloudProviderType}, false),
+			},
+			"connected_account_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "The linked connected account identifier. When specified, the availability zones of the connected AWS account are listed for each region.",
+			},
+			"names": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The names of the regions SDDCs can be deployed in, in the format of the region argument, e.g. US_WEST_2.",
+			},
+			"regions": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The regions SDDCs can be deployed in, in the same order as names.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"aws_region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"sddc_types": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+						"availability_zones": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVmcRegionsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	provisionSpec, err := apiClient.ProvisionSpec().Get(apiClient.OrgID())
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Regions", err))
+	}
+	providerType := d.Get("provider_type").(string)
+	accountID := d.Get("connected_account_id").(string)
+
+	names := []string{}
+	regions := []map[string]interface{}{}
+	sddcConfigSpec := provisionSpec.Provider[providerType]
+	for _, region := range provisionedRegions(sddcConfigSpec) {
+		regionMap := map[string]interface{}{
+			"name":         region,
+			"aws_region":   toAwsRegion(region),
+			"display_name": sddcConfigSpec.RegionDisplayNames[region],
+			"sddc_types":   regionSddcTypes(sddcConfigSpec, region),
+		}
+		if len(accountID) > 0 {
+			forceRefresh := false
+			compatibleSubnets, err := apiClient.CompatibleSubnets().Get(apiClient.OrgID(), accountID, &region,
+				nil, &forceRefresh, nil, nil, nil)
+			if err != nil {
+				return toDiagnostics(HandleDataSourceReadError(fmt.Sprintf("Availability zones of region %s", region), err))
+			}
+			availabilityZones := append([]string{}, compatibleSubnets.CustomerAvailableZones...)
+			sort.Strings(availabilityZones)
+			regionMap["availability_zones"] = availabilityZones
+		}
+		names = append(names, region)
+		regions = append(regions, regionMap)
+	}
+
+	d.SetId(fmt.Sprintf("%s,%s", apiClient.OrgID(), providerType))
+	d.Set("names", names)
+	d.Set("regions", regions)
+	return nil
+}
+
+// provisionedRegions returns the sorted regions, in which SDDCs of any type can be provisioned.
+func provisionedRegions(sddcConfigSpec model.SddcConfigSpec) []string {
+	regionSet := map[string]bool{}
+	for _, configSpec := range sddcConfigSpec.SddcTypeConfigSpec {
+		for region := range configSpec.Availability {
+			regionSet[region] = true
+		}
+	}
+	regions := make([]string, 0, len(regionSet))
+	for region := range regionSet {
+		regions = append(regions, region)
+	}
+	sort.Strings(regions)
+	return regions
+}
+
+// regionSddcTypes returns the sorted types of the SDDCs, that can be provisioned in the region.
+func regionSddcTypes(sddcConfigSpec model.SddcConfigSpec, region string) []string {
+	sddcTypes := []string{}
+	for sddcType, configSpec := range sddcConfigSpec.SddcTypeConfigSpec {
+		if _, ok := configSpec.Availability[region]; ok {
+			sddcTypes = append(sddcTypes, sddcType)
+		}
+	}
+	sort.Strings(sddcTypes)
+	return sddcTypes
+}
+
+// toAwsRegion converts a region from the VMC API format to the AWS one, e.g. US_WEST_2 to us-west-2.
+func toAwsRegion(region string) string {
+	return strings.ReplaceAll(strings.ToLower(region), "_", "-")
+}
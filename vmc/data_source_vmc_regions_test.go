@@ -0,0 +1,49 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestDataSourceVmcRegionsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.AddCustomerSubnet("vpc-1", "subnet-1b", "10.1.1.0/24", "us-west-2b", "usw2-az2")
+	server.AddCustomerSubnet("vpc-1", "subnet-1a", "10.1.0.0/24", "us-west-2a", "usw2-az1")
+	server.AddCustomerSubnet("vpc-2", "subnet-2a", "10.2.0.0/24", "eu-central-1a", "euc1-az2")
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcRegions().Schema, map[string]interface{}{})
+	assert.NoError(t, diagsErr(dataSourceVmcRegionsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, []interface{}{"EU_CENTRAL_1", "US_WEST_2"}, d.Get("names"))
+	assert.Equal(t, "us-west-2", d.Get("regions.1.aws_region"))
+	assert.Equal(t, "US West (Oregon)", d.Get("regions.1.display_name"))
+	assert.Equal(t, []interface{}{"1NODE", "DEFAULT"}, d.Get("regions.1.sddc_types"))
+	assert.Equal(t, []interface{}{"DEFAULT"}, d.Get("regions.0.sddc_types"))
+	assert.Empty(t, d.Get("regions.1.availability_zones"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcRegions().Schema, map[string]interface{}{
+		"connected_account_id": "account-1",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcRegionsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, []interface{}{"eu-central-1a"}, d.Get("regions.0.availability_zones"))
+	assert.Equal(t, []interface{}{"us-west-2a", "us-west-2b"}, d.Get("regions.1.availability_zones"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcRegions().Schema, map[string]interface{}{
+		"provider_type": "ZEROCLOUD",
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcRegionsRead(context.Background(), d, connectorWrapper)))
+	assert.Empty(t, d.Get("names"))
+
+	server.InjectError(http.MethodGet, "/vmc/api/orgs/"+simulator.TestOrgID+"/account-link/compatible-subnets", http.StatusInternalServerError)
+	d = schema.TestResourceDataRaw(t, dataSourceVmcRegions().Schema, map[string]interface{}{
+		"connected_account_id": "account-1",
+	})
+	assert.Error(t, diagsErr(dataSourceVmcRegionsRead(context.Background(), d, connectorWrapper)))
+}
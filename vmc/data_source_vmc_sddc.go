@@ -4,11 +4,16 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/sddcs"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
 	"log"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs"
@@ -16,7 +21,10 @@ import (
 
 func dataSourceVmcSddc() *schema.Resource {
 	return &schema.Resource{
-		Read: dataSourceVmcSddcRead,
+		ReadContext: dataSourceVmcSddcRead,
+		Timeouts: &schema.ResourceTimeout{
+			Read: schema.DefaultTimeout(180 * time.Minute),
+		},
 
 		Schema: map[string]*schema.Schema{
 			"sddc_id": {
@@ -24,6 +32,12 @@ func dataSourceVmcSddc() *schema.Resource {
 				Description: "Sddc ID.",
 				Required:    true,
 			},
+			"wait_until_ready": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Wait for the SDDC to finish deploying, before reading it. The wait is bounded by the read timeout.",
+			},
 			"sddc_name": {
 				Type:     schema.TypeString,
 				Computed: true,
@@ -86,16 +100,18 @@ func dataSourceVmcSddc() *schema.Resource {
 				Computed: true,
 			},
 			"nsxt_cloudadmin_password": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
 			},
 			"nsxt_cloudaudit": {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
 			"nsxt_cloudaudit_password": {
-				Type:     schema.TypeString,
-				Computed: true,
+				Type:      schema.TypeString,
+				Computed:  true,
+				Sensitive: true,
 			},
 			"nsxt_private_ip": {
 				Type:     schema.TypeString,
@@ -105,6 +121,12 @@ func dataSourceVmcSddc() *schema.Resource {
 				Type:     schema.TypeString,
 				Computed: true,
 			},
+			"sddc_security": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The security profile of the SDDC, e.g. PROFILE_PCI_COMMERCIAL for PCI hardened SDDCs.",
+				Elem:        sddcSecurityElem(),
+			},
 			// Below are added as part of the schema as they are set in the
 			// dataSourceVmcSddcRead method
 			"updated": {
@@ -151,19 +173,25 @@ func dataSourceVmcSddc() *schema.Resource {
 	}
 }
 
-func dataSourceVmcSddcRead(d *schema.ResourceData, m interface{}) error {
-	connectorWrapper := (m.(*connector.Wrapper)).Connector
-	sddcClient := orgs.NewSddcsClient(connectorWrapper)
+func dataSourceVmcSddcRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	sddcClient := apiClient.Sddcs()
 	sddcID := d.Get("sddc_id").(string)
 	orgID := (m.(*connector.Wrapper)).OrgID
-	sddc, err := sddcClient.Get(orgID, sddcID)
+	var sddc model.Sddc
+	var err error
+	if d.Get("wait_until_ready").(bool) {
+		sddc, err = waitForSddcReady(ctx, sddcClient, orgID, sddcID, d.Timeout(schema.TimeoutRead))
+	} else {
+		sddc, err = sddcClient.Get(orgID, sddcID)
+	}
 	if err != nil {
 		if err.Error() == errors.NewNotFound().Error() {
 			log.Printf("SDDC with ID %s not found", sddcID)
 			d.SetId("")
 			return nil
 		}
-		return fmt.Errorf("Error while getting the SDDC with ID %s,%v", sddcID, err)
+		return diag.Errorf("Error while getting the SDDC with ID %s,%v", sddcID, err)
 	}
 
 	if *sddc.SddcState == "DELETED" {
@@ -198,10 +226,10 @@ func dataSourceVmcSddcRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("region", sddc.ResourceConfig.Region)
 		// Query the API for primary Cluster ID so only it's hosts can be added to the
 		// sddc host
-		primaryClusterClient := sddcs.NewPrimaryclusterClient(connectorWrapper)
+		primaryClusterClient := apiClient.PrimaryCluster()
 		primaryCluster, err := primaryClusterClient.Get(orgID, sddcID)
 		if err != nil {
-			return HandleReadError(d, "Primary Cluster", sddcID, err)
+			return toDiagnostics(HandleReadError(d, "Primary Cluster", sddcID, err))
 		}
 		d.Set("num_host", getHostCountCluster(&sddc, primaryCluster.ClusterId))
 		d.Set("provider_type", sddc.ResourceConfig.Provider)
@@ -210,6 +238,7 @@ func dataSourceVmcSddcRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("sso_domain", *sddc.ResourceConfig.SsoDomain)
 		d.Set("skip_creating_vxlan", *sddc.ResourceConfig.SkipCreatingVxlan)
 		d.Set("nsxt_ui", *sddc.ResourceConfig.Nsxt)
+		d.Set("sddc_security", flattenSddcSecurity(sddc.ResourceConfig.SddcSecurity))
 		if sddc.ResourceConfig.NsxCloudAdmin != nil {
 			d.Set("nsxt_cloudadmin", *sddc.ResourceConfig.NsxCloudAdmin)
 			// Evade nil pointer dereference when user's access_token doesn't have NSX roles
@@ -227,3 +256,24 @@ func dataSourceVmcSddcRead(d *schema.ResourceData, m interface{}) error {
 
 	return nil
 }
+
+// waitForSddcReady polls the SDDC with the specified ID for as long as it is deploying, and
+// returns it as soon as it has reached another state.
+func waitForSddcReady(ctx context.Context, sddcClient orgs.SddcsClient, orgID string, sddcID string, timeout time.Duration) (model.Sddc, error) {
+	var sddc model.Sddc
+	err := resource.RetryContext(ctx, timeout, func() *resource.RetryError {
+		var err error
+		sddc, err = sddcClient.Get(orgID, sddcID)
+		if err != nil {
+			return resource.NonRetryableError(err)
+		}
+		if sddc.SddcState != nil && *sddc.SddcState == model.Sddc_SDDC_STATE_DEPLOYING {
+			return resource.RetryableError(fmt.Errorf("expected SDDC %s to be ready, but it is still deploying", sddcID))
+		}
+		if sddc.SddcState != nil && *sddc.SddcState == model.Sddc_SDDC_STATE_FAILED {
+			return resource.NonRetryableError(fmt.Errorf("SDDC %s failed to deploy", sddcID))
+		}
+		return nil
+	})
+	return sddc, err
+}
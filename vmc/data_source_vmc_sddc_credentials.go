@@ -0,0 +1,109 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+)
+
+func dataSourceVmcSddcCredentials() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcSddcCredentialsRead,
+
+		Schema: map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "Identifier of the SDDC, the credentials are read from.",
+			},
+			"vc_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the vCenter of the SDDC.",
+			},
+			"cloud_username": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The cloudadmin user of the SDDC vCenter.",
+			},
+			"cloud_password": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Password of the cloudadmin user of the SDDC vCenter.",
+			},
+			"nsxt_reverse_proxy_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "NSX API public endpoint url of the SDDC.",
+			},
+			"nsxt_private_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Login URL of the NSX manager of the SDDC, for direct NSX access.",
+			},
+			"nsxt_cloudadmin": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The NSX cloudadmin user, for direct NSX access.",
+			},
+			"nsxt_cloudadmin_password": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Password of the NSX cloudadmin user.",
+			},
+			"nsxt_cloudaudit": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The NSX cloudaudit user, for direct read-only NSX access.",
+			},
+			"nsxt_cloudaudit_password": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Sensitive:   true,
+				Description: "Password of the NSX cloudaudit user.",
+			},
+		},
+	}
+}
+
+func dataSourceVmcSddcCredentialsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	sddcID := d.Get("sddc_id").(string)
+	sddc, err := apiClient.Sddcs().Get(apiClient.OrgID(), sddcID)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("SDDC", err))
+	}
+	if sddc.ResourceConfig == nil {
+		return toDiagnostics(newAttributeError("sddc_id", "credentials of SDDC %s are not available, it is in state %s",
+			sddcID, *sddc.SddcState))
+	}
+
+	d.SetId(sddc.Id)
+	// The NSX credentials are only returned to users, whose access token has NSX roles
+	for key, value := range map[string]*string{
+		"vc_url":                   sddc.ResourceConfig.VcUrl,
+		"cloud_username":           sddc.ResourceConfig.CloudUsername,
+		"cloud_password":           sddc.ResourceConfig.CloudPassword,
+		"nsxt_reverse_proxy_url":   sddc.ResourceConfig.NsxApiPublicEndpointUrl,
+		"nsxt_private_url":         sddc.ResourceConfig.NsxMgrLoginUrl,
+		"nsxt_cloudadmin":          sddc.ResourceConfig.NsxCloudAdmin,
+		"nsxt_cloudadmin_password": sddc.ResourceConfig.NsxCloudAdminPassword,
+		"nsxt_cloudaudit":          sddc.ResourceConfig.NsxCloudAudit,
+		"nsxt_cloudaudit_password": sddc.ResourceConfig.NsxCloudAuditPassword,
+	} {
+		if value != nil {
+			d.Set(key, *value)
+		} else {
+			d.Set(key, "")
+		}
+	}
+	return nil
+}
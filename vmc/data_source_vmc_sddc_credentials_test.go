@@ -0,0 +1,39 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestDataSourceVmcSddcCredentialsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "credentials_sddc"})
+	server.SetCloudPassword(sddcID, "reset-cloud-password")
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSddcCredentials().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcSddcCredentialsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, "cloudadmin@vmc.local", d.Get("cloud_username"))
+	assert.Equal(t, "reset-cloud-password", d.Get("cloud_password"))
+	assert.Equal(t, server.NsxtReverseProxyURL(sddcID), d.Get("nsxt_reverse_proxy_url"))
+	assert.Equal(t, "cloud_admin", d.Get("nsxt_cloudadmin"))
+	assert.Equal(t, "simulated-nsx-admin-password", d.Get("nsxt_cloudadmin_password"))
+
+	for _, key := range []string{"cloud_password", "nsxt_cloudadmin_password", "nsxt_cloudaudit_password"} {
+		assert.True(t, dataSourceVmcSddcCredentials().Schema[key].Sensitive, key)
+	}
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcSddcCredentials().Schema, map[string]interface{}{
+		"sddc_id": "missing",
+	})
+	assert.Error(t, diagsErr(dataSourceVmcSddcCredentialsRead(context.Background(), d, connectorWrapper)))
+}
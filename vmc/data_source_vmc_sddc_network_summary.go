@@ -0,0 +1,168 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	nsxmodel "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func dataSourceVmcSddcNetworkSummary() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcSddcNetworkSummaryRead,
+
+		Schema: map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "SDDC identifier",
+			},
+			"management_cidr": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The CIDR of the management network of the SDDC.",
+			},
+			"compute_segment_cidrs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The CIDRs of the default compute segments, created along with the SDDC.",
+			},
+			"connected_vpc_cidrs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The CIDRs of the customer VPCs, the SDDC is connected to.",
+			},
+			"public_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The public IPs allocated for the SDDC.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVmcSddcNetworkSummaryRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	sddcID := d.Get("sddc_id").(string)
+	sddc, err := apiClient.Sddcs().Get(apiClient.OrgID(), sddcID)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("SDDC", err))
+	}
+
+	managementCidr := ""
+	computeSegmentCidrs := []string{}
+	nsxtReverseProxyURL := ""
+	if sddc.ResourceConfig != nil {
+		if sddc.ResourceConfig.VpcInfo != nil && sddc.ResourceConfig.VpcInfo.VpcCidr != nil {
+			managementCidr = *sddc.ResourceConfig.VpcInfo.VpcCidr
+		}
+		// The default compute segment is created along with the SDDC, unless skipped
+		skipCreatingVxlan := sddc.ResourceConfig.SkipCreatingVxlan != nil && *sddc.ResourceConfig.SkipCreatingVxlan
+		if !skipCreatingVxlan && sddc.ResourceConfig.VxlanSubnet != nil && len(*sddc.ResourceConfig.VxlanSubnet) > 0 {
+			computeSegmentCidrs = append(computeSegmentCidrs, *sddc.ResourceConfig.VxlanSubnet)
+		}
+		if sddc.ResourceConfig.NsxApiPublicEndpointUrl != nil {
+			nsxtReverseProxyURL = *sddc.ResourceConfig.NsxApiPublicEndpointUrl
+		}
+	}
+
+	connections, err := apiClient.SddcConnections().Get(apiClient.OrgID(), &sddcID)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("SDDC connections", err))
+	}
+
+	publicIPs := []map[string]interface{}{}
+	if len(nsxtReverseProxyURL) > 0 {
+		nsxClient, err := apiClient.ForNsx(nsxtReverseProxyURL)
+		if err != nil {
+			return toDiagnostics(HandleDataSourceReadError("NSXT reverse proxy URL connector", err))
+		}
+		allPublicIPs, err := listPublicIPs(nsxClient)
+		if err != nil {
+			return toDiagnostics(HandleDataSourceReadError("Public IPs", err))
+		}
+		for _, publicIP := range allPublicIPs {
+			publicIPMap := map[string]interface{}{}
+			if publicIP.Id != nil {
+				publicIPMap["id"] = *publicIP.Id
+			}
+			if publicIP.Ip != nil {
+				publicIPMap["ip"] = *publicIP.Ip
+			}
+			if publicIP.DisplayName != nil {
+				publicIPMap["display_name"] = *publicIP.DisplayName
+			}
+			publicIPs = append(publicIPs, publicIPMap)
+		}
+	}
+
+	d.SetId(sddcID)
+	d.Set("management_cidr", managementCidr)
+	d.Set("compute_segment_cidrs", computeSegmentCidrs)
+	d.Set("connected_vpc_cidrs", getConnectedVpcCidrs(connections, sddcID))
+	d.Set("public_ips", publicIPs)
+	return nil
+}
+
+// listPublicIPs returns the public IPs allocated at the NSX manager of the provided client,
+// following the cursor over all pages of the list.
+func listPublicIPs(nsxClient *api.Client) ([]nsxmodel.PublicIp, error) {
+	var publicIPs []nsxmodel.PublicIp
+	var cursor *string
+	for {
+		publicIPResultList, err := nsxClient.PublicIps().List(cursor, nil, nil, nil, nil)
+		if err != nil {
+			return nil, err
+		}
+		publicIPs = append(publicIPs, publicIPResultList.Results...)
+		if publicIPResultList.Cursor == nil || len(*publicIPResultList.Cursor) == 0 {
+			return publicIPs, nil
+		}
+		cursor = publicIPResultList.Cursor
+	}
+}
+
+// getConnectedVpcCidrs returns the distinct CIDRs of the customer VPCs, that the SDDC with the
+// specified ID has active connections to.
+func getConnectedVpcCidrs(connections []model.AwsSddcConnection, sddcID string) []string {
+	vpcCidrs := []string{}
+	seen := map[string]bool{}
+	for _, connection := range connections {
+		if connection.SddcId != nil && *connection.SddcId != sddcID {
+			continue
+		}
+		if connection.State != nil && *connection.State != model.AwsSddcConnection_STATE_ACTIVE {
+			continue
+		}
+		if connection.CidrBlockVpc == nil || seen[*connection.CidrBlockVpc] {
+			continue
+		}
+		seen[*connection.CidrBlockVpc] = true
+		vpcCidrs = append(vpcCidrs, *connection.CidrBlockVpc)
+	}
+	return vpcCidrs
+}
@@ -0,0 +1,80 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"testing"
+)
+
+func TestDataSourceVmcSddcNetworkSummarySimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{
+		Name:        "network_sddc",
+		VpcCidr:     "10.2.0.0/16",
+		VxlanSubnet: "192.168.1.0/24",
+	})
+	otherSddcID := server.AddSddc(simulator.SddcConfig{Name: "other_sddc"})
+	server.AddSddcConnection(sddcID, "172.31.0.0/16", "172.31.0.0/20")
+	// A second connection to the same VPC is only reported once
+	server.AddSddcConnection(sddcID, "172.31.0.0/16", "172.31.16.0/20")
+	server.AddSddcConnection(otherSddcID, "172.30.0.0/16", "172.30.0.0/20")
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSddcNetworkSummary().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	publicIP := schema.TestResourceDataRaw(t, resourcePublicIP().Schema, map[string]interface{}{
+		"nsxt_reverse_proxy_url": server.NsxtReverseProxyURL(sddcID),
+		"display_name":           "web",
+	})
+	assert.NoError(t, diagsErr(resourcePublicIPCreate(context.Background(), publicIP, connectorWrapper)))
+
+	assert.NoError(t, diagsErr(dataSourceVmcSddcNetworkSummaryRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, "10.2.0.0/16", d.Get("management_cidr"))
+	assert.Equal(t, []interface{}{"192.168.1.0/24"}, d.Get("compute_segment_cidrs"))
+	assert.Equal(t, []interface{}{"172.31.0.0/16"}, d.Get("connected_vpc_cidrs"))
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{
+			"id":           publicIP.Id(),
+			"ip":           publicIP.Get("ip"),
+			"display_name": "web",
+		},
+	}, d.Get("public_ips"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcSddcNetworkSummary().Schema, map[string]interface{}{
+		"sddc_id": otherSddcID,
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcSddcNetworkSummaryRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Get("management_cidr"))
+	assert.Empty(t, d.Get("compute_segment_cidrs"))
+	assert.Equal(t, []interface{}{"172.30.0.0/16"}, d.Get("connected_vpc_cidrs"))
+	assert.Empty(t, d.Get("public_ips"))
+}
+
+func TestDataSourceVmcSddcNetworkSummaryPublicIPPagesSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "network_sddc"})
+	server.PublicIPsPageSize = 2
+	for _, displayName := range []string{"web", "api", "mail"} {
+		publicIP := schema.TestResourceDataRaw(t, resourcePublicIP().Schema, map[string]interface{}{
+			"nsxt_reverse_proxy_url": server.NsxtReverseProxyURL(sddcID),
+			"display_name":           displayName,
+		})
+		assert.NoError(t, diagsErr(resourcePublicIPCreate(context.Background(), publicIP, connectorWrapper)))
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSddcNetworkSummary().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcSddcNetworkSummaryRead(context.Background(), d, connectorWrapper)))
+	displayNames := []string{}
+	for _, publicIP := range d.Get("public_ips").([]interface{}) {
+		displayNames = append(displayNames, publicIP.(map[string]interface{})["display_name"].(string))
+	}
+	assert.ElementsMatch(t, []string{"web", "api", "mail"}, displayNames)
+}
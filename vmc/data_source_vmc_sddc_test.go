@@ -4,10 +4,17 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
@@ -38,3 +45,49 @@ data "vmc_sddc" "sddc_imported" {
 		os.Getenv(constants.TestSddcID),
 	)
 }
+
+func TestDataSourceVmcSddcSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "imported_sddc", NumHosts: 3})
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSddc().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+
+	err := diagsErr(dataSourceVmcSddcRead(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, "imported_sddc", d.Get("sddc_name"))
+	assert.Equal(t, 3, d.Get("num_host"))
+	assert.Equal(t, server.NsxtReverseProxyURL(sddcID), d.Get("nsxt_reverse_proxy_url"))
+}
+
+func TestDataSourceVmcSddcWaitUntilReadySimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "deploying_sddc"})
+	server.SetSddcState(sddcID, model.Sddc_SDDC_STATE_DEPLOYING)
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSddc().Schema, map[string]interface{}{
+		"sddc_id":          sddcID,
+		"wait_until_ready": true,
+	})
+
+	time.AfterFunc(200*time.Millisecond, func() {
+		server.SetSddcState(sddcID, model.Sddc_SDDC_STATE_READY)
+	})
+	err := diagsErr(dataSourceVmcSddcRead(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, d.Get("sddc_state"))
+
+	server.SetSddcState(sddcID, model.Sddc_SDDC_STATE_FAILED)
+	err = diagsErr(dataSourceVmcSddcRead(context.Background(), d, connectorWrapper))
+	assert.ErrorContains(t, err, "failed to deploy")
+}
+
+func TestWaitForSddcReadyTimeoutSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "deploying_sddc"})
+	server.SetSddcState(sddcID, model.Sddc_SDDC_STATE_DEPLOYING)
+
+	_, err := waitForSddcReady(context.Background(), orgs.NewSddcsClient(connectorWrapper), connectorWrapper.OrgID, sddcID, time.Second)
+	assert.ErrorContains(t, err, "still deploying")
+}
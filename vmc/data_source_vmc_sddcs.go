@@ -0,0 +1,181 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func dataSourceVmcSddcs() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcSddcsRead,
+
+		Schema: map[string]*schema.Schema{
+			"name": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the SDDCs with this name.",
+			},
+			"region": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the SDDCs deployed in this region, e.g. US_WEST_2 or us-west-2.",
+			},
+			"provider_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the SDDCs of this cloud provider (AWS or ZEROCLOUD).",
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.AwsProviderType, constants.ZeroCloudProviderType}, false),
+			},
+			"deployment_type": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return the SDDCs of this deployment type (SingleAZ or MultiAZ).",
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.SingleAvailabilityZone, constants.MultiAvailabilityZone}, false),
+			},
+			"ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The IDs of the matching SDDCs.",
+			},
+			"sddcs": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching SDDCs.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"sddc_state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"region": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"provider_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"deployment_type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vc_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"nsxt_reverse_proxy_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceVmcSddcsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	sddcList, err := apiClient.Sddcs().List(apiClient.OrgID(), nil)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("SDDCs", err))
+	}
+
+	filters := map[string]string{}
+	for _, key := range []string{"name", "region", "provider_type", "deployment_type"} {
+		if value, ok := d.GetOk(key); ok {
+			filters[key] = value.(string)
+		}
+	}
+	ids := []string{}
+	sddcs := []map[string]interface{}{}
+	for _, sddc := range sddcList {
+		if sddc.SddcState != nil && *sddc.SddcState == model.Sddc_SDDC_STATE_DELETED {
+			continue
+		}
+		sddcMap := flattenSddcSummary(sddc)
+		if !matchesSddcFilters(sddcMap, filters) {
+			continue
+		}
+		ids = append(ids, sddc.Id)
+		sddcs = append(sddcs, sddcMap)
+	}
+
+	d.SetId(apiClient.OrgID())
+	d.Set("ids", ids)
+	d.Set("sddcs", sddcs)
+	return nil
+}
+
+// flattenSddcSummary converts the attributes of an SDDC, that the vmc_sddcs data source
+// exports, into their schema format.
+func flattenSddcSummary(sddc model.Sddc) map[string]interface{} {
+	sddcMap := map[string]interface{}{
+		"id": sddc.Id,
+	}
+	if sddc.Name != nil {
+		sddcMap["name"] = *sddc.Name
+	}
+	if sddc.SddcState != nil {
+		sddcMap["sddc_state"] = *sddc.SddcState
+	}
+	if sddc.Provider != nil {
+		sddcMap["provider_type"] = *sddc.Provider
+	}
+	if sddc.ResourceConfig != nil {
+		if len(sddc.ResourceConfig.Provider) > 0 {
+			sddcMap["provider_type"] = sddc.ResourceConfig.Provider
+		}
+		if sddc.ResourceConfig.Region != nil {
+			sddcMap["region"] = *sddc.ResourceConfig.Region
+		}
+		if sddc.ResourceConfig.DeploymentType != nil {
+			sddcMap["deployment_type"] = ConvertDeployType(*sddc.ResourceConfig.DeploymentType)
+		}
+		if sddc.ResourceConfig.VcUrl != nil {
+			sddcMap["vc_url"] = *sddc.ResourceConfig.VcUrl
+		}
+		if sddc.ResourceConfig.NsxApiPublicEndpointUrl != nil {
+			sddcMap["nsxt_reverse_proxy_url"] = *sddc.ResourceConfig.NsxApiPublicEndpointUrl
+		}
+	}
+	return sddcMap
+}
+
+// matchesSddcFilters checks whether a flattened SDDC matches all the provided filters.
+// Regions are matched regardless of the format they are specified in, the same way
+// the region of the vmc_sddc resource is.
+func matchesSddcFilters(sddcMap map[string]interface{}, filters map[string]string) bool {
+	for key, expected := range filters {
+		actual, _ := sddcMap[key].(string)
+		if key == "region" {
+			expected = strings.ReplaceAll(strings.ToUpper(expected), "-", "_")
+		}
+		if actual != expected {
+			return false
+		}
+	}
+	return true
+}
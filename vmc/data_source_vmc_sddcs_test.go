@@ -0,0 +1,51 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"testing"
+)
+
+func TestDataSourceVmcSddcsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	westID := server.AddSddc(simulator.SddcConfig{Name: "west", Region: "US_WEST_2"})
+	eastID := server.AddSddc(simulator.SddcConfig{Name: "east", Region: "US_EAST_1", DeploymentType: "MULTI_AZ"})
+	deletedID := server.AddSddc(simulator.SddcConfig{Name: "deleted", Region: "US_WEST_2"})
+	server.SetSddcState(deletedID, "DELETED")
+
+	testCases := []struct {
+		filters     map[string]interface{}
+		expectedIDs []string
+	}{
+		{filters: map[string]interface{}{}, expectedIDs: []string{westID, eastID}},
+		{filters: map[string]interface{}{"name": "east"}, expectedIDs: []string{eastID}},
+		{filters: map[string]interface{}{"region": "us-west-2"}, expectedIDs: []string{westID}},
+		{filters: map[string]interface{}{"deployment_type": "MultiAZ"}, expectedIDs: []string{eastID}},
+		{filters: map[string]interface{}{"provider_type": "ZEROCLOUD"}, expectedIDs: []string{}},
+		{filters: map[string]interface{}{"provider_type": "AWS", "region": "US_EAST_1"}, expectedIDs: []string{eastID}},
+	}
+	for _, testCase := range testCases {
+		d := schema.TestResourceDataRaw(t, dataSourceVmcSddcs().Schema, testCase.filters)
+		assert.NoError(t, diagsErr(dataSourceVmcSddcsRead(context.Background(), d, connectorWrapper)))
+		assert.Equal(t, simulator.TestOrgID, d.Id())
+		ids := []string{}
+		for _, id := range d.Get("ids").([]interface{}) {
+			ids = append(ids, id.(string))
+		}
+		assert.ElementsMatch(t, testCase.expectedIDs, ids, "filters: %v", testCase.filters)
+		assert.Len(t, d.Get("sddcs").([]interface{}), len(testCase.expectedIDs))
+	}
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSddcs().Schema, map[string]interface{}{"name": "east"})
+	assert.NoError(t, diagsErr(dataSourceVmcSddcsRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, eastID, d.Get("sddcs.0.id"))
+	assert.Equal(t, "US_EAST_1", d.Get("sddcs.0.region"))
+	assert.Equal(t, "AWS", d.Get("sddcs.0.provider_type"))
+	assert.Equal(t, "MultiAZ", d.Get("sddcs.0.deployment_type"))
+	assert.Equal(t, "READY", d.Get("sddcs.0.sddc_state"))
+}
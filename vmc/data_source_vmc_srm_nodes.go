@@ -0,0 +1,134 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
+)
+
+func dataSourceVmcSrmNodes() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcSrmNodesRead,
+
+		Schema: map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				Description: "SDDC identifier",
+			},
+			"srm_nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The SRM nodes of the SDDC, including the one deployed upon site recovery activation.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vm_moref_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ui_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"api_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"vr_node": {
+				Type:        schema.TypeMap,
+				Computed:    true,
+				Description: "The vSphere Replication node of the SDDC.",
+			},
+		},
+	}
+}
+
+func dataSourceVmcSrmNodesRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Get("sddc_id").(string)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	siteRecovery, err := draasClient.SiteRecovery().Get(draasClient.OrgID(), sddcID)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("SRM nodes", err))
+	}
+
+	srmNodes := []map[string]interface{}{}
+	for _, srmNode := range siteRecovery.SrmNodes {
+		srmNodes = append(srmNodes, flattenSrmNode(srmNode))
+	}
+	vrNodeMap := map[string]string{}
+	if siteRecovery.VrNode != nil {
+		vrNode := siteRecovery.VrNode
+		for key, value := range map[string]*string{
+			"id":          vrNode.Id,
+			"hostname":    vrNode.Hostname,
+			"ip_address":  vrNode.IpAddress,
+			"state":       vrNode.State,
+			"type":        vrNode.Type_,
+			"vm_moref_id": vrNode.VmMorefId,
+		} {
+			if value != nil {
+				vrNodeMap[key] = *value
+			}
+		}
+	}
+
+	d.SetId(sddcID)
+	d.Set("srm_nodes", srmNodes)
+	d.Set("vr_node", vrNodeMap)
+	return nil
+}
+
+// flattenSrmNode converts an SRM node into the schema format of the vmc_srm_nodes data source.
+// Nil attributes, e.g. the VM moref ID of a node that is still being deployed, are left empty.
+func flattenSrmNode(srmNode draasmodel.SrmNode) map[string]interface{} {
+	srmNodeMap := map[string]interface{}{}
+	if srmNode.Id != nil {
+		srmNodeMap["id"] = *srmNode.Id
+	}
+	if srmNode.Hostname != nil {
+		srmNodeMap["host_name"] = *srmNode.Hostname
+		srmNodeMap["ui_url"], srmNodeMap["api_url"] = getSrmNodeURLs(*srmNode.Hostname)
+	}
+	if srmNode.IpAddress != nil {
+		srmNodeMap["ip_address"] = *srmNode.IpAddress
+	}
+	if srmNode.State != nil {
+		srmNodeMap["state"] = *srmNode.State
+	}
+	if srmNode.Type_ != nil {
+		srmNodeMap["type"] = *srmNode.Type_
+	}
+	if srmNode.VmMorefId != nil {
+		srmNodeMap["vm_moref_id"] = *srmNode.VmMorefId
+	}
+	return srmNodeMap
+}
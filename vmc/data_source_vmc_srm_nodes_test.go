@@ -0,0 +1,57 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"testing"
+)
+
+func TestDataSourceVmcSrmNodesSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_nodes_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+	srmNodeData := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+		"sddc_id":                       sddcID,
+		"srm_node_extension_key_suffix": "second",
+	})
+	assert.NoError(t, diagsErr(resourceSrmNodeCreate(context.Background(), srmNodeData, connectorWrapper)))
+
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSrmNodes().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcSrmNodesRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, sddcID, d.Id())
+	srmNodes := d.Get("srm_nodes").([]interface{})
+	assert.Len(t, srmNodes, 2)
+	ids := []interface{}{}
+	for _, srmNode := range srmNodes {
+		srmNodeMap := srmNode.(map[string]interface{})
+		ids = append(ids, srmNodeMap["id"])
+		assert.NotEmpty(t, srmNodeMap["host_name"])
+		assert.NotEmpty(t, srmNodeMap["ip_address"])
+		uiURL, apiURL := getSrmNodeURLs(srmNodeMap["host_name"].(string))
+		assert.Equal(t, uiURL, srmNodeMap["ui_url"])
+		assert.Equal(t, apiURL, srmNodeMap["api_url"])
+	}
+	assert.Contains(t, ids, srmNodeData.Id())
+	assert.NotEmpty(t, d.Get("vr_node.id"))
+	assert.NotEmpty(t, d.Get("vr_node.hostname"))
+}
+
+func TestDataSourceVmcSrmNodesNotActivatedSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_nodes_sddc"})
+	d := schema.TestResourceDataRaw(t, dataSourceVmcSrmNodes().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.Error(t, diagsErr(dataSourceVmcSrmNodesRead(context.Background(), d, connectorWrapper)))
+}
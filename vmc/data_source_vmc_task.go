@@ -0,0 +1,141 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/task"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func dataSourceVmcTask() *schema.Resource {
+	return &schema.Resource{
+		ReadContext: dataSourceVmcTaskRead,
+		Schema:      taskSchema(false),
+	}
+}
+
+// taskSchema returns the schema shared by the vmc_task data source and the vmc_task_wait
+// resource. The arguments of the resource force a new resource.
+func taskSchema(forceNew bool) map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"task_id": {
+			Type:        schema.TypeString,
+			Required:    true,
+			ForceNew:    forceNew,
+			Description: "Task identifier",
+		},
+		"service": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     forceNew,
+			Default:      constants.VmcTaskService,
+			ValidateFunc: validation.StringInSlice([]string{constants.VmcTaskService, constants.DraasTaskService}, false),
+			Description:  "The service, that tracks the task. Possible values: vmc, draas. Default: vmc.",
+		},
+		"task_type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The type of the task.",
+		},
+		"status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The status of the task.",
+		},
+		"sub_status": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The sub-status of the task.",
+		},
+		"progress_percent": {
+			Type:        schema.TypeInt,
+			Computed:    true,
+			Description: "The estimated progress of the task, in percent.",
+		},
+		"error_message": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The error message of the task, if it failed.",
+		},
+		"resource_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "Identifier of the resource the task acts on.",
+		},
+		"resource_type": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The type of the resource the task acts on.",
+		},
+		"start_time": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The time the task started, in RFC 3339 format.",
+		},
+		"end_time": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The time the task reached a terminal state, in RFC 3339 format.",
+		},
+	}
+}
+
+func dataSourceVmcTaskRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	taskID := d.Get("task_id").(string)
+	serviceTask, err := getServiceTask(d, m)
+	if err != nil {
+		return toDiagnostics(HandleDataSourceReadError("Task", err))
+	}
+	d.SetId(taskID)
+	setTaskAttributes(d, serviceTask)
+	return nil
+}
+
+// getServiceTask looks up the task with the configured ID on the configured service.
+func getServiceTask(d *schema.ResourceData, m interface{}) (model.Task, error) {
+	connectorWrapper := m.(*connector.Wrapper)
+	taskID := d.Get("task_id").(string)
+	if d.Get("service").(string) != constants.DraasTaskService {
+		return task.GetTask(connectorWrapper, taskID)
+	}
+	return task.GetDraasTask(connectorWrapper, taskID)
+}
+
+func setTaskAttributes(d *schema.ResourceData, serviceTask model.Task) {
+	d.Set("task_type", serviceTask.TaskType)
+	d.Set("status", serviceTask.Status)
+	d.Set("sub_status", serviceTask.SubStatus)
+	d.Set("progress_percent", serviceTask.ProgressPercent)
+	d.Set("error_message", serviceTask.ErrorMessage)
+	d.Set("resource_id", serviceTask.ResourceId)
+	d.Set("resource_type", serviceTask.ResourceType)
+	startTime := ""
+	if serviceTask.StartTime != nil {
+		startTime = serviceTask.StartTime.Format(time.RFC3339)
+	}
+	d.Set("start_time", startTime)
+	endTime := ""
+	if serviceTask.EndTime != nil {
+		endTime = serviceTask.EndTime.Format(time.RFC3339)
+	}
+	d.Set("end_time", endTime)
+}
+
+// isTerminalTaskStatus checks whether a task with the provided status is done, either
+// successfully or not.
+func isTerminalTaskStatus(status *string) bool {
+	if status == nil {
+		return false
+	}
+	return *status == model.Task_STATUS_FINISHED || *status == model.Task_STATUS_FAILED ||
+		*status == model.Task_STATUS_CANCELED
+}
@@ -0,0 +1,42 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func TestDataSourceVmcTaskSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.TaskPollsUntilFinished = 2
+	taskID := server.StartTask("SDDC-MAINTENANCE", "sddcId")
+
+	for _, service := range []string{constants.VmcTaskService, constants.DraasTaskService} {
+		d := schema.TestResourceDataRaw(t, dataSourceVmcTask().Schema, map[string]interface{}{
+			"task_id": taskID,
+			"service": service,
+		})
+		assert.NoError(t, diagsErr(dataSourceVmcTaskRead(context.Background(), d, connectorWrapper)))
+		assert.Equal(t, taskID, d.Id())
+		assert.Equal(t, "SDDC-MAINTENANCE", d.Get("task_type"))
+		assert.Equal(t, "sddcId", d.Get("resource_id"))
+	}
+	// The task finishes after being polled twice
+	d := schema.TestResourceDataRaw(t, dataSourceVmcTask().Schema, map[string]interface{}{
+		"task_id": taskID,
+	})
+	assert.NoError(t, diagsErr(dataSourceVmcTaskRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, model.Task_STATUS_FINISHED, d.Get("status"))
+
+	d = schema.TestResourceDataRaw(t, dataSourceVmcTask().Schema, map[string]interface{}{
+		"task_id": "missing-task",
+	})
+	assert.Error(t, diagsErr(dataSourceVmcTaskRead(context.Background(), d, connectorWrapper)))
+}
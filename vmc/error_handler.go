@@ -4,9 +4,13 @@
 package vmc
 
 import (
+	"errors"
 	"fmt"
 	"log"
+	"strings"
 
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std"
 	e "github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
@@ -111,6 +115,39 @@ func isNotFoundError(err error) bool {
 	return false
 }
 
+// isTaskInProgressError checks whether the request was rejected, because another task is
+// in progress on the resource, e.g. DRaaS rejects SRM node operations while another SRM node
+// of the SDDC is being provisioned.
+func isTaskInProgressError(err error) bool {
+	vapiError, ok := err.(e.InvalidRequest)
+	if !ok {
+		return false
+	}
+	var messages []string
+	for _, message := range vapiError.Messages {
+		messages = append(messages, message.DefaultMessage)
+	}
+	// The error messages are read from the raw error data, as the error responses of the
+	// DRaaS API do not necessarily contain all the fields of model.ErrorResponse
+	if vapiError.Data != nil {
+		if errorMessages, fieldErr := vapiError.Data.Field("error_messages"); fieldErr == nil {
+			if errorMessagesList, ok := errorMessages.(*data.ListValue); ok {
+				for _, errorMessage := range errorMessagesList.List() {
+					if stringValue, ok := errorMessage.(*data.StringValue); ok {
+						messages = append(messages, stringValue.Value())
+					}
+				}
+			}
+		}
+	}
+	for _, message := range messages {
+		if strings.Contains(strings.ToLower(message), "in progress") {
+			return true
+		}
+	}
+	return false
+}
+
 func HandleCreateError(resourceType string, err error) error {
 	msg := fmt.Sprintf("Failed to create %s", resourceType)
 	return logAPIError(msg, err)
@@ -150,3 +187,41 @@ func HandleDeleteError(resourceType string, resourceID string, err error) error
 	msg := fmt.Sprintf("Failed to delete %s %s", resourceType, resourceID)
 	return logAPIError(msg, err)
 }
+
+// attributeError is an error caused by the configured value of an attribute.
+type attributeError struct {
+	attribute string
+	err       error
+}
+
+// newAttributeError returns an error caused by the configured value of the specified top-level
+// attribute.
+func newAttributeError(attribute string, format string, a ...interface{}) error {
+	return attributeError{attribute: attribute, err: fmt.Errorf(format, a...)}
+}
+
+func (e attributeError) Error() string {
+	return e.err.Error()
+}
+
+func (e attributeError) Unwrap() error {
+	return e.err
+}
+
+// toDiagnostics converts the error returned by a resource or data source operation to
+// diagnostics. Errors caused by the value of an attribute point to that attribute, so that
+// Terraform shows it in the configuration.
+func toDiagnostics(err error) diag.Diagnostics {
+	if err == nil {
+		return nil
+	}
+	var attrErr attributeError
+	if errors.As(err, &attrErr) {
+		return diag.Diagnostics{{
+			Severity:      diag.Error,
+			Summary:       err.Error(),
+			AttributePath: cty.GetAttrPath(attrErr.attribute),
+		}}
+	}
+	return diag.FromErr(err)
+}
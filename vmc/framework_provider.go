@@ -0,0 +1,262 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+)
+
+// ProviderServer returns a factory of the provider server, that muxes the SDK provider returned by
+// Provider with the framework provider returned by NewFrameworkProvider. Resources and data
+// sources are migrated to terraform-plugin-framework one at a time, by moving them from the
+// former to the latter. Both providers share the connector.Wrapper configured by whichever of them is
+// configured first.
+func ProviderServer(ctx context.Context) (func() tfprotov5.ProviderServer, error) {
+	shared := &sharedConnectorWrapper{}
+	providers := []func() tfprotov5.ProviderServer{
+		providerWithSharedWrapper(shared).GRPCProvider,
+		providerserver.NewProtocol5(&frameworkProvider{shared: shared}),
+	}
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, providers...)
+	if err != nil {
+		return nil, err
+	}
+	return muxServer.ProviderServer, nil
+}
+
+// sharedConnectorWrapper the connector.Wrapper shared by the providers of a provider server. The muxed
+// providers are configured with the same arguments, so the wrapper is created once, rather than running
+// the credential_process, exchanging the credentials for an access token and opening the audit log once
+// per provider.
+type sharedConnectorWrapper struct {
+	once    sync.Once
+	wrapper *connector.Wrapper
+	err     error
+}
+
+// get returns the shared wrapper, creating it with newWrapper on first use.
+func (shared *sharedConnectorWrapper) get(newWrapper func() (*connector.Wrapper, error)) (*connector.Wrapper, error) {
+	shared.once.Do(func() {
+		shared.wrapper, shared.err = newWrapper()
+	})
+	return shared.wrapper, shared.err
+}
+
+// frameworkProvider serves the resources and data sources, that have been migrated to
+// terraform-plugin-framework.
+type frameworkProvider struct {
+	shared *sharedConnectorWrapper
+}
+
+// frameworkProviderModel the arguments of the framework provider, see Provider for their defaults.
+type frameworkProviderModel struct {
+	RefreshToken        types.String `tfsdk:"refresh_token"`
+	ClientID            types.String `tfsdk:"client_id"`
+	ClientSecret        types.String `tfsdk:"client_secret"`
+	CredentialFile      types.String `tfsdk:"credential_file"`
+	CredentialProcess   types.List   `tfsdk:"credential_process"`
+	OrgID               types.String `tfsdk:"org_id"`
+	Environment         types.String `tfsdk:"environment"`
+	VmcURL              types.String `tfsdk:"vmc_url"`
+	CspURL              types.String `tfsdk:"csp_url"`
+	ExtraHeaders        types.Map    `tfsdk:"extra_headers"`
+	ProxyURL            types.String `tfsdk:"proxy_url"`
+	CAFile              types.String `tfsdk:"ca_file"`
+	AllowUnverifiedSSL  types.Bool   `tfsdk:"allow_unverified_ssl"`
+	AuditLogPath        types.String `tfsdk:"audit_log_path"`
+	MaxRetries          types.Int64  `tfsdk:"max_retries"`
+	RetryMinDelay       types.Int64  `tfsdk:"retry_min_delay"`
+	RetryMaxDelay       types.Int64  `tfsdk:"retry_max_delay"`
+	TaskPollInterval    types.Int64  `tfsdk:"task_poll_interval"`
+	TaskPollMaxInterval types.Int64  `tfsdk:"task_poll_max_interval"`
+}
+
+// NewFrameworkProvider returns the terraform-plugin-framework part of the provider.
+func NewFrameworkProvider() provider.Provider {
+	return &frameworkProvider{shared: &sharedConnectorWrapper{}}
+}
+
+func (p *frameworkProvider) Metadata(_ context.Context, _ provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "vmc"
+}
+
+// Schema must be identical to the schema of the SDK provider, as muxed providers cannot
+// differ in their provider schema. The descriptions are taken from the SDK provider.
+func (p *frameworkProvider) Schema(_ context.Context, _ provider.SchemaRequest, resp *provider.SchemaResponse) {
+	// The SDK reports org_id as optional, when its default is provided by the environment
+	orgIDRequired := len(os.Getenv(constants.OrgID)) == 0
+	sdkSchema := Provider().Schema
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"refresh_token": schema.StringAttribute{
+				Optional: true,
+			},
+			"client_id": schema.StringAttribute{
+				Optional: true,
+			},
+			"client_secret": schema.StringAttribute{
+				Optional: true,
+			},
+			"credential_file": schema.StringAttribute{
+				Optional: true,
+			},
+			"credential_process": schema.ListAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"org_id": schema.StringAttribute{
+				Required: orgIDRequired,
+				Optional: !orgIDRequired,
+			},
+			"environment": schema.StringAttribute{
+				Optional: true,
+			},
+			"vmc_url": schema.StringAttribute{
+				Optional: true,
+			},
+			"csp_url": schema.StringAttribute{
+				Optional: true,
+			},
+			"extra_headers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+			},
+			"proxy_url": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["proxy_url"].Description,
+			},
+			"ca_file": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["ca_file"].Description,
+			},
+			"allow_unverified_ssl": schema.BoolAttribute{
+				Optional:    true,
+				Description: sdkSchema["allow_unverified_ssl"].Description,
+			},
+			"audit_log_path": schema.StringAttribute{
+				Optional:    true,
+				Description: sdkSchema["audit_log_path"].Description,
+			},
+			"max_retries": schema.Int64Attribute{
+				Optional:    true,
+				Description: sdkSchema["max_retries"].Description,
+			},
+			"retry_min_delay": schema.Int64Attribute{
+				Optional:    true,
+				Description: sdkSchema["retry_min_delay"].Description,
+			},
+			"retry_max_delay": schema.Int64Attribute{
+				Optional:    true,
+				Description: sdkSchema["retry_max_delay"].Description,
+			},
+			"task_poll_interval": schema.Int64Attribute{
+				Optional:    true,
+				Description: sdkSchema["task_poll_interval"].Description,
+			},
+			"task_poll_max_interval": schema.Int64Attribute{
+				Optional:    true,
+				Description: sdkSchema["task_poll_max_interval"].Description,
+			},
+		},
+	}
+}
+
+func (p *frameworkProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var model frameworkProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &model)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	config := providerConfig{
+		RefreshToken:        stringValueOrEnv(model.RefreshToken, constants.APIToken, ""),
+		ClientID:            stringValueOrEnv(model.ClientID, constants.ClientID, ""),
+		ClientSecret:        stringValueOrEnv(model.ClientSecret, constants.ClientSecret, ""),
+		CredentialFile:      stringValueOrEnv(model.CredentialFile, constants.CredentialFile, ""),
+		OrgID:               stringValueOrEnv(model.OrgID, constants.OrgID, ""),
+		Environment:         stringValueOrEnv(model.Environment, constants.Environment, constants.CommercialEnvironment),
+		VmcURL:              stringValueOrEnv(model.VmcURL, constants.VmcURL, ""),
+		CspURL:              stringValueOrEnv(model.CspURL, constants.CspURL, ""),
+		ExtraHeaders:        map[string]string{},
+		ProxyURL:            stringValueOrEnv(model.ProxyURL, constants.ProxyURL, ""),
+		CAFile:              stringValueOrEnv(model.CAFile, constants.CAFile, ""),
+		AllowUnverifiedSSL:  boolValueOrEnv(model.AllowUnverifiedSSL, constants.AllowUnverifiedSSL),
+		AuditLogPath:        stringValueOrEnv(model.AuditLogPath, constants.AuditLogPath, ""),
+		MaxRetries:          intValueOrDefault(model.MaxRetries, constants.DefaultMaxRetries),
+		RetryMinDelay:       intValueOrDefault(model.RetryMinDelay, constants.DefaultRetryMinDelay),
+		RetryMaxDelay:       intValueOrDefault(model.RetryMaxDelay, constants.DefaultRetryMaxDelay),
+		TaskPollInterval:    intValueOrDefault(model.TaskPollInterval, constants.DefaultTaskPollInterval),
+		TaskPollMaxInterval: intValueOrDefault(model.TaskPollMaxInterval, constants.DefaultTaskPollMaxInterval),
+	}
+	if !model.CredentialProcess.IsNull() && !model.CredentialProcess.IsUnknown() {
+		resp.Diagnostics.Append(model.CredentialProcess.ElementsAs(ctx, &config.CredentialProcess, false)...)
+	}
+	resp.Diagnostics.Append(model.ExtraHeaders.ElementsAs(ctx, &config.ExtraHeaders, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	connectorWrapper, err := p.shared.get(func() (*connector.Wrapper, error) {
+		return newConnectorWrapper(config)
+	})
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to configure the VMC provider", err.Error())
+		return
+	}
+	resp.DataSourceData = connectorWrapper
+	resp.ResourceData = connectorWrapper
+}
+
+func (p *frameworkProvider) DataSources(_ context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		newOrgDataSource,
+	}
+}
+
+func (p *frameworkProvider) Resources(_ context.Context) []func() resource.Resource {
+	return nil
+}
+
+// stringValueOrEnv returns the value of a string argument, falling back to the specified
+// environment variable and default value, the same way schema.EnvDefaultFunc does.
+func stringValueOrEnv(value types.String, envVariable string, defaultValue string) string {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueString()
+	}
+	if envValue := os.Getenv(envVariable); len(envValue) > 0 {
+		return envValue
+	}
+	return defaultValue
+}
+
+// boolValueOrEnv returns the value of a boolean argument, falling back to the specified
+// environment variable and false, the same way schema.EnvDefaultFunc does.
+func boolValueOrEnv(value types.Bool, envVariable string) bool {
+	if !value.IsNull() && !value.IsUnknown() {
+		return value.ValueBool()
+	}
+	envValue, err := strconv.ParseBool(os.Getenv(envVariable))
+	return err == nil && envValue
+}
+
+// intValueOrDefault returns the value of an integer argument, falling back to the default value
+// the same way the Default of the SDK schema does.
+func intValueOrDefault(value types.Int64, defaultValue int) int {
+	if !value.IsNull() && !value.IsUnknown() {
+		return int(value.ValueInt64())
+	}
+	return defaultValue
+}
@@ -0,0 +1,98 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/task"
+)
+
+const (
+	hostActionAdd    = "add"
+	hostActionRemove = "remove"
+)
+
+// hostScalingTimeoutSchema the schema of the scale_out_timeout and scale_in_timeout arguments
+// of the resources, that add and remove the hosts of a cluster.
+func hostScalingTimeoutSchema(description string) *schema.Schema {
+	return &schema.Schema{
+		Type:         schema.TypeString,
+		Optional:     true,
+		ValidateFunc: validateDuration,
+		Description:  description,
+	}
+}
+
+// hostUpdateTimeout returns the time to wait for hosts to be added to or removed from a cluster,
+// the scale_out_timeout or scale_in_timeout of the resource if set, its update timeout otherwise.
+func hostUpdateTimeout(d *schema.ResourceData, action string) (time.Duration, error) {
+	attribute := "scale_out_timeout"
+	if action == hostActionRemove {
+		attribute = "scale_in_timeout"
+	}
+	value, ok := d.GetOk(attribute)
+	if !ok {
+		return d.Timeout(schema.TimeoutUpdate), nil
+	}
+	timeout, err := time.ParseDuration(value.(string))
+	if err != nil {
+		return 0, newAttributeError(attribute, "invalid %s: %v", attribute, err)
+	}
+	return timeout, nil
+}
+
+// waitForHostUpdate waits for the task with the specified ID, that adds the hosts to or removes them
+// from a cluster of the SDDC, for the duration returned by hostUpdateTimeout. When the wait times
+// out, the error reports how many hosts are still to be added or removed, as the task carries on.
+func waitForHostUpdate(ctx context.Context, d *schema.ResourceData, connectorWrapper *connector.Wrapper,
+	taskID string, sddcID string, clusterID string, action string, targetHosts int, f resource.RetryFunc) error {
+	timeout, err := hostUpdateTimeout(d, action)
+	if err != nil {
+		return err
+	}
+	// The task is still running, if the last attempt to wait for it failed with a retryable error
+	stillRunning := false
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, timeout, func() *resource.RetryError {
+		retryErr := f()
+		stillRunning = retryErr != nil && retryErr.Retryable
+		return retryErr
+	})
+	if err == nil || !stillRunning {
+		return err
+	}
+	return fmt.Errorf("timed out after %s waiting for task %s to %s hosts of cluster %s, the task carries on: %s",
+		timeout, taskID, action, clusterID, describeHostUpdateProgress(connectorWrapper, taskID, sddcID, clusterID, action, targetHosts))
+}
+
+// describeHostUpdateProgress describes how many hosts are still to be added to or removed from the cluster.
+func describeHostUpdateProgress(connectorWrapper *connector.Wrapper, taskID string, sddcID string, clusterID string,
+	action string, targetHosts int) string {
+	apiClient := api.NewClient(connectorWrapper)
+	sddc, err := apiClient.Sddcs().Get(apiClient.OrgID(), sddcID)
+	if err != nil {
+		log.Printf("[DEBUG] Unable to get SDDC %s: %v", sddcID, err)
+		return "unable to determine the current number of hosts"
+	}
+	currentHosts := getHostCountCluster(&sddc, clusterID)
+	remaining, verb := targetHosts-currentHosts, "added"
+	if action == hostActionRemove {
+		remaining, verb = currentHosts-targetHosts, "removed"
+	}
+	if remaining < 0 {
+		remaining = 0
+	}
+	progress := fmt.Sprintf("the cluster has %d hosts, %d hosts are still to be %s", currentHosts, remaining, verb)
+	if subTasks, err := task.GetSubTasks(connectorWrapper, taskID); err == nil && len(subTasks) > 0 {
+		progress += " (" + task.SummarizeSubTasks(subTasks) + ")"
+	}
+	return progress
+}
@@ -0,0 +1,215 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+// Package api provides a facade over the clients of the VMC, autoscaler, DRaaS and NSX APIs used by
+// the provider. Clients are created on first use and share the connector of the service they belong
+// to, which makes it the single place to instrument or replace the API clients. The resources and
+// data sources create a new facade for each operation, so the clients are not reused across
+// operations.
+package api
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/clusterconversion"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/inventory"
+	"github.com/vmware/terraform-provider-vmc/vmc/sddcgroup"
+	"github.com/vmware/terraform-provider-vmc/vmc/tkg"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra/direct_connect"
+	directconnectroutes "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra/direct_connect/routes"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra/external"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc"
+	autoscalerapi "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/api"
+	autoscalercluster "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/api/orgs/sddcs/clusters"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/account_link"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/sddcs"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/sddcs/clusters/msft_licensing"
+)
+
+// Client the facade over the API clients of a single service endpoint. The Client returned by
+// NewClient talks to the VMC, autoscaler and DRaaS APIs, while the ones returned by ForNsx talk
+// to the NSX manager of an SDDC.
+type Client struct {
+	wrapper *connector.Wrapper
+
+	mutex   sync.Mutex
+	clients map[string]interface{}
+	nsx     map[string]*Client
+}
+
+// NewClient returns a Client using the provided connector.Wrapper. The API clients it creates are
+// only reused for the lifetime of the returned Client.
+func NewClient(wrapper *connector.Wrapper) *Client {
+	return &Client{
+		wrapper: wrapper,
+		clients: map[string]interface{}{},
+		nsx:     map[string]*Client{},
+	}
+}
+
+// Wrapper returns the connector.Wrapper of the service endpoint the Client talks to.
+func (c *Client) Wrapper() *connector.Wrapper {
+	return c.wrapper
+}
+
+// OrgID returns the ID of the organization the Client operates on.
+func (c *Client) OrgID() string {
+	return c.wrapper.OrgID
+}
+
+// client returns the API client with the specified name, creating it on first use. The clients
+// use the wrapper itself as connector, so that they pick up re-authentications.
+func (c *Client) client(name string, newClient func() interface{}) interface{} {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	apiClient, ok := c.clients[name]
+	if !ok {
+		apiClient = newClient()
+		c.clients[name] = apiClient
+	}
+	return apiClient
+}
+
+func (c *Client) Orgs() vmc.OrgsClient {
+	return c.client("orgs", func() interface{} { return vmc.NewOrgsClient(c.wrapper) }).(vmc.OrgsClient)
+}
+
+func (c *Client) Sddcs() orgs.SddcsClient {
+	return c.client("sddcs", func() interface{} { return orgs.NewSddcsClient(c.wrapper) }).(orgs.SddcsClient)
+}
+
+func (c *Client) Tasks() orgs.TasksClient {
+	return c.client("tasks", func() interface{} { return orgs.NewTasksClient(c.wrapper) }).(orgs.TasksClient)
+}
+
+func (c *Client) Clusters() sddcs.ClustersClient {
+	return c.client("clusters", func() interface{} { return sddcs.NewClustersClient(c.wrapper) }).(sddcs.ClustersClient)
+}
+
+func (c *Client) Esxs() sddcs.EsxsClient {
+	return c.client("esxs", func() interface{} { return sddcs.NewEsxsClient(c.wrapper) }).(sddcs.EsxsClient)
+}
+
+func (c *Client) ProvisionSpec() sddcs.ProvisionSpecClient {
+	return c.client("provisionSpec", func() interface{} { return sddcs.NewProvisionSpecClient(c.wrapper) }).(sddcs.ProvisionSpecClient)
+}
+
+func (c *Client) PrimaryCluster() sddcs.PrimaryclusterClient {
+	return c.client("primarycluster", func() interface{} { return sddcs.NewPrimaryclusterClient(c.wrapper) }).(sddcs.PrimaryclusterClient)
+}
+
+func (c *Client) Convert() sddcs.ConvertClient {
+	return c.client("convert", func() interface{} { return sddcs.NewConvertClient(c.wrapper) }).(sddcs.ConvertClient)
+}
+
+func (c *Client) MsftLicensingPublish() msft_licensing.PublishClient {
+	return c.client("msft_licensing_publish", func() interface{} { return msft_licensing.NewPublishClient(c.wrapper) }).(msft_licensing.PublishClient)
+}
+
+func (c *Client) ConnectedAccounts() account_link.ConnectedAccountsClient {
+	return c.client("connected_accounts", func() interface{} { return account_link.NewConnectedAccountsClient(c.wrapper) }).(account_link.ConnectedAccountsClient)
+}
+
+func (c *Client) CompatibleSubnets() account_link.CompatibleSubnetsClient {
+	return c.client("compatible_subnets", func() interface{} { return account_link.NewCompatibleSubnetsClient(c.wrapper) }).(account_link.CompatibleSubnetsClient)
+}
+
+func (c *Client) SddcConnections() account_link.SddcConnectionsClient {
+	return c.client("sddc_connections", func() interface{} { return account_link.NewSddcConnectionsClient(c.wrapper) }).(account_link.SddcConnectionsClient)
+}
+
+func (c *Client) EdrsPolicy() autoscalercluster.EdrsPolicyClient {
+	return c.client("edrs_policy", func() interface{} { return autoscalercluster.NewEdrsPolicyClient(c.wrapper) }).(autoscalercluster.EdrsPolicyClient)
+}
+
+func (c *Client) AutoscalerTasks() autoscalerapi.AutoscalerClient {
+	return c.client("autoscaler_tasks", func() interface{} { return autoscalerapi.NewAutoscalerClient(c.wrapper) }).(autoscalerapi.AutoscalerClient)
+}
+
+// SddcGroups returns a client of the SDDC groups API. Unlike the other clients, it has to be
+// authenticated before use.
+func (c *Client) SddcGroups() *sddcgroup.ClientImpl {
+	return c.client("sddc_groups", func() interface{} { return sddcgroup.NewSddcGroupClient(*c.wrapper) }).(*sddcgroup.ClientImpl)
+}
+
+// Tkg returns a client of the Tanzu Kubernetes Grid API, which the SDK does not cover.
+func (c *Client) Tkg() *tkg.ClientImpl {
+	return c.client("tkg", func() interface{} { return tkg.NewTkgClient(*c.wrapper) }).(*tkg.ClientImpl)
+}
+
+// ClusterConversion returns a client of the API converting the hosts of a cluster to another host
+// instance type, which the SDK does not cover.
+func (c *Client) ClusterConversion() *clusterconversion.ClientImpl {
+	return c.client("cluster_conversion", func() interface{} { return clusterconversion.NewConversionClient(*c.wrapper) }).(*clusterconversion.ClientImpl)
+}
+
+// Inventory returns a client of the inventory API resizing the appliances of an SDDC, which the SDK
+// does not cover.
+func (c *Client) Inventory() *inventory.ClientImpl {
+	return c.client("inventory", func() interface{} { return inventory.NewInventoryClient(*c.wrapper) }).(*inventory.ClientImpl)
+}
+
+func (c *Client) SiteRecovery() draas.SiteRecoveryClient {
+	return c.client("site_recovery", func() interface{} { return draas.NewSiteRecoveryClient(c.wrapper) }).(draas.SiteRecoveryClient)
+}
+
+func (c *Client) SiteRecoverySrmNodes() draas.SiteRecoverySrmNodesClient {
+	return c.client("site_recovery_srm_nodes", func() interface{} { return draas.NewSiteRecoverySrmNodesClient(c.wrapper) }).(draas.SiteRecoverySrmNodesClient)
+}
+
+func (c *Client) SiteRecoveryVersions() draas.SiteRecoveryVersionsClient {
+	return c.client("site_recovery_versions", func() interface{} { return draas.NewSiteRecoveryVersionsClient(c.wrapper) }).(draas.SiteRecoveryVersionsClient)
+}
+
+func (c *Client) DraasTasks() draas.TaskClient {
+	return c.client("draas_tasks", func() interface{} { return draas.NewTaskClient(c.wrapper) }).(draas.TaskClient)
+}
+
+func (c *Client) PublicIps() infra.PublicIpsClient {
+	return c.client("public_ips", func() interface{} { return infra.NewPublicIpsClient(c.wrapper) }).(infra.PublicIpsClient)
+}
+
+func (c *Client) ExternalConfig() external.ConfigClient {
+	return c.client("external_config", func() interface{} { return external.NewConfigClient(c.wrapper) }).(external.ConfigClient)
+}
+
+func (c *Client) DirectConnectBgp() direct_connect.BgpClient {
+	return c.client("direct_connect_bgp", func() interface{} { return direct_connect.NewBgpClient(c.wrapper) }).(direct_connect.BgpClient)
+}
+
+func (c *Client) DirectConnectAdvertisedRoutes() directconnectroutes.AdvertisedClient {
+	return c.client("direct_connect_advertised_routes", func() interface{} { return directconnectroutes.NewAdvertisedClient(c.wrapper) }).(directconnectroutes.AdvertisedClient)
+}
+
+// ForNsx returns a Client for the NSX manager behind the provided NSX reverse proxy URL.
+func (c *Client) ForNsx(nsxtReverseProxyURL string) (*Client, error) {
+	if len(nsxtReverseProxyURL) == 0 {
+		return nil, fmt.Errorf("NSX reverse proxy url is required for public IP resource creation")
+	}
+	c.mutex.Lock()
+	nsxClient, ok := c.nsx[nsxtReverseProxyURL]
+	c.mutex.Unlock()
+	if ok {
+		return nsxClient, nil
+	}
+	copyWrapper := connector.CopyWrapper(*c.wrapper)
+	// The wrapper uses the VmcURL as service URL, so setting it to the NSX URL will
+	// force authentication against the NSX instance
+	copyWrapper.VmcURL = strings.Replace(nsxtReverseProxyURL, constants.SksNSXTManager, "", -1)
+	err := copyWrapper.Authenticate()
+	if err != nil {
+		return nil, err
+	}
+	nsxClient = NewClient(copyWrapper)
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.nsx[nsxtReverseProxyURL] = nsxClient
+	return nsxClient, nil
+}
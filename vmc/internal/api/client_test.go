@@ -0,0 +1,33 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package api
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func newTestClient(t *testing.T) (*simulator.Server, *Client) {
+	server := simulator.NewServer()
+	t.Cleanup(server.Close)
+	wrapper, err := server.Wrapper()
+	if err != nil {
+		t.Fatalf("error authenticating against simulator: %s", err)
+	}
+	return server, NewClient(wrapper)
+}
+
+func TestClientCachesClients(t *testing.T) {
+	_, apiClient := newTestClient(t)
+	assert.Same(t, apiClient.SddcGroups(), apiClient.SddcGroups())
+	assert.Equal(t, apiClient.Sddcs(), apiClient.Sddcs())
+}
+
+func TestForNsxRequiresURL(t *testing.T) {
+	_, apiClient := newTestClient(t)
+	_, err := apiClient.ForNsx("")
+	assert.Error(t, err)
+}
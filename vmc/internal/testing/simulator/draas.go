@@ -0,0 +1,246 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package simulator
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
+)
+
+// SrmVersion the version reported for all simulated SRM nodes.
+const SrmVersion = "8.8.0.1-22375117"
+
+// siteRecoveryState the simulated DRaaS state of an SDDC.
+type siteRecoveryState struct {
+	siteRecovery draasmodel.SiteRecovery
+}
+
+// SiteRecoveryState returns the site recovery state of the SDDC with the specified ID,
+// or an empty string if site recovery has never been activated on it.
+func (server *Server) SiteRecoveryState(sddcID string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.siteRecoveries[sddcID]
+	if !ok {
+		return ""
+	}
+	return *simulated.siteRecovery.SiteRecoveryState
+}
+
+// SrmNodeCount returns the amount of SRM nodes on the SDDC with the specified ID.
+func (server *Server) SrmNodeCount(sddcID string) int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.siteRecoveries[sddcID]
+	if !ok {
+		return 0
+	}
+	return len(simulated.siteRecovery.SrmNodes)
+}
+
+// StartSiteRecoveryActivation simulates a site recovery activation with the provided SRM extension
+// key suffix on the SDDC with the specified ID, started outside the provider. Returns the ID of the
+// activation task.
+func (server *Server) StartSiteRecoveryActivation(sddcID string, extensionKeySuffix string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.activateSiteRecovery(sddcID, extensionKeySuffix).id
+}
+
+func newSrmNode(hostname string, extensionKeySuffix string) draasmodel.SrmNode {
+	srmNode := draasmodel.SrmNode{
+		Id:        strPtr(newID()),
+		Hostname:  strPtr(hostname),
+		IpAddress: strPtr("10.2.224.10"),
+		State:     strPtr(draasmodel.SiteRecoveryNode_STATE_DEPLOYING),
+		Type_:     strPtr(draasmodel.SiteRecoveryNode_TYPE_SRM),
+	}
+	if len(extensionKeySuffix) > 0 {
+		srmNode.SrmExtensionKeySuffix = strPtr(extensionKeySuffix)
+		srmNode.SrmExtensionKey = strPtr("com.vmware.vcDr-" + extensionKeySuffix)
+	}
+	return srmNode
+}
+
+func srmHostname(extensionKeySuffix string, sddcID string) string {
+	return constants.SrmPrefix + extensionKeySuffix + constants.SddcSuffix + sddcID + ".vmc.local"
+}
+
+// activateSiteRecovery must be called while holding the server mutex.
+func (server *Server) activateSiteRecovery(sddcID string, extensionKeySuffix string) *simulatedTask {
+	now := time.Now().UTC()
+	hostnameSuffix := extensionKeySuffix
+	if len(hostnameSuffix) == 0 {
+		hostnameSuffix = "default"
+	}
+	// The details of the nodes are only known, once they are deployed
+	simulated := &siteRecoveryState{
+		siteRecovery: draasmodel.SiteRecovery{
+			Created:           now,
+			Updated:           now,
+			Id:                sddcID,
+			SddcId:            strPtr(sddcID),
+			SiteRecoveryState: strPtr(draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATING),
+			DraasH5Url:        strPtr("https://vcenter.sddc.vmc.local/dr"),
+			SrmNodes: []draasmodel.SrmNode{{
+				Id:    strPtr(newID()),
+				State: strPtr(draasmodel.SiteRecoveryNode_STATE_DEPLOYING),
+				Type_: strPtr(draasmodel.SiteRecoveryNode_TYPE_SRM),
+			}},
+		},
+	}
+	server.siteRecoveries[sddcID] = simulated
+	return server.startTask("SITE_RECOVERY_ACTIVATE", sddcID, func() {
+		simulated.siteRecovery.SiteRecoveryState = strPtr(draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED)
+		srmNode := newSrmNode(srmHostname(hostnameSuffix, sddcID), extensionKeySuffix)
+		srmNode.Id = simulated.siteRecovery.SrmNodes[0].Id
+		srmNode.State = strPtr(draasmodel.SiteRecoveryNode_STATE_READY)
+		simulated.siteRecovery.SrmNodes[0] = srmNode
+		simulated.siteRecovery.VrNode = &draasmodel.SiteRecoveryNode{
+			Id:        strPtr(newID()),
+			Hostname:  strPtr("vr." + sddcID + ".vmc.local"),
+			IpAddress: strPtr("10.2.224.11"),
+			State:     strPtr(draasmodel.SiteRecoveryNode_STATE_READY),
+			Type_:     strPtr(draasmodel.SiteRecoveryNode_TYPE_VRMS),
+		}
+	})
+}
+
+// inProgressDraasTask returns the started task acting on the site recovery or on any of the
+// nodes of an SDDC, if there is one. DRaaS rejects node operations while such a task runs.
+// Must be called while holding the server mutex.
+func (server *Server) inProgressDraasTask(sddcID string, simulated *siteRecoveryState) *simulatedTask {
+	resourceIDs := map[string]bool{sddcID: true}
+	for _, srmNode := range simulated.siteRecovery.SrmNodes {
+		resourceIDs[*srmNode.Id] = true
+	}
+	for _, simulatedTask := range server.tasks {
+		if simulatedTask.status == statusStarted && resourceIDs[simulatedTask.resourceID] {
+			return simulatedTask
+		}
+	}
+	return nil
+}
+
+func (server *Server) registerDraasRoutes() {
+	siteRecoveryPath := "/vmc/draas/api/orgs/([^/]+)/sddcs/([^/]+)/site-recovery"
+	server.handle(http.MethodGet, siteRecoveryPath, func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.siteRecoveries[params[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "site recovery not found for SDDC "+params[1])
+			return
+		}
+		writeModel(w, simulated.siteRecovery, draasmodel.SiteRecoveryBindingType())
+	})
+	server.handle(http.MethodGet, siteRecoveryPath+"/versions", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.siteRecoveries[params[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "site recovery not found for SDDC "+params[1])
+			return
+		}
+		now := time.Now().UTC()
+		versions := draasmodel.SiteRecoveryVersions{Generated: &now, SddcId: strPtr(params[1])}
+		for _, srmNode := range simulated.siteRecovery.SrmNodes {
+			versions.NodeVersions = append(versions.NodeVersions, draasmodel.SiteRecoveryNodeVersion{
+				NodeId:      srmNode.Id,
+				NodeIp:      srmNode.IpAddress,
+				NodeType:    strPtr(draasmodel.SiteRecoveryNodeVersion_NODE_TYPE_SRM),
+				FullVersion: strPtr(SrmVersion),
+			})
+		}
+		writeModel(w, versions, draasmodel.SiteRecoveryVersionsBindingType())
+	})
+	server.handle(http.MethodPost, siteRecoveryPath, func(w http.ResponseWriter, r *http.Request, params []string) {
+		sddcID := params[1]
+		if _, ok := server.getSddc(w, sddcID); !ok {
+			return
+		}
+		if simulated, ok := server.siteRecoveries[sddcID]; ok &&
+			*simulated.siteRecovery.SiteRecoveryState != draasmodel.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATED {
+			writeError(w, http.StatusBadRequest, "site recovery is "+*simulated.siteRecovery.SiteRecoveryState)
+			return
+		}
+		body := readBody(r)
+		activationTask := server.activateSiteRecovery(sddcID, stringField(body, "srm_extension_key_suffix"))
+		server.writeDraasTask(w, activationTask)
+	})
+	server.handle(http.MethodDelete, siteRecoveryPath, func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.siteRecoveries[params[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "site recovery not found for SDDC "+params[1])
+			return
+		}
+		simulated.siteRecovery.SiteRecoveryState = strPtr(draasmodel.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATING)
+		deactivationTask := server.startTask("SITE_RECOVERY_DEACTIVATE", params[1], func() {
+			simulated.siteRecovery.SiteRecoveryState = strPtr(draasmodel.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATED)
+			simulated.siteRecovery.SrmNodes = nil
+		})
+		server.writeDraasTask(w, deactivationTask)
+	})
+	server.handle(http.MethodPost, siteRecoveryPath+"/srm-nodes", func(w http.ResponseWriter, r *http.Request, params []string) {
+		sddcID := params[1]
+		simulated, ok := server.siteRecoveries[sddcID]
+		if !ok || *simulated.siteRecovery.SiteRecoveryState != draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED {
+			writeError(w, http.StatusBadRequest, "site recovery is not activated for SDDC "+sddcID)
+			return
+		}
+		if inProgressTask := server.inProgressDraasTask(sddcID, simulated); inProgressTask != nil {
+			writeError(w, http.StatusBadRequest, "Another task ("+inProgressTask.taskType+") is in progress for SDDC "+sddcID)
+			return
+		}
+		if len(simulated.siteRecovery.SrmNodes) > constants.MaxAdditionalSrmNodes {
+			writeError(w, http.StatusBadRequest, "Maximum number of SRM nodes reached for SDDC "+sddcID)
+			return
+		}
+		body := readBody(r)
+		extensionKeySuffix := stringField(body, "srm_extension_key_suffix")
+		srmNode := newSrmNode(srmHostname(extensionKeySuffix, sddcID), extensionKeySuffix)
+		simulated.siteRecovery.SrmNodes = append(simulated.siteRecovery.SrmNodes, srmNode)
+		srmNodeID := *srmNode.Id
+		nodeTask := server.startTask("SRM_NODE_PROVISION", srmNodeID, func() {
+			for i := range simulated.siteRecovery.SrmNodes {
+				if *simulated.siteRecovery.SrmNodes[i].Id == srmNodeID {
+					simulated.siteRecovery.SrmNodes[i].State = strPtr(draasmodel.SiteRecoveryNode_STATE_READY)
+				}
+			}
+		})
+		server.writeDraasTask(w, nodeTask)
+	})
+	server.handle(http.MethodDelete, siteRecoveryPath+"/srm-nodes/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		sddcID := params[1]
+		srmNodeID := params[2]
+		simulated, ok := server.siteRecoveries[sddcID]
+		if !ok {
+			writeError(w, http.StatusNotFound, "site recovery not found for SDDC "+sddcID)
+			return
+		}
+		found := false
+		for _, srmNode := range simulated.siteRecovery.SrmNodes {
+			if *srmNode.Id == srmNodeID {
+				found = true
+			}
+		}
+		if !found {
+			writeError(w, http.StatusNotFound, "SRM node "+srmNodeID+" not found")
+			return
+		}
+		if inProgressTask := server.inProgressDraasTask(sddcID, simulated); inProgressTask != nil {
+			writeError(w, http.StatusBadRequest, "Another task ("+inProgressTask.taskType+") is in progress for SDDC "+sddcID)
+			return
+		}
+		nodeTask := server.startTask("SRM_NODE_DELETE", srmNodeID, func() {
+			var remaining []draasmodel.SrmNode
+			for _, srmNode := range simulated.siteRecovery.SrmNodes {
+				if *srmNode.Id != srmNodeID {
+					remaining = append(remaining, srmNode)
+				}
+			}
+			simulated.siteRecovery.SrmNodes = remaining
+		})
+		server.writeDraasTask(w, nodeTask)
+	})
+}
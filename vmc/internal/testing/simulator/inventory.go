@@ -0,0 +1,44 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package simulator
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func (server *Server) registerInventoryRoutes() {
+	server.handle(http.MethodPost, "/api/inventory/([^/]+)/vmc-aws/operations", func(w http.ResponseWriter, r *http.Request, params []string) {
+		body := readBody(r)
+		if stringField(body, "type") != "UPSIZE" {
+			writeError(w, http.StatusBadRequest, "unsupported operation "+stringField(body, "type"))
+			return
+		}
+		config, _ := body["config"].(map[string]interface{})
+		size, _ := config["size"].(map[string]interface{})
+		simulated, ok := server.getSddc(w, stringField(config, "sddc_id"))
+		if !ok {
+			return
+		}
+		vcSize := stringField(size, "vc_size")
+		nsxSize := stringField(size, "nsx_size")
+		currentSize := simulated.sddc.ResourceConfig.SddcSize
+		if currentSize != nil && currentSize.VcSize != nil && *currentSize.VcSize == vcSize &&
+			currentSize.NsxSize != nil && *currentSize.NsxSize == nsxSize {
+			writeError(w, http.StatusBadRequest, "SDDC "+simulated.sddc.Id+" already has "+vcSize+" appliances")
+			return
+		}
+		upsizeTask := server.startTask(constants.SddcUpsizeTaskType, simulated.sddc.Id, func() {
+			simulated.sddc.ResourceConfig.SddcSize = &model.SddcSize{
+				VcSize:  strPtr(vcSize),
+				NsxSize: strPtr(nsxSize),
+				Size:    strPtr(strings.ToUpper(vcSize)),
+			}
+		})
+		server.writeVmcTask(w, upsizeTask)
+	})
+}
@@ -0,0 +1,193 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package simulator
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+
+	nsxmodel "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
+)
+
+// PublicIPCount returns the amount of public IPs allocated on the SDDC with the specified ID.
+func (server *Server) PublicIPCount(sddcID string) int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcs[sddcID]
+	if !ok {
+		return 0
+	}
+	return len(simulated.publicIPs)
+}
+
+// IntranetMtu returns the intranet uplink MTU of the SDDC with the specified ID.
+func (server *Server) IntranetMtu(sddcID string) int64 {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcs[sddcID]
+	if !ok {
+		return 0
+	}
+	return simulated.intranetMtu
+}
+
+// AddDirectConnect attaches Direct Connect to the SDDC with the specified ID, which
+// advertises the provided prefixes to the on-premise datacenter.
+func (server *Server) AddDirectConnect(sddcID string, bgpAsn string, advertisedPrefixes []string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if simulated, ok := server.sddcs[sddcID]; ok {
+		simulated.directConnect = &directConnectState{
+			bgpAsn:             bgpAsn,
+			advertisedPrefixes: advertisedPrefixes,
+		}
+	}
+}
+
+// registerNsxRoutes registers the NSX manager APIs, served under the NSX reverse proxy URL
+// of each simulated SDDC.
+func (server *Server) registerNsxRoutes() {
+	nsxPath := "/orgs/([^/]+)/sddcs/([^/]+)/cloud-service/api/v1/infra"
+	server.handle(http.MethodGet, nsxPath+"/public-ips", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		publicIPIDs := make([]string, 0, len(simulated.publicIPs))
+		for publicIPID := range simulated.publicIPs {
+			publicIPIDs = append(publicIPIDs, publicIPID)
+		}
+		sort.Strings(publicIPIDs)
+		// The cursor is the index of the first public IP of the page
+		start, _ := strconv.Atoi(r.URL.Query().Get("cursor"))
+		end := len(publicIPIDs)
+		if server.PublicIPsPageSize > 0 && start+server.PublicIPsPageSize < end {
+			end = start + server.PublicIPsPageSize
+		}
+		results := []nsxmodel.PublicIp{}
+		for _, publicIPID := range publicIPIDs[start:end] {
+			results = append(results, *simulated.publicIPs[publicIPID])
+		}
+		listResult := nsxmodel.PublicIpsListResult{
+			Results:     results,
+			ResultCount: int64Ptr(int64(len(publicIPIDs))),
+		}
+		if end < len(publicIPIDs) {
+			listResult.Cursor = strPtr(strconv.Itoa(end))
+		}
+		writeModel(w, listResult, nsxmodel.PublicIpsListResultBindingType())
+	})
+	server.handle(http.MethodGet, nsxPath+"/public-ips/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		publicIP, ok := simulated.publicIPs[params[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "public IP "+params[2]+" not found")
+			return
+		}
+		writeModel(w, *publicIP, nsxmodel.PublicIpBindingType())
+	})
+	server.handle(http.MethodPut, nsxPath+"/public-ips/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		publicIPID := params[2]
+		body := readBody(r)
+		publicIP, ok := simulated.publicIPs[publicIPID]
+		if !ok {
+			publicIP = &nsxmodel.PublicIp{
+				Id: strPtr(publicIPID),
+				Ip: strPtr(fmt.Sprintf("52.10.0.%d", len(simulated.publicIPs)+1)),
+			}
+			simulated.publicIPs[publicIPID] = publicIP
+		}
+		publicIP.DisplayName = strPtr(stringField(body, "display_name"))
+		writeModel(w, *publicIP, nsxmodel.PublicIpBindingType())
+	})
+	server.handle(http.MethodDelete, nsxPath+"/public-ips/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		if _, ok := simulated.publicIPs[params[2]]; !ok {
+			writeError(w, http.StatusNotFound, "public IP "+params[2]+" not found")
+			return
+		}
+		delete(simulated.publicIPs, params[2])
+		w.WriteHeader(http.StatusOK)
+	})
+	server.handle(http.MethodGet, nsxPath+"/external/config", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		// Like VMware Cloud on AWS, ZEROCLOUD SDDCs have no intranet uplink
+		if simulated.zeroCloud() {
+			writeError(w, http.StatusBadRequest, "external connectivity configuration is not supported for ZEROCLOUD SDDCs")
+			return
+		}
+		writeModel(w, nsxmodel.ExternalConnectivityConfig{
+			IntranetMtu: int64Ptr(simulated.intranetMtu),
+		}, nsxmodel.ExternalConnectivityConfigBindingType())
+	})
+	server.handle(http.MethodGet, nsxPath+"/direct-connect/bgp", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		if simulated.directConnect == nil {
+			writeError(w, http.StatusNotFound, "Direct Connect is not configured")
+			return
+		}
+		writeModel(w, nsxmodel.DirectConnectBgpInfo{
+			LocalAsNum:      strPtr(simulated.directConnect.bgpAsn),
+			RoutePreference: strPtr(nsxmodel.DirectConnectBgpInfo_ROUTE_PREFERENCE_VPN_PREFERRED_OVER_DIRECT_CONNECT),
+		}, nsxmodel.DirectConnectBgpInfoBindingType())
+	})
+	server.handle(http.MethodGet, nsxPath+"/direct-connect/routes/advertised", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		if simulated.directConnect == nil {
+			writeError(w, http.StatusNotFound, "Direct Connect is not configured")
+			return
+		}
+		routes := []nsxmodel.AdvertisedRoute{}
+		for _, prefix := range simulated.directConnect.advertisedPrefixes {
+			routes = append(routes, nsxmodel.AdvertisedRoute{
+				AddressFamily:      strPtr(nsxmodel.AdvertisedRoute_ADDRESS_FAMILY_IPV4),
+				AdvertisementState: strPtr(nsxmodel.AdvertisedRoute_ADVERTISEMENT_STATE_SUCCESS),
+				Cidr:               strPtr(prefix),
+			})
+		}
+		writeModel(w, nsxmodel.BGPAdvertisedRoutes{
+			AdvertisedRoutes:       routes,
+			FailedAdvertisedRoutes: int64Ptr(0),
+		}, nsxmodel.BGPAdvertisedRoutesBindingType())
+	})
+	server.handle(http.MethodPut, nsxPath+"/external/config", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		// Like VMware Cloud on AWS, ZEROCLOUD SDDCs have no intranet uplink
+		if simulated.zeroCloud() {
+			writeError(w, http.StatusBadRequest, "external connectivity configuration is not supported for ZEROCLOUD SDDCs")
+			return
+		}
+		body := readBody(r)
+		if mtu := intField(body, "intranet_mtu"); mtu > 0 {
+			simulated.intranetMtu = mtu
+		}
+		writeModel(w, nsxmodel.ExternalConnectivityConfig{
+			IntranetMtu: int64Ptr(simulated.intranetMtu),
+		}, nsxmodel.ExternalConnectivityConfigBindingType())
+	})
+}
@@ -0,0 +1,379 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package simulator
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/sddcgroup"
+)
+
+const (
+	sddcGroupCreateTaskType        = "CREATE_GROUP_NETWORK_CONNECTIVITY"
+	sddcGroupUpdateMembersTaskType = "UPDATE_MEMBERS"
+	sddcGroupUpdateAttachmentsType = "UPDATE_EXTERNAL_ATTACHMENTS"
+	sddcGroupDeleteTaskType        = "DELETE_DEPLOYMENT_GROUP"
+)
+
+// sddcGroupState an SDDC group, together with its network connectivity config. The members
+// of the group are kept in the order they were added, with the realized state of their connectivity.
+type sddcGroupState struct {
+	group        sddcgroup.DeploymentGroup
+	configID     string
+	memberStates map[string]string
+	accounts     []sddcgroup.AwsAccount
+	externalTgws []sddcgroup.CustomerTransitGatewayAssociation
+}
+
+// AddSddcGroupVpcAttachment simulates the attachment of a VPC in an AWS account to the transit
+// gateway of an SDDC group, created in AWS and pending acceptance, and returns its ID.
+func (server *Server) AddSddcGroupVpcAttachment(groupID string, accountNumber string, vpcID string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcGroups[groupID]
+	if !ok {
+		return ""
+	}
+	attachment := sddcgroup.AccountAttachment{
+		VpcID:        vpcID,
+		State:        sddcgroup.AttachmentStatePendingAcceptance,
+		AttachmentID: "tgw-attach-" + strings.ReplaceAll(newID(), "-", "")[:17],
+	}
+	for i := range simulated.accounts {
+		if simulated.accounts[i].AccountNumber == accountNumber {
+			simulated.accounts[i].AccountAttachments = append(simulated.accounts[i].AccountAttachments, attachment)
+			return attachment.AttachmentID
+		}
+	}
+	simulated.accounts = append(simulated.accounts, sddcgroup.AwsAccount{
+		AccountNumber:      accountNumber,
+		RAMShareID:         "share-" + accountNumber,
+		Status:             sddcgroup.AttachmentStateConnected,
+		AccountAttachments: []sddcgroup.AccountAttachment{attachment},
+	})
+	return attachment.AttachmentID
+}
+
+func (simulated *sddcGroupState) vpcAttachment(attachmentID string) *sddcgroup.AccountAttachment {
+	for i := range simulated.accounts {
+		for j := range simulated.accounts[i].AccountAttachments {
+			if simulated.accounts[i].AccountAttachments[j].AttachmentID == attachmentID {
+				return &simulated.accounts[i].AccountAttachments[j]
+			}
+		}
+	}
+	return nil
+}
+
+func (simulated *sddcGroupState) removeVpcAttachment(attachmentID string) {
+	for i := range simulated.accounts {
+		var attachments []sddcgroup.AccountAttachment
+		for _, attachment := range simulated.accounts[i].AccountAttachments {
+			if attachment.AttachmentID != attachmentID {
+				attachments = append(attachments, attachment)
+			}
+		}
+		simulated.accounts[i].AccountAttachments = attachments
+	}
+}
+
+func (simulated *sddcGroupState) externalTgwIndex(tgwID string) int {
+	for i, association := range simulated.externalTgws {
+		if association.TgwID == tgwID {
+			return i
+		}
+	}
+	return -1
+}
+
+// SetSddcGroupMemberState overrides the realized connectivity state of a member of an SDDC group,
+// e.g. to simulate an SDDC, that got disconnected outside of the provider.
+func (server *Server) SetSddcGroupMemberState(groupID string, sddcID string, state string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if simulated, ok := server.sddcGroups[groupID]; ok {
+		simulated.memberStates[sddcID] = state
+	}
+}
+
+func (simulated *sddcGroupState) addMembers(sddcIDs []string, state string) {
+	for _, sddcID := range sddcIDs {
+		if _, ok := simulated.memberStates[sddcID]; !ok {
+			simulated.group.Membership.Included = append(simulated.group.Membership.Included,
+				sddcgroup.GroupMember{ID: sddcID})
+		}
+		simulated.memberStates[sddcID] = state
+	}
+}
+
+func (simulated *sddcGroupState) removeMembers(sddcIDs []string) {
+	for _, sddcID := range sddcIDs {
+		delete(simulated.memberStates, sddcID)
+		var included []sddcgroup.GroupMember
+		for _, member := range simulated.group.Membership.Included {
+			if member.ID != sddcID {
+				included = append(included, member)
+			}
+		}
+		simulated.group.Membership.Included = included
+	}
+}
+
+func (simulated *sddcGroupState) networkConnectivityConfig() sddcgroup.NetworkConnectivityConfig {
+	realized := &sddcgroup.AwsRealizedSddcConnectivityTrait{}
+	for _, member := range simulated.group.Membership.Included {
+		realized.Sddcs = append(realized.Sddcs, sddcgroup.SddcConnectivity{
+			SddcID: member.ID,
+			State:  simulated.memberStates[member.ID],
+		})
+	}
+	traits := &sddcgroup.Traits{RealizedSddcs: realized}
+	if len(simulated.accounts) > 0 {
+		traits.AwsInfo = &sddcgroup.AwsVpcAttachmentsTrait{Accounts: simulated.accounts}
+	}
+	if len(simulated.externalTgws) > 0 {
+		traits.ExternalTgw = &sddcgroup.AwsCustomerTransitGatewayAssociationsTrait{
+			CustomerTransitGatewayAssociations: simulated.externalTgws,
+		}
+	}
+	return sddcgroup.NetworkConnectivityConfig{
+		ID:      simulated.configID,
+		GroupID: simulated.group.ID,
+		Name:    simulated.group.Name,
+		NetworkConnectivityConfigState: sddcgroup.NetworkConnectivityConfigState{
+			Name: sddcgroup.SddcMemberStateConnected,
+		},
+		Traits: traits,
+	}
+}
+
+// memberIDs returns the IDs of the SDDCs in a list of members of a request body.
+func memberIDs(body map[string]interface{}, name string) []string {
+	var ids []string
+	members, _ := body[name].([]interface{})
+	for _, member := range members {
+		if memberMap, ok := member.(map[string]interface{}); ok {
+			ids = append(ids, stringField(memberMap, "id"))
+		}
+	}
+	return ids
+}
+
+// unknownSddcs returns the IDs of the provided SDDCs, that do not exist.
+func (server *Server) unknownSddcs(sddcIDs []string) []string {
+	var unknown []string
+	for _, sddcID := range sddcIDs {
+		if _, ok := server.sddcs[sddcID]; !ok {
+			unknown = append(unknown, sddcID)
+		}
+	}
+	return unknown
+}
+
+func writeValidationError(w http.ResponseWriter, message string, members []string) {
+	writeJSON(w, http.StatusConflict, sddcgroup.ValidationErrorResponse{
+		Status:  http.StatusConflict,
+		Message: "validation failed",
+		Details: []sddcgroup.Details{{
+			ValidationErrorMessage: message,
+			Members:                members,
+		}},
+	})
+}
+
+// decodeField decodes a field of a request body into the provided value, writing a Bad Request
+// response if it does not match.
+func decodeField(w http.ResponseWriter, body map[string]interface{}, name string, value interface{}) bool {
+	raw, _ := json.Marshal(body[name])
+	if err := json.Unmarshal(raw, value); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid "+name+": "+err.Error())
+		return false
+	}
+	return true
+}
+
+func (server *Server) configByID(w http.ResponseWriter, configID string) (*sddcGroupState, bool) {
+	for _, simulated := range server.sddcGroups {
+		if simulated.configID == configID {
+			return simulated, true
+		}
+	}
+	writeError(w, http.StatusNotFound, "network connectivity config "+configID+" not found")
+	return nil, false
+}
+
+func (server *Server) registerSddcGroupRoutes() {
+	const configsPath = "/api/network/([^/]+)/core/network-connectivity-configs"
+	server.handle(http.MethodPost, configsPath+"/validate-members", func(w http.ResponseWriter, r *http.Request, params []string) {
+		if unknown := server.unknownSddcs(memberIDs(readBody(r), "members")); len(unknown) > 0 {
+			writeValidationError(w, "SDDCs do not exist.", unknown)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	})
+	server.handle(http.MethodPost, configsPath+"/create-group-network-connectivity", func(w http.ResponseWriter, r *http.Request, params []string) {
+		body := readBody(r)
+		sddcIDs := memberIDs(body, "members")
+		if unknown := server.unknownSddcs(sddcIDs); len(unknown) > 0 {
+			writeValidationError(w, "SDDCs do not exist.", unknown)
+			return
+		}
+		simulated := &sddcGroupState{
+			group: sddcgroup.DeploymentGroup{
+				ID:          newID(),
+				Name:        stringField(body, "name"),
+				Description: stringField(body, "description"),
+				OrgID:       params[0],
+				Creator:     sddcgroup.Creator{UserName: "simulated-user"},
+			},
+			configID:     newID(),
+			memberStates: map[string]string{},
+		}
+		simulated.addMembers(sddcIDs, sddcgroup.SddcMemberStateConnecting)
+		server.sddcGroups[simulated.group.ID] = simulated
+		createTask := server.startTask(sddcGroupCreateTaskType, simulated.configID, func() {
+			simulated.addMembers(sddcIDs, sddcgroup.SddcMemberStateConnected)
+		})
+		writeJSON(w, http.StatusOK, sddcgroup.CreateGroupNetworkConnectivityResponse{
+			ConfigID: simulated.configID,
+			GroupID:  simulated.group.ID,
+			TaskID:   createTask.id,
+		})
+	})
+	server.handle(http.MethodGet, configsPath, func(w http.ResponseWriter, r *http.Request, params []string) {
+		configs := []sddcgroup.NetworkConnectivityConfig{}
+		if !r.URL.Query().Has("group_id") {
+			for _, simulated := range server.sddcGroups {
+				if simulated.group.OrgID == params[0] && !simulated.group.Deleted {
+					configs = append(configs, simulated.networkConnectivityConfig())
+				}
+			}
+		} else if simulated, ok := server.sddcGroups[r.URL.Query().Get("group_id")]; ok {
+			configs = append(configs, simulated.networkConnectivityConfig())
+		}
+		writeJSON(w, http.StatusOK, configs)
+	})
+	server.handle(http.MethodGet, configsPath+"/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.configByID(w, params[1])
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, simulated.networkConnectivityConfig())
+	})
+	server.handle(http.MethodGet, "/api/inventory/([^/]+)/core/deployment-groups/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.sddcGroups[params[1]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "deployment group "+params[1]+" not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, simulated.group)
+	})
+	server.handle(http.MethodPost, "/api/network/([^/]+)/aws/operations", func(w http.ResponseWriter, r *http.Request, params []string) {
+		body := readBody(r)
+		simulated, ok := server.configByID(w, stringField(body, "resource_id"))
+		if !ok {
+			return
+		}
+		config, _ := body["config"].(map[string]interface{})
+		var operationTask *simulatedTask
+		switch stringField(body, "type") {
+		case sddcgroup.UpdateMembersNetworkOperationType:
+			addedIDs := memberIDs(config, "add_members")
+			removedIDs := memberIDs(config, "remove_members")
+			if unknown := server.unknownSddcs(addedIDs); len(unknown) > 0 {
+				writeValidationError(w, "SDDCs do not exist.", unknown)
+				return
+			}
+			simulated.addMembers(addedIDs, sddcgroup.SddcMemberStateConnecting)
+			for _, sddcID := range removedIDs {
+				if _, ok := simulated.memberStates[sddcID]; ok {
+					simulated.memberStates[sddcID] = sddcgroup.SddcMemberStateDisconnecting
+				}
+			}
+			operationTask = server.startTask(sddcGroupUpdateMembersTaskType, simulated.configID, func() {
+				simulated.addMembers(addedIDs, sddcgroup.SddcMemberStateConnected)
+				simulated.removeMembers(removedIDs)
+			})
+		case sddcgroup.UpdateVpcAttachmentsNetworkOperationType:
+			var actions []sddcgroup.VpcAttachmentAction
+			if !decodeField(w, config, "attachments", &actions) {
+				return
+			}
+			for _, action := range actions {
+				attachment := simulated.vpcAttachment(action.AttachmentID)
+				if attachment == nil {
+					writeError(w, http.StatusNotFound, "VPC attachment "+action.AttachmentID+" not found")
+					return
+				}
+				if action.Action == sddcgroup.AttachmentActionAccept &&
+					attachment.State != sddcgroup.AttachmentStatePendingAcceptance {
+					writeValidationError(w, "VPC attachment "+action.AttachmentID+" is not pending acceptance.", nil)
+					return
+				}
+			}
+			operationTask = server.startTask(sddcGroupUpdateAttachmentsType, simulated.configID, func() {
+				for _, action := range actions {
+					if action.Action == sddcgroup.AttachmentActionDelete {
+						simulated.removeVpcAttachment(action.AttachmentID)
+						continue
+					}
+					attachment := simulated.vpcAttachment(action.AttachmentID)
+					attachment.State = sddcgroup.AttachmentStateConnected
+					attachment.StaticRoutes = action.ConfiguredPrefixes
+				}
+			})
+		case sddcgroup.UpdateCustomerTransitGatewayAssociationsNetworkOperationType:
+			var actions []sddcgroup.CustomerTransitGatewayAssociationAction
+			if !decodeField(w, config, "customer_transit_gateway_associations", &actions) {
+				return
+			}
+			for _, action := range actions {
+				associated := simulated.externalTgwIndex(action.TgwID) >= 0
+				if associated == (action.Action == sddcgroup.AttachmentActionAssociate) {
+					writeValidationError(w, "unexpected "+action.Action+" of transit gateway "+action.TgwID+".", nil)
+					return
+				}
+			}
+			operationTask = server.startTask(sddcGroupUpdateAttachmentsType, simulated.configID, func() {
+				for _, action := range actions {
+					index := simulated.externalTgwIndex(action.TgwID)
+					switch action.Action {
+					case sddcgroup.AttachmentActionAssociate:
+						association := action.CustomerTransitGatewayAssociation
+						association.Status = sddcgroup.AttachmentStateConnected
+						simulated.externalTgws = append(simulated.externalTgws, association)
+					case sddcgroup.AttachmentActionDelete:
+						simulated.externalTgws = append(simulated.externalTgws[:index], simulated.externalTgws[index+1:]...)
+					default:
+						simulated.externalTgws[index].PeeringRegions = action.PeeringRegions
+					}
+				}
+			})
+		case sddcgroup.DeleteSddcGroupNetworkOperationType:
+			if len(simulated.group.Membership.Included) > 0 {
+				writeError(w, http.StatusBadRequest, "the SDDC group still has members")
+				return
+			}
+			operationTask = server.startTask(sddcGroupDeleteTaskType, simulated.configID, func() {
+				simulated.group.Deleted = true
+			})
+		default:
+			writeError(w, http.StatusBadRequest, "unsupported network operation "+stringField(body, "type"))
+			return
+		}
+		writeJSON(w, http.StatusOK, sddcgroup.NetworkOperation{
+			ID:           operationTask.id,
+			OrgID:        params[0],
+			ResourceID:   simulated.configID,
+			ResourceType: sddcgroup.NetworkConnectivityConfigResourceType,
+			Type:         stringField(body, "type"),
+			Config: sddcgroup.Config{
+				Type:        stringField(config, "type"),
+				OperationID: operationTask.id,
+			},
+		})
+	})
+}
@@ -0,0 +1,307 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+// Package simulator provides a lightweight, in-memory simulation of the VMC, DRaaS, autoscaler,
+// NSX and CSP APIs used by the provider. It is intended for exercising the resources and data
+// sources in unit tests, without depending on the availability of a live organization.
+package simulator
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/bindings"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data/serializers/cleanjson"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+// TestOrgID the ID of the organization served by the simulator.
+const TestOrgID = "1b2c3d4e-5f60-4718-8a9b-0c1d2e3f4a5b"
+
+// TestAccessToken the prefix of the access tokens handed out by the simulated Cloud Service Provider.
+const TestAccessToken = "simulated-access-token"
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	handler func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+type injectedError struct {
+	method     string
+	path       string
+	statusCode int
+	// remaining the amount of requests still failing, the error is permanent when negative
+	remaining int
+	// retryAfter the value of the Retry-After header of the responses, if not empty
+	retryAfter string
+}
+
+// Server an httptest.Server, that keeps the state of the simulated APIs in memory.
+type Server struct {
+	*httptest.Server
+	// TaskPollsUntilFinished the amount of times a task has to be polled before it
+	// reaches a terminal state.
+	TaskPollsUntilFinished int
+	// TaskFailureMessage when not empty, tasks reach the FAILED state with this error
+	// message instead of finishing successfully.
+	TaskFailureMessage string
+	// PublicIPsPageSize the maximum amount of public IPs listed per page, all of them are
+	// listed on a single page if it is 0.
+	PublicIPsPageSize int
+
+	mutex sync.Mutex
+	// tokenGeneration is incremented by RevokeAccessTokens, rejecting the previously issued tokens
+	tokenGeneration int
+	issuedTokens    int
+	routes          []route
+	injectedErrors  []*injectedError
+	requests        []string
+	orgs            map[string]*model.Organization
+	sddcs           map[string]*sddcState
+	customerVpcs    map[string]*model.VpcInfoSubnets
+	// connectedAccounts the connected AWS accounts of all organizations, in the order they were added
+	connectedAccounts []*model.AwsCustomerConnectedAccount
+	siteRecoveries    map[string]*siteRecoveryState
+	sddcGroups        map[string]*sddcGroupState
+	tasks             map[string]*simulatedTask
+}
+
+// NewServer starts a new simulator. Callers should Close the server when done.
+func NewServer() *Server {
+	server := &Server{
+		TaskPollsUntilFinished: 1,
+		orgs:                   map[string]*model.Organization{},
+		sddcs:                  map[string]*sddcState{},
+		customerVpcs:           map[string]*model.VpcInfoSubnets{},
+		siteRecoveries:         map[string]*siteRecoveryState{},
+		sddcGroups:             map[string]*sddcGroupState{},
+		tasks:                  map[string]*simulatedTask{},
+	}
+	server.addOrg(TestOrgID, "Simulated organization", "simulated-org", nil)
+	server.registerCspRoutes()
+	server.registerTaskRoutes()
+	server.registerVmcRoutes()
+	server.registerAutoscalerRoutes()
+	server.registerDraasRoutes()
+	server.registerNsxRoutes()
+	server.registerWcpRoutes()
+	server.registerInventoryRoutes()
+	server.registerSddcGroupRoutes()
+	server.Server = httptest.NewServer(http.HandlerFunc(server.serveHTTP))
+	return server
+}
+
+// Wrapper returns an authenticated connector.Wrapper, that points to the simulator.
+func (server *Server) Wrapper() (*connector.Wrapper, error) {
+	wrapper := &connector.Wrapper{
+		RefreshToken: "simulated-refresh-token",
+		OrgID:        TestOrgID,
+		VmcURL:       server.URL,
+		CspURL:       server.URL,
+		// Simulated tasks finish after a few polls, there is no point in waiting long between them
+		TaskPoll: connector.TaskPollConfig{Interval: 10 * time.Millisecond, MaxInterval: 50 * time.Millisecond},
+	}
+	err := wrapper.Authenticate()
+	if err != nil {
+		return nil, err
+	}
+	return wrapper, nil
+}
+
+// InjectError makes all subsequent requests with the specified method and path fail
+// with the provided HTTP status code.
+func (server *Server) InjectError(method string, path string, statusCode int) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.injectedErrors = append(server.injectedErrors, &injectedError{
+		method:     method,
+		path:       path,
+		statusCode: statusCode,
+		remaining:  -1,
+	})
+}
+
+// InjectTransientError makes the next count requests with the specified method and path fail
+// with the provided HTTP status code and Retry-After header, as rate limited requests do.
+func (server *Server) InjectTransientError(method string, path string, statusCode int, count int, retryAfter string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.injectedErrors = append(server.injectedErrors, &injectedError{
+		method:     method,
+		path:       path,
+		statusCode: statusCode,
+		remaining:  count,
+		retryAfter: retryAfter,
+	})
+}
+
+// ClearErrors removes all errors injected by InjectError.
+func (server *Server) ClearErrors() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.injectedErrors = nil
+}
+
+// RevokeAccessTokens makes the simulated APIs reject all access tokens issued so far with
+// 401 Unauthorized, as if they had expired.
+func (server *Server) RevokeAccessTokens() {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.tokenGeneration++
+}
+
+// IssuedAccessTokens returns the amount of access tokens issued by the simulated Cloud Service Provider.
+func (server *Server) IssuedAccessTokens() int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.issuedTokens
+}
+
+func (server *Server) issueAccessToken() string {
+	server.issuedTokens++
+	return fmt.Sprintf("%s-%d", TestAccessToken, server.tokenGeneration)
+}
+
+// Requests returns all requests served so far, in "METHOD path" format.
+func (server *Server) Requests() []string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return append([]string{}, server.requests...)
+}
+
+func (server *Server) handle(method string, pattern string,
+	handler func(w http.ResponseWriter, r *http.Request, params []string)) {
+	server.routes = append(server.routes, route{
+		method:  method,
+		pattern: regexp.MustCompile("^" + pattern + "$"),
+		handler: handler,
+	})
+}
+
+func (server *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	server.requests = append(server.requests, r.Method+" "+r.URL.Path)
+	for _, injected := range server.injectedErrors {
+		if injected.method == r.Method && injected.path == r.URL.Path && injected.remaining != 0 {
+			if injected.remaining > 0 {
+				injected.remaining--
+			}
+			if len(injected.retryAfter) > 0 {
+				w.Header().Set("Retry-After", injected.retryAfter)
+			}
+			writeError(w, injected.statusCode, "simulated failure")
+			return
+		}
+	}
+	token := r.Header.Get("csp-auth-token")
+	if len(token) > 0 && token != fmt.Sprintf("%s-%d", TestAccessToken, server.tokenGeneration) {
+		writeError(w, http.StatusUnauthorized, "the access token is invalid or expired")
+		return
+	}
+	for _, route := range server.routes {
+		if route.method != r.Method {
+			continue
+		}
+		params := route.pattern.FindStringSubmatch(r.URL.Path)
+		if params != nil {
+			route.handler(w, r, params[1:])
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no simulated API for %s %s", r.Method, r.URL.Path))
+}
+
+func (server *Server) registerCspRoutes() {
+	server.handle(http.MethodPost, constants.CspRefreshURLSuffix, func(w http.ResponseWriter, r *http.Request, _ []string) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": server.issueAccessToken(),
+			"expires_in":   1799,
+		})
+	})
+	server.handle(http.MethodPost, constants.CspTokenURLSuffix, func(w http.ResponseWriter, r *http.Request, _ []string) {
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"access_token": server.issueAccessToken(),
+			"token_type":   "bearer",
+			"expires_in":   1799,
+		})
+	})
+}
+
+func newID() string {
+	return uuid.Must(uuid.NewV4()).String()
+}
+
+func writeJSON(w http.ResponseWriter, statusCode int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// writeModel serializes a VMC SDK model the same way the VMC APIs do.
+func writeModel(w http.ResponseWriter, value interface{}, bindingType bindings.BindingType) {
+	dataValue, errs := bindings.NewTypeConverter().ConvertToVapi(value, bindingType)
+	if errs != nil {
+		writeError(w, http.StatusInternalServerError, bindings.VAPIerrorsToError(errs).Error())
+		return
+	}
+	body, err := cleanjson.NewDataValueToJsonEncoder().Encode(dataValue)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, err.Error())
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_, _ = io.WriteString(w, body)
+}
+
+func writeError(w http.ResponseWriter, statusCode int, message string) {
+	writeJSON(w, statusCode, map[string]interface{}{
+		"error_code":     fmt.Sprintf("%d", statusCode),
+		"error_messages": []string{message},
+		"status":         statusCode,
+	})
+}
+
+// readBody decodes the JSON body of a request into a generic map.
+func readBody(r *http.Request) map[string]interface{} {
+	body := map[string]interface{}{}
+	_ = json.NewDecoder(r.Body).Decode(&body)
+	return body
+}
+
+func stringField(body map[string]interface{}, name string) string {
+	if value, ok := body[name].(string); ok {
+		return value
+	}
+	return ""
+}
+
+func intField(body map[string]interface{}, name string) int64 {
+	if value, ok := body[name].(float64); ok {
+		return int64(value)
+	}
+	return 0
+}
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func int64Ptr(i int64) *int64 {
+	return &i
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,285 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package simulator
+
+import (
+	"net/http"
+	"reflect"
+	"time"
+
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/bindings"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/data"
+	autoscalermodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/model"
+	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+const (
+	statusStarted  = "STARTED"
+	statusFinished = "FINISHED"
+	statusFailed   = "FAILED"
+)
+
+// simulatedTask a task, that reaches a terminal state after being polled
+// Server.TaskPollsUntilFinished times. The onFinish callback applies the outcome of
+// the task on the simulated state.
+type simulatedTask struct {
+	id           string
+	taskType     string
+	resourceID   string
+	status       string
+	errorMessage string
+	params       map[string]string
+	pollsLeft    int
+	created      time.Time
+	onFinish     func()
+	parentID     string
+	subTasks     []*simulatedTask
+	// progressPercent and phase the progress the task reports, set with Server.SetTaskProgress
+	progressPercent *int64
+	phase           string
+}
+
+// StartTask starts a task, that is not tied to any simulated operation, e.g. to simulate an operation
+// triggered outside of the provider, and returns its ID. The task can be looked up on both the VMC
+// and the DRaaS tasks APIs.
+func (server *Server) StartTask(taskType string, resourceID string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.startTask(taskType, resourceID, nil).id
+}
+
+// SetTaskProgress sets the progress a task reports while it is in progress. The phase is reported
+// as the phase in progress of the VMC tasks and as the sub-status of the DRaaS and autoscaler ones,
+// which have no phases.
+func (server *Server) SetTaskProgress(taskID string, percent int64, phase string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if simulated, ok := server.tasks[taskID]; ok {
+		simulated.progressPercent = &percent
+		simulated.phase = phase
+	}
+}
+
+// subStatus the sub-status of tasks without phases, nil if the task reports no phase.
+func (simulated *simulatedTask) subStatus() *string {
+	if len(simulated.phase) == 0 {
+		return nil
+	}
+	return strPtr(simulated.phase)
+}
+
+// startTask registers a new task. Must be called while holding the server mutex.
+func (server *Server) startTask(taskType string, resourceID string, onFinish func()) *simulatedTask {
+	startedTask := &simulatedTask{
+		id:         newID(),
+		taskType:   taskType,
+		resourceID: resourceID,
+		status:     statusStarted,
+		params:     map[string]string{},
+		pollsLeft:  server.TaskPollsUntilFinished,
+		created:    time.Now().UTC(),
+		onFinish:   onFinish,
+	}
+	server.tasks[startedTask.id] = startedTask
+	if startedTask.pollsLeft <= 0 {
+		server.finishTask(startedTask)
+	}
+	return startedTask
+}
+
+// startSubTasks registers sub-tasks of the provided task, one for each resource ID. Sub-tasks
+// reach a terminal state together with their parent task. Must be called while holding the server mutex.
+func (server *Server) startSubTasks(parentTask *simulatedTask, taskType string, resourceIDs []string) {
+	for _, resourceID := range resourceIDs {
+		subTask := &simulatedTask{
+			id:         newID(),
+			taskType:   taskType,
+			resourceID: resourceID,
+			status:     statusStarted,
+			params:     map[string]string{},
+			created:    time.Now().UTC(),
+			parentID:   parentTask.id,
+		}
+		server.tasks[subTask.id] = subTask
+		parentTask.subTasks = append(parentTask.subTasks, subTask)
+	}
+}
+
+func (server *Server) pollTask(taskID string) (*simulatedTask, bool) {
+	polledTask, ok := server.tasks[taskID]
+	if !ok {
+		return nil, false
+	}
+	if polledTask.status == statusStarted {
+		polledTask.pollsLeft--
+		if polledTask.pollsLeft <= 0 {
+			server.finishTask(polledTask)
+		}
+	}
+	return polledTask, true
+}
+
+func (server *Server) finishTask(finishedTask *simulatedTask) {
+	if len(server.TaskFailureMessage) > 0 {
+		finishedTask.status = statusFailed
+		finishedTask.errorMessage = server.TaskFailureMessage
+		// The failure of a task with sub-tasks is caused by its first sub-task
+		for i, subTask := range finishedTask.subTasks {
+			subTask.status = statusFinished
+			if i == 0 {
+				subTask.status = statusFailed
+				subTask.errorMessage = server.TaskFailureMessage
+			}
+		}
+		return
+	}
+	for _, subTask := range finishedTask.subTasks {
+		subTask.status = statusFinished
+	}
+	finishedTask.status = statusFinished
+	if finishedTask.onFinish != nil {
+		finishedTask.onFinish()
+	}
+}
+
+func (simulated *simulatedTask) paramsValue() *data.StructValue {
+	fields := map[string]data.DataValue{}
+	for name, value := range simulated.params {
+		fields[name] = data.NewStringValue(value)
+	}
+	return data.NewStructValue("", fields)
+}
+
+func (simulated *simulatedTask) toVmcTask() model.Task {
+	var parentTaskID *string
+	if len(simulated.parentID) > 0 {
+		parentTaskID = strPtr(simulated.parentID)
+	}
+	var phaseInProgress *string
+	if len(simulated.phase) > 0 {
+		phaseInProgress = strPtr(simulated.phase)
+	}
+	return model.Task{
+		ParentTaskId:    parentTaskID,
+		Created:         simulated.created,
+		Updated:         time.Now().UTC(),
+		Id:              simulated.id,
+		Status:          strPtr(simulated.status),
+		TaskType:        strPtr(simulated.taskType),
+		ResourceId:      strPtr(simulated.resourceID),
+		ErrorMessage:    strPtr(simulated.errorMessage),
+		Params:          simulated.paramsValue(),
+		ProgressPercent: simulated.progressPercent,
+		PhaseInProgress: phaseInProgress,
+	}
+}
+
+func (simulated *simulatedTask) toDraasTask() draasmodel.Task {
+	return draasmodel.Task{
+		Created:         simulated.created,
+		Updated:         time.Now().UTC(),
+		Id:              simulated.id,
+		Status:          strPtr(simulated.status),
+		TaskType:        strPtr(simulated.taskType),
+		ResourceId:      strPtr(simulated.resourceID),
+		ErrorMessage:    strPtr(simulated.errorMessage),
+		Params:          simulated.paramsValue(),
+		ProgressPercent: simulated.progressPercent,
+		SubStatus:       simulated.subStatus(),
+	}
+}
+
+func (simulated *simulatedTask) toAutoscalerTask() autoscalermodel.Task {
+	return autoscalermodel.Task{
+		Created:         simulated.created,
+		Updated:         time.Now().UTC(),
+		Id:              simulated.id,
+		Status:          strPtr(simulated.status),
+		TaskType:        strPtr(simulated.taskType),
+		ResourceId:      strPtr(simulated.resourceID),
+		ErrorMessage:    strPtr(simulated.errorMessage),
+		Params:          simulated.paramsValue(),
+		ProgressPercent: simulated.progressPercent,
+		SubStatus:       simulated.subStatus(),
+	}
+}
+
+// toV2Task converts the task to the format of the operations API, used by the SDDC groups API.
+func (simulated *simulatedTask) toV2Task() map[string]interface{} {
+	state := "IN_PROGRESS"
+	switch simulated.status {
+	case statusFinished:
+		state = "COMPLETED"
+	case statusFailed:
+		state = statusFailed
+	}
+	return map[string]interface{}{
+		"id":            simulated.id,
+		"type":          simulated.taskType,
+		"state":         map[string]string{"name": state},
+		"error_message": simulated.errorMessage,
+	}
+}
+
+func (server *Server) writeVmcTask(w http.ResponseWriter, simulated *simulatedTask) {
+	writeModel(w, simulated.toVmcTask(), model.TaskBindingType())
+}
+
+func (server *Server) writeDraasTask(w http.ResponseWriter, simulated *simulatedTask) {
+	writeModel(w, simulated.toDraasTask(), draasmodel.TaskBindingType())
+}
+
+func (server *Server) writeAutoscalerTask(w http.ResponseWriter, simulated *simulatedTask) {
+	writeModel(w, simulated.toAutoscalerTask(), autoscalermodel.TaskBindingType())
+}
+
+func (server *Server) registerTaskRoutes() {
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/tasks/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		polledTask, ok := server.pollTask(params[1])
+		if !ok {
+			writeError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		server.writeVmcTask(w, polledTask)
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/tasks", func(w http.ResponseWriter, r *http.Request, params []string) {
+		vmcTasks := []model.Task{}
+		for _, simulated := range server.tasks {
+			vmcTasks = append(vmcTasks, simulated.toVmcTask())
+		}
+		writeModel(w, vmcTasks, bindings.NewListType(model.TaskBindingType(), reflect.TypeOf([]model.Task{})))
+	})
+	server.handle(http.MethodGet, "/vmc/autoscaler/api/orgs/([^/]+)/tasks/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		polledTask, ok := server.pollTask(params[1])
+		if !ok {
+			writeError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		server.writeAutoscalerTask(w, polledTask)
+	})
+	server.handle(http.MethodGet, "/vmc/draas/api/orgs/([^/]+)/tasks/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		polledTask, ok := server.pollTask(params[1])
+		if !ok {
+			writeError(w, http.StatusNotFound, "task not found")
+			return
+		}
+		server.writeDraasTask(w, polledTask)
+	})
+	server.handle(http.MethodGet, "/api/operation/([^/]+)/core/operations/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		polledTask, ok := server.pollTask(params[1])
+		if !ok {
+			writeError(w, http.StatusNotFound, "operation not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, polledTask.toV2Task())
+	})
+	server.handle(http.MethodGet, "/vmc/draas/api/orgs/([^/]+)/tasks", func(w http.ResponseWriter, r *http.Request, params []string) {
+		draasTasks := []draasmodel.Task{}
+		for _, simulated := range server.tasks {
+			draasTasks = append(draasTasks, simulated.toDraasTask())
+		}
+		writeModel(w, draasTasks, bindings.NewListType(draasmodel.TaskBindingType(), reflect.TypeOf([]draasmodel.Task{})))
+	})
+}
@@ -0,0 +1,821 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package simulator
+
+import (
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/bindings"
+	nsxmodel "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
+	autoscalermodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+// sddcState the simulated state of an SDDC, including the state served by its NSX manager.
+type sddcState struct {
+	sddc         model.Sddc
+	edrsPolicies map[string]*autoscalermodel.EdrsPolicy
+	intranetMtu  int64
+	publicIPs    map[string]*nsxmodel.PublicIp
+	connections  []model.AwsSddcConnection
+	// directConnect the Direct Connect configuration of the SDDC, nil if it has none.
+	directConnect *directConnectState
+	// deleteQuery the query parameters of the request deleting the SDDC, nil until it is deleted.
+	deleteQuery url.Values
+}
+
+// directConnectState the simulated Direct Connect configuration of an SDDC.
+type directConnectState struct {
+	bgpAsn             string
+	advertisedPrefixes []string
+}
+
+// SddcConfig describes an SDDC to be added to the simulator with AddSddc.
+type SddcConfig struct {
+	// OrgID of the organization the SDDC belongs to, TestOrgID if not specified.
+	OrgID            string
+	Name             string
+	NumHosts         int
+	Provider         string
+	Region           string
+	HostInstanceType string
+	DeploymentType   string
+	// VpcCidr the management CIDR of the SDDC.
+	VpcCidr string
+	// VxlanSubnet the CIDR of the default compute segment, which is not created if not specified.
+	VxlanSubnet string
+	// SecurityProfile the security profile of a hardened SDDC, e.g. model.SddcSecurity_PROFILE_PCI_COMMERCIAL.
+	// The SDDC reports no security profile if not specified.
+	SecurityProfile string
+}
+
+// AddOrg adds an organization, that the access tokens of the simulator have access to, next to
+// the TestOrgID one, and returns its ID. The tags of the organization are exposed as its properties.
+func (server *Server) AddOrg(displayName string, name string, tags map[string]string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	return server.addOrg(newID(), displayName, name, tags)
+}
+
+// SetOrgProperty sets a property of an organization, e.g. one of its quotas.
+func (server *Server) SetOrgProperty(orgID string, key string, value string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	org, ok := server.orgs[orgID]
+	if !ok {
+		return
+	}
+	if org.Properties == nil {
+		org.Properties = &model.OrgProperties{Values: map[string]string{}}
+	}
+	org.Properties.Values[key] = value
+}
+
+func (server *Server) addOrg(orgID string, displayName string, name string, tags map[string]string) string {
+	now := time.Now().UTC()
+	org := &model.Organization{
+		Created:      now,
+		Updated:      now,
+		Id:           orgID,
+		DisplayName:  strPtr(displayName),
+		Name:         strPtr(name),
+		ProjectState: strPtr(model.Organization_PROJECT_STATE_CREATED),
+	}
+	if tags != nil {
+		org.Properties = &model.OrgProperties{Values: tags}
+	}
+	server.orgs[orgID] = org
+	return orgID
+}
+
+// AddSddc adds a READY SDDC with a primary cluster to the simulator and returns its ID.
+func (server *Server) AddSddc(config SddcConfig) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated := server.newSddc(config)
+	simulated.sddc.SddcState = strPtr(model.Sddc_SDDC_STATE_READY)
+	return simulated.sddc.Id
+}
+
+// SddcState returns the state of the SDDC with the specified ID, or an empty string if
+// there is no such SDDC.
+func (server *Server) SddcState(sddcID string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcs[sddcID]
+	if !ok {
+		return ""
+	}
+	return *simulated.sddc.SddcState
+}
+
+// SddcName returns the name of the SDDC with the specified ID, or an empty string if
+// there is no such SDDC.
+func (server *Server) SddcName(sddcID string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcs[sddcID]
+	if !ok || simulated.sddc.Name == nil {
+		return ""
+	}
+	return *simulated.sddc.Name
+}
+
+// SetSddcState changes the state of the SDDC with the specified ID, e.g. to simulate an
+// SDDC, that is being deployed by another client.
+func (server *Server) SetSddcState(sddcID string, state string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if simulated, ok := server.sddcs[sddcID]; ok {
+		simulated.sddc.SddcState = strPtr(state)
+	}
+}
+
+// SddcDeleteQuery returns the query parameters of the request, which deleted an SDDC, nil if it
+// has not been deleted.
+func (server *Server) SddcDeleteQuery(sddcID string) url.Values {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if simulated, ok := server.sddcs[sddcID]; ok {
+		return simulated.deleteQuery
+	}
+	return nil
+}
+
+// ClusterHostCount returns the amount of hosts on a cluster of an SDDC.
+func (server *Server) ClusterHostCount(sddcID string, clusterID string) int {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcs[sddcID]
+	if !ok {
+		return 0
+	}
+	cluster := simulated.cluster(clusterID)
+	if cluster == nil {
+		return 0
+	}
+	return len(cluster.EsxHostList)
+}
+
+// AddCluster adds a ready cluster to an SDDC, the way creating it from the VMC console would, with
+// the provided Microsoft licensing config, if any, and returns its ID.
+func (server *Server) AddCluster(sddcID string, numHosts int, hostInstanceType string,
+	msftLicenseConfig *model.MsftLicensingConfig) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcs[sddcID]
+	if !ok {
+		return ""
+	}
+	name := "Cluster-" + string(rune('1'+len(simulated.sddc.ResourceConfig.Clusters)))
+	cluster := newCluster(name, numHosts, hostInstanceType)
+	cluster.MsftLicenseConfig = msftLicenseConfig
+	simulated.sddc.ResourceConfig.Clusters = append(simulated.sddc.ResourceConfig.Clusters, cluster)
+	simulated.edrsPolicies[cluster.ClusterId] = newEdrsPolicy()
+	return cluster.ClusterId
+}
+
+// SetCloudPassword replaces the cloudadmin password of an SDDC, the way a reset from
+// the VMC console would.
+func (server *Server) SetCloudPassword(sddcID string, password string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	if simulated, ok := server.sddcs[sddcID]; ok {
+		simulated.sddc.ResourceConfig.CloudPassword = strPtr(password)
+	}
+}
+
+// AddSddcConnection connects the SDDC with the specified ID to a subnet of a customer VPC
+// and returns the ID of the connection.
+func (server *Server) AddSddcConnection(sddcID string, vpcCidr string, subnetCidr string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	simulated, ok := server.sddcs[sddcID]
+	if !ok {
+		return ""
+	}
+	now := time.Now().UTC()
+	connection := model.AwsSddcConnection{
+		Created:         now,
+		Updated:         now,
+		Id:              newID(),
+		OrgId:           strPtr(TestOrgID),
+		SddcId:          strPtr(sddcID),
+		VpcId:           strPtr("vpc-" + newID()[:8]),
+		CidrBlockVpc:    strPtr(vpcCidr),
+		CidrBlockSubnet: strPtr(subnetCidr),
+		State:           strPtr(model.AwsSddcConnection_STATE_ACTIVE),
+	}
+	simulated.connections = append(simulated.connections, connection)
+	return connection.Id
+}
+
+// AddConnectedAccount adds a connected AWS account with the specified number and state to the
+// TestOrgID organization and returns its ID. The account is linked to the specified AWS regions,
+// e.g. us-west-2, whose compatible VPCs are the ones added by AddCustomerSubnet.
+func (server *Server) AddConnectedAccount(accountNumber string, state string, regions ...string) string {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	now := time.Now().UTC()
+	account := &model.AwsCustomerConnectedAccount{
+		Created:                   now,
+		Updated:                   now,
+		Id:                        newID(),
+		OrgId:                     strPtr(TestOrgID),
+		AccountNumber:             strPtr(accountNumber),
+		State:                     strPtr(state),
+		CfStackName:               strPtr("vmware-sddc-formation-" + accountNumber),
+		RegionToAzToShadowMapping: map[string]map[string]string{},
+	}
+	for _, region := range regions {
+		account.RegionToAzToShadowMapping[region] = map[string]string{}
+	}
+	server.connectedAccounts = append(server.connectedAccounts, account)
+	return account.Id
+}
+
+// AddCustomerSubnet adds a compatible subnet of the connected AWS account to the VPC with the
+// specified ID, creating the VPC if needed.
+func (server *Server) AddCustomerSubnet(vpcID string, subnetID string, cidr string, availabilityZone string,
+	availabilityZoneID string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	vpc, ok := server.customerVpcs[vpcID]
+	if !ok {
+		vpc = &model.VpcInfoSubnets{VpcId: strPtr(vpcID), CidrBlock: strPtr("172.31.0.0/16")}
+		server.customerVpcs[vpcID] = vpc
+	}
+	vpc.Subnets = append(vpc.Subnets, model.SubnetInfo{
+		Compatible:         boolPtr(true),
+		SubnetId:           strPtr(subnetID),
+		SubnetCidrBlock:    strPtr(cidr),
+		AvailabilityZone:   strPtr(availabilityZone),
+		AvailabilityZoneId: strPtr(availabilityZoneID),
+		VpcId:              strPtr(vpcID),
+		VpcCidrBlock:       vpc.CidrBlock,
+		Name:               strPtr(subnetID),
+	})
+}
+
+// SetCustomerVpcCidr sets the CIDR of the connected VPC with the specified ID, 172.31.0.0/16
+// if not set, that has been created by AddCustomerSubnet.
+func (server *Server) SetCustomerVpcCidr(vpcID string, cidr string) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+	vpc := server.customerVpcs[vpcID]
+	vpc.CidrBlock = strPtr(cidr)
+	for i := range vpc.Subnets {
+		vpc.Subnets[i].VpcCidrBlock = vpc.CidrBlock
+	}
+}
+
+// NsxtReverseProxyURL returns the NSX reverse proxy URL of the SDDC with the specified ID.
+func (server *Server) NsxtReverseProxyURL(sddcID string) string {
+	return server.URL + "/orgs/" + TestOrgID + "/sddcs/" + sddcID + constants.SksNSXTManager
+}
+
+// newSddc creates the simulated state of an SDDC. Must be called while holding the server mutex.
+func (server *Server) newSddc(config SddcConfig) *sddcState {
+	sddcID := newID()
+	if len(config.OrgID) == 0 {
+		config.OrgID = TestOrgID
+	}
+	if len(config.Provider) == 0 {
+		config.Provider = constants.AwsProviderType
+	}
+	if len(config.Region) == 0 {
+		config.Region = "US_WEST_2"
+	}
+	if len(config.HostInstanceType) == 0 {
+		config.HostInstanceType = model.SddcConfig_HOST_INSTANCE_TYPE_I3_METAL
+	}
+	if len(config.DeploymentType) == 0 {
+		config.DeploymentType = "SINGLE_AZ"
+	}
+	if config.NumHosts == 0 {
+		config.NumHosts = constants.MinHosts
+	}
+	now := time.Now().UTC()
+	primaryCluster := newCluster("Cluster-1", config.NumHosts, config.HostInstanceType)
+	simulated := &sddcState{
+		sddc: model.Sddc{
+			Created:          now,
+			Updated:          now,
+			Id:               sddcID,
+			Name:             strPtr(config.Name),
+			OrgId:            strPtr(config.OrgID),
+			SddcState:        strPtr(model.Sddc_SDDC_STATE_DEPLOYING),
+			Provider:         strPtr(config.Provider),
+			AccountLinkState: strPtr(model.Sddc_ACCOUNT_LINK_STATE_DELAYED),
+			SddcAccessState:  strPtr("ENABLED"),
+			ResourceConfig: &model.AwsSddcResourceConfig{
+				Provider:                config.Provider,
+				Region:                  strPtr(config.Region),
+				DeploymentType:          strPtr(config.DeploymentType),
+				SsoDomain:               strPtr("vmc.local"),
+				SkipCreatingVxlan:       boolPtr(len(config.VxlanSubnet) == 0),
+				Nsxt:                    boolPtr(true),
+				VcUrl:                   strPtr("https://vcenter.sddc.vmc.local/"),
+				CloudUsername:           strPtr("cloudadmin@vmc.local"),
+				CloudPassword:           strPtr("simulated-cloud-password"),
+				NsxApiPublicEndpointUrl: strPtr(server.NsxtReverseProxyURL(sddcID)),
+				NsxCloudAdmin:           strPtr("cloud_admin"),
+				NsxCloudAdminPassword:   strPtr("simulated-nsx-admin-password"),
+				NsxCloudAudit:           strPtr("cloud_audit"),
+				NsxCloudAuditPassword:   strPtr("simulated-nsx-audit-password"),
+				NsxMgrManagementIp:      strPtr("10.2.224.4"),
+				NsxMgrLoginUrl:          strPtr("https://nsxmanager.sddc.vmc.local/login.jsp"),
+				AvailabilityZones:       []string{"us-west-2a"},
+				SddcSize: &model.SddcSize{
+					VcSize:  strPtr(constants.MediumSddcSize),
+					NsxSize: strPtr(constants.MediumSddcSize),
+					Size:    strPtr(constants.CapitalMediumSddcSize),
+				},
+				Clusters: []model.Cluster{primaryCluster},
+			},
+		},
+		edrsPolicies: map[string]*autoscalermodel.EdrsPolicy{},
+		intranetMtu:  constants.MinIntranetMtuLink,
+		publicIPs:    map[string]*nsxmodel.PublicIp{},
+	}
+	if len(config.SecurityProfile) > 0 {
+		simulated.sddc.ResourceConfig.SddcSecurity = &model.SddcSecurity{
+			Profile:  strPtr(config.SecurityProfile),
+			Hardened: boolPtr(true),
+		}
+	}
+	if len(config.VpcCidr) > 0 {
+		simulated.sddc.ResourceConfig.VpcInfo = &model.VpcInfo{VpcCidr: strPtr(config.VpcCidr)}
+	}
+	if len(config.VxlanSubnet) > 0 {
+		simulated.sddc.ResourceConfig.VxlanSubnet = strPtr(config.VxlanSubnet)
+	}
+	if config.DeploymentType == "MULTI_AZ" {
+		// The primary cluster is stretched across two availability zones with a witness in a third one
+		simulated.sddc.ResourceConfig.AvailabilityZones = []string{"us-west-2a", "us-west-2b"}
+		simulated.sddc.ResourceConfig.WitnessAvailabilityZone = strPtr("us-west-2c")
+		simulated.sddc.ResourceConfig.Clusters[0].VsanWitness = newVsanWitness()
+	}
+	simulated.edrsPolicies[primaryCluster.ClusterId] = newEdrsPolicy()
+	server.sddcs[sddcID] = simulated
+	return simulated
+}
+
+// zeroCloud returns true for the zero-cost SDDCs of the ZEROCLOUD provider, which have no AWS
+// infrastructure.
+func (simulated *sddcState) zeroCloud() bool {
+	return simulated.sddc.Provider != nil && *simulated.sddc.Provider == constants.ZeroCloudProviderType
+}
+
+func newCluster(name string, numHosts int, hostInstanceType string) model.Cluster {
+	cluster := model.Cluster{
+		ClusterId:    newID(),
+		ClusterName:  strPtr(name),
+		ClusterState: strPtr("READY"),
+		EsxHostInfo:  &model.EsxHostInfo{InstanceType: strPtr(hostInstanceType)},
+	}
+	cluster.EsxHostList = newHosts(numHosts)
+	return cluster
+}
+
+func newVsanWitness() *model.AwsWitnessEsx {
+	return &model.AwsWitnessEsx{
+		EsxId:      strPtr(newID()),
+		Name:       strPtr("witness"),
+		Hostname:   strPtr("witness.sddc.vmc.local"),
+		EnumState:  strPtr("READY"),
+		InstanceId: strPtr("i-witness"),
+		Provider:   constants.AwsProviderType,
+	}
+}
+
+func newHosts(numHosts int) []model.AwsEsxHost {
+	var hosts []model.AwsEsxHost
+	for i := 0; i < numHosts; i++ {
+		hosts = append(hosts, model.AwsEsxHost{
+			EsxId:    strPtr(newID()),
+			EsxState: strPtr("READY"),
+			Provider: constants.AwsProviderType,
+		})
+	}
+	return hosts
+}
+
+func newEdrsPolicy() *autoscalermodel.EdrsPolicy {
+	return &autoscalermodel.EdrsPolicy{
+		EnableEdrs: true,
+		PolicyType: strPtr(constants.StorageScaleUpPolicyType),
+		MinHosts:   int64Ptr(constants.MinHosts),
+		MaxHosts:   int64Ptr(constants.MaxHosts),
+	}
+}
+
+func (simulated *sddcState) cluster(clusterID string) *model.Cluster {
+	for i := range simulated.sddc.ResourceConfig.Clusters {
+		if simulated.sddc.ResourceConfig.Clusters[i].ClusterId == clusterID {
+			return &simulated.sddc.ResourceConfig.Clusters[i]
+		}
+	}
+	return nil
+}
+
+// getSddc returns the simulated SDDC addressed by the request or writes a not found error.
+func (server *Server) getSddc(w http.ResponseWriter, sddcID string) (*sddcState, bool) {
+	simulated, ok := server.sddcs[sddcID]
+	if !ok || *simulated.sddc.SddcState == model.Sddc_SDDC_STATE_DELETED {
+		writeError(w, http.StatusNotFound, "SDDC "+sddcID+" not found")
+		return nil, false
+	}
+	return simulated, true
+}
+
+// getOrgSddc returns the simulated SDDC addressed by the request, if it belongs to the organization
+// of the request, or writes a not found error.
+func (server *Server) getOrgSddc(w http.ResponseWriter, orgID string, sddcID string) (*sddcState, bool) {
+	simulated, ok := server.sddcs[sddcID]
+	if ok && *simulated.sddc.OrgId != orgID {
+		writeError(w, http.StatusNotFound, "SDDC "+sddcID+" not found")
+		return nil, false
+	}
+	return server.getSddc(w, sddcID)
+}
+
+func (server *Server) registerVmcRoutes() {
+	server.handle(http.MethodGet, "/vmc/api/orgs", func(w http.ResponseWriter, r *http.Request, params []string) {
+		orgList := []model.Organization{}
+		for _, org := range server.orgs {
+			orgList = append(orgList, *org)
+		}
+		sort.Slice(orgList, func(i, j int) bool {
+			if orgList[i].Created.Equal(orgList[j].Created) {
+				return orgList[i].Id < orgList[j].Id
+			}
+			return orgList[i].Created.Before(orgList[j].Created)
+		})
+		writeModel(w, orgList, bindings.NewListType(model.OrganizationBindingType(), reflect.TypeOf([]model.Organization{})))
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		org, ok := server.orgs[params[0]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "organization "+params[0]+" not found")
+			return
+		}
+		writeModel(w, *org, model.OrganizationBindingType())
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/sddcs", func(w http.ResponseWriter, r *http.Request, params []string) {
+		sddcList := []model.Sddc{}
+		for _, simulated := range server.sddcs {
+			if *simulated.sddc.OrgId == params[0] && *simulated.sddc.SddcState != model.Sddc_SDDC_STATE_DELETED {
+				sddcList = append(sddcList, simulated.sddc)
+			}
+		}
+		writeModel(w, sddcList, bindings.NewListType(model.SddcBindingType(), reflect.TypeOf([]model.Sddc{})))
+	})
+	server.handle(http.MethodPost, "/vmc/api/orgs/([^/]+)/sddcs", func(w http.ResponseWriter, r *http.Request, params []string) {
+		body := readBody(r)
+		simulated := server.newSddc(SddcConfig{
+			OrgID:            params[0],
+			Name:             stringField(body, "name"),
+			NumHosts:         int(intField(body, "num_hosts")),
+			Provider:         stringField(body, "provider"),
+			Region:           stringField(body, "region"),
+			HostInstanceType: stringField(body, "host_instance_type"),
+			DeploymentType:   toAPIDeploymentType(stringField(body, "deployment_type")),
+			VpcCidr:          stringField(body, "vpc_cidr"),
+			VxlanSubnet:      stringField(body, "vxlan_subnet"),
+		})
+		createTask := server.startTask(constants.SddcProvisionTaskType, simulated.sddc.Id, func() {
+			simulated.sddc.SddcState = strPtr(model.Sddc_SDDC_STATE_READY)
+		})
+		server.writeVmcTask(w, createTask)
+	})
+	// Registered before the SDDC routes, which would match it otherwise
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/sddcs/provision-spec", func(w http.ResponseWriter, r *http.Request, params []string) {
+		writeModel(w, newProvisionSpec(), model.ProvisionSpecBindingType())
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getOrgSddc(w, params[0], params[1])
+		if !ok {
+			return
+		}
+		writeModel(w, simulated.sddc, model.SddcBindingType())
+	})
+	server.handle(http.MethodPatch, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getOrgSddc(w, params[0], params[1])
+		if !ok {
+			return
+		}
+		body := readBody(r)
+		if name := stringField(body, "name"); len(name) > 0 {
+			simulated.sddc.Name = strPtr(name)
+		}
+		writeModel(w, simulated.sddc, model.SddcBindingType())
+	})
+	server.handle(http.MethodDelete, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getOrgSddc(w, params[0], params[1])
+		if !ok {
+			return
+		}
+		// Like VMware Cloud on AWS, failed SDDCs can only be deleted forcefully
+		if *simulated.sddc.SddcState == model.Sddc_SDDC_STATE_FAILED && r.URL.Query().Get("force") != "true" {
+			writeError(w, http.StatusBadRequest, "SDDC "+simulated.sddc.Id+" is in a failed state, it can only be deleted forcefully")
+			return
+		}
+		simulated.deleteQuery = r.URL.Query()
+		simulated.sddc.SddcState = strPtr(model.Sddc_SDDC_STATE_DELETING)
+		deleteTask := server.startTask(constants.SddcDeleteTaskType, simulated.sddc.Id, func() {
+			simulated.sddc.SddcState = strPtr(model.Sddc_SDDC_STATE_DELETED)
+		})
+		server.writeVmcTask(w, deleteTask)
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/account-link/connected-accounts", func(w http.ResponseWriter, r *http.Request, params []string) {
+		accounts := []model.AwsCustomerConnectedAccount{}
+		for _, account := range server.connectedAccounts {
+			if *account.OrgId == params[0] {
+				accounts = append(accounts, *account)
+			}
+		}
+		writeModel(w, accounts, bindings.NewListType(model.AwsCustomerConnectedAccountBindingType(),
+			reflect.TypeOf([]model.AwsCustomerConnectedAccount{})))
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/account-link/compatible-subnets", func(w http.ResponseWriter, r *http.Request, params []string) {
+		compatibleSubnets := model.AwsCompatibleSubnets{VpcMap: map[string]model.VpcInfoSubnets{}}
+		// The availability zones of a region are named after it, e.g. us-west-2a is one of US_WEST_2
+		zonePrefix := strings.ReplaceAll(strings.ToLower(r.URL.Query().Get("region")), "_", "-")
+		zones := map[string]bool{}
+		for vpcID, vpc := range server.customerVpcs {
+			regionVpc := *vpc
+			regionVpc.Subnets = nil
+			for _, subnet := range vpc.Subnets {
+				if !strings.HasPrefix(*subnet.AvailabilityZone, zonePrefix) {
+					continue
+				}
+				regionVpc.Subnets = append(regionVpc.Subnets, subnet)
+				if !zones[*subnet.AvailabilityZone] {
+					zones[*subnet.AvailabilityZone] = true
+					compatibleSubnets.CustomerAvailableZones = append(compatibleSubnets.CustomerAvailableZones, *subnet.AvailabilityZone)
+				}
+			}
+			if len(regionVpc.Subnets) > 0 {
+				compatibleSubnets.VpcMap[vpcID] = regionVpc
+			}
+		}
+		writeModel(w, compatibleSubnets, model.AwsCompatibleSubnetsBindingType())
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/account-link/sddc-connections", func(w http.ResponseWriter, r *http.Request, params []string) {
+		connections := []model.AwsSddcConnection{}
+		for sddcID, simulated := range server.sddcs {
+			if sddcFilter := r.URL.Query().Get("sddc"); len(sddcFilter) == 0 || sddcFilter == sddcID {
+				connections = append(connections, simulated.connections...)
+			}
+		}
+		writeModel(w, connections, bindings.NewListType(model.AwsSddcConnectionBindingType(), reflect.TypeOf([]model.AwsSddcConnection{})))
+	})
+	server.handle(http.MethodGet, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)/primarycluster", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		writeModel(w, simulated.sddc.ResourceConfig.Clusters[0], model.ClusterBindingType())
+	})
+	server.handle(http.MethodPost, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)/esxs", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		body := readBody(r)
+		clusterID := stringField(body, "cluster_id")
+		numHosts := int(intField(body, "num_hosts"))
+		action := r.URL.Query().Get("action")
+		if simulated.cluster(clusterID) == nil {
+			writeError(w, http.StatusBadRequest, "cluster "+clusterID+" not found")
+			return
+		}
+		if action == "remove" && len(simulated.cluster(clusterID).EsxHostList) <= numHosts {
+			writeError(w, http.StatusBadRequest, "cannot remove all hosts from cluster "+clusterID)
+			return
+		}
+		esxTask := server.startTask("ESX-"+strings.ToUpper(action), simulated.sddc.Id, func() {
+			cluster := simulated.cluster(clusterID)
+			if action == "remove" {
+				cluster.EsxHostList = cluster.EsxHostList[:len(cluster.EsxHostList)-numHosts]
+			} else {
+				cluster.EsxHostList = append(cluster.EsxHostList, newHosts(numHosts)...)
+			}
+		})
+		var esxIDs []string
+		for i := 0; i < numHosts; i++ {
+			esxIDs = append(esxIDs, newID())
+		}
+		server.startSubTasks(esxTask, "HOST-"+strings.ToUpper(action), esxIDs)
+		server.writeVmcTask(w, esxTask)
+	})
+	server.handle(http.MethodPost, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)/clusters", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		body := readBody(r)
+		hostInstanceType := stringField(body, "host_instance_type")
+		if len(hostInstanceType) == 0 {
+			hostInstanceType = model.SddcConfig_HOST_INSTANCE_TYPE_I3_METAL
+		}
+		name := "Cluster-" + string(rune('1'+len(simulated.sddc.ResourceConfig.Clusters)))
+		cluster := newCluster(name, int(intField(body, "num_hosts")), hostInstanceType)
+		cluster.ClusterState = strPtr("DEPLOYING")
+		if *simulated.sddc.ResourceConfig.DeploymentType == "MULTI_AZ" {
+			cluster.VsanWitness = newVsanWitness()
+		}
+		simulated.sddc.ResourceConfig.Clusters = append(simulated.sddc.ResourceConfig.Clusters, cluster)
+		simulated.edrsPolicies[cluster.ClusterId] = newEdrsPolicy()
+		clusterID := cluster.ClusterId
+		clusterTask := server.startTask(constants.ClusterProvisionTaskType, simulated.sddc.Id, func() {
+			simulated.cluster(clusterID).ClusterState = strPtr("READY")
+		})
+		clusterTask.params[constants.ClusterIDFieldName] = clusterID
+		var esxIDs []string
+		for _, esx := range cluster.EsxHostList {
+			esxIDs = append(esxIDs, *esx.EsxId)
+		}
+		server.startSubTasks(clusterTask, "HOST-PROVISION", esxIDs)
+		server.writeVmcTask(w, clusterTask)
+	})
+	server.handle(http.MethodPost, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)/clusters/([^/]+)/msft-licensing/publish", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		clusterID := params[2]
+		if simulated.cluster(clusterID) == nil {
+			writeError(w, http.StatusNotFound, "cluster "+clusterID+" not found")
+			return
+		}
+		body := readBody(r)
+		licensingTask := server.startTask("MSFT-LICENSING-PUBLISH", simulated.sddc.Id, func() {
+			cluster := simulated.cluster(clusterID)
+			if cluster.MsftLicenseConfig == nil {
+				cluster.MsftLicenseConfig = &model.MsftLicensingConfig{}
+			}
+			// Licensing flags left out of the request keep their current value
+			if mssqlLicensing := stringField(body, "mssql_licensing"); len(mssqlLicensing) > 0 {
+				cluster.MsftLicenseConfig.MssqlLicensing = strPtr(mssqlLicensing)
+			}
+			if windowsLicensing := stringField(body, "windows_licensing"); len(windowsLicensing) > 0 {
+				cluster.MsftLicenseConfig.WindowsLicensing = strPtr(windowsLicensing)
+			}
+			if academicLicense, ok := body["academic_license"].(bool); ok {
+				cluster.MsftLicenseConfig.AcademicLicense = boolPtr(academicLicense)
+			}
+		})
+		server.writeVmcTask(w, licensingTask)
+	})
+	server.handle(http.MethodPost, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)/clusters/([^/]+)/convert", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		clusterID := params[2]
+		cluster := simulated.cluster(clusterID)
+		if cluster == nil {
+			writeError(w, http.StatusNotFound, "cluster "+clusterID+" not found")
+			return
+		}
+		hostInstanceType := stringField(readBody(r), "host_instance_type")
+		if cluster.EsxHostInfo != nil && cluster.EsxHostInfo.InstanceType != nil && *cluster.EsxHostInfo.InstanceType == hostInstanceType {
+			writeError(w, http.StatusBadRequest, "cluster "+clusterID+" already has hosts of type "+hostInstanceType)
+			return
+		}
+		conversionTask := server.startTask(constants.ClusterConversionTaskType, simulated.sddc.Id, func() {
+			simulated.cluster(clusterID).EsxHostInfo = &model.EsxHostInfo{InstanceType: strPtr(hostInstanceType)}
+		})
+		conversionTask.params[constants.ClusterIDFieldName] = clusterID
+		server.writeVmcTask(w, conversionTask)
+	})
+	server.handle(http.MethodDelete, "/vmc/api/orgs/([^/]+)/sddcs/([^/]+)/clusters/([^/]+)", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		clusterID := params[2]
+		if simulated.cluster(clusterID) == nil {
+			writeError(w, http.StatusNotFound, "cluster "+clusterID+" not found")
+			return
+		}
+		clusterTask := server.startTask(constants.ClusterDeleteTaskType, simulated.sddc.Id, func() {
+			var remaining []model.Cluster
+			for _, cluster := range simulated.sddc.ResourceConfig.Clusters {
+				if cluster.ClusterId != clusterID {
+					remaining = append(remaining, cluster)
+				}
+			}
+			simulated.sddc.ResourceConfig.Clusters = remaining
+			delete(simulated.edrsPolicies, clusterID)
+		})
+		clusterTask.params[constants.ClusterIDFieldName] = clusterID
+		server.writeVmcTask(w, clusterTask)
+	})
+}
+
+func (server *Server) registerAutoscalerRoutes() {
+	server.handle(http.MethodGet, "/vmc/autoscaler/api/orgs/([^/]+)/sddcs/([^/]+)/clusters/([^/]+)/edrs-policy", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		edrsPolicy, ok := simulated.edrsPolicies[params[2]]
+		if !ok {
+			writeError(w, http.StatusNotFound, "cluster "+params[2]+" not found")
+			return
+		}
+		writeModel(w, *edrsPolicy, autoscalermodel.EdrsPolicyBindingType())
+	})
+	server.handle(http.MethodPost, "/vmc/autoscaler/api/orgs/([^/]+)/sddcs/([^/]+)/clusters/([^/]+)/edrs-policy", func(w http.ResponseWriter, r *http.Request, params []string) {
+		simulated, ok := server.getSddc(w, params[1])
+		if !ok {
+			return
+		}
+		clusterID := params[2]
+		if _, ok := simulated.edrsPolicies[clusterID]; !ok {
+			writeError(w, http.StatusNotFound, "cluster "+clusterID+" not found")
+			return
+		}
+		body := readBody(r)
+		enableEdrs, _ := body["enable_edrs"].(bool)
+		edrsPolicy := &autoscalermodel.EdrsPolicy{
+			EnableEdrs: enableEdrs,
+			PolicyType: strPtr(stringField(body, "policy_type")),
+			MinHosts:   int64Ptr(intField(body, "min_hosts")),
+			MaxHosts:   int64Ptr(intField(body, "max_hosts")),
+		}
+		edrsTask := server.startTask("EDRS-POLICY-UPDATE", clusterID, func() {
+			simulated.edrsPolicies[clusterID] = edrsPolicy
+		})
+		server.writeAutoscalerTask(w, edrsTask)
+	})
+}
+
+// toAPIDeploymentType converts the deployment type sent on SDDC creation to the format
+// returned by the VMC API.
+func toAPIDeploymentType(deploymentType string) string {
+	if deploymentType == constants.MultiAvailabilityZone {
+		return "MULTI_AZ"
+	}
+	return "SINGLE_AZ"
+}
+
+// newProvisionSpec the host instance types offered to the organizations of the simulator. In
+// US_WEST_2 the i3.metal instance type is offered, but cannot be provisioned.
+func newProvisionSpec() model.ProvisionSpec {
+	i3enMetal := newInstanceTypeConfig("i3en.metal", "I3en", 96, 768, 45840)
+	i4iMetal := newInstanceTypeConfig("i4i.metal", "I4i", 128, 1024, 20466)
+	i3Metal := newInstanceTypeConfig("i3.metal", "I3", 36, 512, 10368)
+	i3Metal.InstanceProvisioningErrorCause = strPtr("Instance type i3.metal is not available for new SDDCs")
+	return model.ProvisionSpec{
+		Provider: map[string]model.SddcConfigSpec{
+			constants.AwsProviderType: {
+				SddcTypeConfigSpec: map[string]model.ConfigSpec{
+					constants.DefaultSddcType: {
+						Availability: map[string][]model.InstanceTypeConfig{
+							"US_WEST_2":    {i3enMetal, i4iMetal, i3Metal},
+							"EU_CENTRAL_1": {i3enMetal},
+						},
+					},
+					constants.OneNodeSddcType: {
+						Availability: map[string][]model.InstanceTypeConfig{
+							"US_WEST_2": {i3enMetal},
+						},
+					},
+				},
+				RegionDisplayNames: map[string]string{
+					"US_WEST_2":    "US West (Oregon)",
+					"EU_CENTRAL_1": "Europe (Frankfurt)",
+				},
+			},
+		},
+	}
+}
+
+func newInstanceTypeConfig(instanceType string, displayName string, cores int64, memoryGib int64,
+	storageGib int64) model.InstanceTypeConfig {
+	hyperThreadingSupported := true
+	return model.InstanceTypeConfig{
+		InstanceType:            strPtr(instanceType),
+		DisplayName:             strPtr(displayName),
+		Description:             strPtr(displayName + " host instance type"),
+		HyperThreadingSupported: &hyperThreadingSupported,
+		Hosts:                   []int64{2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16},
+		CpuCores:                []int64{cores},
+		EntityCapacity: &model.EntityCapacity{
+			StorageCapacityGib: &storageGib,
+			MemoryCapacityGib:  &memoryGib,
+			TotalNumberOfCores: &cores,
+		},
+	}
+}
@@ -0,0 +1,66 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package simulator
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+// cidrField returns the first CIDR block of a workload control plane network, which is
+// either a single {"address", "prefix"} object or a list of them.
+func cidrField(body map[string]interface{}, name string) string {
+	value := body[name]
+	if list, ok := value.([]interface{}); ok {
+		if len(list) == 0 {
+			return ""
+		}
+		value = list[0]
+	}
+	cidr, ok := value.(map[string]interface{})
+	if !ok {
+		return ""
+	}
+	return fmt.Sprintf("%s/%d", stringField(cidr, "address"), intField(cidr, "prefix"))
+}
+
+func (server *Server) registerWcpRoutes() {
+	wcpOperation := func(taskType string, onFinish func(cluster *model.Cluster, body map[string]interface{})) func(w http.ResponseWriter, r *http.Request, params []string) {
+		return func(w http.ResponseWriter, r *http.Request, params []string) {
+			simulated, ok := server.getSddc(w, params[1])
+			if !ok {
+				return
+			}
+			clusterID := params[2]
+			if simulated.cluster(clusterID) == nil {
+				writeError(w, http.StatusNotFound, "cluster "+clusterID+" not found")
+				return
+			}
+			body := readBody(r)
+			wcpTask := server.startTask(taskType, clusterID, func() {
+				onFinish(simulated.cluster(clusterID), body)
+			})
+			server.writeVmcTask(w, wcpTask)
+		}
+	}
+	operationsPath := "/api/wcp/v1/orgs/([^/]+)/deployments/([^/]+)/clusters/([^/]+)/operations/"
+	server.handle(http.MethodPost, operationsPath+"validate-network", wcpOperation("WCP-VALIDATE-NETWORK",
+		func(cluster *model.Cluster, body map[string]interface{}) {}))
+	server.handle(http.MethodPost, operationsPath+"enable-wcp", wcpOperation("WCP-ENABLE",
+		func(cluster *model.Cluster, body map[string]interface{}) {
+			cluster.WcpDetails = &model.WcpDetails{
+				WcpStatus:   strPtr(model.WcpDetails_WCP_STATUS_ENABLED),
+				EgressCidr:  strPtr(cidrField(body, "egress_cidr")),
+				IngressCidr: strPtr(cidrField(body, "ingress_cidr")),
+				PodCidr:     strPtr(cidrField(body, "namespace_cidr")),
+				ServiceCidr: strPtr(cidrField(body, "service_cidr")),
+			}
+		}))
+	server.handle(http.MethodPost, operationsPath+"disable-wcp", wcpOperation("WCP-DISABLE",
+		func(cluster *model.Cluster, body map[string]interface{}) {
+			cluster.WcpDetails = &model.WcpDetails{WcpStatus: strPtr(model.WcpDetails_WCP_STATUS_DISABLED)}
+		}))
+}
@@ -0,0 +1,129 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+// Package inventory provides a client of the VMC inventory operations API, that upsizes the
+// vCenter and NSX appliances of an existing SDDC. This API is not covered by the VMC SDK.
+package inventory
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
+)
+
+const (
+	// UpsizeOperationType the type of the inventory operation, that upsizes the appliances of an SDDC
+	UpsizeOperationType = "UPSIZE"
+	// upsizeConfigType the type of the configuration of an upsize operation of an SDDC on AWS
+	upsizeConfigType = "AwsUpsizeSddcConfig"
+)
+
+type Client interface {
+	connector.Authenticator
+	UpsizeSddc(sddcID string, size SddcSize) (taskID string, err error)
+}
+
+// SddcSize the sizes of the vCenter and NSX appliances of an SDDC.
+type SddcSize struct {
+	VcSize  string `json:"vc_size"`
+	NsxSize string `json:"nsx_size"`
+}
+
+// UpsizeConfig the configuration of an upsize operation.
+type UpsizeConfig struct {
+	Type   string   `json:"type"`
+	SddcID string   `json:"sddc_id"`
+	Size   SddcSize `json:"size"`
+}
+
+// Operation the body of an inventory operation request.
+type Operation struct {
+	Type   string       `json:"type"`
+	Config UpsizeConfig `json:"config"`
+}
+
+// Task the part of the VMC task, returned by the inventory operations API, that is needed to track it.
+type Task struct {
+	ID string `json:"id"`
+}
+
+type ClientImpl struct {
+	connector  connector.Wrapper
+	httpClient connector.HTTPClient
+}
+
+func NewInventoryClient(wrapper connector.Wrapper) *ClientImpl {
+	copyWrapper := connector.CopyWrapper(wrapper)
+	return &ClientImpl{
+		connector:  *copyWrapper,
+		httpClient: copyWrapper.HTTPClient(),
+	}
+}
+
+// newTestInventoryClient intended for injecting dummy accessToken and stubbed httpClient for
+// testing purposes.
+func newTestInventoryClient(vmcURL string, orgID string, accessToken string, httpClient connector.HTTPClient) *ClientImpl {
+	testConnector := connector.Wrapper{
+		VmcURL: vmcURL,
+		OrgID:  orgID,
+	}
+	// Create a dummy connector to house the access token in a security context
+	testConnector.Connector = client.NewConnector("", client.WithHttpClient(&http.Client{}),
+		client.WithSecurityContext(security.NewOauthSecurityContext(accessToken)))
+	return &ClientImpl{
+		connector:  testConnector,
+		httpClient: httpClient,
+	}
+}
+
+// Authenticate grab an access token and set it into the Client instance for later use
+func (client *ClientImpl) Authenticate() error {
+	return client.connector.Authenticate()
+}
+
+// UpsizeSddc starts the upsize of the vCenter and NSX appliances of the specified SDDC to the
+// provided sizes and returns the ID of the VMC task tracking it.
+func (client *ClientImpl) UpsizeSddc(sddcID string, size SddcSize) (string, error) {
+	requestPayload, err := json.Marshal(Operation{
+		Type: UpsizeOperationType,
+		Config: UpsizeConfig{
+			Type:   upsizeConfigType,
+			SddcID: sddcID,
+			Size:   size,
+		},
+	})
+	if err != nil {
+		return "", err
+	}
+	operationsURL := client.getBaseURL() + fmt.Sprintf("/%s/vmc-aws/operations", client.connector.OrgID)
+	req := client.connector.NewRequest(http.MethodPost, operationsURL, bytes.NewBuffer(requestPayload))
+
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
+	if err != nil {
+		return "", err
+	}
+	if statusCode == http.StatusNotFound {
+		return "", fmt.Errorf("SDDC %s not found", sddcID)
+	}
+	if statusCode != http.StatusOK && statusCode != http.StatusAccepted && statusCode != http.StatusCreated {
+		return "", fmt.Errorf("SDDC upsize response code: %d body: %s", statusCode, string(*rawResponse))
+	}
+	var result Task
+	err = json.NewDecoder(bytes.NewReader(*rawResponse)).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+	if len(result.ID) == 0 {
+		return "", fmt.Errorf("SDDC upsize response does not contain a task ID")
+	}
+	return result.ID, nil
+}
+
+func (client *ClientImpl) getBaseURL() string {
+	return client.connector.VmcURL + "/api/inventory"
+}
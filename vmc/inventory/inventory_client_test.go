@@ -0,0 +1,84 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package inventory
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+)
+
+const testAccessToken = "testAccessToken"
+const testOrgID = "testOrgID"
+const testVmcURL = "https://test.vmc.vmware.com"
+const testOperationsURL = testVmcURL + "/api/inventory/testOrgID/vmc-aws/operations"
+const testUpsizeJSON = `{"type":"UPSIZE","config":{"type":"AwsUpsizeSddcConfig","sddc_id":"sddcId",` +
+	`"size":{"vc_size":"large","nsx_size":"large"}}}`
+
+var testLargeSize = SddcSize{VcSize: "large", NsxSize: "large"}
+
+type HTTPClientStub struct {
+	expectedJSON   string
+	expectedURL    string
+	responseJSON   string
+	responseCode   int
+	requestsServed int
+	t              *testing.T
+}
+
+func (stub *HTTPClientStub) Do(req *http.Request) (*http.Response, error) {
+	stub.requestsServed++
+	bodyBytes, _ := io.ReadAll(req.Body)
+	assert.Equal(stub.t, stub.expectedJSON, string(bodyBytes))
+	assert.Equal(stub.t, stub.expectedURL, req.URL.String())
+	assert.Equal(stub.t, http.MethodPost, req.Method)
+	assert.Equal(stub.t, testAccessToken, req.Header.Get(connector.AuthnHeader))
+	return &http.Response{
+		StatusCode: stub.responseCode,
+		Body:       io.NopCloser(strings.NewReader(stub.responseJSON)),
+	}, nil
+}
+
+func TestUpsizeSddc(t *testing.T) {
+	stub := &HTTPClientStub{
+		expectedJSON: testUpsizeJSON,
+		expectedURL:  testOperationsURL,
+		responseJSON: `{"id": "taskId", "status": "STARTED"}`,
+		responseCode: http.StatusAccepted,
+		t:            t,
+	}
+	taskID, err := newTestInventoryClient(testVmcURL, testOrgID, testAccessToken, stub).UpsizeSddc("sddcId", testLargeSize)
+	assert.NoError(t, err)
+	assert.Equal(t, "taskId", taskID)
+	assert.Equal(t, 1, stub.requestsServed)
+}
+
+func TestUpsizeSddcErrors(t *testing.T) {
+	testCases := []struct {
+		responseCode  int
+		responseJSON  string
+		expectedError string
+	}{
+		{responseCode: http.StatusNotFound, expectedError: "SDDC sddcId not found"},
+		{responseCode: http.StatusBadRequest, responseJSON: `{"error_messages": ["SDDC is already large"]}`,
+			expectedError: "SDDC is already large"},
+		{responseCode: http.StatusUnauthorized, expectedError: "Unauthenticated request"},
+		{responseCode: http.StatusOK, responseJSON: `{}`, expectedError: "does not contain a task ID"},
+	}
+	for _, testCase := range testCases {
+		stub := &HTTPClientStub{
+			expectedJSON: testUpsizeJSON,
+			expectedURL:  testOperationsURL,
+			responseJSON: testCase.responseJSON,
+			responseCode: testCase.responseCode,
+			t:            t,
+		}
+		_, err := newTestInventoryClient(testVmcURL, testOrgID, testAccessToken, stub).UpsizeSddc("sddcId", testLargeSize)
+		assert.ErrorContains(t, err, testCase.expectedError)
+	}
+}
@@ -0,0 +1,99 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+)
+
+// importOrgSeparator separates the organization from the ID of a resource imported in an
+// organization other than the one the provider is configured with, e.g. <org_id>/<sddc_id>.
+const importOrgSeparator = "/"
+
+// withOrgOverride adds the optional org_id argument to the resource or data source, which allows
+// managing it in an organization other than the one the provider is configured with. The functions
+// of the resource are passed a connector.Wrapper operating on that organization, and the organization
+// the resource belongs to is recorded in its state, so that later operations keep targeting it.
+func withOrgOverride(r *schema.Resource) *schema.Resource {
+	r.Schema["org_id"] = &schema.Schema{
+		Type:     schema.TypeString,
+		Optional: true,
+		Computed: true,
+		// Resources cannot be moved between organizations
+		ForceNew: r.CreateContext != nil,
+		Description: "ID of the organization the resource belongs to. Defaults to the organization " +
+			"the provider is configured with.",
+	}
+	r.CreateContext = orgOverrideContextFunc(r.CreateContext)
+	r.ReadContext = orgOverrideContextFunc(r.ReadContext)
+	r.UpdateContext = orgOverrideContextFunc(r.UpdateContext)
+	r.DeleteContext = orgOverrideContextFunc(r.DeleteContext)
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			return customizeDiff(ctx, d, wrapperForOrg(m, d.Get("org_id").(string)))
+		}
+	}
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		r.Importer.StateContext = orgOverrideImportFunc(r.Importer.StateContext)
+	}
+	return r
+}
+
+func orgOverrideContextFunc(
+	f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		orgM := wrapperForOrg(m, d.Get("org_id").(string))
+		diags := f(ctx, d, orgM)
+		if len(d.Id()) > 0 && !diags.HasError() {
+			setEffectiveOrgID(d, orgM)
+		}
+		return diags
+	}
+}
+
+// orgOverrideImportFunc wraps the importer of the resource, so that IDs prefixed with an
+// organization, e.g. <org_id>/<sddc_id>, import the resource from that organization.
+func orgOverrideImportFunc(f schema.StateContextFunc) schema.StateContextFunc {
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+		if orgID, resourceID, found := strings.Cut(d.Id(), importOrgSeparator); found {
+			d.SetId(resourceID)
+			d.Set("org_id", orgID)
+		}
+		orgM := wrapperForOrg(m, d.Get("org_id").(string))
+		imported, err := f(ctx, d, orgM)
+		if err != nil {
+			return nil, err
+		}
+		for _, importedData := range imported {
+			setEffectiveOrgID(importedData, orgM)
+		}
+		return imported, nil
+	}
+}
+
+// wrapperForOrg returns a copy of the connector.Wrapper operating on the organization with the
+// specified ID, or the wrapper itself when no organization is specified.
+func wrapperForOrg(m interface{}, orgID string) interface{} {
+	wrapper, ok := m.(*connector.Wrapper)
+	if !ok || len(orgID) == 0 || orgID == wrapper.OrgID {
+		return m
+	}
+	orgWrapper := connector.CopyWrapper(*wrapper)
+	orgWrapper.OrgID = orgID
+	return orgWrapper
+}
+
+func setEffectiveOrgID(d *schema.ResourceData, m interface{}) {
+	if wrapper, ok := m.(*connector.Wrapper); ok {
+		d.Set("org_id", wrapper.OrgID)
+	}
+}
@@ -0,0 +1,60 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestOrgOverrideSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	drOrgID := server.AddOrg("Disaster recovery", "dr-org", nil)
+	drSddcID := server.AddSddc(simulator.SddcConfig{OrgID: drOrgID, Name: "dr"})
+	primarySddcID := server.AddSddc(simulator.SddcConfig{Name: "primary"})
+
+	// Data sources read from the organization of the provider, unless another one is specified
+	sddcsDataSource := withOrgOverride(dataSourceVmcSddcs())
+	assert.False(t, sddcsDataSource.Schema["org_id"].ForceNew)
+	d := schema.TestResourceDataRaw(t, sddcsDataSource.Schema, map[string]interface{}{"org_id": drOrgID})
+	assert.NoError(t, diagsErr(sddcsDataSource.ReadContext(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, drOrgID, d.Id())
+	assert.Equal(t, []interface{}{drSddcID}, d.Get("ids"))
+	d = schema.TestResourceDataRaw(t, sddcsDataSource.Schema, map[string]interface{}{})
+	assert.NoError(t, diagsErr(sddcsDataSource.ReadContext(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, simulator.TestOrgID, d.Get("org_id"))
+	assert.Equal(t, []interface{}{primarySddcID}, d.Get("ids"))
+
+	// Resources keep operating on the organization recorded in their state
+	sddcResource := withOrgOverride(resourceSddc())
+	assert.True(t, sddcResource.Schema["org_id"].ForceNew)
+	d = sddcResource.Data(nil)
+	d.SetId(drSddcID)
+	d.Set("org_id", drOrgID)
+	assert.NoError(t, diagsErr(sddcResource.ReadContext(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, drSddcID, d.Id())
+	assert.Equal(t, "dr", d.Get("sddc_name"))
+	assert.Equal(t, drOrgID, d.Get("org_id"))
+	d = sddcResource.Data(nil)
+	d.SetId(drSddcID)
+	assert.NoError(t, diagsErr(sddcResource.ReadContext(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+
+	// Imported IDs may be prefixed with the organization of the resource
+	d = sddcResource.Data(nil)
+	d.SetId(drOrgID + importOrgSeparator + drSddcID)
+	imported, err := sddcResource.Importer.StateContext(context.Background(), d, connectorWrapper)
+	assert.NoError(t, err)
+	assert.Equal(t, drSddcID, imported[0].Id())
+	assert.Equal(t, drOrgID, imported[0].Get("org_id"))
+	assert.NoError(t, diagsErr(sddcResource.ReadContext(context.Background(), imported[0], connectorWrapper)))
+	assert.Equal(t, drSddcID, imported[0].Id())
+
+	// The wrapper of the provider is never modified
+	assert.Equal(t, simulator.TestOrgID, connectorWrapper.OrgID)
+}
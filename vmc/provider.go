@@ -5,14 +5,17 @@ package vmc
 
 import (
 	"fmt"
+	"time"
+
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
 )
 
 // Provider for VMware VMC Console APIs. Returns terraform.ResourceProvider
 func Provider() *schema.Provider {
-	return &schema.Provider{
+	provider := &schema.Provider{
 		Schema: map[string]*schema.Schema{
 			"refresh_token": {
 				Type:          schema.TypeString,
@@ -34,65 +37,320 @@ func Provider() *schema.Provider {
 				ConflictsWith: []string{"refresh_token"},
 				RequiredWith:  []string{"client_id"},
 			},
+			"credential_file": {
+				Type:          schema.TypeString,
+				Optional:      true,
+				DefaultFunc:   schema.EnvDefaultFunc(constants.CredentialFile, nil),
+				ConflictsWith: []string{"refresh_token", "client_id", "client_secret", "credential_process"},
+			},
+			"credential_process": {
+				Type:          schema.TypeList,
+				Optional:      true,
+				MinItems:      1,
+				Elem:          &schema.Schema{Type: schema.TypeString},
+				ConflictsWith: []string{"refresh_token", "client_id", "client_secret"},
+			},
 			"org_id": {
 				Type:        schema.TypeString,
 				Required:    true,
 				DefaultFunc: schema.EnvDefaultFunc(constants.OrgID, nil),
 			},
+			"environment": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.Environment, constants.CommercialEnvironment),
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.CommercialEnvironment, constants.GovCloudEnvironment, constants.StagingEnvironment}, false),
+			},
+			// The defaults of vmc_url and csp_url depend on the environment
 			"vmc_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc(constants.VmcURL, constants.DefaultVmcURL),
+				DefaultFunc: schema.EnvDefaultFunc(constants.VmcURL, nil),
 			},
 			"csp_url": {
 				Type:        schema.TypeString,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc(constants.CspURL, constants.DefaultCspURL),
+				DefaultFunc: schema.EnvDefaultFunc(constants.CspURL, nil),
+			},
+			"extra_headers": {
+				Type: schema.TypeMap,
+				Elem: &schema.Schema{
+					Type: schema.TypeString,
+				},
+				Optional: true,
+			},
+			"proxy_url": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.ProxyURL, nil),
+				Description: "URL of the proxy all requests to VMware Cloud Services are sent through. Defaults to the proxy of the HTTPS_PROXY and NO_PROXY environment variables.",
+			},
+			"ca_file": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.CAFile, nil),
+				Description: "Path of a PEM bundle with the certificates of CAs trusted in addition to the ones of the system.",
+			},
+			"allow_unverified_ssl": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.AllowUnverifiedSSL, false),
+				Description: "Disables the verification of the certificates of VMware Cloud Services and the NSX endpoints of the SDDCs. Only meant for troubleshooting.",
+			},
+			"audit_log_path": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				DefaultFunc: schema.EnvDefaultFunc(constants.AuditLogPath, nil),
+				Description: "Path of a local file to which a JSON line is appended for each create, update and delete operation performed by the provider.",
+			},
+			"max_retries": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      constants.DefaultMaxRetries,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum number of times a rate limited or temporarily failing request to VMware Cloud Services is retried. Set to 0 to disable retries.",
+			},
+			"retry_min_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      constants.DefaultRetryMinDelay,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Delay in seconds before the first retry of a request, doubled for each subsequent retry.",
+			},
+			"retry_max_delay": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      constants.DefaultRetryMaxDelay,
+				ValidateFunc: validation.IntAtLeast(0),
+				Description:  "Maximum delay in seconds between the retries of a request.",
+			},
+			"task_poll_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      constants.DefaultTaskPollInterval,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Delay in seconds between the first and the second poll of a task, doubled for each subsequent poll.",
+			},
+			"task_poll_max_interval": {
+				Type:         schema.TypeInt,
+				Optional:     true,
+				Default:      constants.DefaultTaskPollMaxInterval,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Maximum delay in seconds between the polls of a task.",
 			},
 		},
 
 		ResourcesMap: map[string]*schema.Resource{
-			"vmc_sddc":          resourceSddc(),
-			"vmc_public_ip":     resourcePublicIP(),
-			"vmc_site_recovery": resourceSiteRecovery(),
-			"vmc_srm_node":      resourceSrmNode(),
-			"vmc_cluster":       resourceCluster(),
-			"vmc_sddc_group":    resourceSddcGroup(),
+			"vmc_sddc":                           withAuditLog("vmc_sddc", withOrgOverride(resourceSddc())),
+			"vmc_public_ip":                      withAuditLog("vmc_public_ip", withOrgOverride(resourcePublicIP())),
+			"vmc_public_ips":                     withAuditLog("vmc_public_ips", withOrgOverride(resourcePublicIPs())),
+			"vmc_site_recovery":                  withAuditLog("vmc_site_recovery", withOrgOverride(resourceSiteRecovery())),
+			"vmc_srm_node":                       withAuditLog("vmc_srm_node", withOrgOverride(resourceSrmNode())),
+			"vmc_cluster":                        withAuditLog("vmc_cluster", withOrgOverride(resourceCluster())),
+			"vmc_sddc_group":                     withAuditLog("vmc_sddc_group", withOrgOverride(resourceSddcGroup())),
+			"vmc_sddc_group_external_attachment": withAuditLog("vmc_sddc_group_external_attachment", withOrgOverride(resourceSddcGroupExternalAttachment())),
+			"vmc_edrs_policy":                    withAuditLog("vmc_edrs_policy", withOrgOverride(resourceEdrsPolicy())),
+			"vmc_sddc_microsoft_licensing":       withAuditLog("vmc_sddc_microsoft_licensing", withOrgOverride(resourceSddcMicrosoftLicensing())),
+			"vmc_sddc_tkg":                       withAuditLog("vmc_sddc_tkg", withOrgOverride(resourceSddcTkg())),
+			"vmc_intranet_mtu":                   withAuditLog("vmc_intranet_mtu", withOrgOverride(resourceIntranetMtu())),
+			"vmc_task_wait":                      withAuditLog("vmc_task_wait", withOrgOverride(resourceTaskWait())),
 		},
 
 		DataSourcesMap: map[string]*schema.Resource{
-			"vmc_org":                dataSourceVmcOrg(),
-			"vmc_connected_accounts": dataSourceVmcConnectedAccounts(),
-			"vmc_customer_subnets":   dataSourceVmcCustomerSubnets(),
-			"vmc_sddc":               dataSourceVmcSddc(),
+			"vmc_connected_accounts":   withOrgOverride(dataSourceVmcConnectedAccounts()),
+			"vmc_customer_subnets":     withOrgOverride(dataSourceVmcCustomerSubnets()),
+			"vmc_sddc":                 withOrgOverride(dataSourceVmcSddc()),
+			"vmc_host_instance_types":  withOrgOverride(dataSourceVmcHostInstanceTypes()),
+			"vmc_intranet_mtu":         withOrgOverride(dataSourceVmcIntranetMtu()),
+			"vmc_orgs":                 dataSourceVmcOrgs(),
+			"vmc_public_ip":            withOrgOverride(dataSourceVmcPublicIP()),
+			"vmc_regions":              withOrgOverride(dataSourceVmcRegions()),
+			"vmc_sddc_credentials":     withOrgOverride(dataSourceVmcSddcCredentials()),
+			"vmc_sddc_network_summary": withOrgOverride(dataSourceVmcSddcNetworkSummary()),
+			"vmc_sddcs":                withOrgOverride(dataSourceVmcSddcs()),
+			"vmc_srm_nodes":            withOrgOverride(dataSourceVmcSrmNodes()),
+			"vmc_task":                 withOrgOverride(dataSourceVmcTask()),
 		},
 
 		ConfigureFunc: providerConfigure,
 	}
+	for _, r := range provider.ResourcesMap {
+		withRequestLogging(r)
+	}
+	for _, r := range provider.DataSourcesMap {
+		withRequestLogging(r)
+	}
+	return provider
+}
+
+// providerWithSharedWrapper returns the SDK provider, configuring the wrapper shared with the framework
+// provider, see ProviderServer.
+func providerWithSharedWrapper(shared *sharedConnectorWrapper) *schema.Provider {
+	provider := Provider()
+	provider.ConfigureFunc = func(d *schema.ResourceData) (interface{}, error) {
+		return shared.get(func() (*connector.Wrapper, error) {
+			return newConnectorWrapper(newProviderConfig(d))
+		})
+	}
+	return provider
 }
 
 func providerConfigure(d *schema.ResourceData) (interface{}, error) {
-	refreshToken := d.Get("refresh_token").(string)
-	clientID := d.Get("client_id").(string)
-	clientSecret := d.Get("client_secret").(string)
-	if len(refreshToken) == 0 && len(clientID) == 0 && len(clientSecret) == 0 {
-		return nil, fmt.Errorf("must provide value for refresh_token or client_id and client_secret")
-	}
-	vmcURL := d.Get("vmc_url").(string)
-	cspURL := d.Get("csp_url").(string)
-	orgID := d.Get("org_id").(string)
+	connectorWrapper, err := newConnectorWrapper(newProviderConfig(d))
+	if err != nil {
+		return nil, err
+	}
+	return connectorWrapper, nil
+}
+
+// newProviderConfig returns the providerConfig of the arguments of the SDK provider.
+func newProviderConfig(d *schema.ResourceData) providerConfig {
+	config := providerConfig{
+		RefreshToken:        d.Get("refresh_token").(string),
+		ClientID:            d.Get("client_id").(string),
+		ClientSecret:        d.Get("client_secret").(string),
+		CredentialFile:      d.Get("credential_file").(string),
+		OrgID:               d.Get("org_id").(string),
+		Environment:         d.Get("environment").(string),
+		VmcURL:              d.Get("vmc_url").(string),
+		CspURL:              d.Get("csp_url").(string),
+		ExtraHeaders:        map[string]string{},
+		ProxyURL:            d.Get("proxy_url").(string),
+		CAFile:              d.Get("ca_file").(string),
+		AllowUnverifiedSSL:  d.Get("allow_unverified_ssl").(bool),
+		AuditLogPath:        d.Get("audit_log_path").(string),
+		MaxRetries:          d.Get("max_retries").(int),
+		RetryMinDelay:       d.Get("retry_min_delay").(int),
+		RetryMaxDelay:       d.Get("retry_max_delay").(int),
+		TaskPollInterval:    d.Get("task_poll_interval").(int),
+		TaskPollMaxInterval: d.Get("task_poll_max_interval").(int),
+	}
+	for _, arg := range d.Get("credential_process").([]interface{}) {
+		config.CredentialProcess = append(config.CredentialProcess, arg.(string))
+	}
+	for name, value := range d.Get("extra_headers").(map[string]interface{}) {
+		config.ExtraHeaders[name] = value.(string)
+	}
+	return config
+}
+
+// providerConfig the arguments of the provider, shared by the SDK provider and the framework provider.
+// VmcURL and CspURL override the URLs of the Environment, if set.
+type providerConfig struct {
+	RefreshToken string
+	ClientID     string
+	ClientSecret string
+	// CredentialFile and CredentialProcess replace the credentials above with the ones they provide
+	CredentialFile    string
+	CredentialProcess []string
+	OrgID             string
+	Environment       string
+	VmcURL            string
+	CspURL            string
+	ExtraHeaders      map[string]string
+	// ProxyURL, CAFile and AllowUnverifiedSSL configure the connections, see connector.TransportConfig
+	ProxyURL           string
+	CAFile             string
+	AllowUnverifiedSSL bool
+	AuditLogPath       string
+	// MaxRetries, RetryMinDelay and RetryMaxDelay configure the retries of rate limited requests,
+	// the delays are in seconds.
+	MaxRetries    int
+	RetryMinDelay int
+	RetryMaxDelay int
+	// TaskPollInterval and TaskPollMaxInterval configure the polling of tasks, in seconds.
+	TaskPollInterval    int
+	TaskPollMaxInterval int
+}
+
+// environmentEndpoints the VMC and CSP URLs of each VMC environment.
+var environmentEndpoints = map[string]struct {
+	vmcURL string
+	cspURL string
+}{
+	constants.CommercialEnvironment: {vmcURL: constants.DefaultVmcURL, cspURL: constants.DefaultCspURL},
+	constants.GovCloudEnvironment:   {vmcURL: constants.GovCloudVmcURL, cspURL: constants.GovCloudCspURL},
+	constants.StagingEnvironment:    {vmcURL: constants.StagingVmcURL, cspURL: constants.StagingCspURL},
+}
+
+// newConnectorWrapper creates an authenticated connector.Wrapper from the provider arguments.
+func newConnectorWrapper(config providerConfig) (*connector.Wrapper, error) {
+	if err := resolveCredentials(&config); err != nil {
+		return nil, err
+	}
+	if len(config.RefreshToken) == 0 && len(config.ClientID) == 0 && len(config.ClientSecret) == 0 {
+		return nil, fmt.Errorf("must provide value for refresh_token or client_id and client_secret, " +
+			"or credential_file or credential_process")
+	}
+	vmcURL, cspURL, err := resolveEnvironmentURLs(config)
+	if err != nil {
+		return nil, err
+	}
+	if config.RetryMinDelay > config.RetryMaxDelay {
+		return nil, fmt.Errorf("retry_min_delay (%d) must not be greater than retry_max_delay (%d)",
+			config.RetryMinDelay, config.RetryMaxDelay)
+	}
+	if config.TaskPollInterval > config.TaskPollMaxInterval {
+		return nil, fmt.Errorf("task_poll_interval (%d) must not be greater than task_poll_max_interval (%d)",
+			config.TaskPollInterval, config.TaskPollMaxInterval)
+	}
+	transport, err := connector.NewTransport(connector.TransportConfig{
+		ProxyURL:           config.ProxyURL,
+		CAFile:             config.CAFile,
+		InsecureSkipVerify: config.AllowUnverifiedSSL,
+	})
+	if err != nil {
+		return nil, err
+	}
 	connectorWrapper := connector.Wrapper{
-		RefreshToken: refreshToken,
-		ClientID:     clientID,
-		ClientSecret: clientSecret,
-		OrgID:        orgID,
+		RefreshToken: config.RefreshToken,
+		ClientID:     config.ClientID,
+		ClientSecret: config.ClientSecret,
+		OrgID:        config.OrgID,
 		VmcURL:       vmcURL,
 		CspURL:       cspURL,
+		ExtraHeaders: config.ExtraHeaders,
+		Retry: connector.RetryConfig{
+			MaxRetries: config.MaxRetries,
+			MinDelay:   time.Duration(config.RetryMinDelay) * time.Second,
+			MaxDelay:   time.Duration(config.RetryMaxDelay) * time.Second,
+		},
+		TaskPoll: connector.TaskPollConfig{
+			Interval:    time.Duration(config.TaskPollInterval) * time.Second,
+			MaxInterval: time.Duration(config.TaskPollMaxInterval) * time.Second,
+		},
+		Transport: transport,
 	}
-	err := connectorWrapper.Authenticate()
+	if len(config.AuditLogPath) > 0 {
+		connectorWrapper.AuditLog = connector.NewAuditLog(config.AuditLogPath)
+	}
+	err = connectorWrapper.Authenticate()
 	if err != nil {
 		return nil, HandleCreateError("Client connector", err)
 	}
 
 	return &connectorWrapper, err
 }
+
+// resolveEnvironmentURLs returns the VMC and CSP URLs of the configured environment, unless
+// they are overridden by vmc_url and csp_url.
+func resolveEnvironmentURLs(config providerConfig) (string, string, error) {
+	environment := config.Environment
+	if len(environment) == 0 {
+		environment = constants.CommercialEnvironment
+	}
+	endpoints, ok := environmentEndpoints[environment]
+	if !ok {
+		return "", "", fmt.Errorf("unknown environment %q, expected one of %s, %s or %s", environment,
+			constants.CommercialEnvironment, constants.GovCloudEnvironment, constants.StagingEnvironment)
+	}
+	vmcURL, cspURL := config.VmcURL, config.CspURL
+	if len(vmcURL) == 0 {
+		vmcURL = endpoints.vmcURL
+	}
+	if len(cspURL) == 0 {
+		cspURL = endpoints.cspURL
+	}
+	return vmcURL, cspURL, nil
+}
@@ -4,17 +4,43 @@
 package vmc
 
 import (
+	"context"
+	"errors"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"github.com/vmware/terraform-provider-vmc/vmc/task"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 )
 
 var testAccProviders map[string]*schema.Provider
 
 var testAccProvider *schema.Provider
 
+// testAccProtoV5ProviderFactories serve the muxed provider, for acceptance tests of resources
+// and data sources migrated to terraform-plugin-framework.
+var testAccProtoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"vmc": func() (tfprotov5.ProviderServer, error) {
+		providerServer, err := ProviderServer(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		return providerServer(), nil
+	},
+}
+
 func init() {
 	testAccProvider = Provider()
 	testAccProviders = map[string]*schema.Provider{
@@ -33,6 +59,30 @@ func TestProvider_impl(_ *testing.T) {
 	var _ *schema.Provider = Provider()
 }
 
+func TestProviderServerSchema(t *testing.T) {
+	// The provider schema of the SDK provider depends on whether org_id is set in the environment
+	for _, orgID := range []string{"", simulator.TestOrgID} {
+		t.Setenv(constants.OrgID, orgID)
+		providerServer, err := ProviderServer(context.Background())
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		resp, err := providerServer().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		for _, diagnostic := range resp.Diagnostics {
+			t.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+		}
+		if _, ok := resp.DataSourceSchemas["vmc_org"]; !ok {
+			t.Errorf("vmc_org data source is not served")
+		}
+		if _, ok := resp.ResourceSchemas["vmc_sddc"]; !ok {
+			t.Errorf("vmc_sddc resource is not served")
+		}
+	}
+}
+
 func testAccPreCheck(t *testing.T) {
 	if v := os.Getenv(constants.APIToken); v == "" {
 		t.Fatal(constants.APIToken + " must be set for acceptance tests")
@@ -57,6 +107,105 @@ func testAccPreCheck(t *testing.T) {
 	}
 }
 
+func TestResolveEnvironmentURLs(t *testing.T) {
+	testCases := []struct {
+		config         providerConfig
+		expectedVmcURL string
+		expectedCspURL string
+	}{
+		{config: providerConfig{}, expectedVmcURL: constants.DefaultVmcURL, expectedCspURL: constants.DefaultCspURL},
+		{config: providerConfig{Environment: constants.GovCloudEnvironment},
+			expectedVmcURL: constants.GovCloudVmcURL, expectedCspURL: constants.GovCloudCspURL},
+		{config: providerConfig{Environment: constants.StagingEnvironment},
+			expectedVmcURL: constants.StagingVmcURL, expectedCspURL: constants.StagingCspURL},
+		// The URLs of the environment can be overridden one at a time
+		{config: providerConfig{Environment: constants.GovCloudEnvironment, VmcURL: "https://vmc.example.com"},
+			expectedVmcURL: "https://vmc.example.com", expectedCspURL: constants.GovCloudCspURL},
+	}
+	for _, testCase := range testCases {
+		vmcURL, cspURL, err := resolveEnvironmentURLs(testCase.config)
+		assert.NoError(t, err)
+		assert.Equal(t, testCase.expectedVmcURL, vmcURL)
+		assert.Equal(t, testCase.expectedCspURL, cspURL)
+	}
+
+	_, _, err := resolveEnvironmentURLs(providerConfig{Environment: "moon"})
+	assert.ErrorContains(t, err, `unknown environment "moon"`)
+}
+
+func TestProviderConfigureEnvironmentSimulator(t *testing.T) {
+	server := simulator.NewServer()
+	defer server.Close()
+	t.Setenv(constants.Environment, "")
+	t.Setenv(constants.VmcURL, "")
+	t.Setenv(constants.CspURL, "")
+
+	d := schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"refresh_token": "refresh-token",
+		"org_id":        simulator.TestOrgID,
+		"environment":   constants.GovCloudEnvironment,
+		"vmc_url":       server.URL,
+		"csp_url":       server.URL,
+	})
+	connectorWrapper, err := providerConfigure(d)
+	assert.NoError(t, err)
+	assert.Equal(t, server.URL, connectorWrapper.(*connector.Wrapper).VmcURL)
+
+	// The environment can be selected through the environment as well
+	t.Setenv(constants.Environment, constants.StagingEnvironment)
+	d = schema.TestResourceDataRaw(t, Provider().Schema, map[string]interface{}{
+		"refresh_token": "refresh-token",
+		"org_id":        simulator.TestOrgID,
+	})
+	assert.Equal(t, constants.StagingEnvironment, d.Get("environment"))
+	assert.Equal(t, "", d.Get("vmc_url"), "the URLs default to the ones of the environment")
+}
+
+func TestProviderServerSharesConnectorWrapperSimulator(t *testing.T) {
+	server := simulator.NewServer()
+	defer server.Close()
+	t.Setenv(constants.APIToken, "")
+	runsFile := filepath.Join(t.TempDir(), "runs")
+	t.Setenv("VMC_TEST_CREDENTIAL_PROCESS_RUNS", runsFile)
+	credentialProcess := credentialProcessHelper(t, "refresh-token")
+
+	shared := &sharedConnectorWrapper{}
+	sdkProvider := providerWithSharedWrapper(shared)
+	muxServer, err := tf5muxserver.NewMuxServer(context.Background(), sdkProvider.GRPCProvider,
+		providerserver.NewProtocol5(&frameworkProvider{shared: shared}))
+	assert.NoError(t, err)
+	providerServer := muxServer.ProviderServer()
+	schemaResp, err := providerServer.GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	assert.NoError(t, err)
+
+	configType := schemaResp.Provider.ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attributeType := range configType.AttributeTypes {
+		values[name] = tftypes.NewValue(attributeType, nil)
+	}
+	values["org_id"] = tftypes.NewValue(tftypes.String, simulator.TestOrgID)
+	values["vmc_url"] = tftypes.NewValue(tftypes.String, server.URL)
+	values["csp_url"] = tftypes.NewValue(tftypes.String, server.URL)
+	processArgs := []tftypes.Value{}
+	for _, arg := range credentialProcess {
+		processArgs = append(processArgs, tftypes.NewValue(tftypes.String, arg))
+	}
+	values["credential_process"] = tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, processArgs)
+	config, err := tfprotov5.NewDynamicValue(configType, tftypes.NewValue(configType, values))
+	assert.NoError(t, err)
+
+	resp, err := providerServer.ConfigureProvider(context.Background(), &tfprotov5.ConfigureProviderRequest{Config: &config})
+	assert.NoError(t, err)
+	for _, diagnostic := range resp.Diagnostics {
+		t.Errorf("%s: %s", diagnostic.Summary, diagnostic.Detail)
+	}
+	assert.NotNil(t, shared.wrapper)
+	assert.Same(t, shared.wrapper, sdkProvider.Meta())
+	runs, err := os.ReadFile(runsFile)
+	assert.NoError(t, err)
+	assert.Equal(t, "run\n", string(runs), "the credential process runs once for both providers")
+}
+
 // testAccPreCheckZerocloud this function validates a smaller set ot
 // environment variables needed for lightweight E2E testing using
 // the Zerocloud SDDC cloud provider option
@@ -89,3 +238,50 @@ func testAccPreCheckZerocloud(t *testing.T) {
 		t.Fatal(constants.SddcGroupTestSddc2Id + " must be set for acceptance tests")
 	}
 }
+
+// newTestSimulator starts a VMC API simulator and returns it, together with an
+// authenticated connector.Wrapper pointing to it. The simulator is closed on test cleanup.
+func newTestSimulator(t *testing.T) (*simulator.Server, *connector.Wrapper) {
+	server := simulator.NewServer()
+	t.Cleanup(server.Close)
+	connectorWrapper, err := server.Wrapper()
+	if err != nil {
+		t.Fatalf("error authenticating against simulator: %s", err)
+	}
+	return server, connectorWrapper
+}
+
+// assertUnlocked checks that the lock on the specified key can be obtained, failing the
+// test instead of blocking forever if it was leaked.
+func assertUnlocked(t *testing.T, keyedMutex *task.KeyedMutex, key string) {
+	obtained := make(chan struct{})
+	go func() {
+		keyedMutex.Lock(key)()
+		close(obtained)
+	}()
+	select {
+	case <-obtained:
+	case <-time.After(5 * time.Second):
+		t.Fatalf("lock on %s was not released", key)
+	}
+}
+
+// diagsErr joins the errors among the diagnostics returned by a CRUD function into a single
+// error, so that they can be asserted on like plain errors. It returns nil, if there are none.
+func diagsErr(diags diag.Diagnostics) error {
+	var messages []string
+	for _, d := range diags {
+		if d.Severity != diag.Error {
+			continue
+		}
+		message := d.Summary
+		if d.Detail != "" {
+			message += ": " + d.Detail
+		}
+		messages = append(messages, message)
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return errors.New(strings.Join(messages, "; "))
+}
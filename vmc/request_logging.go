@@ -0,0 +1,50 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+)
+
+// withRequestLogging wraps the functions of the resource or data source, so that the API requests
+// they issue are logged with the Terraform logger of the operation, see connector.Wrapper.WithLogContext.
+func withRequestLogging(r *schema.Resource) *schema.Resource {
+	r.CreateContext = loggedContextFunc(r.CreateContext)
+	r.ReadContext = loggedContextFunc(r.ReadContext)
+	r.UpdateContext = loggedContextFunc(r.UpdateContext)
+	r.DeleteContext = loggedContextFunc(r.DeleteContext)
+	if customizeDiff := r.CustomizeDiff; customizeDiff != nil {
+		r.CustomizeDiff = func(ctx context.Context, d *schema.ResourceDiff, m interface{}) error {
+			return customizeDiff(ctx, d, wrapperForLogContext(ctx, m))
+		}
+	}
+	if r.Importer != nil && r.Importer.StateContext != nil {
+		importState := r.Importer.StateContext
+		r.Importer.StateContext = func(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+			return importState(ctx, d, wrapperForLogContext(ctx, m))
+		}
+	}
+	return r
+}
+
+func loggedContextFunc(
+	f func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics) func(context.Context, *schema.ResourceData, interface{}) diag.Diagnostics {
+	if f == nil {
+		return nil
+	}
+	return func(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+		return f(ctx, d, wrapperForLogContext(ctx, m))
+	}
+}
+
+func wrapperForLogContext(ctx context.Context, m interface{}) interface{} {
+	if wrapper, ok := m.(*connector.Wrapper); ok {
+		return wrapper.WithLogContext(ctx)
+	}
+	return m
+}
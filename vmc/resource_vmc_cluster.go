@@ -8,19 +8,18 @@ import (
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
 	task "github.com/vmware/terraform-provider-vmc/vmc/task"
 	"log"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	autoscalercluster "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/api/orgs/sddcs/clusters"
 	autoscalermodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/model"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/sddcs"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/sddcs/clusters/msft_licensing"
 )
 
 // clusterMutationKeyedMutex a mutex that allows only a single cluster per sddc to be mutated.
@@ -28,35 +27,66 @@ var clusterMutationKeyedMutex = task.KeyedMutex{}
 
 func resourceCluster() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceClusterCreate,
-		Delete: resourceClusterDelete,
-		Update: resourceClusterUpdate,
-		Read:   resourceClusterRead,
+		CreateContext: resourceClusterCreate,
+		DeleteContext: resourceClusterDelete,
+		UpdateContext: resourceClusterUpdate,
+		ReadContext:   resourceClusterRead,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
-				idParts := strings.Split(d.Id(), ",")
-				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
-					return nil, fmt.Errorf("unexpected format of ID (%q), expected id,sddc_id", d.Id())
-				}
-				if err := IsValidUUID(idParts[0]); err != nil {
-					return nil, fmt.Errorf("invalid format for id : %v", err)
-				}
-				if err := IsValidUUID(idParts[1]); err != nil {
-					return nil, fmt.Errorf("invalid format for sddc_id : %v", err)
-				}
-
-				d.SetId(idParts[0])
-				d.Set("sddc_id", idParts[1])
-				return []*schema.ResourceData{d}, nil
-			},
+			StateContext: resourceClusterImport,
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(60 * time.Minute),
 			Delete: schema.DefaultTimeout(40 * time.Minute),
 			Update: schema.DefaultTimeout(20 * time.Minute),
 		},
-		Schema: clusterSchema(),
+		CustomizeDiff: customizeClusterDiff,
+		Schema:        clusterSchema(),
+	}
+}
+
+// customizeClusterDiff fails the plan of a cluster of a MultiAZ SDDC, if its hosts would not be split
+// evenly across the availability zones, or, if its capacity_precheck is enabled, if the requested
+// hosts are not available, rather than failing at apply time.
+func customizeClusterDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if m == nil || !d.NewValueKnown("sddc_id") || !d.NewValueKnown("num_hosts") ||
+		(len(d.Id()) > 0 && !d.HasChanges("num_hosts", "host_instance_type")) {
+		return nil
+	}
+	sddcID := d.Get("sddc_id").(string)
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	sddc, err := apiClient.Sddcs().Get(apiClient.OrgID(), sddcID)
+	if err != nil {
+		log.Printf("[WARN] Skipping stretched cluster host count check for SDDC %s: %v", sddcID, err)
+		return nil
+	}
+	if (len(d.Id()) == 0 || d.HasChange("num_hosts")) && sddc.ResourceConfig != nil &&
+		sddc.ResourceConfig.DeploymentType != nil &&
+		ConvertDeployType(*sddc.ResourceConfig.DeploymentType) == constants.MultiAvailabilityZone {
+		if err := validateStretchedClusterHostCount(d.Get("num_hosts").(int)); err != nil {
+			return err
+		}
 	}
+	if d.Get("capacity_precheck").(bool) && d.NewValueKnown("host_instance_type") &&
+		sddc.ResourceConfig != nil && sddc.ResourceConfig.Region != nil {
+		request := hostCapacityRequest{
+			providerType:    constants.AwsProviderType,
+			region:          *sddc.ResourceConfig.Region,
+			instanceType:    d.Get("host_instance_type").(string),
+			numHosts:        d.Get("num_hosts").(int),
+			instanceTypeKey: "host_instance_type",
+			numHostsKey:     "num_hosts",
+		}
+		if sddc.Provider != nil {
+			request.providerType = *sddc.Provider
+		}
+		if sddc.SddcType != nil {
+			request.sddcType = *sddc.SddcType
+		}
+		oldNumHosts, _ := d.GetChange("num_hosts")
+		request.addedHosts = request.numHosts - oldNumHosts.(int)
+		return checkHostCapacity(apiClient, request)
+	}
+	return nil
 }
 
 // clusterSchema this helper function extracts the creation of the Cluster schema, so that
@@ -74,25 +104,42 @@ func clusterSchema() map[string]*schema.Schema {
 			ValidateFunc: validation.IntBetween(constants.MinHosts, constants.MaxHosts),
 			Description:  "The number of hosts.",
 		},
+		"deletion_protection": deletionProtectionSchema("cluster"),
+		"capacity_precheck":   capacityPrecheckSchema(),
 		"host_cpu_cores_count": {
 			Type:        schema.TypeInt,
 			Optional:    true,
 			Description: "Customize CPU cores on hosts in a cluster. Specify number of cores to be enabled on hosts in a cluster.",
 		},
 		"host_instance_type": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Description:  "The instance type for the esx hosts added to this cluster. Changing it on an existing cluster converts its hosts to the new instance type.",
+			ValidateFunc: validateHostInstanceType,
+		},
+		"conversion_timeout": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			Default:      constants.DefaultClusterConversionTimeout,
+			ValidateFunc: validateDuration,
+			Description:  "The time to wait for the conversion of the hosts to another host instance type, e.g. 90m or 12h. Default: 12h.",
+		},
+		"scale_out_timeout": hostScalingTimeoutSchema("The time to wait for hosts to be added to the cluster, " +
+			"e.g. 90m or 6h. Defaults to the update timeout of the resource."),
+		"scale_in_timeout": hostScalingTimeoutSchema("The time to wait for hosts to be removed from the cluster, " +
+			"e.g. 90m or 6h. Defaults to the update timeout of the resource."),
+		"conversion_status": {
 			Type:        schema.TypeString,
-			Optional:    true,
-			Description: "The instance type for the esx hosts added to this cluster.",
-			ValidateFunc: validation.StringInSlice(
-				[]string{constants.HostInstancetypeI3, constants.HostInstancetypeI3EN, constants.HostInstancetypeI4I}, false),
+			Computed:    true,
+			Description: "The status of the task converting the hosts of the cluster to another host instance type, if any.",
 		},
 		"edrs_policy_type": {
 			Type: schema.TypeString,
 			// Exact value known after create
 			Optional: true,
 			Computed: true,
-			ValidateFunc: validation.StringInSlice(
-				[]string{constants.StorageScaleUpPolicyType, constants.CostPolicyType, constants.PerformancePolicyType, constants.RapidScaleUpPolicyType}, false),
+			ValidateFunc: validateKnownStringValue(
+				[]string{constants.StorageScaleUpPolicyType, constants.CostPolicyType, constants.PerformancePolicyType, constants.RapidScaleUpPolicyType}),
 			Description: "The EDRS policy type. This can either be 'cost', 'performance', 'storage-scaleup' or 'rapid-scaleup'. Default : storage-scaleup. ",
 		},
 		"enable_edrs": {
@@ -145,31 +192,47 @@ func clusterSchema() map[string]*schema.Schema {
 			},
 			Optional:    true,
 			Description: "Indicates the desired licensing support, if any, of Microsoft software.",
+			Deprecated:  "Use the vmc_sddc_microsoft_licensing resource instead, which can be updated without updating the cluster.",
 		},
 		"cluster_info": {
 			Type:     schema.TypeMap,
 			Computed: true,
 		},
+		"vsan_witness": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: "The vSAN witness node of the cluster, if the cluster is stretched across availability zones.",
+		},
 	}
 }
 
-func resourceClusterCreate(d *schema.ResourceData, m interface{}) error {
+func resourceClusterCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	sddcID := d.Get("sddc_id").(string)
 	clusterConfig, err := buildClusterConfig(d)
 	if err != nil {
-		return HandleCreateError("Cluster", err)
+		return toDiagnostics(HandleCreateError("Cluster", err))
 	}
 	// Obtain a lock to allow only a single cluster creation at a time for a specific SDDC.
 	var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+	defer unlockFunction()
 	connectorWrapper := m.(*connector.Wrapper)
 	orgID := m.(*connector.Wrapper).OrgID
-	clusterClient := sddcs.NewClustersClient(connectorWrapper)
-	clusterCreateTask, err := clusterClient.Create(orgID, sddcID, *clusterConfig)
+	clusterClient := api.NewClient(connectorWrapper).Clusters()
+	// The cluster may already be in the process of being created by an interrupted apply, in which
+	// case waiting for its creation is resumed instead of creating another cluster
+	clusterCreateTask, err := findClusterCreateTask(connectorWrapper, sddcID, clusterConfig)
 	if err != nil {
-		return HandleCreateError("Cluster", err)
+		return toDiagnostics(HandleCreateError("Cluster", err))
+	}
+	if clusterCreateTask == nil {
+		createTask, err := clusterClient.Create(orgID, sddcID, *clusterConfig)
+		if err != nil {
+			return toDiagnostics(HandleCreateError("Cluster", err))
+		}
+		clusterCreateTask = &createTask
 	}
 	var clusterID = ""
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
 		taskErr := task.RetryTaskUntilFinished(connectorWrapper,
 			func() (model.Task, error) {
 				return task.GetTask(connectorWrapper, clusterCreateTask.Id)
@@ -183,28 +246,129 @@ func resourceClusterCreate(d *schema.ResourceData, m interface{}) error {
 					d.SetId(clusterID)
 				}
 			})
+		// Per-host provisioning is tracked by sub-tasks of the cluster creation task
+		taskErr = task.WithSubTaskStatus(taskErr, func() ([]model.Task, error) {
+			return task.GetSubTasks(connectorWrapper, clusterCreateTask.Id)
+		}, clusterCreateTask.Id)
 		if taskErr != nil {
 			return taskErr
 		}
 		if clusterID == "" {
 			return resource.NonRetryableError(fmt.Errorf("error getting clusterID"))
 		}
-		err = resourceClusterRead(d, m)
-		if err == nil {
-			return nil
-		}
-		return resource.NonRetryableError(err)
+		return nil
 	})
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	return resourceClusterRead(ctx, d, m)
+}
+
+// findClusterCreateTask looks up the creation task of a cluster of the specified SDDC, that is
+// being deployed with the provided configuration. Returns nil if there is no such cluster.
+func findClusterCreateTask(connectorWrapper *connector.Wrapper, sddcID string, clusterConfig *model.ClusterConfig) (*model.Task, error) {
+	sddc, err := api.NewClient(connectorWrapper).Sddcs().Get(connectorWrapper.OrgID, sddcID)
+	if err != nil {
+		return nil, err
+	}
+	if sddc.ResourceConfig == nil {
+		return nil, nil
+	}
+	clusterCreateTask, err := findClusterTask(connectorWrapper, sddcID, constants.ClusterProvisionTaskType,
+		func(taskClusterID string) bool {
+			for _, cluster := range sddc.ResourceConfig.Clusters {
+				if cluster.ClusterId != taskClusterID || cluster.ClusterState == nil || *cluster.ClusterState != "DEPLOYING" {
+					continue
+				}
+				if int64(len(cluster.EsxHostList)) != clusterConfig.NumHosts {
+					return false
+				}
+				if clusterConfig.HostInstanceType != nil && len(*clusterConfig.HostInstanceType) > 0 && cluster.EsxHostInfo != nil &&
+					cluster.EsxHostInfo.InstanceType != nil && *cluster.EsxHostInfo.InstanceType != *clusterConfig.HostInstanceType {
+					return false
+				}
+				return true
+			}
+			return false
+		})
+	if err != nil || clusterCreateTask == nil {
+		return nil, err
+	}
+	log.Printf("[INFO] A cluster of SDDC %s is already being created, resuming task %s", sddcID, clusterCreateTask.Id)
+	return clusterCreateTask, nil
+}
+
+// findClusterTask looks up an in progress task of the specified type on a cluster of the specified
+// SDDC, for which matchesCluster returns true. Returns nil if there is no such task.
+func findClusterTask(connectorWrapper *connector.Wrapper, sddcID string, taskType string,
+	matchesCluster func(clusterID string) bool) (*model.Task, error) {
+	clusterTasks, err := task.GetInProgressTasks(connectorWrapper, sddcID, taskType)
+	if err != nil {
+		return nil, err
+	}
+	for i, clusterTask := range clusterTasks {
+		if clusterTask.Params == nil || !clusterTask.Params.HasField(constants.ClusterIDFieldName) {
+			continue
+		}
+		clusterID, err := clusterTask.Params.String(constants.ClusterIDFieldName)
+		if err == nil && matchesCluster(clusterID) {
+			return &clusterTasks[i], nil
+		}
+	}
+	return nil, nil
+}
+
+// resourceClusterImport imports a cluster by its cluster_id,sddc_id ID. The host instance type and
+// the Microsoft licensing config are only kept in the state, so they are looked up on import, so
+// that a configuration matching the cluster, e.g. one created from the VMC console, plans no changes.
+func resourceClusterImport(_ context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	idParts := strings.Split(d.Id(), ",")
+	if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+		return nil, fmt.Errorf("unexpected format of ID (%q), expected id,sddc_id", d.Id())
+	}
+	if err := IsValidUUID(idParts[0]); err != nil {
+		return nil, fmt.Errorf("invalid format for id : %v", err)
+	}
+	if err := IsValidUUID(idParts[1]); err != nil {
+		return nil, fmt.Errorf("invalid format for sddc_id : %v", err)
+	}
+	clusterID, sddcID := idParts[0], idParts[1]
+
+	apiClient := api.NewClient(m.(*connector.Wrapper))
+	sddc, err := apiClient.Sddcs().Get(apiClient.OrgID(), sddcID)
+	if err != nil {
+		return nil, HandleDataSourceReadError("SDDC", err)
+	}
+	var cluster *model.Cluster
+	if sddc.ResourceConfig != nil {
+		for i := range sddc.ResourceConfig.Clusters {
+			if sddc.ResourceConfig.Clusters[i].ClusterId == clusterID {
+				cluster = &sddc.ResourceConfig.Clusters[i]
+				break
+			}
+		}
+	}
+	if cluster == nil {
+		return nil, fmt.Errorf("cluster %s not found in SDDC %s", clusterID, sddcID)
+	}
+
+	d.SetId(clusterID)
+	d.Set("sddc_id", sddcID)
+	if cluster.EsxHostInfo != nil && cluster.EsxHostInfo.InstanceType != nil {
+		d.Set("host_instance_type", fromHostInstanceType(*cluster.EsxHostInfo.InstanceType))
+	}
+	d.Set("microsoft_licensing_config", flattenMsftLicenseConfig(cluster.MsftLicenseConfig))
+	return []*schema.ResourceData{d}, nil
 }
 
-func resourceClusterRead(d *schema.ResourceData, m interface{}) error {
-	connectorWrapper := (m.(*connector.Wrapper)).Connector
+func resourceClusterRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
 	clusterID := d.Id()
 	sddcID := d.Get("sddc_id").(string)
 	orgID := (m.(*connector.Wrapper)).OrgID
-	sddc, err := GetSddc(connectorWrapper, orgID, sddcID)
+	sddc, err := apiClient.Sddcs().Get(orgID, sddcID)
 	if err != nil {
-		return HandleReadError(d, "Cluster", clusterID, err)
+		return toDiagnostics(HandleReadError(d, "Cluster", clusterID, err))
 	}
 
 	if *sddc.SddcState == "DELETED" {
@@ -225,6 +389,11 @@ func resourceClusterRead(d *schema.ResourceData, m interface{}) error {
 		return nil
 	}
 	d.SetId(clusterID)
+	// deletion_protection and capacity_precheck are not known to the API, they are kept in the state,
+	// with imported clusters unprotected and not prechecked
+	for _, key := range []string{"deletion_protection", "capacity_precheck"} {
+		d.Set(key, d.Get(key).(bool))
+	}
 	cluster := map[string]string{}
 	for _, clusterConfig := range sddc.ResourceConfig.Clusters {
 		if clusterConfig.ClusterId == clusterID {
@@ -243,15 +412,25 @@ func resourceClusterRead(d *schema.ResourceData, m interface{}) error {
 				}
 			}
 			d.Set("cluster_info", cluster)
+			d.Set("vsan_witness", flattenVsanWitness(clusterConfig.VsanWitness))
 			d.Set("num_hosts", len(clusterConfig.EsxHostList))
 			break
 		}
 	}
 
-	edrsPolicyClient := autoscalercluster.NewEdrsPolicyClient(connectorWrapper)
+	// The hosts may be in the process of being converted, e.g. by an interrupted apply
+	conversionTask, err := findClusterTask(m.(*connector.Wrapper), sddcID, constants.ClusterConversionTaskType,
+		func(taskClusterID string) bool { return taskClusterID == clusterID })
+	if err != nil {
+		log.Printf("[WARN] Unable to look up the conversion tasks of cluster %s: %v", clusterID, err)
+	} else if conversionTask != nil && conversionTask.Status != nil {
+		d.Set("conversion_status", *conversionTask.Status)
+	}
+
+	edrsPolicyClient := apiClient.EdrsPolicy()
 	edrsPolicy, err := edrsPolicyClient.Get(orgID, sddcID, clusterID)
 	if err != nil {
-		return HandleReadError(d, "Cluster", clusterID, err)
+		return toDiagnostics(HandleReadError(d, "Cluster", clusterID, err))
 	}
 	d.Set("edrs_policy_type", *edrsPolicy.PolicyType)
 	d.Set("enable_edrs", edrsPolicy.EnableEdrs)
@@ -260,19 +439,34 @@ func resourceClusterRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourceClusterDelete(d *schema.ResourceData, m interface{}) error {
+func resourceClusterDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	connectorWrapper := m.(*connector.Wrapper)
 	clusterID := d.Id()
+	if err := checkDeletionProtection(d, "Cluster"); err != nil {
+		return toDiagnostics(err)
+	}
 
 	orgID := (m.(*connector.Wrapper)).OrgID
 	sddcID := d.Get("sddc_id").(string)
 	var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
-	clusterClient := sddcs.NewClustersClient(connectorWrapper)
-	clusterDeleteTask, err := clusterClient.Delete(orgID, sddcID, clusterID)
+	defer unlockFunction()
+	clusterClient := api.NewClient(connectorWrapper).Clusters()
+	// The cluster may already be in the process of being deleted by an interrupted apply
+	clusterDeleteTask, err := findClusterTask(connectorWrapper, sddcID, constants.ClusterDeleteTaskType,
+		func(taskClusterID string) bool { return taskClusterID == clusterID })
 	if err != nil {
-		return HandleDeleteError("Cluster", clusterID, err)
+		return toDiagnostics(HandleDeleteError("Cluster", clusterID, err))
+	}
+	if clusterDeleteTask != nil {
+		log.Printf("[INFO] Deletion of cluster %s is already in progress, resuming task %s", clusterID, clusterDeleteTask.Id)
+	} else {
+		deleteTask, err := clusterClient.Delete(orgID, sddcID, clusterID)
+		if err != nil {
+			return toDiagnostics(HandleDeleteError("Cluster", clusterID, err))
+		}
+		clusterDeleteTask = &deleteTask
 	}
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+	return toDiagnostics(task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		taskErr := task.RetryTaskUntilFinished(connectorWrapper,
 			func() (model.Task, error) {
 				return task.GetTask(connectorWrapper, clusterDeleteTask.Id)
@@ -286,41 +480,56 @@ func resourceClusterDelete(d *schema.ResourceData, m interface{}) error {
 		}
 		d.SetId("")
 		return nil
-	})
+	}))
 }
 
-func resourceClusterUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceClusterUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	connectorWrapper := m.(*connector.Wrapper)
-	esxsClient := sddcs.NewEsxsClient(connectorWrapper)
+	apiClient := api.NewClient(connectorWrapper)
+	esxsClient := apiClient.Esxs()
 	sddcID := d.Get("sddc_id").(string)
 	orgID := (m.(*connector.Wrapper)).OrgID
 	clusterID := d.Id()
 
+	// The hosts are converted before any hosts are added, so that the added hosts are of the new type
+	if d.HasChange("host_instance_type") {
+		err := convertClusterHosts(ctx, d, connectorWrapper)
+		if err != nil {
+			// Keep the previous host instance type in the state, so that the next apply resumes the conversion
+			oldHostInstanceType, _ := d.GetChange("host_instance_type")
+			d.Set("host_instance_type", oldHostInstanceType)
+			return toDiagnostics(err)
+		}
+	}
+
 	// Add or remove hosts from a cluster
 	if d.HasChange("num_hosts") {
 		oldTmp, newTmp := d.GetChange("num_hosts")
 		oldNum := oldTmp.(int)
 		newNum := newTmp.(int)
 
-		action := "add"
+		action := hostActionAdd
 		diffNum := newNum - oldNum
 
 		if newNum < oldNum {
-			action = "remove"
+			action = hostActionRemove
 			diffNum = oldNum - newNum
 		}
 
+		// No availability zone is specified, so that the hosts of stretched clusters are
+		// distributed evenly across the availability zones by the service
 		esxConfig := model.EsxConfig{
 			NumHosts:  int64(diffNum),
 			ClusterId: &clusterID,
 		}
 
 		var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+		defer unlockFunction()
 		hostUpdateTask, err := esxsClient.Create(orgID, sddcID, esxConfig, &action)
 		if err != nil {
-			return HandleUpdateError("Cluster", err)
+			return toDiagnostics(HandleUpdateError("Cluster", err))
 		}
-		err = resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		err = waitForHostUpdate(ctx, d, connectorWrapper, hostUpdateTask.Id, sddcID, clusterID, action, newNum, func() *resource.RetryError {
 			taskErr := task.RetryTaskUntilFinished(connectorWrapper,
 				func() (model.Task, error) {
 					return task.GetTask(connectorWrapper, hostUpdateTask.Id)
@@ -329,21 +538,18 @@ func resourceClusterUpdate(d *schema.ResourceData, m interface{}) error {
 				func(task model.Task) {
 					unlockFunction()
 				})
-			if taskErr != nil {
-				return taskErr
-			}
-			err = resourceClusterRead(d, m)
-			if err == nil {
-				return nil
-			}
-			return resource.NonRetryableError(err)
+			return task.WithSubTaskStatus(taskErr, func() ([]model.Task, error) {
+				return task.GetSubTasks(connectorWrapper, hostUpdateTask.Id)
+			}, hostUpdateTask.Id)
 		})
+		// Release the lock before the EDRS policy update below obtains it again
+		unlockFunction()
 		if err != nil {
-			return err
+			return toDiagnostics(err)
 		}
 	}
 	if d.HasChange("edrs_policy_type") || d.HasChange("enable_edrs") || d.HasChange("min_hosts") || d.HasChange("max_hosts") {
-		edrsPolicyClient := autoscalercluster.NewEdrsPolicyClient(connectorWrapper)
+		edrsPolicyClient := apiClient.EdrsPolicy()
 		minHosts := int64(d.Get("min_hosts").(int))
 		maxHosts := int64(d.Get("max_hosts").(int))
 		policyType := d.Get("edrs_policy_type").(string)
@@ -355,15 +561,16 @@ func resourceClusterUpdate(d *schema.ResourceData, m interface{}) error {
 			MaxHosts:   &maxHosts,
 		}
 		if policyType == constants.StorageScaleUpPolicyType && !enableEDRS {
-			return fmt.Errorf("EDRS policy %s is the default and cannot be disabled", constants.StorageScaleUpPolicyType)
+			return toDiagnostics(newAttributeError("enable_edrs", "EDRS policy %s is the default and cannot be disabled", constants.StorageScaleUpPolicyType))
 		}
 		var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+		defer unlockFunction()
 		edrsPolicyUpdateTask, err := edrsPolicyClient.Post(orgID, sddcID, clusterID, *edrsPolicy)
 		if err != nil {
-			return HandleUpdateError("EDRS Policy", err)
+			return toDiagnostics(HandleUpdateError("EDRS Policy", err))
 		}
-		return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-			taskErr := task.RetryTaskUntilFinished(connectorWrapper,
+		err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+			return task.RetryTaskUntilFinished(connectorWrapper,
 				func() (model.Task, error) {
 					return task.GetAutoscalerTask(connectorWrapper, edrsPolicyUpdateTask.Id)
 				},
@@ -371,27 +578,25 @@ func resourceClusterUpdate(d *schema.ResourceData, m interface{}) error {
 				func(task model.Task) {
 					unlockFunction()
 				})
-			if taskErr != nil {
-				return taskErr
-			}
-			err = resourceClusterRead(d, m)
-			if err == nil {
-				return nil
-			}
-			return resource.NonRetryableError(err)
 		})
+		if err != nil {
+			return toDiagnostics(err)
+		}
+		return resourceClusterRead(ctx, d, m)
 	}
-	// Update Microsoft licensing config
-	if d.HasChange("microsoft_licensing_config") {
-		configChangeParam := expandMsftLicenseConfig(d.Get("microsoft_licensing_config").([]interface{}))
-		publishClient := msft_licensing.NewPublishClient(connectorWrapper)
+	// Update Microsoft licensing config. Removing it from the configuration, e.g. after an import, keeps the
+	// licensing of the cluster.
+	configChangeParam := expandMsftLicenseConfig(d.Get("microsoft_licensing_config").([]interface{}))
+	if d.HasChange("microsoft_licensing_config") && configChangeParam != nil {
+		publishClient := apiClient.MsftLicensingPublish()
 		var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+		defer unlockFunction()
 		microsoftLicensingUpdateTask, err := publishClient.Post(orgID, sddcID, clusterID, *configChangeParam)
 		if err != nil {
-			return HandleUpdateError("Microsoft Licensing Config", err)
+			return toDiagnostics(HandleUpdateError("Microsoft Licensing Config", err))
 		}
-		return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-			taskErr := task.RetryTaskUntilFinished(connectorWrapper,
+		err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+			return task.RetryTaskUntilFinished(connectorWrapper,
 				func() (model.Task, error) {
 					return task.GetTask(connectorWrapper, microsoftLicensingUpdateTask.Id)
 				},
@@ -399,18 +604,70 @@ func resourceClusterUpdate(d *schema.ResourceData, m interface{}) error {
 				func(task model.Task) {
 					unlockFunction()
 				})
-			if taskErr != nil {
-				return taskErr
-			}
-			err = resourceClusterRead(d, m)
-			if err == nil {
-				return nil
-			}
-			return resource.NonRetryableError(err)
 		})
+		if err != nil {
+			return toDiagnostics(err)
+		}
+		return resourceClusterRead(ctx, d, m)
+	}
+	return resourceClusterRead(ctx, d, m)
+}
 
+// convertClusterHosts converts the hosts of the cluster to the configured host instance type, or
+// resumes waiting for a conversion started by an interrupted apply.
+func convertClusterHosts(ctx context.Context, d *schema.ResourceData, connectorWrapper *connector.Wrapper) error {
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Id()
+	dataHostInstanceType := d.Get("host_instance_type").(string)
+	// Removing host_instance_type from the configuration keeps the existing hosts
+	if len(dataHostInstanceType) == 0 {
+		return nil
 	}
-	return nil
+	hostInstanceType, err := toHostInstanceType(dataHostInstanceType)
+	if err != nil {
+		return newAttributeError("host_instance_type", "%v", err)
+	}
+	// Setting host_instance_type to the type of the existing hosts, e.g. after an import, needs no conversion
+	clusterInfo := d.Get("cluster_info").(map[string]interface{})
+	if currentHostInstanceType, ok := clusterInfo["host_instance_type"].(string); ok && currentHostInstanceType == hostInstanceType {
+		return nil
+	}
+	conversionTimeout, err := time.ParseDuration(d.Get("conversion_timeout").(string))
+	if err != nil {
+		return newAttributeError("conversion_timeout", "invalid conversion_timeout: %v", err)
+	}
+
+	var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+	defer unlockFunction()
+	conversionTask, err := findClusterTask(connectorWrapper, sddcID, constants.ClusterConversionTaskType,
+		func(taskClusterID string) bool { return taskClusterID == clusterID })
+	if err != nil {
+		return HandleUpdateError("Cluster", err)
+	}
+	var conversionTaskID string
+	if conversionTask != nil {
+		log.Printf("[INFO] Conversion of the hosts of cluster %s is already in progress, resuming task %s", clusterID, conversionTask.Id)
+		conversionTaskID = conversionTask.Id
+	} else {
+		conversionTaskID, err = api.NewClient(connectorWrapper).ClusterConversion().Convert(sddcID, clusterID, hostInstanceType)
+		if err != nil {
+			return HandleUpdateError("Cluster", err)
+		}
+	}
+	return task.RetryContext(ctx, connectorWrapper.TaskPoll, conversionTimeout, func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper,
+			func() (model.Task, error) {
+				conversionTask, err := task.GetTask(connectorWrapper, conversionTaskID)
+				if err == nil && conversionTask.Status != nil {
+					d.Set("conversion_status", *conversionTask.Status)
+				}
+				return conversionTask, err
+			},
+			"error converting hosts of cluster "+clusterID+" to "+hostInstanceType,
+			func(task model.Task) {
+				unlockFunction()
+			})
+	})
 }
 
 // buildClusterConfig extracts the creation of the model.ClusterConfig, so that it's
@@ -4,14 +4,20 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/clusterconversion"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"net/http"
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
@@ -351,3 +357,373 @@ func TestBuildClusterConfig(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceVmcClusterSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":            sddcID,
+		"num_hosts":          3,
+		"host_instance_type": constants.HostInstancetypeI3EN,
+	})
+
+	err := diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	clusterID := d.Id()
+	assert.NotEmpty(t, clusterID)
+	assert.Equal(t, 3, d.Get("num_hosts"))
+	assert.Equal(t, 3, server.ClusterHostCount(sddcID, clusterID))
+	clusterInfo := d.Get("cluster_info").(map[string]interface{})
+	assert.Equal(t, "READY", clusterInfo["cluster_state"])
+	assert.Equal(t, model.SddcConfig_HOST_INSTANCE_TYPE_I3EN_METAL, clusterInfo["host_instance_type"])
+
+	err = diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, 0, server.ClusterHostCount(sddcID, clusterID))
+}
+
+func TestResourceVmcClusterDeletionProtectionSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":             sddcID,
+		"num_hosts":           3,
+		"deletion_protection": true,
+	})
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	clusterID := d.Id()
+	assert.Equal(t, true, d.Get("deletion_protection"))
+
+	assert.ErrorContains(t, diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper)),
+		"deletion_protection is enabled")
+	assert.Equal(t, 3, server.ClusterHostCount(sddcID, clusterID))
+
+	d.Set("deletion_protection", false)
+	assert.NoError(t, diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, 0, server.ClusterHostCount(sddcID, clusterID))
+}
+
+func TestResourceVmcClusterAccessTokenRevokedSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	})
+
+	// The access token expires while the cluster is created, and again before it is deleted
+	server.RevokeAccessTokens()
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, 3, server.ClusterHostCount(sddcID, d.Id()))
+	assert.Equal(t, 2, server.IssuedAccessTokens())
+
+	server.RevokeAccessTokens()
+	clusterID := d.Id()
+	assert.NoError(t, diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, 0, server.ClusterHostCount(sddcID, clusterID))
+	assert.Equal(t, 3, server.IssuedAccessTokens())
+}
+
+func TestResourceVmcClusterRateLimitedSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	sddcPath := "/vmc/api/orgs/" + simulator.TestOrgID + "/sddcs/" + sddcID
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	})
+
+	// Both the Cloud Service Provider and VMC rate limit the requests
+	connectorWrapper.Retry = connector.RetryConfig{MaxRetries: 2, MinDelay: time.Millisecond, MaxDelay: time.Second}
+	server.InjectTransientError(http.MethodPost, constants.CspRefreshURLSuffix, http.StatusTooManyRequests, 2, "0")
+	assert.NoError(t, connectorWrapper.Authenticate())
+	server.InjectTransientError(http.MethodPost, sddcPath+"/clusters", http.StatusTooManyRequests, 1, "0")
+	server.InjectTransientError(http.MethodGet, sddcPath, http.StatusServiceUnavailable, 2, "")
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, 3, server.ClusterHostCount(sddcID, d.Id()))
+
+	// Requests are retried up to MaxRetries times
+	server.InjectTransientError(http.MethodGet, sddcPath, http.StatusTooManyRequests, 3, "0")
+	assert.Error(t, diagsErr(resourceClusterRead(context.Background(), d, connectorWrapper)))
+}
+
+func TestResourceVmcClusterReleasesLockOnFailureSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	sddcPath := "/vmc/api/orgs/" + simulator.TestOrgID + "/sddcs/" + sddcID
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	})
+
+	// Create request rejected
+	server.InjectError(http.MethodPost, sddcPath+"/clusters", http.StatusInternalServerError)
+	assert.Error(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+	server.ClearErrors()
+
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+
+	// Hosts update request rejected
+	server.InjectError(http.MethodPost, sddcPath+"/esxs", http.StatusInternalServerError)
+	assert.Error(t, diagsErr(resourceClusterUpdate(context.Background(), d, connectorWrapper)))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+	server.ClearErrors()
+
+	// Delete request rejected
+	server.InjectError(http.MethodDelete, sddcPath+"/clusters/"+d.Id(), http.StatusInternalServerError)
+	assert.Error(t, diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper)))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+	server.ClearErrors()
+
+	// Delete task failed
+	server.TaskFailureMessage = "simulated task failure"
+	assert.Error(t, diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper)))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+}
+
+func TestResourceVmcClusterSubTaskFailureSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 4,
+	})
+
+	server.TaskFailureMessage = "insufficient capacity"
+	err := diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper))
+	assert.ErrorContains(t, err, "1 of 4 sub-tasks failed, 3 finished, 0 in progress")
+	assert.ErrorContains(t, err, "(HOST-PROVISION)")
+	assert.ErrorContains(t, err, "failed: insufficient capacity")
+}
+
+func TestResourceVmcClusterCanceledSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	})
+
+	// Interrupting the apply stops waiting on a task, that would otherwise not finish in time
+	server.TaskPollsUntilFinished = 1000
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Error(t, diagsErr(resourceClusterCreate(ctx, d, connectorWrapper)))
+	assert.Less(t, time.Since(start), 5*time.Second)
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+}
+
+func TestResourceVmcClusterMultiAZSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	stretchedSddcID := server.AddSddc(simulator.SddcConfig{Name: "stretched", NumHosts: 6, DeploymentType: "MULTI_AZ"})
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "single_az"})
+	newClusterConfig := func(sddcID string, numHosts int) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"sddc_id":   sddcID,
+			"num_hosts": numHosts,
+		})
+	}
+
+	_, err := resourceCluster().Diff(context.Background(), nil, newClusterConfig(stretchedSddcID, 3), connectorWrapper)
+	assert.Error(t, err)
+	_, err = resourceCluster().Diff(context.Background(), nil, newClusterConfig(stretchedSddcID, 4), connectorWrapper)
+	assert.NoError(t, err)
+	_, err = resourceCluster().Diff(context.Background(), nil, newClusterConfig(sddcID, 3), connectorWrapper)
+	assert.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   stretchedSddcID,
+		"num_hosts": 4,
+	})
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "READY", d.Get("vsan_witness.state"))
+}
+
+func TestResourceVmcClusterResumesInterruptedOperationsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.TaskPollsUntilFinished = 2
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	clustersPath := "/vmc/api/orgs/" + simulator.TestOrgID + "/sddcs/" + sddcID + "/clusters"
+	d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	})
+
+	// Creation started by an apply, that was interrupted
+	clusterClient := api.NewClient(connectorWrapper).Clusters()
+	createTask, err := clusterClient.Create(simulator.TestOrgID, sddcID, model.ClusterConfig{NumHosts: 3})
+	assert.NoError(t, err)
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	clusterID := d.Id()
+	assert.Equal(t, 3, server.ClusterHostCount(sddcID, clusterID))
+	assert.Contains(t, server.Requests(), "GET /vmc/api/orgs/"+simulator.TestOrgID+"/tasks/"+createTask.Id)
+
+	// Deletion started by an apply, that was interrupted
+	deleteTask, err := clusterClient.Delete(simulator.TestOrgID, sddcID, clusterID)
+	assert.NoError(t, err)
+	assert.NoError(t, diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, 0, server.ClusterHostCount(sddcID, clusterID))
+	assert.Contains(t, server.Requests(), "GET /vmc/api/orgs/"+simulator.TestOrgID+"/tasks/"+deleteTask.Id)
+
+	creates, deletes := 0, 0
+	for _, request := range server.Requests() {
+		switch request {
+		case "POST " + clustersPath:
+			creates++
+		case "DELETE " + clustersPath + "/" + clusterID:
+			deletes++
+		}
+	}
+	assert.Equal(t, 1, creates)
+	assert.Equal(t, 1, deletes)
+}
+
+func TestResourceVmcClusterScaleTimeoutsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	rawConfig := map[string]interface{}{
+		"sddc_id":          sddcID,
+		"num_hosts":        4,
+		"scale_in_timeout": "1s",
+	}
+	d := schema.TestResourceDataRaw(t, clusterSchema(), rawConfig)
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	clusterID := d.Id()
+
+	// Adding hosts is bounded by the update timeout, as no scale_out_timeout is specified
+	rawConfig["num_hosts"] = 5
+	diff, err := resourceCluster().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	state, diags := resourceCluster().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, 5, server.ClusterHostCount(sddcID, clusterID))
+
+	// The removal of the hosts outlasts the scale_in_timeout
+	server.TaskPollsUntilFinished = 1000
+	rawConfig["num_hosts"] = 3
+	diff, err = resourceCluster().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	start := time.Now()
+	_, diags = resourceCluster().Apply(context.Background(), state, diff, connectorWrapper)
+	assert.Less(t, time.Since(start), 10*time.Second)
+	err = diagsErr(diags)
+	assert.ErrorContains(t, err, "timed out after 1s")
+	assert.ErrorContains(t, err, "the cluster has 5 hosts, 2 hosts are still to be removed")
+	assert.ErrorContains(t, err, "2 in progress")
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+}
+
+func TestResourceVmcClusterHostInstanceTypeConversionSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	rawConfig := map[string]interface{}{
+		"sddc_id":            sddcID,
+		"num_hosts":          3,
+		"host_instance_type": constants.HostInstancetypeI3,
+	}
+	d := schema.TestResourceDataRaw(t, clusterSchema(), rawConfig)
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	clusterID := d.Id()
+	assert.Equal(t, "", d.Get("conversion_status"))
+
+	// The hosts are converted in place, instead of the cluster being replaced
+	rawConfig["host_instance_type"] = constants.HostInstancetypeI4I
+	diff, err := resourceCluster().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	state, diags := resourceCluster().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, clusterID, state.ID)
+	assert.Equal(t, model.SddcConfig_HOST_INSTANCE_TYPE_I4I_METAL, state.Attributes["cluster_info.host_instance_type"])
+	assert.Equal(t, model.Task_STATUS_FINISHED, state.Attributes["conversion_status"])
+	assert.Equal(t, 3, server.ClusterHostCount(sddcID, clusterID))
+	assert.Contains(t, server.Requests(), "POST /vmc/api/orgs/"+simulator.TestOrgID+"/sddcs/"+sddcID+"/clusters/"+clusterID+"/convert")
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+
+	// A failed conversion keeps the previous host instance type, so that the next apply retries it
+	server.TaskFailureMessage = "insufficient i3en.metal capacity"
+	rawConfig["host_instance_type"] = constants.HostInstancetypeI3EN
+	diff, err = resourceCluster().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	state, diags = resourceCluster().Apply(context.Background(), state, diff, connectorWrapper)
+	assert.ErrorContains(t, diagsErr(diags), "insufficient i3en.metal capacity")
+	assert.Equal(t, constants.HostInstancetypeI4I, state.Attributes["host_instance_type"])
+	assert.Equal(t, model.Task_STATUS_FAILED, state.Attributes["conversion_status"])
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+}
+
+func TestResourceVmcClusterResumesInterruptedConversionSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_sddc"})
+	rawConfig := map[string]interface{}{
+		"sddc_id":   sddcID,
+		"num_hosts": 3,
+	}
+	d := schema.TestResourceDataRaw(t, clusterSchema(), rawConfig)
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), d, connectorWrapper)))
+	clusterID := d.Id()
+	convertPath := "/vmc/api/orgs/" + simulator.TestOrgID + "/sddcs/" + sddcID + "/clusters/" + clusterID + "/convert"
+
+	// Conversion started by an apply, that was interrupted
+	server.TaskPollsUntilFinished = 3
+	conversionTaskID, err := clusterconversion.NewConversionClient(*connectorWrapper).
+		Convert(sddcID, clusterID, model.SddcConfig_HOST_INSTANCE_TYPE_I4I_METAL)
+	assert.NoError(t, err)
+	assert.NoError(t, diagsErr(resourceClusterRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, model.Task_STATUS_STARTED, d.Get("conversion_status"))
+
+	rawConfig["host_instance_type"] = constants.HostInstancetypeI4I
+	diff, err := resourceCluster().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	state, diags := resourceCluster().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, model.Task_STATUS_FINISHED, state.Attributes["conversion_status"])
+	assert.Contains(t, server.Requests(), "GET /vmc/api/orgs/"+simulator.TestOrgID+"/tasks/"+conversionTaskID)
+	conversions := 0
+	for _, request := range server.Requests() {
+		if request == "POST "+convertPath {
+			conversions++
+		}
+	}
+	assert.Equal(t, 1, conversions)
+}
+
+func TestResourceVmcClusterImportSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "cluster_import_sddc"})
+	mssqlLicensing := constants.CapitalLicenseConfigEnabled
+	windowsLicensing := constants.CapitalLicenseConfigDisabled
+	academicLicense := false
+	clusterID := server.AddCluster(sddcID, 4, model.SddcConfig_HOST_INSTANCE_TYPE_I3EN_METAL, &model.MsftLicensingConfig{
+		MssqlLicensing: &mssqlLicensing, WindowsLicensing: &windowsLicensing, AcademicLicense: &academicLicense})
+
+	importCluster := func(id string) (*schema.ResourceData, error) {
+		d := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{})
+		d.SetId(id)
+		imported, err := resourceClusterImport(context.Background(), d, connectorWrapper)
+		if err != nil {
+			return nil, err
+		}
+		return imported[0], diagsErr(resourceClusterRead(context.Background(), imported[0], connectorWrapper))
+	}
+
+	d, err := importCluster(clusterID + "," + sddcID)
+	assert.NoError(t, err)
+	assert.Equal(t, clusterID, d.Id())
+	assert.Equal(t, sddcID, d.Get("sddc_id"))
+	assert.Equal(t, 4, d.Get("num_hosts"))
+	assert.Equal(t, constants.HostInstancetypeI3EN, d.Get("host_instance_type"))
+	assert.Equal(t, constants.StorageScaleUpPolicyType, d.Get("edrs_policy_type"))
+	assert.Equal(t, mssqlLicensing, d.Get("microsoft_licensing_config.0.mssql_licensing"))
+	assert.Equal(t, windowsLicensing, d.Get("microsoft_licensing_config.0.windows_licensing"))
+	assert.Equal(t, false, d.Get("deletion_protection"))
+
+	_, err = importCluster(clusterID)
+	assert.ErrorContains(t, err, "expected id,sddc_id")
+	_, err = importCluster(sddcID + "," + sddcID)
+	assert.ErrorContains(t, err, "not found in SDDC")
+}
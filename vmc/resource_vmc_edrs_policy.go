@@ -0,0 +1,201 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	task "github.com/vmware/terraform-provider-vmc/vmc/task"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	autoscalermodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func resourceEdrsPolicy() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceEdrsPolicyCreate,
+		ReadContext:   resourceEdrsPolicyRead,
+		UpdateContext: resourceEdrsPolicyUpdate,
+		DeleteContext: resourceEdrsPolicyDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ",")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected cluster_id,sddc_id", d.Id())
+				}
+				if err := IsValidUUID(idParts[0]); err != nil {
+					return nil, fmt.Errorf("invalid format for cluster_id : %v", err)
+				}
+				if err := IsValidUUID(idParts[1]); err != nil {
+					return nil, fmt.Errorf("invalid format for sddc_id : %v", err)
+				}
+
+				d.SetId(idParts[0])
+				d.Set("cluster_id", idParts[0])
+				d.Set("sddc_id", idParts[1])
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "SDDC identifier",
+			},
+			"cluster_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "Identifier of the cluster the EDRS policy applies to.",
+			},
+			"policy_type": {
+				Type:     schema.TypeString,
+				Required: true,
+				ValidateFunc: validateKnownStringValue(
+					[]string{constants.StorageScaleUpPolicyType, constants.CostPolicyType, constants.PerformancePolicyType, constants.RapidScaleUpPolicyType}),
+				Description: "The EDRS policy type. This can either be 'cost', 'performance', 'storage-scaleup' or 'rapid-scaleup'.",
+			},
+			"enable_edrs": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     true,
+				Description: "True if EDRS is enabled",
+			},
+			"min_hosts": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(constants.MinHosts, constants.MaxHosts),
+				Description:  "The minimum number of hosts that the cluster can scale in to.",
+			},
+			"max_hosts": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(constants.MinHosts, constants.MaxHosts),
+				Description:  "The maximum number of hosts that the cluster can scale out to.",
+			},
+		},
+	}
+}
+
+func resourceEdrsPolicyCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	edrsPolicy, err := expandEdrsPolicy(d)
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	err = postEdrsPolicy(ctx, d, m, edrsPolicy, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return toDiagnostics(HandleCreateError("EDRS Policy", err))
+	}
+	d.SetId(d.Get("cluster_id").(string))
+	return resourceEdrsPolicyRead(ctx, d, m)
+}
+
+func resourceEdrsPolicyRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Id()
+	orgID := (m.(*connector.Wrapper)).OrgID
+	edrsPolicyClient := api.NewClient(m.(*connector.Wrapper)).EdrsPolicy()
+	edrsPolicy, err := edrsPolicyClient.Get(orgID, sddcID, clusterID)
+	if err != nil {
+		return toDiagnostics(HandleReadError(d, "EDRS Policy", clusterID, err))
+	}
+	d.Set("cluster_id", clusterID)
+	d.Set("policy_type", *edrsPolicy.PolicyType)
+	d.Set("enable_edrs", edrsPolicy.EnableEdrs)
+	d.Set("max_hosts", *edrsPolicy.MaxHosts)
+	d.Set("min_hosts", *edrsPolicy.MinHosts)
+	return nil
+}
+
+func resourceEdrsPolicyUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	edrsPolicy, err := expandEdrsPolicy(d)
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	err = postEdrsPolicy(ctx, d, m, edrsPolicy, d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return toDiagnostics(HandleUpdateError("EDRS Policy", err))
+	}
+	return resourceEdrsPolicyRead(ctx, d, m)
+}
+
+// resourceEdrsPolicyDelete reverts the cluster to the default EDRS policy, as the policy of
+// a cluster cannot be removed.
+func resourceEdrsPolicyDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	policyType := constants.StorageScaleUpPolicyType
+	minHosts := int64(d.Get("min_hosts").(int))
+	maxHosts := int64(d.Get("max_hosts").(int))
+	edrsPolicy := autoscalermodel.EdrsPolicy{
+		EnableEdrs: true,
+		PolicyType: &policyType,
+		MinHosts:   &minHosts,
+		MaxHosts:   &maxHosts,
+	}
+	err := postEdrsPolicy(ctx, d, m, edrsPolicy, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return toDiagnostics(HandleDeleteError("EDRS Policy", d.Id(), err))
+	}
+	d.SetId("")
+	return nil
+}
+
+func expandEdrsPolicy(d *schema.ResourceData) (autoscalermodel.EdrsPolicy, error) {
+	policyType := d.Get("policy_type").(string)
+	enableEDRS := d.Get("enable_edrs").(bool)
+	minHosts := int64(d.Get("min_hosts").(int))
+	maxHosts := int64(d.Get("max_hosts").(int))
+	if policyType == constants.StorageScaleUpPolicyType && !enableEDRS {
+		return autoscalermodel.EdrsPolicy{}, newAttributeError("enable_edrs", "EDRS policy %s is the default and cannot be disabled", constants.StorageScaleUpPolicyType)
+	}
+	if minHosts > maxHosts {
+		return autoscalermodel.EdrsPolicy{}, newAttributeError("min_hosts", "min_hosts (%d) cannot be greater than max_hosts (%d)", minHosts, maxHosts)
+	}
+	return autoscalermodel.EdrsPolicy{
+		EnableEdrs: enableEDRS,
+		PolicyType: &policyType,
+		MinHosts:   &minHosts,
+		MaxHosts:   &maxHosts,
+	}, nil
+}
+
+// postEdrsPolicy applies the EDRS policy to the cluster and waits for the autoscaler task to finish.
+// The cluster mutation lock is held while the task runs, as the autoscaler rejects EDRS policy
+// updates on clusters that are being resized.
+func postEdrsPolicy(ctx context.Context, d *schema.ResourceData, m interface{}, edrsPolicy autoscalermodel.EdrsPolicy, timeout time.Duration) error {
+	connectorWrapper := m.(*connector.Wrapper)
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+	var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+	defer unlockFunction()
+	edrsPolicyClient := api.NewClient(connectorWrapper).EdrsPolicy()
+	edrsPolicyUpdateTask, err := edrsPolicyClient.Post(connectorWrapper.OrgID, sddcID, clusterID, edrsPolicy)
+	if err != nil {
+		return err
+	}
+	return task.RetryContext(ctx, connectorWrapper.TaskPoll, timeout, func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper,
+			func() (model.Task, error) {
+				return task.GetAutoscalerTask(connectorWrapper, edrsPolicyUpdateTask.Id)
+			},
+			"error updating EDRS policy configuration "+clusterID,
+			func(task model.Task) {
+				unlockFunction()
+			})
+	})
+}
@@ -0,0 +1,102 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/go-cty/cty"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"testing"
+)
+
+func TestResourceVmcEdrsPolicySimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "edrs_sddc"})
+	primaryCluster, err := api.NewClient(connectorWrapper).PrimaryCluster().Get(connectorWrapper.OrgID, sddcID)
+	assert.NoError(t, err)
+	clusterID := primaryCluster.ClusterId
+
+	d := schema.TestResourceDataRaw(t, resourceEdrsPolicy().Schema, map[string]interface{}{
+		"sddc_id":     sddcID,
+		"cluster_id":  clusterID,
+		"policy_type": constants.CostPolicyType,
+		"min_hosts":   3,
+		"max_hosts":   8,
+	})
+	assert.NoError(t, diagsErr(resourceEdrsPolicyCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, clusterID, d.Id())
+	assert.Equal(t, constants.CostPolicyType, d.Get("policy_type"))
+	assert.Equal(t, true, d.Get("enable_edrs"))
+	assert.Equal(t, 3, d.Get("min_hosts"))
+	assert.Equal(t, 8, d.Get("max_hosts"))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+
+	// The policy is read back from the autoscaler API, so changes made elsewhere are detected
+	cluster := schema.TestResourceDataRaw(t, clusterSchema(), map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	cluster.SetId(clusterID)
+	assert.NoError(t, diagsErr(resourceClusterRead(context.Background(), cluster, connectorWrapper)))
+	assert.Equal(t, constants.CostPolicyType, cluster.Get("edrs_policy_type"))
+	assert.Equal(t, 8, cluster.Get("max_hosts"))
+
+	d.Set("policy_type", constants.PerformancePolicyType)
+	d.Set("max_hosts", 10)
+	assert.NoError(t, diagsErr(resourceEdrsPolicyUpdate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, constants.PerformancePolicyType, d.Get("policy_type"))
+	assert.Equal(t, 10, d.Get("max_hosts"))
+
+	assert.NoError(t, diagsErr(resourceEdrsPolicyDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	d.SetId(clusterID)
+	assert.NoError(t, diagsErr(resourceEdrsPolicyRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, constants.StorageScaleUpPolicyType, d.Get("policy_type"))
+	assert.Equal(t, true, d.Get("enable_edrs"))
+}
+
+func TestResourceVmcEdrsPolicyValidationSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "edrs_sddc"})
+	requestCount := len(server.Requests())
+	testCases := []struct {
+		config    map[string]interface{}
+		attribute string
+	}{
+		{
+			config:    map[string]interface{}{"policy_type": constants.StorageScaleUpPolicyType, "enable_edrs": false, "min_hosts": 3, "max_hosts": 8},
+			attribute: "enable_edrs",
+		},
+		{
+			config:    map[string]interface{}{"policy_type": constants.CostPolicyType, "min_hosts": 8, "max_hosts": 3},
+			attribute: "min_hosts",
+		},
+	}
+	for _, testCase := range testCases {
+		testCase.config["sddc_id"] = sddcID
+		testCase.config["cluster_id"] = "cluster-id"
+		d := schema.TestResourceDataRaw(t, resourceEdrsPolicy().Schema, testCase.config)
+		diags := resourceEdrsPolicyCreate(context.Background(), d, connectorWrapper)
+		if assert.True(t, diags.HasError()) {
+			assert.Equal(t, cty.GetAttrPath(testCase.attribute), diags[0].AttributePath)
+		}
+	}
+	// Invalid policies are rejected before reaching the autoscaler API
+	assert.Len(t, server.Requests(), requestCount)
+}
+
+func TestResourceVmcEdrsPolicyReadNotFoundSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "edrs_sddc"})
+	d := schema.TestResourceDataRaw(t, resourceEdrsPolicy().Schema, map[string]interface{}{
+		"sddc_id":    sddcID,
+		"cluster_id": "missing-cluster",
+	})
+	d.SetId("missing-cluster")
+	assert.NoError(t, diagsErr(resourceEdrsPolicyRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
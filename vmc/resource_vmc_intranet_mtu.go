@@ -0,0 +1,195 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	nsxmodel "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
+)
+
+func resourceIntranetMtu() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceIntranetMtuCreate,
+		ReadContext:   resourceIntranetMtuRead,
+		UpdateContext: resourceIntranetMtuUpdate,
+		DeleteContext: resourceIntranetMtuDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				if err := IsValidUUID(d.Id()); err != nil {
+					return nil, fmt.Errorf("invalid format for sddc_id : %v", err)
+				}
+				d.Set("sddc_id", d.Id())
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Schema: intranetMtuSchema(map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "SDDC identifier",
+			},
+			"mtu": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntBetween(constants.MinIntranetMtuLink, constants.MaxIntranetMtuLink),
+				Description:  "Uplink MTU of the direct connect, SDDC grouping and outposts traffic of the SDDC.",
+			},
+		}),
+	}
+}
+
+// intranetMtuSchema adds the computed Direct Connect attributes, shared by the vmc_intranet_mtu
+// resource and data source, to the provided schema.
+func intranetMtuSchema(s map[string]*schema.Schema) map[string]*schema.Schema {
+	s["bgp_asn"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The BGP ASN paired with the virtual private gateway, that Direct Connect is attached to.",
+	}
+	s["route_preference"] = &schema.Schema{
+		Type:        schema.TypeString,
+		Computed:    true,
+		Description: "The preference of the Direct Connect routes over the route based VPN routes.",
+	}
+	s["advertised_prefixes"] = &schema.Schema{
+		Type:        schema.TypeList,
+		Computed:    true,
+		Elem:        &schema.Schema{Type: schema.TypeString},
+		Description: "The prefixes advertised over Direct Connect to the on-premise datacenter.",
+	}
+	return s
+}
+
+func resourceIntranetMtuCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Get("sddc_id").(string)
+	nsxClient, err := getIntranetMtuNsxClient(m.(*connector.Wrapper), sddcID)
+	if err != nil {
+		return toDiagnostics(HandleCreateError("Intranet MTU uplink", err))
+	}
+	err = updateIntranetMtu(nsxClient, d.Get("mtu").(int))
+	if err != nil {
+		return toDiagnostics(HandleCreateError("Intranet MTU uplink", err))
+	}
+	d.SetId(sddcID)
+	return resourceIntranetMtuRead(ctx, d, m)
+}
+
+func resourceIntranetMtuRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Id()
+	nsxClient, err := getIntranetMtuNsxClient(m.(*connector.Wrapper), sddcID)
+	if err != nil {
+		return toDiagnostics(HandleReadError(d, "Intranet MTU uplink", sddcID, err))
+	}
+	err = setIntranetMtuAttributes(d, nsxClient)
+	if err != nil {
+		return toDiagnostics(HandleReadError(d, "Intranet MTU uplink", sddcID, err))
+	}
+	d.Set("sddc_id", sddcID)
+	return nil
+}
+
+func resourceIntranetMtuUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Id()
+	if d.HasChange("mtu") {
+		nsxClient, err := getIntranetMtuNsxClient(m.(*connector.Wrapper), sddcID)
+		if err != nil {
+			return toDiagnostics(HandleUpdateError("Intranet MTU uplink", err))
+		}
+		err = updateIntranetMtu(nsxClient, d.Get("mtu").(int))
+		if err != nil {
+			return toDiagnostics(HandleUpdateError("Intranet MTU uplink", err))
+		}
+	}
+	return resourceIntranetMtuRead(ctx, d, m)
+}
+
+func resourceIntranetMtuDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Id()
+	// The intranet MTU uplink cannot be removed, so it is restored to its default value
+	nsxClient, err := getIntranetMtuNsxClient(m.(*connector.Wrapper), sddcID)
+	if err != nil {
+		return toDiagnostics(HandleDeleteError("Intranet MTU uplink", sddcID, err))
+	}
+	err = updateIntranetMtu(nsxClient, constants.MinIntranetMtuLink)
+	if err != nil {
+		return toDiagnostics(HandleDeleteError("Intranet MTU uplink", sddcID, err))
+	}
+	d.SetId("")
+	return nil
+}
+
+// getIntranetMtuNsxClient returns a client of the NSX manager of the specified SDDC.
+func getIntranetMtuNsxClient(connectorWrapper *connector.Wrapper, sddcID string) (*api.Client, error) {
+	apiClient := api.NewClient(connectorWrapper)
+	sddc, err := apiClient.Sddcs().Get(apiClient.OrgID(), sddcID)
+	if err != nil {
+		return nil, err
+	}
+	if isZeroCloudSddc(&sddc) {
+		return nil, fmt.Errorf("intranet MTU uplink is not supported for %s provider type", constants.ZeroCloudProviderType)
+	}
+	if sddc.ResourceConfig == nil || sddc.ResourceConfig.NsxApiPublicEndpointUrl == nil {
+		return nil, fmt.Errorf("NSX reverse proxy URL of SDDC %s is not available", sddcID)
+	}
+	return apiClient.ForNsx(*sddc.ResourceConfig.NsxApiPublicEndpointUrl)
+}
+
+func updateIntranetMtu(nsxClient *api.Client, mtu int) error {
+	intranetMtu := int64(mtu)
+	_, err := nsxClient.ExternalConfig().Update(nsxmodel.ExternalConnectivityConfig{IntranetMtu: &intranetMtu})
+	return err
+}
+
+// setIntranetMtuAttributes reads the intranet MTU uplink together with the Direct Connect
+// configuration of an SDDC. The Direct Connect attributes are left empty for SDDCs, that have no
+// Direct Connect configuration.
+func setIntranetMtuAttributes(d *schema.ResourceData, nsxClient *api.Client) error {
+	externalConnectivityConfig, err := nsxClient.ExternalConfig().Get()
+	if err != nil {
+		return err
+	}
+	bgpAsn := ""
+	routePreference := ""
+	bgpInfo, err := nsxClient.DirectConnectBgp().Get()
+	if err != nil && !isNotFoundError(err) {
+		return err
+	}
+	if err == nil {
+		if bgpInfo.LocalAsNum != nil {
+			bgpAsn = *bgpInfo.LocalAsNum
+		}
+		if bgpInfo.RoutePreference != nil {
+			routePreference = *bgpInfo.RoutePreference
+		}
+	}
+	advertisedPrefixes := []string{}
+	advertisedRoutes, err := nsxClient.DirectConnectAdvertisedRoutes().Get()
+	if err != nil && !isNotFoundError(err) {
+		return err
+	}
+	for _, route := range advertisedRoutes.AdvertisedRoutes {
+		if route.Cidr == nil {
+			continue
+		}
+		// Prefixes, that failed to be advertised, are not reachable from the on-premise datacenter
+		if route.AdvertisementState != nil && *route.AdvertisementState != nsxmodel.AdvertisedRoute_ADVERTISEMENT_STATE_SUCCESS {
+			continue
+		}
+		advertisedPrefixes = append(advertisedPrefixes, *route.Cidr)
+	}
+	d.Set("mtu", externalConnectivityConfig.IntranetMtu)
+	d.Set("bgp_asn", bgpAsn)
+	d.Set("route_preference", routePreference)
+	d.Set("advertised_prefixes", advertisedPrefixes)
+	return nil
+}
@@ -0,0 +1,40 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestResourceVmcIntranetMtuSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "dx_sddc"})
+	server.AddDirectConnect(sddcID, "64512", []string{"10.2.0.0/16", "192.168.1.0/24"})
+
+	d := schema.TestResourceDataRaw(t, resourceIntranetMtu().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+		"mtu":     constants.MaxIntranetMtuLink,
+	})
+	assert.NoError(t, diagsErr(resourceIntranetMtuCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, int64(constants.MaxIntranetMtuLink), server.IntranetMtu(sddcID))
+	assert.Equal(t, constants.MaxIntranetMtuLink, d.Get("mtu"))
+	assert.Equal(t, "64512", d.Get("bgp_asn"))
+	assert.Equal(t, []interface{}{"10.2.0.0/16", "192.168.1.0/24"}, d.Get("advertised_prefixes"))
+
+	d.Set("mtu", 8000)
+	assert.NoError(t, diagsErr(resourceIntranetMtuUpdate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, int64(8000), server.IntranetMtu(sddcID))
+
+	// Destroying the resource restores the default MTU
+	assert.NoError(t, diagsErr(resourceIntranetMtuDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, int64(constants.MinIntranetMtuLink), server.IntranetMtu(sddcID))
+}
@@ -4,24 +4,26 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
-	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
 	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
 	"strings"
 
 	"github.com/gofrs/uuid/v5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 )
 
 func resourcePublicIP() *schema.Resource {
 	return &schema.Resource{
-		Create: resourcePublicIPCreate,
-		Read:   resourcePublicIPRead,
-		Update: resourcePublicIPUpdate,
-		Delete: resourcePublicIPDelete,
+		CreateContext: resourcePublicIPCreate,
+		ReadContext:   resourcePublicIPRead,
+		UpdateContext: resourcePublicIPUpdate,
+		DeleteContext: resourcePublicIPDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), ",")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 					return nil, fmt.Errorf("unexpected format of ID (%q), expected public_ip_id,nsxt_reverse_proxy_url", d.Id())
@@ -58,20 +60,20 @@ func resourcePublicIP() *schema.Resource {
 	}
 }
 
-func resourcePublicIPCreate(d *schema.ResourceData, m interface{}) error {
+func resourcePublicIPCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
 	connectorWrapper := m.(*connector.Wrapper)
-	connector, err := getNsxtReverseProxyURLConnector(nsxtReverseProxyURL, connectorWrapper)
+	nsxClient, err := api.NewClient(connectorWrapper).ForNsx(nsxtReverseProxyURL)
 	if err != nil {
-		return HandleCreateError("NSXT reverse proxy URL connector", err)
+		return toDiagnostics(HandleCreateError("NSXT reverse proxy URL connector", err))
 	}
-	publicIpsClient := infra.NewPublicIpsClient(connector)
+	publicIpsClient := nsxClient.PublicIps()
 
 	displayName := d.Get("display_name").(string)
 	// generate random UUID
 	UUIDObject, err := uuid.NewV4()
 	if err != nil {
-		return HandleCreateError("Public IP", err)
+		return toDiagnostics(HandleCreateError("Public IP", err))
 	}
 	UUIDStr := UUIDObject.String()
 
@@ -84,27 +86,27 @@ func resourcePublicIPCreate(d *schema.ResourceData, m interface{}) error {
 	// API call to create public IP
 	publicIP, err := publicIpsClient.Update(UUIDStr, *publicIPModel)
 	if err != nil {
-		return HandleCreateError("Public IP", err)
+		return toDiagnostics(HandleCreateError("Public IP", err))
 	}
 
 	d.SetId(*publicIP.Id)
-	return resourcePublicIPRead(d, m)
+	return resourcePublicIPRead(ctx, d, m)
 }
 
-func resourcePublicIPRead(d *schema.ResourceData, m interface{}) error {
+func resourcePublicIPRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
 	connectorWrapper := m.(*connector.Wrapper)
-	connector, err := getNsxtReverseProxyURLConnector(nsxtReverseProxyURL, connectorWrapper)
+	nsxClient, err := api.NewClient(connectorWrapper).ForNsx(nsxtReverseProxyURL)
 	if err != nil {
-		return HandleCreateError("NSXT reverse proxy URL connector", err)
+		return toDiagnostics(HandleCreateError("NSXT reverse proxy URL connector", err))
 	}
-	publicIpsClient := infra.NewPublicIpsClient(connector)
+	publicIpsClient := nsxClient.PublicIps()
 	uuid := d.Id()
 
 	if len(uuid) > 0 {
 		publicIP, err := publicIpsClient.Get(uuid)
 		if err != nil {
-			return HandleReadError(d, "Public IP", uuid, err)
+			return toDiagnostics(HandleReadError(d, "Public IP", uuid, err))
 		}
 		d.Set("ip", publicIP.Ip)
 		d.Set("display_name", publicIP.DisplayName)
@@ -114,7 +116,7 @@ func resourcePublicIPRead(d *schema.ResourceData, m interface{}) error {
 			// get the list of IPs
 			publicIPResultList, err := publicIpsClient.List(nil, nil, nil, nil, nil)
 			if err != nil {
-				return HandleListError("Public IP", err)
+				return toDiagnostics(HandleListError("Public IP", err))
 			}
 			publicIpsList := publicIPResultList.Results
 			for _, publicIP := range publicIpsList {
@@ -129,14 +131,14 @@ func resourcePublicIPRead(d *schema.ResourceData, m interface{}) error {
 	return nil
 }
 
-func resourcePublicIPUpdate(d *schema.ResourceData, m interface{}) error {
+func resourcePublicIPUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
 	connectorWrapper := m.(*connector.Wrapper)
-	connector, err := getNsxtReverseProxyURLConnector(nsxtReverseProxyURL, connectorWrapper)
+	nsxClient, err := api.NewClient(connectorWrapper).ForNsx(nsxtReverseProxyURL)
 	if err != nil {
-		return HandleCreateError("NSXT reverse proxy URL connector", err)
+		return toDiagnostics(HandleCreateError("NSXT reverse proxy URL connector", err))
 	}
-	publicIpsClient := infra.NewPublicIpsClient(connector)
+	publicIpsClient := nsxClient.PublicIps()
 
 	if d.HasChange("display_name") {
 		uuid := d.Id()
@@ -151,28 +153,28 @@ func resourcePublicIPUpdate(d *schema.ResourceData, m interface{}) error {
 		// API call to update public IP
 		publicIP, err := publicIpsClient.Update(uuid, *publicIPModel)
 		if err != nil {
-			return HandleUpdateError("Public IP", err)
+			return toDiagnostics(HandleUpdateError("Public IP", err))
 		}
 
 		d.Set("display_name", publicIP.DisplayName)
 	}
 
-	return resourcePublicIPRead(d, m)
+	return resourcePublicIPRead(ctx, d, m)
 }
 
-func resourcePublicIPDelete(d *schema.ResourceData, m interface{}) error {
+func resourcePublicIPDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
 	connectorWrapper := m.(*connector.Wrapper)
-	connector, err := getNsxtReverseProxyURLConnector(nsxtReverseProxyURL, connectorWrapper)
+	nsxClient, err := api.NewClient(connectorWrapper).ForNsx(nsxtReverseProxyURL)
 	if err != nil {
-		return HandleCreateError("NSXT reverse proxy URL connector", err)
+		return toDiagnostics(HandleCreateError("NSXT reverse proxy URL connector", err))
 	}
-	publicIpsClient := infra.NewPublicIpsClient(connector)
+	publicIpsClient := nsxClient.PublicIps()
 	uuid := d.Id()
 	forceDelete := true
 	err = publicIpsClient.Delete(uuid, &forceDelete)
 	if err != nil {
-		return HandleDeleteError("Public IP", uuid, err)
+		return toDiagnostics(HandleDeleteError("Public IP", uuid, err))
 	}
 	d.SetId("")
 	return nil
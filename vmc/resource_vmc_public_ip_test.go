@@ -4,10 +4,14 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
-	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
 	"os"
 	"testing"
 
@@ -18,7 +22,7 @@ import (
 )
 
 func TestAccResourceVmcPublicIp_basic(t *testing.T) {
-	displayName := acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
+	displayName := "terraform_test_public_ip_" + acctest.RandStringFromCharSet(10, acctest.CharSetAlphaNum)
 	resourceName := "vmc_public_ip.public_ip_1"
 	resource.Test(t, resource.TestCase{
 		PreCheck:     func() { testAccPreCheck(t) },
@@ -51,12 +55,12 @@ func testAccCheckVmcPublicIPExists(name string) resource.TestCheckFunc {
 		uuid := rs.Primary.Attributes["id"]
 		displayName := rs.Primary.Attributes["display_name"]
 		connectorWrapper := testAccProvider.Meta().(*connector.Wrapper)
-		nsxConnector, err := getNsxtReverseProxyURLConnector(os.Getenv(constants.NsxtReverseProxyURL), connectorWrapper)
+		nsxClient, err := api.NewClient(connectorWrapper).ForNsx(os.Getenv(constants.NsxtReverseProxyURL))
 		if err != nil {
 			return fmt.Errorf("error creating client nsxConnector : %v ", err)
 		}
 
-		publicIpsClient := infra.NewPublicIpsClient(nsxConnector)
+		publicIpsClient := nsxClient.PublicIps()
 		publicIP, err := publicIpsClient.Get(uuid)
 		if err != nil {
 			return fmt.Errorf("error getting public IP with ID %s : %v", uuid, err)
@@ -72,11 +76,11 @@ func testAccCheckVmcPublicIPExists(name string) resource.TestCheckFunc {
 func testCheckVmcPublicIPDestroy(s *terraform.State) error {
 	fmt.Printf("Reverse proxy : %s", os.Getenv(constants.NsxtReverseProxyURL))
 	connectorWrapper := testAccProvider.Meta().(*connector.Wrapper)
-	nsxConnector, err := getNsxtReverseProxyURLConnector(os.Getenv(constants.NsxtReverseProxyURL), connectorWrapper)
+	nsxClient, err := api.NewClient(connectorWrapper).ForNsx(os.Getenv(constants.NsxtReverseProxyURL))
 	if err != nil {
 		return fmt.Errorf("error creating client nsxConnector : %v ", err)
 	}
-	publicIpsClient := infra.NewPublicIpsClient(nsxConnector)
+	publicIpsClient := nsxClient.PublicIps()
 
 	for _, rs := range s.RootModule().Resources {
 		if rs.Type != "vmc_public_ip" {
@@ -124,3 +128,24 @@ func testAccVmcPublicIPResourceImportStateIDFunc(resourceName string) resource.I
 		return fmt.Sprintf("%s,%s", rs.Primary.ID, rs.Primary.Attributes["nsxt_reverse_proxy_url"]), nil
 	}
 }
+
+func TestResourceVmcPublicIPSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "public_ip_sddc"})
+	d := schema.TestResourceDataRaw(t, resourcePublicIP().Schema, map[string]interface{}{
+		"nsxt_reverse_proxy_url": server.NsxtReverseProxyURL(sddcID),
+		"display_name":           "public_ip_1",
+	})
+
+	err := diagsErr(resourcePublicIPCreate(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, d.Id())
+	assert.NotEmpty(t, d.Get("ip"))
+	assert.Equal(t, "public_ip_1", d.Get("display_name"))
+	assert.Equal(t, 1, server.PublicIPCount(sddcID))
+
+	err = diagsErr(resourcePublicIPDelete(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, 0, server.PublicIPCount(sddcID))
+}
@@ -0,0 +1,316 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra"
+	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/gofrs/uuid/v5"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+)
+
+func resourcePublicIPs() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourcePublicIPsCreate,
+		ReadContext:   resourcePublicIPsRead,
+		UpdateContext: resourcePublicIPsUpdate,
+		DeleteContext: resourcePublicIPsDelete,
+		Schema: map[string]*schema.Schema{
+			"nsxt_reverse_proxy_url": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "NSX API public endpoint url used for public IP resource management",
+			},
+			"quantity": {
+				Type:         schema.TypeInt,
+				Required:     true,
+				ValidateFunc: validation.IntAtLeast(1),
+				Description:  "Number of public IPs to allocate.",
+			},
+			"display_name_prefix": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Prefix of the display names of the public IPs. The public IPs are named <prefix>-1 to <prefix>-<quantity>.",
+			},
+			"ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The allocated public IPs, ordered by the index in their display name.",
+			},
+			"public_ip_ids": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Elem:        &schema.Schema{Type: schema.TypeString},
+				Description: "The identifiers of the allocated public IPs, in the same order as ips.",
+			},
+			"public_ips": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The allocated public IPs, in the same order as ips.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"display_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func resourcePublicIPsCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	publicIpsClient, err := getPublicIpsClient(d, m)
+	if err != nil {
+		return toDiagnostics(HandleCreateError("NSXT reverse proxy URL connector", err))
+	}
+	UUIDObject, err := uuid.NewV4()
+	if err != nil {
+		return toDiagnostics(HandleCreateError("Public IPs", err))
+	}
+	d.SetId(UUIDObject.String())
+
+	publicIPIDs, err := reconcilePublicIPs(d, publicIpsClient, []string{})
+	d.Set("public_ip_ids", publicIPIDs)
+	if err != nil {
+		if len(publicIPIDs) == 0 {
+			d.SetId("")
+		}
+		return toDiagnostics(HandleCreateError("Public IPs", err))
+	}
+	return resourcePublicIPsRead(ctx, d, m)
+}
+
+func resourcePublicIPsRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	publicIpsClient, err := getPublicIpsClient(d, m)
+	if err != nil {
+		return toDiagnostics(HandleReadError(d, "NSXT reverse proxy URL connector", d.Id(), err))
+	}
+	publicIPIDs := []string{}
+	ips := []string{}
+	publicIPs := []map[string]interface{}{}
+	for _, publicIPID := range d.Get("public_ip_ids").([]interface{}) {
+		publicIP, err := publicIpsClient.Get(publicIPID.(string))
+		if err != nil {
+			if isNotFoundError(err) {
+				log.Printf("Public IP %s not found, removing it from the state of %s", publicIPID, d.Id())
+				continue
+			}
+			return toDiagnostics(HandleReadError(d, "Public IP", publicIPID.(string), err))
+		}
+		publicIPMap := map[string]interface{}{
+			"id": *publicIP.Id,
+		}
+		if publicIP.Ip != nil {
+			publicIPMap["ip"] = *publicIP.Ip
+			ips = append(ips, *publicIP.Ip)
+		}
+		if publicIP.DisplayName != nil {
+			publicIPMap["display_name"] = *publicIP.DisplayName
+		}
+		publicIPIDs = append(publicIPIDs, *publicIP.Id)
+		publicIPs = append(publicIPs, publicIPMap)
+	}
+	if len(publicIPIDs) == 0 {
+		log.Printf("None of the public IPs of %s were found, removing it from state", d.Id())
+		d.SetId("")
+		return nil
+	}
+	// Setting the quantity to the number of public IPs found means that released
+	// public IPs are allocated again on the next apply.
+	d.Set("quantity", len(publicIPIDs))
+	d.Set("public_ip_ids", publicIPIDs)
+	d.Set("ips", ips)
+	d.Set("public_ips", publicIPs)
+	return nil
+}
+
+func resourcePublicIPsUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	publicIpsClient, err := getPublicIpsClient(d, m)
+	if err != nil {
+		return toDiagnostics(HandleUpdateError("NSXT reverse proxy URL connector", err))
+	}
+	currentIDs := []string{}
+	for _, publicIPID := range d.Get("public_ip_ids").([]interface{}) {
+		currentIDs = append(currentIDs, publicIPID.(string))
+	}
+	publicIPIDs, err := reconcilePublicIPs(d, publicIpsClient, currentIDs)
+	d.Set("public_ip_ids", publicIPIDs)
+	if err != nil {
+		return toDiagnostics(HandleUpdateError("Public IPs", err))
+	}
+	return resourcePublicIPsRead(ctx, d, m)
+}
+
+func resourcePublicIPsDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	publicIpsClient, err := getPublicIpsClient(d, m)
+	if err != nil {
+		return toDiagnostics(HandleDeleteError("NSXT reverse proxy URL connector", d.Id(), err))
+	}
+	publicIPIDs := []string{}
+	for _, publicIPID := range d.Get("public_ip_ids").([]interface{}) {
+		publicIPIDs = append(publicIPIDs, publicIPID.(string))
+	}
+	remainingIDs, err := releasePublicIPs(publicIpsClient, publicIPIDs)
+	if err != nil {
+		d.Set("public_ip_ids", remainingIDs)
+		return toDiagnostics(HandleDeleteError("Public IPs", d.Id(), err))
+	}
+	d.SetId("")
+	return nil
+}
+
+func getPublicIpsClient(d *schema.ResourceData, m interface{}) (infra.PublicIpsClient, error) {
+	nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
+	nsxClient, err := api.NewClient(m.(*connector.Wrapper)).ForNsx(nsxtReverseProxyURL)
+	if err != nil {
+		return nil, err
+	}
+	return nsxClient.PublicIps(), nil
+}
+
+// reconcilePublicIPs makes the public IPs with the provided IDs match the quantity and display
+// name prefix of the resource. Public IPs are renamed in place, missing ones are allocated and
+// the ones beyond the quantity are released.
+// The NSX API has no bulk allocation, so the public IPs are allocated with up to
+// constants.MaxConcurrentPublicIPRequests concurrent requests instead.
+// The IDs of the public IPs, that exist after the reconciliation, are returned also on error,
+// so that partially allocated public IPs are kept in state.
+func reconcilePublicIPs(d *schema.ResourceData, publicIpsClient infra.PublicIpsClient, publicIPIDs []string) ([]string, error) {
+	quantity := d.Get("quantity").(int)
+	displayNamePrefix := d.Get("display_name_prefix").(string)
+	currentDisplayNames := map[string]string{}
+	for _, publicIP := range d.Get("public_ips").([]interface{}) {
+		publicIPMap := publicIP.(map[string]interface{})
+		currentDisplayNames[publicIPMap["id"].(string)] = publicIPMap["display_name"].(string)
+	}
+
+	var released []string
+	if len(publicIPIDs) > quantity {
+		released = publicIPIDs[quantity:]
+		publicIPIDs = publicIPIDs[:quantity]
+	}
+	results := make([]string, quantity)
+	errs := make([]error, quantity)
+	runConcurrently(quantity, func(i int) {
+		displayName := fmt.Sprintf("%s-%d", displayNamePrefix, i+1)
+		var publicIPID string
+		if i < len(publicIPIDs) {
+			publicIPID = publicIPIDs[i]
+			if currentDisplayNames[publicIPID] == displayName {
+				results[i] = publicIPID
+				return
+			}
+		} else {
+			UUIDObject, err := uuid.NewV4()
+			if err != nil {
+				errs[i] = err
+				return
+			}
+			publicIPID = UUIDObject.String()
+		}
+		publicIP, err := publicIpsClient.Update(publicIPID, model.PublicIp{
+			DisplayName: &displayName,
+			Id:          &publicIPID,
+		})
+		if err != nil {
+			errs[i] = fmt.Errorf("failed to allocate public IP %s: %v", displayName, err)
+			if i < len(publicIPIDs) {
+				// the public IP exists, only renaming it failed
+				results[i] = publicIPID
+			}
+			return
+		}
+		results[i] = *publicIP.Id
+	})
+
+	reconciledIDs := []string{}
+	for _, publicIPID := range results {
+		if len(publicIPID) > 0 {
+			reconciledIDs = append(reconciledIDs, publicIPID)
+		}
+	}
+	remainingIDs, err := releasePublicIPs(publicIpsClient, released)
+	reconciledIDs = append(reconciledIDs, remainingIDs...)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return reconciledIDs, joinErrors(errs)
+}
+
+// releasePublicIPs concurrently releases the public IPs with the provided IDs.
+// The IDs of the public IPs, that could not be released, are returned.
+func releasePublicIPs(publicIpsClient infra.PublicIpsClient, publicIPIDs []string) ([]string, error) {
+	errs := make([]error, len(publicIPIDs))
+	runConcurrently(len(publicIPIDs), func(i int) {
+		forceDelete := true
+		err := publicIpsClient.Delete(publicIPIDs[i], &forceDelete)
+		if err != nil && !isNotFoundError(err) {
+			errs[i] = fmt.Errorf("failed to release public IP %s: %v", publicIPIDs[i], err)
+		}
+	})
+	remainingIDs := []string{}
+	for i, err := range errs {
+		if err != nil {
+			remainingIDs = append(remainingIDs, publicIPIDs[i])
+		}
+	}
+	return remainingIDs, joinErrors(errs)
+}
+
+// runConcurrently calls task for each index up to count, with at most
+// constants.MaxConcurrentPublicIPRequests calls running at the same time.
+func runConcurrently(count int, task func(i int)) {
+	semaphore := make(chan struct{}, constants.MaxConcurrentPublicIPRequests)
+	var waitGroup sync.WaitGroup
+	for i := 0; i < count; i++ {
+		waitGroup.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-semaphore
+				waitGroup.Done()
+			}()
+			task(i)
+		}(i)
+	}
+	waitGroup.Wait()
+}
+
+// joinErrors combines the non-nil errors into a single one, or returns nil if there are none.
+func joinErrors(errs []error) error {
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
@@ -0,0 +1,73 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+)
+
+func TestResourceVmcPublicIPsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "public_ips_sddc"})
+	d := schema.TestResourceDataRaw(t, resourcePublicIPs().Schema, map[string]interface{}{
+		"nsxt_reverse_proxy_url": server.NsxtReverseProxyURL(sddcID),
+		"quantity":               12,
+		"display_name_prefix":    "web",
+	})
+
+	assert.NoError(t, diagsErr(resourcePublicIPsCreate(context.Background(), d, connectorWrapper)))
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, 12, server.PublicIPCount(sddcID))
+	assert.Len(t, d.Get("ips"), 12)
+	assert.Len(t, d.Get("public_ip_ids"), 12)
+	assert.Equal(t, "web-1", d.Get("public_ips.0.display_name"))
+	assert.Equal(t, "web-12", d.Get("public_ips.11.display_name"))
+	assert.Equal(t, d.Get("ips.11"), d.Get("public_ips.11.ip"))
+	firstID := d.Get("public_ip_ids.0")
+
+	// Renaming keeps the allocated public IPs
+	d.Set("quantity", 14)
+	d.Set("display_name_prefix", "app")
+	assert.NoError(t, diagsErr(resourcePublicIPsUpdate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, 14, server.PublicIPCount(sddcID))
+	assert.Equal(t, firstID, d.Get("public_ip_ids.0"))
+	assert.Equal(t, "app-1", d.Get("public_ips.0.display_name"))
+	assert.Equal(t, "app-14", d.Get("public_ips.13.display_name"))
+
+	d.Set("quantity", 2)
+	assert.NoError(t, diagsErr(resourcePublicIPsUpdate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, 2, server.PublicIPCount(sddcID))
+	assert.Equal(t, firstID, d.Get("public_ip_ids.0"))
+	assert.Len(t, d.Get("ips"), 2)
+
+	assert.NoError(t, diagsErr(resourcePublicIPsDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, 0, server.PublicIPCount(sddcID))
+}
+
+func TestResourceVmcPublicIPsReadDropsReleasedIPs(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "public_ips_sddc"})
+	d := schema.TestResourceDataRaw(t, resourcePublicIPs().Schema, map[string]interface{}{
+		"nsxt_reverse_proxy_url": server.NsxtReverseProxyURL(sddcID),
+		"quantity":               3,
+		"display_name_prefix":    "web",
+	})
+	assert.NoError(t, diagsErr(resourcePublicIPsCreate(context.Background(), d, connectorWrapper)))
+
+	publicIpsClient, err := getPublicIpsClient(d, connectorWrapper)
+	assert.NoError(t, err)
+	forceDelete := true
+	assert.NoError(t, publicIpsClient.Delete(d.Get("public_ip_ids.1").(string), &forceDelete))
+
+	assert.NoError(t, diagsErr(resourcePublicIPsRead(context.Background(), d, connectorWrapper)))
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, 2, d.Get("quantity"))
+	assert.Equal(t, "web-3", d.Get("public_ips.1.display_name"))
+}
@@ -8,30 +8,28 @@ import (
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/inventory"
 	task "github.com/vmware/terraform-provider-vmc/vmc/task"
-	"github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/infra/external"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/sddcs/clusters/msft_licensing"
 	"log"
 	"strings"
 	"time"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	nsx_vmc_appModel "github.com/vmware/vsphere-automation-sdk-go/services/nsxt-vmc-aws-integration/nsx_vmc_app/model"
-	autoscalercluster "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/api/orgs/sddcs/clusters"
 	autoscalermodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/model"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs/sddcs"
 )
 
 func resourceSddc() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSddcCreate,
-		Read:   resourceSddcRead,
-		Update: resourceSddcUpdate,
-		Delete: resourceSddcDelete,
+		CreateContext: resourceSddcCreate,
+		ReadContext:   resourceSddcRead,
+		UpdateContext: resourceSddcUpdate,
+		DeleteContext: resourceSddcDelete,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -40,10 +38,75 @@ func resourceSddc() *schema.Resource {
 			Update: schema.DefaultTimeout(300 * time.Minute),
 			Delete: schema.DefaultTimeout(180 * time.Minute),
 		},
-		Schema: sddcSchema(),
+		// Removing an attribute or changing its type requires bumping the SchemaVersion and adding a
+		// StateUpgrader from the pinned state schema of the previous version, see TestResourceStateSchemas.
+		SchemaVersion: 0,
+		Schema:        sddcSchema(),
+		CustomizeDiff: customizeSddcDiff,
 	}
 }
 
+// customizeSddcDiff fails the plan, rather than the apply, of an SDDC configuration the VMC API
+// would reject: a MultiAZ primary cluster that cannot be split evenly across the availability
+// zones, a downsize of the appliances, overlapping or reserved CIDRs, AWS infrastructure on a
+// ZEROCLOUD SDDC, a template_name without retain_configuration, or more hosts than available,
+// if capacity_precheck is enabled. It also marks cloud_password as recomputed whenever
+// cloud_password_keepers change, so it is re-read from the SDDC. The password itself is not reset.
+func customizeSddcDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	// Fail the plan, rather than the apply, if the primary cluster of a MultiAZ SDDC would not be split
+	// evenly across the availability zones
+	if d.Get("deployment_type").(string) == constants.MultiAvailabilityZone && d.NewValueKnown("num_host") &&
+		(d.Id() == "" || d.HasChange("num_host")) {
+		if err := validateStretchedClusterHostCount(d.Get("num_host").(int)); err != nil {
+			return err
+		}
+	}
+	// The appliances of an existing SDDC can only be upsized
+	if d.Id() != "" && d.HasChange("size") {
+		oldSize, newSize := d.GetChange("size")
+		if !strings.EqualFold(oldSize.(string), newSize.(string)) && !strings.EqualFold(newSize.(string), constants.LargeSddcSize) {
+			return fmt.Errorf("downsizing the SDDC from %s to %s is not supported by the VMC API", oldSize, newSize)
+		}
+	}
+	if err := validateSddcCidrs(d, m); err != nil {
+		return err
+	}
+	if err := validateZeroCloudSddc(d); err != nil {
+		return err
+	}
+	if len(d.Get("template_name").(string)) > 0 && !d.Get("retain_configuration").(bool) {
+		return newAttributeError("template_name", "template_name requires retain_configuration to be true")
+	}
+	if err := precheckSddcCapacity(d, m); err != nil {
+		return err
+	}
+	if d.Id() != "" && d.HasChange("cloud_password_keepers") {
+		return d.SetNewComputed("cloud_password")
+	}
+	return nil
+}
+
+// validateZeroCloudSddc fails the plan of a ZEROCLOUD SDDC, if it configures AWS infrastructure, that
+// ZEROCLOUD SDDCs do not have, as the configuration would be ignored or rejected after the SDDC is created.
+func validateZeroCloudSddc(d *schema.ResourceDiff) error {
+	if d.Get("provider_type").(string) != constants.ZeroCloudProviderType {
+		return nil
+	}
+	if d.NewValueKnown("intranet_mtu_uplink") && d.Get("intranet_mtu_uplink").(int) != constants.MinIntranetMtuLink {
+		return newAttributeError("intranet_mtu_uplink", "intranet_mtu_uplink cannot be configured for %s SDDCs, "+
+			"which have no intranet uplink", constants.ZeroCloudProviderType)
+	}
+	return nil
+}
+
+// isZeroCloudSddc returns true for the zero-cost SDDCs of the ZEROCLOUD provider, which have no AWS infrastructure.
+func isZeroCloudSddc(sddc *model.Sddc) bool {
+	if sddc.ResourceConfig != nil && len(sddc.ResourceConfig.Provider) > 0 {
+		return sddc.ResourceConfig.Provider == constants.ZeroCloudProviderType
+	}
+	return sddc.Provider != nil && *sddc.Provider == constants.ZeroCloudProviderType
+}
+
 // sddcSchema this helper function extracts the creation of the SDDC schema, so that
 // it's made available for mocking in tests.
 func sddcSchema() map[string]*schema.Schema {
@@ -59,7 +122,7 @@ func sddcSchema() map[string]*schema.Schema {
 			Default:  constants.MediumSddcSize,
 			ValidateFunc: validation.StringInSlice([]string{
 				constants.MediumSddcSize, constants.CapitalMediumSddcSize, constants.LargeSddcSize, constants.CapitalLargeSddcSize}, false),
-			Description: "The size of the vCenter and NSX appliances. 'large' or 'LARGE' SDDC size corresponds to a large vCenter appliance and large NSX appliance. 'medium' or 'MEDIUM' SDDC size corresponds to medium vCenter appliance and medium NSX appliance. Default : 'medium'.",
+			Description: "The size of the vCenter and NSX appliances. 'large' or 'LARGE' SDDC size corresponds to a large vCenter appliance and large NSX appliance. 'medium' or 'MEDIUM' SDDC size corresponds to medium vCenter appliance and medium NSX appliance. Default : 'medium'. Changing it from 'medium' to 'large' upsizes the appliances of an existing SDDC, downsizing is not supported.",
 		},
 		"account_link_sddc_config": {
 			Type: schema.TypeList,
@@ -92,6 +155,10 @@ func sddcSchema() map[string]*schema.Schema {
 			ValidateFunc: validation.IntAtLeast(1),
 			Description:  "The amount of hosts in the primary cluster of the SDDC",
 		},
+		"scale_out_timeout": hostScalingTimeoutSchema("The time to wait for hosts to be added to the primary cluster, " +
+			"e.g. 90m or 6h. Defaults to the update timeout of the resource."),
+		"scale_in_timeout": hostScalingTimeoutSchema("The time to wait for hosts to be removed from the primary cluster, " +
+			"e.g. 90m or 6h. Defaults to the update timeout of the resource."),
 		"sddc_type": {
 			Type:     schema.TypeString,
 			Optional: true,
@@ -107,6 +174,26 @@ func sddcSchema() map[string]*schema.Schema {
 			Default:  false,
 			ForceNew: true,
 		},
+		"deletion_protection": deletionProtectionSchema("SDDC"),
+		"capacity_precheck":   capacityPrecheckSchema(),
+		"force_delete": {
+			Type:     schema.TypeBool,
+			Optional: true,
+			Default:  false,
+			Description: "Deletes the SDDC forcefully, e.g. when it is stuck in a failed state. " +
+				"Must not be used while a task is running against the SDDC. Restricted by VMware Cloud on AWS.",
+		},
+		"retain_configuration": {
+			Type:        schema.TypeBool,
+			Optional:    true,
+			Default:     false,
+			Description: "Retains the configuration of the SDDC as a template for later use, when the SDDC is deleted.",
+		},
+		"template_name": {
+			Type:        schema.TypeString,
+			Optional:    true,
+			Description: "Name of the configuration template retained, when the SDDC is deleted with retain_configuration.",
+		},
 		"provider_type": {
 			Type:     schema.TypeString,
 			Optional: true,
@@ -137,9 +224,9 @@ func sddcSchema() map[string]*schema.Schema {
 			Optional: true,
 			ForceNew: true,
 			Default:  constants.SingleAvailabilityZone,
-			ValidateFunc: validation.StringInSlice([]string{
+			ValidateFunc: validateKnownStringValue([]string{
 				constants.SingleAvailabilityZone, constants.MultiAvailabilityZone,
-			}, false),
+			}),
 		},
 		"region": {
 			Type:     schema.TypeString,
@@ -157,19 +244,18 @@ func sddcSchema() map[string]*schema.Schema {
 			Computed: true,
 		},
 		"host_instance_type": {
-			Type:     schema.TypeString,
-			Optional: true,
-			ForceNew: true,
-			ValidateFunc: validation.StringInSlice(
-				[]string{constants.HostInstancetypeI3, constants.HostInstancetypeI3EN, constants.HostInstancetypeI4I}, false),
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validateHostInstanceType,
 		},
 		"edrs_policy_type": {
 			Type: schema.TypeString,
 			// Exact value known after create
 			Optional: true,
 			Computed: true,
-			ValidateFunc: validation.StringInSlice(
-				[]string{constants.StorageScaleUpPolicyType, constants.CostPolicyType, constants.PerformancePolicyType, constants.RapidScaleUpPolicyType}, false),
+			ValidateFunc: validateKnownStringValue(
+				[]string{constants.StorageScaleUpPolicyType, constants.CostPolicyType, constants.PerformancePolicyType, constants.RapidScaleUpPolicyType}),
 			Description: "The EDRS policy type. This can either be 'cost', 'performance', 'storage-scaleup' or 'rapid-scaleup'. Default : storage-scaleup. ",
 		},
 		"enable_edrs": {
@@ -222,6 +308,7 @@ func sddcSchema() map[string]*schema.Schema {
 			},
 			Optional:    true,
 			Description: "Indicates the desired licensing support, if any, of Microsoft software.",
+			Deprecated:  "Use the vmc_sddc_microsoft_licensing resource instead, which can be updated without updating the SDDC.",
 		},
 		"intranet_mtu_uplink": {
 			Type:         schema.TypeInt,
@@ -234,21 +321,40 @@ func sddcSchema() map[string]*schema.Schema {
 			Type:     schema.TypeString,
 			Computed: true,
 		},
+		"provisioning_phase": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The phase of the creation of the SDDC, while it is being deployed. Empty once the SDDC is ready.",
+		},
 		"vc_url": {
-			Type:     schema.TypeString,
-			Computed: true,
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "URL of the vCenter of the SDDC.",
 		},
 		"cloud_username": {
-			Type:     schema.TypeString,
-			Computed: true,
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The cloudadmin user of the SDDC vCenter.",
 		},
 		"cloud_password": {
-			Type:     schema.TypeString,
-			Computed: true,
+			Type:        schema.TypeString,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "Password of the cloudadmin user of the SDDC vCenter.",
+		},
+		"cloud_password_keepers": {
+			Type: schema.TypeMap,
+			Elem: &schema.Schema{
+				Type: schema.TypeString,
+			},
+			Optional: true,
+			Description: "Arbitrary map of values that, when changed, force cloud_password to be re-read from the SDDC. " +
+				"Changing them does not reset the cloudadmin password.",
 		},
 		"nsxt_reverse_proxy_url": {
-			Type:     schema.TypeString,
-			Computed: true,
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "NSX API public endpoint url of the SDDC.",
 		},
 		"cluster_info": {
 			Type:     schema.TypeMap,
@@ -263,6 +369,27 @@ func sddcSchema() map[string]*schema.Schema {
 			Computed: true,
 			Elem:     &schema.Schema{Type: schema.TypeString},
 		},
+		"secondary_availability_zone": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The availability zone, that the primary cluster of a MultiAZ SDDC is stretched to.",
+		},
+		"witness_availability_zone": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "The availability zone of the vSAN witness node of a MultiAZ SDDC.",
+		},
+		"vsan_witness": {
+			Type:        schema.TypeMap,
+			Computed:    true,
+			Description: "The vSAN witness node of the primary cluster of a MultiAZ SDDC.",
+		},
+		"sddc_security": {
+			Type:        schema.TypeList,
+			Computed:    true,
+			Description: "The security profile of the SDDC, e.g. PROFILE_PCI_COMMERCIAL for PCI hardened SDDCs. Setting it is not supported.",
+			Elem:        sddcSecurityElem(),
+		},
 		"nsxt_ui": {
 			Type:     schema.TypeBool,
 			Optional: true,
@@ -274,9 +401,11 @@ func sddcSchema() map[string]*schema.Schema {
 			Computed: true,
 		},
 		"nsxt_cloudadmin_password": {
-			Type:     schema.TypeString,
-			Optional: true,
-			Computed: true,
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "Password of the NSX cloudadmin user.",
 		},
 		"nsxt_cloudaudit": {
 			Type:     schema.TypeString,
@@ -284,9 +413,11 @@ func sddcSchema() map[string]*schema.Schema {
 			Computed: true,
 		},
 		"nsxt_cloudaudit_password": {
-			Type:     schema.TypeString,
-			Optional: true,
-			Computed: true,
+			Type:        schema.TypeString,
+			Optional:    true,
+			Computed:    true,
+			Sensitive:   true,
+			Description: "Password of the NSX cloudaudit user.",
 		},
 		"nsxt_private_ip": {
 			Type:     schema.TypeString,
@@ -343,59 +474,105 @@ func sddcSchema() map[string]*schema.Schema {
 	}
 }
 
-func resourceSddcCreate(d *schema.ResourceData, m interface{}) error {
+func resourceSddcCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	connectorWrapper := m.(*connector.Wrapper)
-	sddcClient := orgs.NewSddcsClient(connectorWrapper)
+	sddcClient := api.NewClient(connectorWrapper).Sddcs()
 	orgID := connectorWrapper.OrgID
 
 	var awsSddcConfig, err = buildAwsSddcConfig(d)
 	if err != nil {
-		return err
+		return toDiagnostics(err)
 	}
 
-	// Create a Sddc
-	sddcCreateTask, err := sddcClient.Create(orgID, *awsSddcConfig, nil)
+	// The SDDC may already be in the process of being created by an interrupted apply, in which
+	// case waiting for its creation is resumed instead of creating a duplicate SDDC
+	sddcCreateTask, err := findSddcCreateTask(connectorWrapper, d.Get("sddc_name").(string))
 	if err != nil {
-		return HandleCreateError("SDDC", err)
+		return toDiagnostics(HandleCreateError("SDDC", err))
+	}
+	if sddcCreateTask == nil {
+		// Create a Sddc
+		createTask, err := sddcClient.Create(orgID, *awsSddcConfig, nil)
+		if err != nil {
+			return toDiagnostics(HandleCreateError("SDDC", err))
+		}
+		sddcCreateTask = &createTask
 	}
 
 	sddcID := sddcCreateTask.ResourceId
 	d.SetId(*sddcID)
 	msftLicensingConfig := expandMsftLicenseConfig(d.Get("microsoft_licensing_config").([]interface{}))
 
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 			return task.GetTask(connectorWrapper, sddcCreateTask.Id)
 		}, "error creating SDDC", nil)
-		if taskErr != nil {
-			return taskErr
+	})
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	diags := resourceSddcRead(ctx, d, m)
+	if diags.HasError() {
+		return diags
+	}
+
+	// Updating the microsoft_license_config after creation since
+	// the backend API throws an error when non nil microsoft_licensing_config
+	// is present in the sddc spec
+	if msftLicensingConfig != nil {
+		return updateMsftLicenseConfig(ctx, d, m, msftLicensingConfig)
+	}
+	return diags
+}
+
+// findSddcCreateTask looks up the creation task of an SDDC with the specified name, that is being
+// deployed. Returns nil if there is no such SDDC.
+func findSddcCreateTask(connectorWrapper *connector.Wrapper, sddcName string) (*model.Task, error) {
+	sddcs, err := api.NewClient(connectorWrapper).Sddcs().List(connectorWrapper.OrgID, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, sddc := range sddcs {
+		if sddc.Name == nil || *sddc.Name != sddcName {
+			continue
+		}
+		if sddc.SddcState == nil || *sddc.SddcState != model.Sddc_SDDC_STATE_DEPLOYING {
+			continue
 		}
-		err = resourceSddcRead(d, m)
+		sddcCreateTasks, err := task.GetInProgressTasks(connectorWrapper, sddc.Id, constants.SddcProvisionTaskType)
 		if err != nil {
-			return resource.NonRetryableError(err)
+			return nil, err
 		}
-
-		// Updating the microsoft_license_config after creation since
-		// the backend API throws an error when non nil microsoft_licensing_config
-		// is present in the sddc spec
-		if msftLicensingConfig != nil {
-			err = updateMsftLicenseConfig(d, m, msftLicensingConfig)
-			if err != nil {
-				return resource.NonRetryableError(err)
-			}
+		if len(sddcCreateTasks) > 0 {
+			log.Printf("[INFO] SDDC %s with name %s is already being created, resuming task %s",
+				sddc.Id, sddcName, sddcCreateTasks[0].Id)
+			return &sddcCreateTasks[0], nil
 		}
+	}
+	return nil, nil
+}
 
-		return nil
-	})
+// lookupSddcProvisioningPhase returns the phase of the creation task of the SDDC, or an empty string
+// if it is not known, e.g. because the task reports no phase.
+func lookupSddcProvisioningPhase(connectorWrapper *connector.Wrapper, sddcID string) string {
+	sddcCreateTasks, err := task.GetInProgressTasks(connectorWrapper, sddcID, constants.SddcProvisionTaskType)
+	if err != nil {
+		log.Printf("[WARN] Unable to look up the creation task of SDDC %s: %v", sddcID, err)
+		return ""
+	}
+	if len(sddcCreateTasks) == 0 {
+		return ""
+	}
+	return task.ProvisioningPhase(sddcCreateTasks[0])
 }
 
-func resourceSddcRead(d *schema.ResourceData, m interface{}) error {
-	connectorWrapper := m.(*connector.Wrapper)
+func resourceSddcRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	apiClient := api.NewClient(m.(*connector.Wrapper))
 	sddcID := d.Id()
 	orgID := (m.(*connector.Wrapper)).OrgID
-	sddc, err := GetSddc(connectorWrapper.Connector, orgID, sddcID)
+	sddc, err := apiClient.Sddcs().Get(orgID, sddcID)
 	if err != nil {
-		return HandleReadError(d, "SDDC", sddcID, err)
+		return toDiagnostics(HandleReadError(d, "SDDC", sddcID, err))
 	}
 
 	if *sddc.SddcState == "DELETED" {
@@ -405,6 +582,11 @@ func resourceSddcRead(d *schema.ResourceData, m interface{}) error {
 	}
 
 	d.SetId(sddc.Id)
+	// The deletion options and capacity_precheck are not known to the API, they are kept in the state,
+	// with imported SDDCs unprotected, deleted with the default options and not prechecked
+	for _, key := range []string{"deletion_protection", "force_delete", "retain_configuration", "capacity_precheck"} {
+		d.Set(key, d.Get(key).(bool))
+	}
 
 	d.Set("sddc_name", sddc.Name)
 	// The Terraform SDK does not support the use of time.Time type, so save the string
@@ -424,10 +606,15 @@ func resourceSddcRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("account_link_state", sddc.AccountLinkState)
 	d.Set("sddc_access_state", sddc.SddcAccessState)
 	d.Set("sddc_state", sddc.SddcState)
-	primaryClusterClient := sddcs.NewPrimaryclusterClient(connectorWrapper.Connector)
+	provisioningPhase := ""
+	if *sddc.SddcState == model.Sddc_SDDC_STATE_DEPLOYING {
+		provisioningPhase = lookupSddcProvisioningPhase(m.(*connector.Wrapper), sddcID)
+	}
+	d.Set("provisioning_phase", provisioningPhase)
+	primaryClusterClient := apiClient.PrimaryCluster()
 	primaryCluster, err := primaryClusterClient.Get(orgID, sddcID)
 	if err != nil {
-		return HandleReadError(d, "Primary Cluster", sddcID, err)
+		return toDiagnostics(HandleReadError(d, "Primary Cluster", sddcID, err))
 	}
 	d.Set("cluster_id", primaryCluster.ClusterId)
 	cluster := map[string]string{}
@@ -445,6 +632,7 @@ func resourceSddcRead(d *schema.ResourceData, m interface{}) error {
 		}
 	}
 	d.Set("cluster_info", cluster)
+	d.Set("vsan_witness", flattenVsanWitness(primaryCluster.VsanWitness))
 	if sddc.ResourceConfig != nil {
 		d.Set("vc_url", sddc.ResourceConfig.VcUrl)
 		d.Set("cloud_username", sddc.ResourceConfig.CloudUsername)
@@ -452,6 +640,13 @@ func resourceSddcRead(d *schema.ResourceData, m interface{}) error {
 		d.Set("nsxt_reverse_proxy_url", sddc.ResourceConfig.NsxApiPublicEndpointUrl)
 		d.Set("region", *sddc.ResourceConfig.Region)
 		d.Set("availability_zones", sddc.ResourceConfig.AvailabilityZones)
+		secondaryAvailabilityZone := ""
+		if len(sddc.ResourceConfig.AvailabilityZones) > 1 {
+			secondaryAvailabilityZone = sddc.ResourceConfig.AvailabilityZones[1]
+		}
+		d.Set("secondary_availability_zone", secondaryAvailabilityZone)
+		d.Set("witness_availability_zone", sddc.ResourceConfig.WitnessAvailabilityZone)
+		d.Set("sddc_security", flattenSddcSecurity(sddc.ResourceConfig.SddcSecurity))
 		d.Set("deployment_type", ConvertDeployType(*sddc.ResourceConfig.DeploymentType))
 		d.Set("sso_domain", *sddc.ResourceConfig.SsoDomain)
 		d.Set("skip_creating_vxlan", *sddc.ResourceConfig.SkipCreatingVxlan)
@@ -484,44 +679,72 @@ func resourceSddcRead(d *schema.ResourceData, m interface{}) error {
 			d.Set("nsxt_private_url", *sddc.ResourceConfig.NsxMgrLoginUrl)
 		}
 	}
-	edrsPolicyClient := autoscalercluster.NewEdrsPolicyClient(connectorWrapper.Connector)
+	edrsPolicyClient := apiClient.EdrsPolicy()
 	edrsPolicy, err := edrsPolicyClient.Get(orgID, sddcID, primaryCluster.ClusterId)
 	if err != nil {
-		return HandleReadError(d, "SDDC", sddcID, err)
+		return toDiagnostics(HandleReadError(d, "SDDC", sddcID, err))
 	}
 	d.Set("edrs_policy_type", *edrsPolicy.PolicyType)
 	d.Set("enable_edrs", edrsPolicy.EnableEdrs)
 	d.Set("max_hosts", *edrsPolicy.MaxHosts)
 	d.Set("min_hosts", *edrsPolicy.MinHosts)
 
-	if *sddc.Provider != constants.ZeroCloudProviderType {
+	if !isZeroCloudSddc(&sddc) {
 		// store intranet_mtu_uplink only for non zerocloud provider types
 		nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
-		nsxtReverseProxyURLConnector, err := getNsxtReverseProxyURLConnector(nsxtReverseProxyURL, connectorWrapper)
+		nsxClient, err := apiClient.ForNsx(nsxtReverseProxyURL)
 		if err != nil {
-			return HandleCreateError("NSXT reverse proxy URL connectorWrapper", err)
+			return toDiagnostics(HandleCreateError("NSXT reverse proxy URL connectorWrapper", err))
 		}
-		cloudServicesCommonClient := external.NewConfigClient(nsxtReverseProxyURLConnector)
+		cloudServicesCommonClient := nsxClient.ExternalConfig()
 		externalConnectivityConfig, err := cloudServicesCommonClient.Get()
 		if err != nil {
-			return HandleReadError(d, "External connectivity configuration", sddcID, err)
+			return toDiagnostics(HandleReadError(d, "External connectivity configuration", sddcID, err))
 		}
 		d.Set("intranet_mtu_uplink", externalConnectivityConfig.IntranetMtu)
 	}
 	return nil
 }
 
-func resourceSddcDelete(d *schema.ResourceData, m interface{}) error {
+func resourceSddcDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	connectorWrapper := m.(*connector.Wrapper)
-	sddcClient := orgs.NewSddcsClient(connectorWrapper.Connector)
+	sddcClient := api.NewClient(connectorWrapper).Sddcs()
 	sddcID := d.Id()
 	orgID := (m.(*connector.Wrapper)).OrgID
+	if err := checkDeletionProtection(d, "SDDC"); err != nil {
+		return toDiagnostics(err)
+	}
 
-	sddcDeleteTask, err := sddcClient.Delete(orgID, sddcID, nil, nil, nil)
+	// The SDDC may already be in the process of being deleted by an interrupted apply
+	sddcDeleteTasks, err := task.GetInProgressTasks(connectorWrapper, sddcID, constants.SddcDeleteTaskType)
 	if err != nil {
-		return HandleDeleteError("SDDC", sddcID, err)
+		return toDiagnostics(HandleDeleteError("SDDC", sddcID, err))
 	}
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+	var sddcDeleteTask model.Task
+	if len(sddcDeleteTasks) > 0 {
+		sddcDeleteTask = sddcDeleteTasks[0]
+		log.Printf("[INFO] Deletion of SDDC %s is already in progress, resuming task %s", sddcID, sddcDeleteTask.Id)
+	} else {
+		var retainConfiguration, forceDelete *bool
+		var templateName *string
+		if d.Get("retain_configuration").(bool) {
+			retain := true
+			retainConfiguration = &retain
+			if name := d.Get("template_name").(string); len(name) > 0 {
+				templateName = &name
+			}
+		}
+		if d.Get("force_delete").(bool) {
+			log.Printf("[WARN] Deleting SDDC %s forcefully", sddcID)
+			force := true
+			forceDelete = &force
+		}
+		sddcDeleteTask, err = sddcClient.Delete(orgID, sddcID, retainConfiguration, templateName, forceDelete)
+		if err != nil {
+			return toDiagnostics(HandleDeleteError("SDDC", sddcID, err))
+		}
+	}
+	return toDiagnostics(task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 			return task.GetTask(connectorWrapper, sddcDeleteTask.Id)
 		}, "failed to delete SDDC", nil)
@@ -530,13 +753,14 @@ func resourceSddcDelete(d *schema.ResourceData, m interface{}) error {
 		}
 		d.SetId("")
 		return nil
-	})
+	}))
 }
 
-func resourceSddcUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceSddcUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	connectorWrapper := m.(*connector.Wrapper)
-	esxsClient := sddcs.NewEsxsClient(connectorWrapper)
-	sddcClient := orgs.NewSddcsClient(connectorWrapper)
+	apiClient := api.NewClient(connectorWrapper)
+	esxsClient := apiClient.Esxs()
+	sddcClient := apiClient.Sddcs()
 	sddcID := d.Id()
 	orgID := (m.(*connector.Wrapper)).OrgID
 
@@ -552,36 +776,31 @@ func resourceSddcUpdate(d *schema.ResourceData, m interface{}) error {
 			newNum := newTmp.(int)
 
 			if newNum == 2 { // 2node SDDC creation
-				err := resourceSddcDelete(d, m)
-				if err != nil {
-					return err
+				diags := resourceSddcDelete(ctx, d, m)
+				if diags.HasError() {
+					return diags
 				}
-				return resourceSddcCreate(d, m)
+				return resourceSddcCreate(ctx, d, m)
 			} else if newNum == 3 { // 3node SDDC scale up
-				convertClient := sddcs.NewConvertClient(connectorWrapper)
+				convertClient := apiClient.Convert()
 				sddcTypeUpdateTask, err := convertClient.Create(orgID, sddcID, nil)
 
 				if err != nil {
-					return HandleUpdateError("SDDC", err)
+					return toDiagnostics(HandleUpdateError("SDDC", err))
 				}
-				err = resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-					taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+				err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+					return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 						return task.GetTask(connectorWrapper, sddcTypeUpdateTask.Id)
 					}, "error scaling SDDC", nil)
-					if taskErr != nil {
-						return taskErr
-					}
-					err = resourceSddcRead(d, m)
-					if err == nil {
-						return nil
-					}
-					return resource.NonRetryableError(err)
 				})
 				if err != nil {
-					return err
+					return toDiagnostics(err)
+				}
+				if diags := resourceSddcRead(ctx, d, m); diags.HasError() {
+					return diags
 				}
 			} else {
-				return fmt.Errorf("scaling SDDC is not supported. Please check sddc_type and num_host")
+				return toDiagnostics(newAttributeError("sddc_type", "scaling SDDC is not supported. Please check sddc_type and num_host"))
 			}
 		}
 	}
@@ -594,19 +813,20 @@ func resourceSddcUpdate(d *schema.ResourceData, m interface{}) error {
 		newNum := newTmp.(int)
 
 		if len(primaryClusterID) == 0 {
-			return fmt.Errorf("cannot find primary cluster on SDDC %s", sddcID)
+			return diag.Errorf("cannot find primary cluster on SDDC %s", sddcID)
 		}
-		action := "add"
+		action := hostActionAdd
 		diffNum := newNum - oldNum
 
 		if newNum < oldNum {
-			action = "remove"
+			action = hostActionRemove
 			diffNum = oldNum - newNum
 		}
 		if d.Get("deployment_type").(string) == constants.MultiAvailabilityZone && diffNum%2 != 0 {
-
-			return fmt.Errorf("for multiAZ deployment type, SDDC hosts must be added in pairs across availability zones")
+			return toDiagnostics(newAttributeError("num_host", "for multiAZ deployment type, SDDC hosts must be added in pairs across availability zones"))
 		}
+		// No availability zone is specified, so that the hosts of stretched clusters are
+		// distributed evenly across the availability zones by the service
 		esxConfig := model.EsxConfig{
 			NumHosts:  int64(diffNum),
 			ClusterId: &primaryClusterID,
@@ -615,23 +835,34 @@ func resourceSddcUpdate(d *schema.ResourceData, m interface{}) error {
 		hostUpdateTask, err := esxsClient.Create(orgID, sddcID, esxConfig, &action)
 
 		if err != nil {
-			return HandleUpdateError("SDDC", err)
+			return toDiagnostics(HandleUpdateError("SDDC", err))
 		}
-		err = resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-			taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+		err = waitForHostUpdate(ctx, d, connectorWrapper, hostUpdateTask.Id, sddcID, primaryClusterID, action, newNum, func() *resource.RetryError {
+			return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 				return task.GetTask(connectorWrapper, hostUpdateTask.Id)
 			}, "failed to update hosts", nil)
-			if taskErr != nil {
-				return taskErr
-			}
-			err = resourceSddcRead(d, m)
-			if err == nil {
-				return nil
-			}
-			return resource.NonRetryableError(err)
 		})
 		if err != nil {
-			return err
+			return toDiagnostics(err)
+		}
+		if diags := resourceSddcRead(ctx, d, m); diags.HasError() {
+			return diags
+		}
+	}
+
+	// Upsize the vCenter and NSX appliances
+	if d.HasChange("size") {
+		oldSize, newSize := d.GetChange("size")
+		if !strings.EqualFold(oldSize.(string), newSize.(string)) {
+			err := upsizeSddc(ctx, d, connectorWrapper)
+			if err != nil {
+				// Keep the previous size in the state, so that the next apply resumes the upsize
+				d.Set("size", oldSize)
+				return toDiagnostics(err)
+			}
+			if diags := resourceSddcRead(ctx, d, m); diags.HasError() {
+				return diags
+			}
 		}
 	}
 
@@ -644,34 +875,34 @@ func resourceSddcUpdate(d *schema.ResourceData, m interface{}) error {
 		sddc, err := sddcClient.Patch(orgID, sddcID, sddcPatchRequest)
 
 		if err != nil {
-			return HandleUpdateError("SDDC", err)
+			return toDiagnostics(HandleUpdateError("SDDC", err))
 		}
 		d.Set("sddc_name", sddc.Name)
 	}
 
 	if d.HasChange("intranet_mtu_uplink") {
 		if d.Get("provider_type") == constants.ZeroCloudProviderType {
-			return fmt.Errorf("Intranet MTU uplink cannot be updated for %s provider type", constants.ZeroCloudProviderType)
+			return toDiagnostics(newAttributeError("intranet_mtu_uplink", "Intranet MTU uplink cannot be updated for %s provider type", constants.ZeroCloudProviderType))
 		}
 		intranetMTUUplink := d.Get("intranet_mtu_uplink").(int)
 		intranetMTUUplinkPointer := int64(intranetMTUUplink)
 		nsxtReverseProxyURL := d.Get("nsxt_reverse_proxy_url").(string)
-		nxstReverseProxyURLConnector, err := getNsxtReverseProxyURLConnector(nsxtReverseProxyURL, connectorWrapper)
+		nsxClient, err := apiClient.ForNsx(nsxtReverseProxyURL)
 		if err != nil {
-			return HandleCreateError("NSXT reverse proxy URL connector", err)
+			return toDiagnostics(HandleCreateError("NSXT reverse proxy URL connector", err))
 		}
-		cloudServicesCommonClient := external.NewConfigClient(nxstReverseProxyURLConnector)
+		cloudServicesCommonClient := nsxClient.ExternalConfig()
 		externalConnectivityConfig := nsx_vmc_appModel.ExternalConnectivityConfig{IntranetMtu: &intranetMTUUplinkPointer}
 		_, err = cloudServicesCommonClient.Update(externalConnectivityConfig)
 		if err != nil {
-			return HandleUpdateError("Intranet MTU Uplink", err)
+			return toDiagnostics(HandleUpdateError("Intranet MTU Uplink", err))
 		}
 	}
 
 	if d.HasChange("edrs_policy_type") || d.HasChange("enable_edrs") || d.HasChange("min_hosts") || d.HasChange("max_hosts") {
 		sddcType := d.Get("sddc_type").(string)
 		if sddcType == constants.OneNodeSddcType {
-			return fmt.Errorf("EDRS policy cannot be updated for SDDC with type %s", constants.OneNodeSddcType)
+			return toDiagnostics(newAttributeError("edrs_policy_type", "EDRS policy cannot be updated for SDDC with type %s", constants.OneNodeSddcType))
 		}
 		clusterID := d.Get("cluster_id").(string)
 		minHosts := int64(d.Get("min_hosts").(int))
@@ -679,7 +910,7 @@ func resourceSddcUpdate(d *schema.ResourceData, m interface{}) error {
 		policyType := d.Get("edrs_policy_type").(string)
 		enableEDRS := d.Get("enable_edrs").(bool)
 		if policyType == constants.StorageScaleUpPolicyType && !enableEDRS {
-			return fmt.Errorf("EDRS policy %s is the default and cannot be disabled", constants.StorageScaleUpPolicyType)
+			return toDiagnostics(newAttributeError("enable_edrs", "EDRS policy %s is the default and cannot be disabled", constants.StorageScaleUpPolicyType))
 		}
 		edrsPolicy := &autoscalermodel.EdrsPolicy{
 			EnableEdrs: enableEDRS,
@@ -687,67 +918,86 @@ func resourceSddcUpdate(d *schema.ResourceData, m interface{}) error {
 			MinHosts:   &minHosts,
 			MaxHosts:   &maxHosts,
 		}
-		edrsPolicyClient := autoscalercluster.NewEdrsPolicyClient(connectorWrapper)
+		edrsPolicyClient := apiClient.EdrsPolicy()
 		edrsPolicyUpdateTask, err := edrsPolicyClient.Post(orgID, sddcID, clusterID, *edrsPolicy)
 		if err != nil {
-			return HandleUpdateError("EDRS Policy", err)
+			return toDiagnostics(HandleUpdateError("EDRS Policy", err))
 		}
 
-		return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
-			taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+		err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+			return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 				return task.GetTask(connectorWrapper, edrsPolicyUpdateTask.Id)
 			}, "failed to update EDRS policy configuration", nil)
-			if taskErr != nil {
-				return taskErr
-			}
-			err = resourceSddcRead(d, m)
-			if err == nil {
-				return nil
-			}
-			return resource.NonRetryableError(err)
 		})
-	}
-
-	// Update sddc_size is not supported
-	if d.HasChange("size") {
-		return fmt.Errorf("SDDC size update operation is not supported")
+		if err != nil {
+			return toDiagnostics(err)
+		}
+		return resourceSddcRead(ctx, d, m)
 	}
 
 	// Update Microsoft licensing config
 	if d.HasChange("microsoft_licensing_config") {
 		configChangeParam := expandMsftLicenseConfig(d.Get("microsoft_licensing_config").([]interface{}))
-		return updateMsftLicenseConfig(d, m, configChangeParam)
+		return updateMsftLicenseConfig(ctx, d, m, configChangeParam)
 	}
-	return resourceSddcRead(d, m)
+	return resourceSddcRead(ctx, d, m)
 }
 
-func updateMsftLicenseConfig(d *schema.ResourceData, m interface{}, msftLicenseConfig *model.MsftLicensingConfig) error {
+// upsizeSddc upsizes the vCenter and NSX appliances of the SDDC to the configured size, or resumes
+// waiting for an upsize started by an interrupted apply. The VMC API does not support downsizing.
+func upsizeSddc(ctx context.Context, d *schema.ResourceData, connectorWrapper *connector.Wrapper) error {
+	sddcID := d.Id()
+	size := strings.ToLower(d.Get("size").(string))
+	if size != constants.LargeSddcSize {
+		oldSize, _ := d.GetChange("size")
+		return newAttributeError("size", "downsizing the SDDC from %s to %s is not supported by the VMC API", oldSize, size)
+	}
+	upsizeTasks, err := task.GetInProgressTasks(connectorWrapper, sddcID, constants.SddcUpsizeTaskType)
+	if err != nil {
+		return HandleUpdateError("SDDC", err)
+	}
+	var upsizeTaskID string
+	if len(upsizeTasks) > 0 {
+		upsizeTaskID = upsizeTasks[0].Id
+		log.Printf("[INFO] Upsize of SDDC %s is already in progress, resuming task %s", sddcID, upsizeTaskID)
+	} else {
+		upsizeTaskID, err = api.NewClient(connectorWrapper).Inventory().UpsizeSddc(sddcID,
+			inventory.SddcSize{VcSize: size, NsxSize: size})
+		if err != nil {
+			return HandleUpdateError("SDDC", err)
+		}
+	}
+	return task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutUpdate), func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+			return task.GetTask(connectorWrapper, upsizeTaskID)
+		}, "failed to upsize SDDC", nil)
+	})
+}
+
+func updateMsftLicenseConfig(ctx context.Context, d *schema.ResourceData, m interface{}, msftLicenseConfig *model.MsftLicensingConfig) diag.Diagnostics {
 	connectorWrapper := m.(*connector.Wrapper)
+	apiClient := api.NewClient(connectorWrapper)
 	sddcID := d.Id()
 	orgID := (m.(*connector.Wrapper)).OrgID
-	primaryClusterClient := sddcs.NewPrimaryclusterClient(connectorWrapper)
+	primaryClusterClient := apiClient.PrimaryCluster()
 	primaryCluster, err := primaryClusterClient.Get(orgID, sddcID)
 	if err != nil {
-		return HandleReadError(d, "Primary Cluster", sddcID, err)
+		return toDiagnostics(HandleReadError(d, "Primary Cluster", sddcID, err))
 	}
-	publishClient := msft_licensing.NewPublishClient(connectorWrapper)
+	publishClient := apiClient.MsftLicensingPublish()
 	microsoftLicensingUpdateTask, err := publishClient.Post(orgID, sddcID, primaryCluster.ClusterId, *msftLicenseConfig)
 	if err != nil {
-		return fmt.Errorf("error updating license : %s", err)
+		return diag.Errorf("error updating license : %s", err)
 	}
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 			return task.GetTask(connectorWrapper, microsoftLicensingUpdateTask.Id)
 		}, "failed updating Microsoft licensing configuration", nil)
-		if taskErr != nil {
-			return taskErr
-		}
-		err = resourceSddcRead(d, m)
-		if err == nil {
-			return nil
-		}
-		return resource.NonRetryableError(err)
 	})
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	return resourceSddcRead(ctx, d, m)
 }
 
 // buildAwsSddcConfig extracts the creation of the model.AwsSddcConfig, so that it's
@@ -781,11 +1031,11 @@ func buildAwsSddcConfig(d *schema.ResourceData) (*model.AwsSddcConfig, error) {
 	}
 
 	if deploymentType == constants.MultiAvailabilityZone && c != nil && len(c["customer_subnet_ids"].([]interface{})) != 2 {
-		return nil, fmt.Errorf("deployment type %s requires 2 subnet IDs, one in each availability zone ", deploymentType)
+		return nil, newAttributeError("account_link_sddc_config", "deployment type %s requires 2 subnet IDs, one in each availability zone ", deploymentType)
 	}
 
 	if deploymentType == constants.SingleAvailabilityZone && c != nil && len(c["customer_subnet_ids"].([]interface{})) != 1 {
-		return nil, fmt.Errorf("deployment type %s requires 1 subnet ID ", deploymentType)
+		return nil, newAttributeError("account_link_sddc_config", "deployment type %s requires 1 subnet ID ", deploymentType)
 	}
 
 	accountLinkSddcConfig := expandAccountLinkSddcConfig(accountLinkSddcConfigVar)
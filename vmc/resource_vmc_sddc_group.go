@@ -11,6 +11,7 @@ import (
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
 	"github.com/vmware/terraform-provider-vmc/vmc/sddcgroup"
 	"github.com/vmware/terraform-provider-vmc/vmc/task"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
@@ -18,6 +19,10 @@ import (
 	"time"
 )
 
+// sddcGroupOperationsKeyedMutex a mutex that allows only a single network operation per SDDC group, e.g.
+// a change of its members or of one of its external attachments.
+var sddcGroupOperationsKeyedMutex = task.KeyedMutex{}
+
 func resourceSddcGroup() *schema.Resource {
 	return &schema.Resource{
 		CreateContext: resourceSddcGroupCreate,
@@ -25,6 +30,7 @@ func resourceSddcGroup() *schema.Resource {
 		UpdateContext: resourceSddcGroupUpdate,
 		DeleteContext: resourceSddcGroupDelete,
 		Schema:        sddcGroupSchema(),
+		CustomizeDiff: customizeSddcGroupDiff,
 		Importer: &schema.ResourceImporter{
 			StateContext: schema.ImportStatePassthroughContext,
 		},
@@ -58,6 +64,23 @@ func sddcGroupSchema() map[string]*schema.Schema {
 			Required:    true,
 			Description: "A set of the IDs of SDDC members of the SDDC Group",
 		},
+		"sddc_members": {
+			Type: schema.TypeList,
+			Elem: &schema.Resource{
+				Schema: map[string]*schema.Schema{
+					"sddc_id": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+					"state": {
+						Type:     schema.TypeString,
+						Computed: true,
+					},
+				},
+			},
+			Computed:    true,
+			Description: "The members of the SDDC Group, with the state of their connectivity to the group",
+		},
 		"org_id": {
 			Type:     schema.TypeString,
 			Computed: true,
@@ -170,7 +193,7 @@ func sddcGroupSchema() map[string]*schema.Schema {
 
 func resourceSddcGroupCreate(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
 	connectorWrapper := i.(*connector.Wrapper)
-	sddcGroupsClient := sddcgroup.NewSddcGroupClient(*connectorWrapper)
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
 	err := sddcGroupsClient.Authenticate()
 	if err != nil {
 		return diag.FromErr(err)
@@ -187,28 +210,20 @@ func resourceSddcGroupCreate(ctx context.Context, data *schema.ResourceData, i i
 		return diag.FromErr(err)
 	}
 	data.SetId(sddcGroupID)
-	err = resource.RetryContext(context.Background(), data.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, data.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 			return task.GetV2Task(connectorWrapper, taskID)
 		}, "error creating SDDC group", nil)
-		if taskErr != nil {
-			return taskErr
-		}
-		diags := resourceSddcGroupRead(ctx, data, i)
-		if !diags.HasError() {
-			return nil
-		}
-		return resource.NonRetryableError(err)
 	})
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	return nil
+	return resourceSddcGroupRead(ctx, data, i)
 }
 
 func resourceSddcGroupRead(_ context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
 	connectorWrapper := i.(*connector.Wrapper)
-	sddcGroupsClient := sddcgroup.NewSddcGroupClient(*connectorWrapper)
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
 	err := sddcGroupsClient.Authenticate()
 	if err != nil {
 		return diag.FromErr(err)
@@ -232,6 +247,7 @@ func resourceSddcGroupRead(_ context.Context, data *schema.ResourceData, i inter
 		sddcMemberIDs = append(sddcMemberIDs, groupMember.ID)
 	}
 	_ = data.Set("sddc_member_ids", sddcMemberIDs)
+	_ = data.Set("sddc_members", flattenSddcGroupMembers(sddcGroup, networkConnectivityConfig))
 	if networkConnectivityConfig == nil || networkConnectivityConfig.Traits == nil {
 		// below data cannot be read, so skip
 		return nil
@@ -284,33 +300,81 @@ func resourceSddcGroupUpdate(ctx context.Context, data *schema.ResourceData, i i
 		addedIds := getAddedIds(oldIds, newIds)
 		removedIds := getRemovedIds(oldIds, newIds)
 
-		diags := updateSddcGroupMembers(data, i, addedIds, removedIds)
+		if len(*addedIds) > 0 {
+			connectorWrapper := i.(*connector.Wrapper)
+			sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
+			err := sddcGroupsClient.Authenticate()
+			if err != nil {
+				return diag.FromErr(err)
+			}
+			err = sddcGroupsClient.ValidateUpdateSddcGroupMembers(data.Id(), addedIds)
+			if err != nil {
+				_ = data.Set("sddc_member_ids", oldIds)
+				return diag.FromErr(err)
+			}
+		}
+		diags := updateSddcGroupMembers(ctx, data, i, addedIds, removedIds, data.Timeout(schema.TimeoutUpdate))
 		if diags != nil {
+			// Keep the previous members in the state, so that the next apply retries the update
+			_ = data.Set("sddc_member_ids", oldIds)
 			return diags
 		}
 	}
 	return resourceSddcGroupRead(ctx, data, i)
 }
 
-func resourceSddcGroupDelete(_ context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
+// customizeSddcGroupDiff marks the state of the members of the SDDC group as unknown in plans,
+// that add or remove members.
+func customizeSddcGroupDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	if d.Id() != "" && d.HasChange("sddc_member_ids") {
+		return d.SetNewComputed("sddc_members")
+	}
+	return nil
+}
+
+// flattenSddcGroupMembers returns the members of an SDDC group together with the realized state
+// of their connectivity, in the order the members are listed by the API. The state is empty for
+// members, which the network connectivity config does not report yet.
+func flattenSddcGroupMembers(sddcGroup *sddcgroup.DeploymentGroup,
+	networkConnectivityConfig *sddcgroup.NetworkConnectivityConfig) []map[string]string {
+	memberStates := map[string]string{}
+	if networkConnectivityConfig != nil && networkConnectivityConfig.Traits != nil &&
+		networkConnectivityConfig.Traits.RealizedSddcs != nil {
+		for _, sddc := range networkConnectivityConfig.Traits.RealizedSddcs.Sddcs {
+			memberStates[sddc.SddcID] = sddc.State
+		}
+	}
+	members := []map[string]string{}
+	for _, groupMember := range sddcGroup.Membership.Included {
+		members = append(members, map[string]string{
+			"sddc_id": groupMember.ID,
+			"state":   memberStates[groupMember.ID],
+		})
+	}
+	return members
+}
+
+func resourceSddcGroupDelete(ctx context.Context, data *schema.ResourceData, i interface{}) diag.Diagnostics {
 	connectorWrapper := i.(*connector.Wrapper)
-	sddcGroupsClient := sddcgroup.NewSddcGroupClient(*connectorWrapper)
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
 	err := sddcGroupsClient.Authenticate()
 	if err != nil {
 		return diag.FromErr(err)
 	}
 	sddcMemberIds := getCurrentSddcMemberIDs(data)
 	// Removal of all sddc members from the group is required prior to deletion
-	diags := updateSddcGroupMembers(data, i, new([]string), sddcMemberIds)
+	diags := updateSddcGroupMembers(ctx, data, i, new([]string), sddcMemberIds, data.Timeout(schema.TimeoutDelete))
 	if diags != nil {
 		return diags
 	}
 
+	unlock := sddcGroupOperationsKeyedMutex.Lock(data.Id())
+	defer unlock()
 	deleteSddcTaskID, err := sddcGroupsClient.DeleteSddcGroup(data.Id())
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	err = resource.RetryContext(context.Background(), data.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, data.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 			return task.GetV2Task(connectorWrapper, deleteSddcTaskID)
 		}, "error deleting SDDC group", nil)
@@ -326,20 +390,22 @@ func resourceSddcGroupDelete(_ context.Context, data *schema.ResourceData, i int
 	return nil
 }
 
-func updateSddcGroupMembers(data *schema.ResourceData,
-	i interface{}, addedIds *[]string, removedIds *[]string) diag.Diagnostics {
+func updateSddcGroupMembers(ctx context.Context, data *schema.ResourceData,
+	i interface{}, addedIds *[]string, removedIds *[]string, timeout time.Duration) diag.Diagnostics {
 	connectorWrapper := i.(*connector.Wrapper)
-	sddcGroupsClient := sddcgroup.NewSddcGroupClient(*connectorWrapper)
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
 	err := sddcGroupsClient.Authenticate()
 	if err != nil {
 		return diag.FromErr(err)
 	}
 
+	unlock := sddcGroupOperationsKeyedMutex.Lock(data.Id())
+	defer unlock()
 	updateMembersTaskID, err := sddcGroupsClient.UpdateSddcGroupMembers(data.Id(), addedIds, removedIds)
 	if err != nil {
 		return diag.FromErr(err)
 	}
-	err = resource.RetryContext(context.Background(), data.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, timeout, func() *resource.RetryError {
 		taskErr := task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
 			return task.GetV2Task(connectorWrapper, updateMembersTaskID)
 		}, "error updating SDDC group members", nil)
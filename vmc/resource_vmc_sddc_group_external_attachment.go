@@ -0,0 +1,283 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/sddcgroup"
+	"github.com/vmware/terraform-provider-vmc/vmc/task"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func resourceSddcGroupExternalAttachment() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSddcGroupExternalAttachmentCreate,
+		ReadContext:   resourceSddcGroupExternalAttachmentRead,
+		UpdateContext: resourceSddcGroupExternalAttachmentUpdate,
+		DeleteContext: resourceSddcGroupExternalAttachmentDelete,
+		CustomizeDiff: customizeSddcGroupExternalAttachmentDiff,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ",")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected attachment_id,sddc_group_id", d.Id())
+				}
+				d.SetId(idParts[0])
+				d.Set("sddc_group_id", idParts[1])
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(60 * time.Minute),
+			Update: schema.DefaultTimeout(60 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
+		Schema: sddcGroupExternalAttachmentSchema(),
+	}
+}
+
+func sddcGroupExternalAttachmentSchema() map[string]*schema.Schema {
+	return map[string]*schema.Schema{
+		"sddc_group_id": {
+			Type:         schema.TypeString,
+			Required:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Description:  "ID of the SDDC group, whose transit gateway the external attachment is connected to.",
+		},
+		"attachment_type": {
+			Type:     schema.TypeString,
+			Required: true,
+			ForceNew: true,
+			ValidateFunc: validation.StringInSlice(
+				[]string{constants.VpcExternalAttachmentType, constants.TgwExternalAttachmentType}, false),
+			Description: "Type of the external attachment. Use VPC to accept the attachment of a VPC in an AWS " +
+				"account, added to the SDDC group, or TGW to associate an external AWS transit gateway with the SDDC group.",
+		},
+		"attachment_id": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Description:  "ID of the transit gateway attachment of the VPC, e.g. tgw-attach-0123456789abcdef0. Required for VPC attachments.",
+		},
+		"transit_gateway_id": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Description:  "ID of the external AWS transit gateway. Required for TGW attachments.",
+		},
+		"transit_gateway_owner": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Description:  "ID of the AWS account owning the external transit gateway. Required for TGW attachments.",
+		},
+		"transit_gateway_region": {
+			Type:         schema.TypeString,
+			Optional:     true,
+			ForceNew:     true,
+			ValidateFunc: validation.NoZeroValues,
+			Description:  "AWS region of the external transit gateway, e.g. us-east-1. Required for TGW attachments.",
+		},
+		"configured_prefixes": {
+			Type: schema.TypeSet,
+			Elem: &schema.Schema{
+				Type:         schema.TypeString,
+				ValidateFunc: validation.IsCIDR,
+			},
+			Optional:    true,
+			Description: "The route prefixes, that are routed from the SDDC group through the external attachment.",
+		},
+		"vpc_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the attached VPC, for VPC attachments.",
+		},
+		"aws_account_id": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "ID of the AWS account owning the attached VPC, for VPC attachments.",
+		},
+		"state": {
+			Type:        schema.TypeString,
+			Computed:    true,
+			Description: "State of the external attachment.",
+		},
+	}
+}
+
+// customizeSddcGroupExternalAttachmentDiff validates, that the arguments required by the type of
+// the external attachment are set.
+func customizeSddcGroupExternalAttachmentDiff(_ context.Context, d *schema.ResourceDiff, _ interface{}) error {
+	required := []string{"attachment_id"}
+	if d.Get("attachment_type").(string) == constants.TgwExternalAttachmentType {
+		required = []string{"transit_gateway_id", "transit_gateway_owner", "transit_gateway_region"}
+	}
+	for _, attr := range required {
+		if value, ok := d.GetOk(attr); d.NewValueKnown(attr) && (!ok || value.(string) == "") {
+			return newAttributeError(attr, "%s is required for %s attachments", attr, d.Get("attachment_type"))
+		}
+	}
+	return nil
+}
+
+func resourceSddcGroupExternalAttachmentCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	attachmentType := d.Get("attachment_type").(string)
+	action := sddcgroup.AttachmentActionAccept
+	id := d.Get("attachment_id").(string)
+	if attachmentType == constants.TgwExternalAttachmentType {
+		action = sddcgroup.AttachmentActionAssociate
+		id = d.Get("transit_gateway_id").(string)
+	}
+	err := updateSddcGroupExternalAttachment(ctx, d, m, action, d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	d.SetId(id)
+	return resourceSddcGroupExternalAttachmentRead(ctx, d, m)
+}
+
+func resourceSddcGroupExternalAttachmentRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	connectorWrapper := m.(*connector.Wrapper)
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
+	err := sddcGroupsClient.Authenticate()
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	sddcGroupID := d.Get("sddc_group_id").(string)
+	sddcGroup, networkConnectivityConfig, err := sddcGroupsClient.GetSddcGroup(sddcGroupID)
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	if sddcGroup == nil || sddcGroup.Deleted {
+		log.Printf("[WARN] SDDC group %s is deleted, removing external attachment %s from the state", sddcGroupID, d.Id())
+		d.SetId("")
+		return nil
+	}
+	var traits sddcgroup.Traits
+	if networkConnectivityConfig != nil && networkConnectivityConfig.Traits != nil {
+		traits = *networkConnectivityConfig.Traits
+	}
+	if traits.AwsInfo != nil {
+		for _, account := range traits.AwsInfo.Accounts {
+			for _, attachment := range account.AccountAttachments {
+				if attachment.AttachmentID != d.Id() {
+					continue
+				}
+				_ = d.Set("attachment_type", constants.VpcExternalAttachmentType)
+				_ = d.Set("attachment_id", attachment.AttachmentID)
+				_ = d.Set("vpc_id", attachment.VpcID)
+				_ = d.Set("aws_account_id", account.AccountNumber)
+				_ = d.Set("state", attachment.State)
+				_ = d.Set("configured_prefixes", attachment.StaticRoutes)
+				return nil
+			}
+		}
+	}
+	if traits.ExternalTgw != nil {
+		for _, association := range traits.ExternalTgw.CustomerTransitGatewayAssociations {
+			if association.TgwID != d.Id() {
+				continue
+			}
+			var configuredPrefixes []string
+			for _, peeringRegion := range association.PeeringRegions {
+				configuredPrefixes = append(configuredPrefixes, peeringRegion.ConfiguredPrefixes...)
+			}
+			_ = d.Set("attachment_type", constants.TgwExternalAttachmentType)
+			_ = d.Set("transit_gateway_id", association.TgwID)
+			_ = d.Set("transit_gateway_owner", association.TgwOwner)
+			_ = d.Set("transit_gateway_region", association.TgwRegion.Region)
+			_ = d.Set("state", association.Status)
+			_ = d.Set("configured_prefixes", configuredPrefixes)
+			return nil
+		}
+	}
+	log.Printf("[WARN] External attachment %s of SDDC group %s not found, removing it from the state", d.Id(), sddcGroupID)
+	d.SetId("")
+	return nil
+}
+
+func resourceSddcGroupExternalAttachmentUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	if d.HasChange("configured_prefixes") {
+		err := updateSddcGroupExternalAttachment(ctx, d, m, sddcgroup.AttachmentActionUpdate, d.Timeout(schema.TimeoutUpdate))
+		if err != nil {
+			// Keep the previous prefixes in the state, so that the next apply retries the update
+			oldPrefixes, _ := d.GetChange("configured_prefixes")
+			_ = d.Set("configured_prefixes", oldPrefixes)
+			return toDiagnostics(err)
+		}
+	}
+	return resourceSddcGroupExternalAttachmentRead(ctx, d, m)
+}
+
+func resourceSddcGroupExternalAttachmentDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := updateSddcGroupExternalAttachment(ctx, d, m, sddcgroup.AttachmentActionDelete, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	d.SetId("")
+	return nil
+}
+
+// updateSddcGroupExternalAttachment applies the provided action to the external attachment and waits
+// for the resulting task to finish. Operations on the same SDDC group are serialized, as the VMC API
+// rejects concurrent changes of the network connectivity config of a group.
+func updateSddcGroupExternalAttachment(ctx context.Context, d *schema.ResourceData, m interface{},
+	action string, timeout time.Duration) error {
+	connectorWrapper := m.(*connector.Wrapper)
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
+	err := sddcGroupsClient.Authenticate()
+	if err != nil {
+		return err
+	}
+	sddcGroupID := d.Get("sddc_group_id").(string)
+	var configuredPrefixes []string
+	for _, prefix := range d.Get("configured_prefixes").(*schema.Set).List() {
+		configuredPrefixes = append(configuredPrefixes, prefix.(string))
+	}
+	unlock := sddcGroupOperationsKeyedMutex.Lock(sddcGroupID)
+	defer unlock()
+	var taskID string
+	if d.Get("attachment_type").(string) == constants.TgwExternalAttachmentType {
+		taskID, err = sddcGroupsClient.UpdateCustomerTransitGatewayAssociation(sddcGroupID,
+			sddcgroup.CustomerTransitGatewayAssociationAction{
+				Action: action,
+				CustomerTransitGatewayAssociation: sddcgroup.CustomerTransitGatewayAssociation{
+					TgwID:          d.Get("transit_gateway_id").(string),
+					TgwOwner:       d.Get("transit_gateway_owner").(string),
+					TgwRegion:      sddcgroup.TgwRegion{Region: d.Get("transit_gateway_region").(string)},
+					PeeringRegions: []sddcgroup.PeeringRegions{{ConfiguredPrefixes: configuredPrefixes}},
+				},
+			})
+	} else {
+		taskID, err = sddcGroupsClient.UpdateVpcAttachment(sddcGroupID, sddcgroup.VpcAttachmentAction{
+			Action:             action,
+			AttachmentID:       d.Get("attachment_id").(string),
+			ConfiguredPrefixes: configuredPrefixes,
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return task.RetryContext(ctx, connectorWrapper.TaskPoll, timeout, func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+			return task.GetV2Task(connectorWrapper, taskID)
+		}, "error updating external attachment of SDDC group", nil)
+	})
+}
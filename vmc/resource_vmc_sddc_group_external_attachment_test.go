@@ -0,0 +1,114 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"github.com/vmware/terraform-provider-vmc/vmc/sddcgroup"
+)
+
+// newTestSddcGroup creates an SDDC group with a single member on the simulator and returns its ID.
+func newTestSddcGroup(t *testing.T, server *simulator.Server, connectorWrapper *connector.Wrapper) string {
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "group_member"})
+	d := schema.TestResourceDataRaw(t, sddcGroupSchema(), map[string]interface{}{
+		"name":            "transit_connect",
+		"description":     "external attachments",
+		"sddc_member_ids": []interface{}{sddcID},
+	})
+	assert.NoError(t, diagsErr(resourceSddcGroupCreate(context.Background(), d, connectorWrapper)))
+	return d.Id()
+}
+
+func TestResourceSddcGroupExternalAttachmentVpcSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcGroupID := newTestSddcGroup(t, server, connectorWrapper)
+	attachmentID := server.AddSddcGroupVpcAttachment(sddcGroupID, "123456789012", "vpc-0a1b2c3d")
+	rawConfig := map[string]interface{}{
+		"sddc_group_id":       sddcGroupID,
+		"attachment_type":     constants.VpcExternalAttachmentType,
+		"attachment_id":       attachmentID,
+		"configured_prefixes": []interface{}{"10.20.0.0/16"},
+	}
+	d := schema.TestResourceDataRaw(t, sddcGroupExternalAttachmentSchema(), rawConfig)
+	assert.NoError(t, diagsErr(resourceSddcGroupExternalAttachmentCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, attachmentID, d.Id())
+	assert.Equal(t, sddcgroup.AttachmentStateConnected, d.Get("state"))
+	assert.Equal(t, "vpc-0a1b2c3d", d.Get("vpc_id"))
+	assert.Equal(t, "123456789012", d.Get("aws_account_id"))
+	assertUnlocked(t, &sddcGroupOperationsKeyedMutex, sddcGroupID)
+
+	// The configured prefixes are updated in place
+	rawConfig["configured_prefixes"] = []interface{}{"10.20.0.0/16", "10.21.0.0/16"}
+	diff, err := resourceSddcGroupExternalAttachment().Diff(context.Background(), d.State(),
+		terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	state, diags := resourceSddcGroupExternalAttachment().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "2", state.Attributes["configured_prefixes.#"])
+
+	// An attachment, that is no longer pending acceptance, cannot be accepted again
+	diff, err = resourceSddcGroupExternalAttachment().Diff(context.Background(), nil,
+		terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	_, diags = resourceSddcGroupExternalAttachment().Apply(context.Background(), nil, diff, connectorWrapper)
+	assert.ErrorContains(t, diagsErr(diags), "is not pending acceptance")
+
+	d = resourceSddcGroupExternalAttachment().Data(state)
+	assert.NoError(t, diagsErr(resourceSddcGroupExternalAttachmentDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	d.SetId(attachmentID)
+	assert.NoError(t, diagsErr(resourceSddcGroupExternalAttachmentRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResourceSddcGroupExternalAttachmentTgwSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcGroupID := newTestSddcGroup(t, server, connectorWrapper)
+	d := schema.TestResourceDataRaw(t, sddcGroupExternalAttachmentSchema(), map[string]interface{}{
+		"sddc_group_id":          sddcGroupID,
+		"attachment_type":        constants.TgwExternalAttachmentType,
+		"transit_gateway_id":     "tgw-0f1e2d3c4b5a69788",
+		"transit_gateway_owner":  "123456789012",
+		"transit_gateway_region": "us-east-1",
+		"configured_prefixes":    []interface{}{"10.30.0.0/16"},
+	})
+	assert.NoError(t, diagsErr(resourceSddcGroupExternalAttachmentCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "tgw-0f1e2d3c4b5a69788", d.Id())
+	assert.Equal(t, sddcgroup.AttachmentStateConnected, d.Get("state"))
+	assert.Contains(t, server.Requests(), "POST /api/network/"+simulator.TestOrgID+"/aws/operations")
+
+	// The type and the transit gateway of an imported attachment are read from the SDDC group
+	imported := resourceSddcGroupExternalAttachment().Data(nil)
+	imported.SetId("tgw-0f1e2d3c4b5a69788," + sddcGroupID)
+	importedStates, err := resourceSddcGroupExternalAttachment().Importer.StateContext(context.Background(), imported, connectorWrapper)
+	assert.NoError(t, err)
+	assert.NoError(t, diagsErr(resourceSddcGroupExternalAttachmentRead(context.Background(), importedStates[0], connectorWrapper)))
+	assert.Equal(t, constants.TgwExternalAttachmentType, importedStates[0].Get("attachment_type"))
+	assert.Equal(t, "us-east-1", importedStates[0].Get("transit_gateway_region"))
+	assert.Equal(t, 1, importedStates[0].Get("configured_prefixes").(*schema.Set).Len())
+
+	assert.NoError(t, diagsErr(resourceSddcGroupExternalAttachmentDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResourceSddcGroupExternalAttachmentRequiredArguments(t *testing.T) {
+	for attachmentType, rawConfig := range map[string]map[string]interface{}{
+		constants.VpcExternalAttachmentType: {"sddc_group_id": "group", "attachment_type": constants.VpcExternalAttachmentType},
+		constants.TgwExternalAttachmentType: {"sddc_group_id": "group", "attachment_type": constants.TgwExternalAttachmentType,
+			"transit_gateway_id": "tgw-0f1e2d3c4b5a69788"},
+	} {
+		_, err := resourceSddcGroupExternalAttachment().Diff(context.Background(), nil,
+			terraform.NewResourceConfigRaw(rawConfig), nil)
+		assert.ErrorContains(t, err, "is required for "+attachmentType+" attachments")
+	}
+}
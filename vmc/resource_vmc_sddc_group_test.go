@@ -4,12 +4,16 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/stretchr/testify/assert"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
 	"github.com/vmware/terraform-provider-vmc/vmc/sddcgroup"
 	"os"
 	"testing"
@@ -39,6 +43,66 @@ func TestAccResourceSddcGroupZerocloud(t *testing.T) {
 	})
 }
 
+func TestResourceSddcGroupMembershipSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	firstSddcID := server.AddSddc(simulator.SddcConfig{Name: "first"})
+	secondSddcID := server.AddSddc(simulator.SddcConfig{Name: "second"})
+	rawConfig := map[string]interface{}{
+		"name":            "sddc_group",
+		"description":     "membership",
+		"sddc_member_ids": []interface{}{firstSddcID},
+	}
+	d := schema.TestResourceDataRaw(t, sddcGroupSchema(), rawConfig)
+	assert.NoError(t, diagsErr(resourceSddcGroupCreate(context.Background(), d, connectorWrapper)))
+	groupID := d.Id()
+	assert.Equal(t, []interface{}{map[string]interface{}{"sddc_id": firstSddcID, "state": sddcgroup.SddcMemberStateConnected}},
+		d.Get("sddc_members"))
+
+	// Members are added and removed in place, instead of the group being replaced
+	rawConfig["sddc_member_ids"] = []interface{}{secondSddcID}
+	diff, err := resourceSddcGroup().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	assert.True(t, diff.Attributes["sddc_members.#"].NewComputed)
+	state, diags := resourceSddcGroup().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, groupID, state.ID)
+	assert.Equal(t, "1", state.Attributes["sddc_members.#"])
+	assert.Equal(t, secondSddcID, state.Attributes["sddc_members.0.sddc_id"])
+	assert.Equal(t, sddcgroup.SddcMemberStateConnected, state.Attributes["sddc_members.0.state"])
+	assert.Contains(t, server.Requests(), "POST /api/network/"+simulator.TestOrgID+"/aws/operations")
+
+	// Members, that got disconnected outside of Terraform, are visible on refresh
+	server.SetSddcGroupMemberState(groupID, secondSddcID, sddcgroup.SddcMemberStateDisconnecting)
+	d = resourceSddcGroup().Data(state)
+	assert.NoError(t, diagsErr(resourceSddcGroupRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, sddcgroup.SddcMemberStateDisconnecting, d.Get("sddc_members.0.state"))
+
+	// A failed update keeps the previous members, so that the next apply retries it
+	server.TaskFailureMessage = "transit gateway attachment failed"
+	rawConfig["sddc_member_ids"] = []interface{}{firstSddcID, secondSddcID}
+	diff, err = resourceSddcGroup().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	state, diags = resourceSddcGroup().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.ErrorContains(t, diagsErr(diags), "transit gateway attachment failed")
+	assert.Equal(t, "1", state.Attributes["sddc_member_ids.#"])
+
+	// Members are validated before the update is started
+	server.TaskFailureMessage = ""
+	rawConfig["sddc_member_ids"] = []interface{}{secondSddcID, "unknown-sddc"}
+	diff, err = resourceSddcGroup().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	_, diags = resourceSddcGroup().Apply(context.Background(), state, diff, connectorWrapper)
+	assert.ErrorContains(t, diagsErr(diags), "unknown-sddc")
+
+	// The member, that failed to attach, is removed from the group together with the rest on destroy
+	d = resourceSddcGroup().Data(state)
+	assert.NoError(t, diagsErr(resourceSddcGroupRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, 2, d.Get("sddc_member_ids").(*schema.Set).Len())
+	assert.NoError(t, diagsErr(resourceSddcGroupDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
+
 func testCheckSddcGroupDestroyed(s *terraform.State) error {
 	if sddcGroupExists(s) {
 		return fmt.Errorf("sddc group still exists")
@@ -0,0 +1,221 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	task "github.com/vmware/terraform-provider-vmc/vmc/task"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func resourceSddcMicrosoftLicensing() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSddcMicrosoftLicensingCreate,
+		ReadContext:   resourceSddcMicrosoftLicensingRead,
+		UpdateContext: resourceSddcMicrosoftLicensingUpdate,
+		DeleteContext: resourceSddcMicrosoftLicensingDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ",")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected cluster_id,sddc_id", d.Id())
+				}
+				if err := IsValidUUID(idParts[0]); err != nil {
+					return nil, fmt.Errorf("invalid format for cluster_id : %v", err)
+				}
+				if err := IsValidUUID(idParts[1]); err != nil {
+					return nil, fmt.Errorf("invalid format for sddc_id : %v", err)
+				}
+
+				d.SetId(idParts[0])
+				d.Set("cluster_id", idParts[0])
+				d.Set("sddc_id", idParts[1])
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(20 * time.Minute),
+			Update: schema.DefaultTimeout(20 * time.Minute),
+			Delete: schema.DefaultTimeout(20 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "SDDC identifier",
+			},
+			"cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Identifier of the cluster the licensing configuration applies to. Defaults to the primary cluster of the SDDC.",
+			},
+			"mssql_licensing": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The status of MSSQL licensing for the cluster. Possible values : enabled, ENABLED, disabled, DISABLED.",
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.LicenseConfigEnabled, constants.LicenseConfigDisabled, constants.CapitalLicenseConfigEnabled, constants.CapitalLicenseConfigDisabled}, false),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+			"windows_licensing": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "The status of Windows licensing for the cluster. Possible values : enabled, ENABLED, disabled, DISABLED.",
+				ValidateFunc: validation.StringInSlice([]string{
+					constants.LicenseConfigEnabled, constants.LicenseConfigDisabled, constants.CapitalLicenseConfigEnabled, constants.CapitalLicenseConfigDisabled}, false),
+				DiffSuppressFunc: suppressCaseDiff,
+			},
+			"academic_license": {
+				Type:        schema.TypeBool,
+				Optional:    true,
+				Default:     false,
+				Description: "Flag to identify if it is Academic Standard or Commercial Standard License.",
+			},
+		},
+	}
+}
+
+func suppressCaseDiff(k, old, new string, d *schema.ResourceData) bool {
+	return strings.EqualFold(old, new)
+}
+
+func resourceSddcMicrosoftLicensingCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+	if len(clusterID) == 0 {
+		orgID := (m.(*connector.Wrapper)).OrgID
+		primaryCluster, err := api.NewClient(m.(*connector.Wrapper)).PrimaryCluster().Get(orgID, sddcID)
+		if err != nil {
+			return toDiagnostics(HandleCreateError("Microsoft Licensing", err))
+		}
+		clusterID = primaryCluster.ClusterId
+		d.Set("cluster_id", clusterID)
+	}
+	err := publishMsftLicenseConfig(ctx, d, m, expandSddcMicrosoftLicensing(d), d.Timeout(schema.TimeoutCreate))
+	if err != nil {
+		return toDiagnostics(HandleCreateError("Microsoft Licensing", err))
+	}
+	d.SetId(clusterID)
+	return resourceSddcMicrosoftLicensingRead(ctx, d, m)
+}
+
+func resourceSddcMicrosoftLicensingRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Id()
+	orgID := (m.(*connector.Wrapper)).OrgID
+	sddc, err := api.NewClient(m.(*connector.Wrapper)).Sddcs().Get(orgID, sddcID)
+	if err != nil {
+		return toDiagnostics(HandleReadError(d, "Microsoft Licensing", clusterID, err))
+	}
+	var cluster *model.Cluster
+	if sddc.ResourceConfig != nil {
+		for i := range sddc.ResourceConfig.Clusters {
+			if sddc.ResourceConfig.Clusters[i].ClusterId == clusterID {
+				cluster = &sddc.ResourceConfig.Clusters[i]
+				break
+			}
+		}
+	}
+	if cluster == nil {
+		log.Printf("Cluster %s of SDDC %s not found, removing the Microsoft licensing configuration from state", clusterID, sddcID)
+		d.SetId("")
+		return nil
+	}
+	d.Set("cluster_id", clusterID)
+	if cluster.MsftLicenseConfig != nil {
+		if cluster.MsftLicenseConfig.MssqlLicensing != nil {
+			d.Set("mssql_licensing", *cluster.MsftLicenseConfig.MssqlLicensing)
+		}
+		if cluster.MsftLicenseConfig.WindowsLicensing != nil {
+			d.Set("windows_licensing", *cluster.MsftLicenseConfig.WindowsLicensing)
+		}
+		if cluster.MsftLicenseConfig.AcademicLicense != nil {
+			d.Set("academic_license", *cluster.MsftLicenseConfig.AcademicLicense)
+		}
+	}
+	return nil
+}
+
+func resourceSddcMicrosoftLicensingUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	err := publishMsftLicenseConfig(ctx, d, m, expandSddcMicrosoftLicensing(d), d.Timeout(schema.TimeoutUpdate))
+	if err != nil {
+		return toDiagnostics(HandleUpdateError("Microsoft Licensing", err))
+	}
+	return resourceSddcMicrosoftLicensingRead(ctx, d, m)
+}
+
+// resourceSddcMicrosoftLicensingDelete disables the Microsoft licensing of the cluster, as the
+// licensing configuration of a cluster cannot be removed.
+func resourceSddcMicrosoftLicensingDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	disabled := constants.CapitalLicenseConfigDisabled
+	academicLicense := false
+	msftLicenseConfig := model.MsftLicensingConfig{
+		MssqlLicensing:   &disabled,
+		WindowsLicensing: &disabled,
+		AcademicLicense:  &academicLicense,
+	}
+	err := publishMsftLicenseConfig(ctx, d, m, msftLicenseConfig, d.Timeout(schema.TimeoutDelete))
+	if err != nil {
+		return toDiagnostics(HandleDeleteError("Microsoft Licensing", d.Id(), err))
+	}
+	d.SetId("")
+	return nil
+}
+
+// expandSddcMicrosoftLicensing converts the arguments of the resource to a model.MsftLicensingConfig.
+// Licensing flags, that are not configured, are left out, so that the service keeps their current value.
+func expandSddcMicrosoftLicensing(d *schema.ResourceData) model.MsftLicensingConfig {
+	academicLicense := d.Get("academic_license").(bool)
+	msftLicenseConfig := model.MsftLicensingConfig{AcademicLicense: &academicLicense}
+	if value, ok := d.GetOk("mssql_licensing"); ok {
+		mssqlLicensing := strings.ToUpper(value.(string))
+		msftLicenseConfig.MssqlLicensing = &mssqlLicensing
+	}
+	if value, ok := d.GetOk("windows_licensing"); ok {
+		windowsLicensing := strings.ToUpper(value.(string))
+		msftLicenseConfig.WindowsLicensing = &windowsLicensing
+	}
+	return msftLicenseConfig
+}
+
+// publishMsftLicenseConfig applies the licensing configuration to the cluster and waits for the task to finish.
+func publishMsftLicenseConfig(ctx context.Context, d *schema.ResourceData, m interface{}, msftLicenseConfig model.MsftLicensingConfig, timeout time.Duration) error {
+	connectorWrapper := m.(*connector.Wrapper)
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+	var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+	defer unlockFunction()
+	publishClient := api.NewClient(connectorWrapper).MsftLicensingPublish()
+	microsoftLicensingUpdateTask, err := publishClient.Post(connectorWrapper.OrgID, sddcID, clusterID, msftLicenseConfig)
+	if err != nil {
+		return err
+	}
+	return task.RetryContext(ctx, connectorWrapper.TaskPoll, timeout, func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper,
+			func() (model.Task, error) {
+				return task.GetTask(connectorWrapper, microsoftLicensingUpdateTask.Id)
+			},
+			"failed updating Microsoft licensing configuration of cluster "+clusterID,
+			func(task model.Task) {
+				unlockFunction()
+			})
+	})
+}
@@ -0,0 +1,68 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"testing"
+)
+
+func TestResourceVmcSddcMicrosoftLicensingSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "licensing_sddc"})
+	primaryCluster, err := api.NewClient(connectorWrapper).PrimaryCluster().Get(connectorWrapper.OrgID, sddcID)
+	assert.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceSddcMicrosoftLicensing().Schema, map[string]interface{}{
+		"sddc_id":         sddcID,
+		"mssql_licensing": constants.LicenseConfigEnabled,
+	})
+	assert.NoError(t, diagsErr(resourceSddcMicrosoftLicensingCreate(context.Background(), d, connectorWrapper)))
+	// Defaults to the primary cluster
+	assert.Equal(t, primaryCluster.ClusterId, d.Id())
+	assert.Equal(t, primaryCluster.ClusterId, d.Get("cluster_id"))
+	assert.Equal(t, constants.CapitalLicenseConfigEnabled, d.Get("mssql_licensing"))
+	assert.Equal(t, false, d.Get("academic_license"))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+
+	d.Set("windows_licensing", constants.CapitalLicenseConfigEnabled)
+	d.Set("academic_license", true)
+	assert.NoError(t, diagsErr(resourceSddcMicrosoftLicensingUpdate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, constants.CapitalLicenseConfigEnabled, d.Get("mssql_licensing"))
+	assert.Equal(t, constants.CapitalLicenseConfigEnabled, d.Get("windows_licensing"))
+	assert.Equal(t, true, d.Get("academic_license"))
+
+	assert.NoError(t, diagsErr(resourceSddcMicrosoftLicensingDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	d.SetId(primaryCluster.ClusterId)
+	assert.NoError(t, diagsErr(resourceSddcMicrosoftLicensingRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, constants.CapitalLicenseConfigDisabled, d.Get("mssql_licensing"))
+	assert.Equal(t, constants.CapitalLicenseConfigDisabled, d.Get("windows_licensing"))
+	assert.Equal(t, false, d.Get("academic_license"))
+}
+
+func TestResourceVmcSddcMicrosoftLicensingClusterNotFoundSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "licensing_sddc"})
+	d := schema.TestResourceDataRaw(t, resourceSddcMicrosoftLicensing().Schema, map[string]interface{}{
+		"sddc_id":    sddcID,
+		"cluster_id": "missing-cluster",
+	})
+	assert.Error(t, diagsErr(resourceSddcMicrosoftLicensingCreate(context.Background(), d, connectorWrapper)))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+
+	d.SetId("missing-cluster")
+	assert.NoError(t, diagsErr(resourceSddcMicrosoftLicensingRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
+
+func TestSuppressCaseDiff(t *testing.T) {
+	assert.True(t, suppressCaseDiff("mssql_licensing", "ENABLED", "enabled", nil))
+	assert.False(t, suppressCaseDiff("mssql_licensing", "ENABLED", "disabled", nil))
+}
@@ -4,14 +4,18 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/stretchr/testify/assert"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"github.com/vmware/terraform-provider-vmc/vmc/inventory"
 	"os"
 	"testing"
 
+	"github.com/hashicorp/go-cty/cty"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
@@ -295,3 +299,483 @@ func TestBuildAwsSddcConfigHostInstanceType(t *testing.T) {
 		}
 	}
 }
+
+func TestResourceVmcSddcSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	d := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{
+		"sddc_name":          "simulated_sddc",
+		"num_host":           2,
+		"region":             "US_WEST_2",
+		"host_instance_type": constants.HostInstancetypeI3,
+		"delay_account_link": true,
+	})
+
+	err := diagsErr(resourceSddcCreate(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	sddcID := d.Id()
+	assert.NotEmpty(t, sddcID)
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, d.Get("sddc_state"))
+	assert.Equal(t, 2, d.Get("num_host"))
+	assert.Equal(t, constants.SingleAvailabilityZone, d.Get("deployment_type"))
+	assert.Equal(t, constants.StorageScaleUpPolicyType, d.Get("edrs_policy_type"))
+	assert.Equal(t, constants.MinIntranetMtuLink, d.Get("intranet_mtu_uplink"))
+
+	err = diagsErr(resourceSddcDelete(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, model.Sddc_SDDC_STATE_DELETED, server.SddcState(sddcID))
+}
+
+func TestResourceVmcSddcDeletionProtectionSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "protected_sddc", NumHosts: 2})
+	d := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{
+		"deletion_protection": true,
+	})
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, true, d.Get("deletion_protection"))
+
+	diags := resourceSddcDelete(context.Background(), d, connectorWrapper)
+	if assert.True(t, diags.HasError()) {
+		assert.Contains(t, diags[0].Summary, "deletion_protection is enabled")
+		assert.Equal(t, cty.GetAttrPath("deletion_protection"), diags[0].AttributePath)
+	}
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, server.SddcState(sddcID))
+
+	// Imported SDDCs are not protected
+	imported := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{})
+	imported.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), imported, connectorWrapper)))
+	assert.Equal(t, false, imported.Get("deletion_protection"))
+	assert.NoError(t, diagsErr(resourceSddcDelete(context.Background(), imported, connectorWrapper)))
+	assert.Equal(t, model.Sddc_SDDC_STATE_DELETED, server.SddcState(sddcID))
+}
+
+func TestResourceVmcSddcDeleteOptionsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "failed_sddc", NumHosts: 2})
+	server.SetSddcState(sddcID, model.Sddc_SDDC_STATE_FAILED)
+	d := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{})
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, false, d.Get("force_delete"))
+	assert.Error(t, diagsErr(resourceSddcDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, model.Sddc_SDDC_STATE_FAILED, server.SddcState(sddcID))
+
+	d = schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{
+		"force_delete":         true,
+		"retain_configuration": true,
+		"template_name":        "failed_sddc_template",
+	})
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, model.Sddc_SDDC_STATE_DELETED, server.SddcState(sddcID))
+	query := server.SddcDeleteQuery(sddcID)
+	assert.Equal(t, "true", query.Get("force"))
+	assert.Equal(t, "true", query.Get("retain_configuration"))
+	assert.Equal(t, "failed_sddc_template", query.Get("template_name"))
+}
+
+func TestResourceVmcSddcTemplateNameRequiresRetainConfiguration(t *testing.T) {
+	_, err := resourceSddc().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+		"sddc_name":     "sddc",
+		"num_host":      2,
+		"region":        "US_WEST_2",
+		"template_name": "sddc_template",
+	}), nil)
+	assert.ErrorContains(t, err, "template_name requires retain_configuration")
+}
+
+func TestResourceVmcSddcRenameSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc", NumHosts: 2})
+	rawConfig := map[string]interface{}{
+		"sddc_name": "sddc",
+		"num_host":  2,
+		"region":    "US_WEST_2",
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+
+	// Renaming the SDDC must not plan to replace it
+	rawConfig["sddc_name"] = "prod-sddc"
+	diff, err := resourceSddc().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	assert.Len(t, diff.Attributes, 1)
+	assert.Equal(t, "prod-sddc", diff.Attributes["sddc_name"].New)
+	assert.False(t, diff.RequiresNew())
+
+	state, diags := resourceSddc().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, sddcID, state.ID)
+	assert.Equal(t, "prod-sddc", state.Attributes["sddc_name"])
+	assert.Equal(t, "prod-sddc", server.SddcName(sddcID))
+	assert.Contains(t, server.Requests(), "PATCH /vmc/api/orgs/"+simulator.TestOrgID+"/sddcs/"+sddcID)
+}
+
+func TestResourceVmcSddcUpsizeSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc", NumHosts: 2})
+	rawConfig := map[string]interface{}{
+		"sddc_name": "sddc",
+		"num_host":  2,
+		"region":    "US_WEST_2",
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, constants.MediumSddcSize, d.Get("sddc_size.vc_size"))
+
+	// The appliances are upsized in place, instead of the SDDC being replaced
+	rawConfig["size"] = constants.CapitalLargeSddcSize
+	diff, err := resourceSddc().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	assert.False(t, diff.RequiresNew())
+	state, diags := resourceSddc().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, sddcID, state.ID)
+	assert.Equal(t, constants.LargeSddcSize, state.Attributes["sddc_size.vc_size"])
+	assert.Equal(t, constants.LargeSddcSize, state.Attributes["sddc_size.nsx_size"])
+	assert.Contains(t, server.Requests(), "POST /api/inventory/"+simulator.TestOrgID+"/vmc-aws/operations")
+
+	// Downsizing fails the plan
+	rawConfig["size"] = constants.MediumSddcSize
+	_, err = resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.ErrorContains(t, err, "downsizing the SDDC from LARGE to medium is not supported")
+}
+
+func TestResourceVmcSddcUpsizeFailureSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc", NumHosts: 2})
+	rawConfig := map[string]interface{}{
+		"sddc_name": "sddc",
+		"num_host":  2,
+		"region":    "US_WEST_2",
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+
+	// A failed upsize keeps the previous size, so that the next apply retries it
+	server.TaskFailureMessage = "simulated upsize failure"
+	rawConfig["size"] = constants.LargeSddcSize
+	diff, err := resourceSddc().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	state, diags := resourceSddc().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	assert.ErrorContains(t, diagsErr(diags), "simulated upsize failure")
+	assert.Equal(t, constants.MediumSddcSize, state.Attributes["size"])
+
+	// An upsize started by an apply, that was interrupted, is resumed
+	server.TaskFailureMessage = ""
+	server.TaskPollsUntilFinished = 3
+	upsizeTaskID, err := inventory.NewInventoryClient(*connectorWrapper).UpsizeSddc(sddcID,
+		inventory.SddcSize{VcSize: constants.LargeSddcSize, NsxSize: constants.LargeSddcSize})
+	assert.NoError(t, err)
+	diff, err = resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	state, diags = resourceSddc().Apply(context.Background(), state, diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, constants.LargeSddcSize, state.Attributes["sddc_size.vc_size"])
+	assert.Contains(t, server.Requests(), "GET /vmc/api/orgs/"+simulator.TestOrgID+"/tasks/"+upsizeTaskID)
+	upsizes := 0
+	for _, request := range server.Requests() {
+		if request == "POST /api/inventory/"+simulator.TestOrgID+"/vmc-aws/operations" {
+			upsizes++
+		}
+	}
+	assert.Equal(t, 2, upsizes)
+}
+
+func TestResourceVmcSddcScaleOutTimeoutSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc", NumHosts: 2})
+	rawConfig := map[string]interface{}{
+		"sddc_name":         "sddc",
+		"num_host":          2,
+		"region":            "US_WEST_2",
+		"scale_out_timeout": "1s",
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+
+	// Adding the hosts outlasts the scale_out_timeout
+	server.TaskPollsUntilFinished = 1000
+	rawConfig["num_host"] = 3
+	diff, err := resourceSddc().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+	_, diags := resourceSddc().Apply(context.Background(), d.State(), diff, connectorWrapper)
+	err = diagsErr(diags)
+	assert.ErrorContains(t, err, "timed out after 1s")
+	assert.ErrorContains(t, err, "the cluster has 2 hosts, 1 hosts are still to be added")
+}
+
+func TestResourceVmcSddcCloudPasswordKeepers(t *testing.T) {
+	assert.True(t, sddcSchema()["cloud_password"].Sensitive)
+
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc"})
+	rawConfig := map[string]interface{}{
+		"sddc_name":              "sddc",
+		"region":                 "US_WEST_2",
+		"cloud_password_keepers": map[string]interface{}{"rotation": "1"},
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	state := d.State()
+
+	testCases := []struct {
+		rotation         string
+		expectRecomputed bool
+	}{
+		{rotation: "1", expectRecomputed: false},
+		{rotation: "2", expectRecomputed: true},
+	}
+	for _, testCase := range testCases {
+		rawConfig["cloud_password_keepers"] = map[string]interface{}{"rotation": testCase.rotation}
+		diff, err := resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), nil)
+		assert.NoError(t, err)
+		recomputed := false
+		if diff != nil {
+			passwordDiff, ok := diff.Attributes["cloud_password"]
+			recomputed = ok && passwordDiff.NewComputed
+		}
+		assert.Equal(t, testCase.expectRecomputed, recomputed)
+	}
+
+	server.SetCloudPassword(sddcID, "console-reset-password")
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "console-reset-password", d.Get("cloud_password"))
+}
+
+func TestResourceVmcSddcReadUnknownEnumValuesSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{
+		Name:             "future_sddc",
+		HostInstanceType: "m7i.metal-24xl",
+		DeploymentType:   "FUTURE_AZ",
+	})
+	d := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{
+		"sddc_name": "future_sddc",
+	})
+	d.SetId(sddcID)
+
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, "FUTURE_AZ", d.Get("deployment_type"))
+	clusterInfo := d.Get("cluster_info").(map[string]interface{})
+	assert.Equal(t, "m7i.metal-24xl", clusterInfo["host_instance_type"])
+}
+
+func TestResourceVmcSddcMultiAZSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "stretched", NumHosts: 6, DeploymentType: "MULTI_AZ"})
+	rawConfig := map[string]interface{}{
+		"sddc_name":       "stretched",
+		"region":          "US_WEST_2",
+		"num_host":        6,
+		"deployment_type": constants.MultiAvailabilityZone,
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "us-west-2b", d.Get("secondary_availability_zone"))
+	assert.Equal(t, "us-west-2c", d.Get("witness_availability_zone"))
+	assert.Equal(t, "witness.sddc.vmc.local", d.Get("vsan_witness.hostname"))
+	state := d.State()
+
+	// Hosts of stretched clusters are added and removed in pairs
+	for numHost, expectError := range map[int]bool{8: false, 7: true} {
+		rawConfig["num_host"] = numHost
+		_, err := resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), nil)
+		assert.Equal(t, expectError, err != nil, "num_host: %d", numHost)
+	}
+	rawConfig["num_host"] = 3
+	_, err := resourceSddc().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.Error(t, err)
+	rawConfig["deployment_type"] = constants.SingleAvailabilityZone
+	_, err = resourceSddc().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(rawConfig), nil)
+	assert.NoError(t, err)
+}
+
+func TestResourceVmcSddcCidrValidationSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.AddCustomerSubnet("vpc-1", "subnet-1a", "10.20.0.0/24", "us-west-2a", "usw2-az1")
+	server.SetCustomerVpcCidr("vpc-1", "10.20.0.0/16")
+
+	testCases := []struct {
+		vpcCidr     string
+		vxlanSubnet string
+		linked      bool
+		expectedErr string
+	}{
+		{vpcCidr: "10.2.0.0/16", vxlanSubnet: "192.168.1.0/24"},
+		{vpcCidr: "10.2.0.0/16", vxlanSubnet: "192.168.1.0/24", linked: true},
+		{vpcCidr: "10.2.0.0/16"},
+		{vpcCidr: "10.1.0.0/16", expectedErr: "overlaps with the reserved range 10.0.0.0/15"},
+		{vpcCidr: "172.31.0.0/20", expectedErr: "overlaps with the reserved range 172.31.0.0/16"},
+		{vpcCidr: "10.2.0.0/16", vxlanSubnet: "100.64.1.0/24", expectedErr: "overlaps with the reserved range 100.64.0.0/16"},
+		{vpcCidr: "10.2.0.0/16", vxlanSubnet: "10.2.1.0/24", expectedErr: "overlaps with the management CIDR"},
+		{vpcCidr: "10.2.0.0/33", expectedErr: "invalid vpc_cidr"},
+		{vpcCidr: "10.20.0.0/20"},
+		{vpcCidr: "10.20.0.0/20", linked: true, expectedErr: "overlaps with the CIDR of the connected VPC 10.20.0.0/16"},
+		{vpcCidr: "10.2.0.0/16", vxlanSubnet: "10.20.8.0/24", linked: true, expectedErr: "vxlan_subnet 10.20.8.0/24 overlaps"},
+	}
+	for _, testCase := range testCases {
+		rawConfig := map[string]interface{}{
+			"sddc_name": "sddc",
+			"num_host":  2,
+			"region":    "US_WEST_2",
+			"vpc_cidr":  testCase.vpcCidr,
+		}
+		if len(testCase.vxlanSubnet) > 0 {
+			rawConfig["vxlan_subnet"] = testCase.vxlanSubnet
+		}
+		if testCase.linked {
+			rawConfig["account_link_sddc_config"] = []interface{}{map[string]interface{}{
+				"connected_account_id": "account-1",
+				"customer_subnet_ids":  []interface{}{"subnet-1a"},
+			}}
+		}
+		_, err := resourceSddc().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+		if len(testCase.expectedErr) == 0 {
+			assert.NoError(t, err, "%+v", testCase)
+		} else {
+			assert.ErrorContains(t, err, testCase.expectedErr, "%+v", testCase)
+		}
+	}
+
+	// The CIDRs of existing SDDCs are validated only when they change
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "sddc", NumHosts: 2, VpcCidr: "10.1.0.0/16"})
+	rawConfig := map[string]interface{}{
+		"sddc_name": "sddc",
+		"num_host":  2,
+		"region":    "US_WEST_2",
+		"vpc_cidr":  "10.1.0.0/16",
+	}
+	d := schema.TestResourceDataRaw(t, sddcSchema(), rawConfig)
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	_, err := resourceSddc().Diff(context.Background(), d.State(), terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+	assert.NoError(t, err)
+}
+
+func TestResourceVmcSddcResumesInterruptedOperationsSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.TaskPollsUntilFinished = 2
+	sddcsPath := "/vmc/api/orgs/" + simulator.TestOrgID + "/sddcs"
+	d := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{
+		"sddc_name":          "interrupted",
+		"num_host":           2,
+		"region":             "US_WEST_2",
+		"delay_account_link": true,
+	})
+
+	// Creation started by an apply, that was interrupted
+	createTask, err := orgs.NewSddcsClient(connectorWrapper).Create(simulator.TestOrgID,
+		model.AwsSddcConfig{Name: "interrupted", NumHosts: 2, Region: "US_WEST_2"}, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, diagsErr(resourceSddcCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, *createTask.ResourceId, d.Id())
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, d.Get("sddc_state"))
+	assert.Contains(t, server.Requests(), "GET /vmc/api/orgs/"+simulator.TestOrgID+"/tasks/"+createTask.Id)
+
+	// Deletion started by an apply, that was interrupted
+	sddcID := d.Id()
+	deleteTask, err := orgs.NewSddcsClient(connectorWrapper).Delete(simulator.TestOrgID, sddcID, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.NoError(t, diagsErr(resourceSddcDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, model.Sddc_SDDC_STATE_DELETED, server.SddcState(sddcID))
+	assert.Contains(t, server.Requests(), "GET /vmc/api/orgs/"+simulator.TestOrgID+"/tasks/"+deleteTask.Id)
+
+	creates, deletes := 0, 0
+	for _, request := range server.Requests() {
+		switch request {
+		case "POST " + sddcsPath:
+			creates++
+		case "DELETE " + sddcsPath + "/" + sddcID:
+			deletes++
+		}
+	}
+	assert.Equal(t, 1, creates)
+	assert.Equal(t, 1, deletes)
+}
+
+func TestResourceVmcSddcProvisioningPhaseSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "deploying_sddc"})
+	server.SetSddcState(sddcID, model.Sddc_SDDC_STATE_DEPLOYING)
+	server.SetTaskProgress(server.StartTask(constants.SddcProvisionTaskType, sddcID), 45, "DEPLOY_HOSTS")
+
+	d := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{})
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "DEPLOY_HOSTS", d.Get("provisioning_phase"))
+
+	server.SetSddcState(sddcID, model.Sddc_SDDC_STATE_READY)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Get("provisioning_phase"))
+}
+
+func TestResourceVmcSddcSecuritySimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	pciSddcID := server.AddSddc(simulator.SddcConfig{Name: "pci_sddc", SecurityProfile: model.SddcSecurity_PROFILE_PCI_COMMERCIAL})
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "default_sddc"})
+
+	d := schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{})
+	d.SetId(pciSddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, model.SddcSecurity_PROFILE_PCI_COMMERCIAL, d.Get("sddc_security.0.profile"))
+	assert.Equal(t, true, d.Get("sddc_security.0.hardened"))
+
+	d = schema.TestResourceDataRaw(t, sddcSchema(), map[string]interface{}{})
+	d.SetId(sddcID)
+	assert.NoError(t, diagsErr(resourceSddcRead(context.Background(), d, connectorWrapper)))
+	assert.Empty(t, d.Get("sddc_security"))
+}
+
+func TestResourceVmcSddcZeroCloudSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	rawConfig := map[string]interface{}{
+		"sddc_name":     "zerocloud_sddc",
+		"num_host":      2,
+		"provider_type": constants.ZeroCloudProviderType,
+		"region":        "US_WEST_2",
+	}
+	diff, err := resourceSddc().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+	assert.NoError(t, err)
+	state, diags := resourceSddc().Apply(context.Background(), nil, diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	sddcID := state.ID
+	assert.Equal(t, constants.ZeroCloudProviderType, state.Attributes["provider_type"])
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, server.SddcState(sddcID))
+
+	// Scaling the primary cluster
+	rawConfig["num_host"] = 3
+	diff, err = resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+	assert.NoError(t, err)
+	state, diags = resourceSddc().Apply(context.Background(), state, diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, "3", state.Attributes["num_host"])
+	diff, err = resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+	assert.NoError(t, err)
+	assert.Nil(t, diff, "a refreshed ZEROCLOUD SDDC plans no changes")
+
+	// ZEROCLOUD SDDCs have no intranet uplink
+	rawConfig["intranet_mtu_uplink"] = 8900
+	_, err = resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(rawConfig), connectorWrapper)
+	assert.ErrorContains(t, err, "intranet_mtu_uplink cannot be configured for ZEROCLOUD SDDCs")
+	delete(rawConfig, "intranet_mtu_uplink")
+
+	diff, err = resourceSddc().Diff(context.Background(), state, terraform.NewResourceConfigRaw(map[string]interface{}{}), connectorWrapper)
+	assert.NoError(t, err)
+	diff.Destroy = true
+	_, diags = resourceSddc().Apply(context.Background(), state, diff, connectorWrapper)
+	assert.False(t, diags.HasError(), "%v", diags)
+	assert.Equal(t, model.Sddc_SDDC_STATE_DELETED, server.SddcState(sddcID))
+}
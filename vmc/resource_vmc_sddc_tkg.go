@@ -0,0 +1,239 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/task"
+	"github.com/vmware/terraform-provider-vmc/vmc/tkg"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func resourceSddcTkg() *schema.Resource {
+	return &schema.Resource{
+		CreateContext: resourceSddcTkgCreate,
+		ReadContext:   resourceSddcTkgRead,
+		DeleteContext: resourceSddcTkgDelete,
+		Importer: &schema.ResourceImporter{
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+				idParts := strings.Split(d.Id(), ",")
+				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
+					return nil, fmt.Errorf("unexpected format of ID (%q), expected cluster_id,sddc_id", d.Id())
+				}
+				if err := IsValidUUID(idParts[0]); err != nil {
+					return nil, fmt.Errorf("invalid format for cluster_id : %v", err)
+				}
+				if err := IsValidUUID(idParts[1]); err != nil {
+					return nil, fmt.Errorf("invalid format for sddc_id : %v", err)
+				}
+
+				d.SetId(idParts[0])
+				d.Set("cluster_id", idParts[0])
+				d.Set("sddc_id", idParts[1])
+				return []*schema.ResourceData{d}, nil
+			},
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(120 * time.Minute),
+			Delete: schema.DefaultTimeout(60 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"sddc_id": {
+				Type:        schema.TypeString,
+				Required:    true,
+				ForceNew:    true,
+				Description: "SDDC identifier",
+			},
+			"cluster_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				ForceNew:    true,
+				Description: "Identifier of the cluster to activate Tanzu Kubernetes Grid on. Defaults to the primary cluster of the SDDC.",
+			},
+			"egress_cidr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "CIDR block from which the source NAT IPs of the Kubernetes workloads are allotted.",
+			},
+			"ingress_cidr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "CIDR block from which the IPs of the Kubernetes load balancers are allotted.",
+			},
+			"namespace_cidr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "CIDR block from which the IPs of the pods of the vSphere namespaces are allotted.",
+			},
+			"service_cidr": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.IsCIDR,
+				Description:  "CIDR block from which the IPs of the Kubernetes services are allotted.",
+			},
+			"wcp_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The status of the workload control plane of the cluster.",
+			},
+		},
+	}
+}
+
+func resourceSddcTkgCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	connectorWrapper := m.(*connector.Wrapper)
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Get("cluster_id").(string)
+	if len(clusterID) == 0 {
+		primaryCluster, err := api.NewClient(connectorWrapper).PrimaryCluster().Get(connectorWrapper.OrgID, sddcID)
+		if err != nil {
+			return toDiagnostics(HandleCreateError("Tanzu Kubernetes Grid", err))
+		}
+		clusterID = primaryCluster.ClusterId
+		d.Set("cluster_id", clusterID)
+	}
+	networkConfig, err := expandTkgNetworkConfig(d)
+	if err != nil {
+		return toDiagnostics(HandleCreateError("Tanzu Kubernetes Grid", err))
+	}
+
+	// Activation reconfigures the cluster, so it must not overlap with other cluster mutations
+	var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+	defer unlockFunction()
+	tkgClient := api.NewClient(connectorWrapper).Tkg()
+	// The networks have to be validated against the networks of the SDDC first
+	validationTaskID, err := tkgClient.ValidateNetwork(sddcID, clusterID, networkConfig)
+	if err != nil {
+		return toDiagnostics(HandleCreateError("Tanzu Kubernetes Grid", err))
+	}
+	err = waitForTkgTask(ctx, connectorWrapper, validationTaskID, d.Timeout(schema.TimeoutCreate),
+		"Tanzu Kubernetes Grid network validation failed")
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	enableTaskID, err := tkgClient.Enable(sddcID, clusterID, networkConfig)
+	if err != nil {
+		return toDiagnostics(HandleCreateError("Tanzu Kubernetes Grid", err))
+	}
+	d.SetId(clusterID)
+	err = waitForTkgTask(ctx, connectorWrapper, enableTaskID, d.Timeout(schema.TimeoutCreate),
+		"failed to activate Tanzu Kubernetes Grid")
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	return resourceSddcTkgRead(ctx, d, m)
+}
+
+func resourceSddcTkgRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Id()
+	orgID := (m.(*connector.Wrapper)).OrgID
+	sddc, err := api.NewClient(m.(*connector.Wrapper)).Sddcs().Get(orgID, sddcID)
+	if err != nil {
+		return toDiagnostics(HandleReadError(d, "Tanzu Kubernetes Grid", clusterID, err))
+	}
+	var cluster *model.Cluster
+	if sddc.ResourceConfig != nil {
+		for i := range sddc.ResourceConfig.Clusters {
+			if sddc.ResourceConfig.Clusters[i].ClusterId == clusterID {
+				cluster = &sddc.ResourceConfig.Clusters[i]
+				break
+			}
+		}
+	}
+	if cluster == nil {
+		log.Printf("Cluster %s of SDDC %s not found, removing Tanzu Kubernetes Grid from state", clusterID, sddcID)
+		d.SetId("")
+		return nil
+	}
+	wcpDetails := cluster.WcpDetails
+	if wcpDetails == nil || wcpDetails.WcpStatus == nil || *wcpDetails.WcpStatus == model.WcpDetails_WCP_STATUS_DISABLED {
+		log.Printf("Tanzu Kubernetes Grid is not activated on cluster %s of SDDC %s, removing it from state", clusterID, sddcID)
+		d.SetId("")
+		return nil
+	}
+	d.Set("cluster_id", clusterID)
+	d.Set("wcp_status", *wcpDetails.WcpStatus)
+	// The networks are reported only once the activation has completed
+	for key, value := range map[string]*string{
+		"egress_cidr":    wcpDetails.EgressCidr,
+		"ingress_cidr":   wcpDetails.IngressCidr,
+		"namespace_cidr": wcpDetails.PodCidr,
+		"service_cidr":   wcpDetails.ServiceCidr,
+	} {
+		if value != nil && len(*value) > 0 {
+			d.Set(key, *value)
+		}
+	}
+	return nil
+}
+
+func resourceSddcTkgDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	connectorWrapper := m.(*connector.Wrapper)
+	sddcID := d.Get("sddc_id").(string)
+	clusterID := d.Id()
+
+	var unlockFunction = clusterMutationKeyedMutex.Lock(sddcID)
+	defer unlockFunction()
+	disableTaskID, err := api.NewClient(connectorWrapper).Tkg().Disable(sddcID, clusterID)
+	if err != nil {
+		return toDiagnostics(HandleDeleteError("Tanzu Kubernetes Grid", clusterID, err))
+	}
+	err = waitForTkgTask(ctx, connectorWrapper, disableTaskID, d.Timeout(schema.TimeoutDelete),
+		"failed to deactivate Tanzu Kubernetes Grid")
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	d.SetId("")
+	return nil
+}
+
+// waitForTkgTask polls the VMC task tracking a workload control plane operation until it finishes.
+func waitForTkgTask(ctx context.Context, connectorWrapper *connector.Wrapper, taskID string, timeout time.Duration, errorMessage string) error {
+	return task.RetryContext(ctx, connectorWrapper.TaskPoll, timeout, func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper, func() (model.Task, error) {
+			return task.GetTask(connectorWrapper, taskID)
+		}, errorMessage, nil)
+	})
+}
+
+// expandTkgNetworkConfig converts the CIDR arguments of the vmc_sddc_tkg resource into the
+// network configuration expected by the workload control plane APIs.
+func expandTkgNetworkConfig(d *schema.ResourceData) (tkg.NetworkConfig, error) {
+	cidrs := map[string]tkg.Cidr{}
+	for _, key := range []string{"egress_cidr", "ingress_cidr", "namespace_cidr", "service_cidr"} {
+		_, network, err := net.ParseCIDR(d.Get(key).(string))
+		if err != nil {
+			return tkg.NetworkConfig{}, newAttributeError(key, "invalid %s: %v", key, err)
+		}
+		prefix, _ := network.Mask.Size()
+		cidrs[key] = tkg.Cidr{Address: network.IP.String(), Prefix: prefix}
+	}
+	return tkg.NetworkConfig{
+		EgressCidr:    []tkg.Cidr{cidrs["egress_cidr"]},
+		IngressCidr:   []tkg.Cidr{cidrs["ingress_cidr"]},
+		NamespaceCidr: []tkg.Cidr{cidrs["namespace_cidr"]},
+		ServiceCidr:   cidrs["service_cidr"],
+	}, nil
+}
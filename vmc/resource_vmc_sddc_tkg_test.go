@@ -0,0 +1,81 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func testSddcTkgConfig(sddcID string) map[string]interface{} {
+	return map[string]interface{}{
+		"sddc_id":        sddcID,
+		"egress_cidr":    "10.2.0.0/26",
+		"ingress_cidr":   "10.3.0.0/26",
+		"namespace_cidr": "10.4.0.0/20",
+		"service_cidr":   "10.5.0.0/23",
+	}
+}
+
+func TestResourceVmcSddcTkgSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "tkg_sddc"})
+	primaryCluster, err := api.NewClient(connectorWrapper).PrimaryCluster().Get(connectorWrapper.OrgID, sddcID)
+	assert.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceSddcTkg().Schema, testSddcTkgConfig(sddcID))
+	assert.NoError(t, diagsErr(resourceSddcTkgCreate(context.Background(), d, connectorWrapper)))
+	// Defaults to the primary cluster
+	assert.Equal(t, primaryCluster.ClusterId, d.Id())
+	assert.Equal(t, primaryCluster.ClusterId, d.Get("cluster_id"))
+	assert.Equal(t, model.WcpDetails_WCP_STATUS_ENABLED, d.Get("wcp_status"))
+	assert.Equal(t, "10.4.0.0/20", d.Get("namespace_cidr"))
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+	assert.Contains(t, server.Requests(), "POST /api/wcp/v1/orgs/"+simulator.TestOrgID+"/deployments/"+sddcID+
+		"/clusters/"+primaryCluster.ClusterId+"/operations/validate-network")
+
+	assert.NoError(t, diagsErr(resourceSddcTkgDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+
+	// Deactivated outside of Terraform
+	d.SetId(primaryCluster.ClusterId)
+	assert.NoError(t, diagsErr(resourceSddcTkgRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResourceVmcSddcTkgValidationFailedSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "tkg_sddc"})
+	server.TaskFailureMessage = "egress CIDR overlaps with the management network"
+
+	d := schema.TestResourceDataRaw(t, resourceSddcTkg().Schema, testSddcTkgConfig(sddcID))
+	err := diagsErr(resourceSddcTkgCreate(context.Background(), d, connectorWrapper))
+	assert.ErrorContains(t, err, "network validation failed")
+	assert.Equal(t, "", d.Id())
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+	for _, request := range server.Requests() {
+		assert.NotContains(t, request, "enable-wcp")
+	}
+}
+
+func TestResourceVmcSddcTkgClusterNotFoundSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "tkg_sddc"})
+	config := testSddcTkgConfig(sddcID)
+	config["cluster_id"] = "missing-cluster"
+	d := schema.TestResourceDataRaw(t, resourceSddcTkg().Schema, config)
+	assert.ErrorContains(t, diagsErr(resourceSddcTkgCreate(context.Background(), d, connectorWrapper)), "not found")
+	assertUnlocked(t, &clusterMutationKeyedMutex, sddcID)
+
+	d.SetId("missing-cluster")
+	assert.NoError(t, diagsErr(resourceSddcTkgRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
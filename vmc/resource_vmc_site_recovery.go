@@ -7,32 +7,39 @@ import (
 	"context"
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
 	task "github.com/vmware/terraform-provider-vmc/vmc/task"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas"
 	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
 )
 
+// siteRecoveryActivationMutex a mutex that allows only a single site recovery activation per sddc.
+var siteRecoveryActivationMutex = task.KeyedMutex{}
+
 func resourceSiteRecovery() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSiteRecoveryCreate,
-		Read:   resourceSiteRecoveryRead,
-		Update: resourceSiteRecoveryUpdate,
-		Delete: resourceSiteRecoveryDelete,
+		CreateContext: resourceSiteRecoveryCreate,
+		ReadContext:   resourceSiteRecoveryRead,
+		UpdateContext: resourceSiteRecoveryUpdate,
+		DeleteContext: resourceSiteRecoveryDelete,
 		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			StateContext: resourceSiteRecoveryImport,
 		},
 		Timeouts: &schema.ResourceTimeout{
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
+		// See resourceSddc for changes requiring a new SchemaVersion
+		SchemaVersion: 0,
 		Schema: map[string]*schema.Schema{
 			"sddc_id": {
 				Type:        schema.TypeString,
@@ -65,6 +72,47 @@ func resourceSiteRecovery() *schema.Resource {
 				Type:     schema.TypeMap,
 				Computed: true,
 			},
+			"srm_nodes": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "All SRM nodes of the SDDC, including the ones managed by vmc_srm_node resources.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"host_name": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vm_moref_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ui_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"api_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
 			"vr_node": {
 				Type:     schema.TypeMap,
 				Computed: true,
@@ -77,60 +125,179 @@ func resourceSiteRecovery() *schema.Resource {
 	}
 }
 
-func resourceSiteRecoveryCreate(d *schema.ResourceData, m interface{}) error {
-
-	err := (m.(*connector.Wrapper)).Authenticate()
-	if err != nil {
-		return fmt.Errorf("authentication error from Cloud Service Provider: %s", err)
-	}
-	connectorWrapper := (m.(*connector.Wrapper))
-
-	siteRecoveryClient := draas.NewSiteRecoveryClient(connectorWrapper)
-
+func resourceSiteRecoveryCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	srmExtensionKeySuffix := d.Get("srm_extension_key_suffix").(string)
 	orgID := (m.(*connector.Wrapper)).OrgID
 	sddcID := d.Get("sddc_id").(string)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	connectorWrapper := draasClient.Wrapper()
 
-	activateSiteRecoveryConfigParam := &draasmodel.ActivateSiteRecoveryConfig{
-		SrmExtensionKeySuffix: &srmExtensionKeySuffix,
-	}
+	siteRecoveryClient := draasClient.SiteRecovery()
 
-	siteRecoveryCreateTask, err := siteRecoveryClient.Post(orgID, sddcID, activateSiteRecoveryConfigParam)
+	// Allow only a single activation per SDDC to be in flight from this provider instance
+	unlockFn := siteRecoveryActivationMutex.Lock(sddcID)
+	defer unlockFn()
 
+	// Site recovery may already be activated, or be in the process of activation, by
+	// another Terraform workspace. Converge on the existing activation in that case.
+	activationTaskID, activated, err := getExistingSiteRecoveryActivation(draasClient, sddcID, srmExtensionKeySuffix)
 	if err != nil {
-		return HandleCreateError("Site recovery", err)
+		return toDiagnostics(HandleCreateError("Site recovery", err))
+	}
+	if activated {
+		log.Printf("[INFO] Site recovery is already activated for SDDC %s", sddcID)
+		d.SetId(sddcID)
+		return resourceSiteRecoveryRead(ctx, d, m)
 	}
 
+	if activationTaskID == "" {
+		activateSiteRecoveryConfigParam := &draasmodel.ActivateSiteRecoveryConfig{
+			SrmExtensionKeySuffix: &srmExtensionKeySuffix,
+		}
+		siteRecoveryCreateTask, postErr := siteRecoveryClient.Post(orgID, sddcID, activateSiteRecoveryConfigParam)
+		if postErr != nil {
+			// The activation may have been started concurrently, between the check above
+			// and the activation request.
+			activationTaskID, activated, err = getExistingSiteRecoveryActivation(draasClient, sddcID, srmExtensionKeySuffix)
+			if err != nil || (!activated && activationTaskID == "") {
+				return toDiagnostics(HandleCreateError("Site recovery", postErr))
+			}
+			if activated {
+				log.Printf("[INFO] Site recovery is already activated for SDDC %s", sddcID)
+				d.SetId(sddcID)
+				return resourceSiteRecoveryRead(ctx, d, m)
+			}
+		} else {
+			activationTaskID = siteRecoveryCreateTask.Id
+		}
+	}
+	log.Printf("[DEBUG] Waiting for site recovery activation task %s for SDDC %s", activationTaskID, sddcID)
+
 	// Wait until site recovery is activated
-	taskID := siteRecoveryCreateTask.ResourceId
-	d.SetId(*taskID)
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		taskErr := task.RetryTaskUntilFinished(connectorWrapper,
+	d.SetId(sddcID)
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper,
 			func() (model.Task, error) {
-				return task.GetDraasTask(connectorWrapper, siteRecoveryCreateTask.Id)
+				return task.GetDraasTask(connectorWrapper, activationTaskID)
 			},
 			"error activation site recovery ",
 			nil)
-		if taskErr != nil {
-			return taskErr
+	})
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	return resourceSiteRecoveryRead(ctx, d, m)
+}
+
+// getExistingSiteRecoveryActivation checks the site recovery state of an SDDC. Returns true if site recovery
+// is already activated, or the ID of the activation task if site recovery is being activated at the moment.
+// An existing activation is only adopted, if it uses the provided SRM extension key suffix.
+func getExistingSiteRecoveryActivation(draasClient *api.Client, sddcID string, srmExtensionKeySuffix string) (string, bool, error) {
+	siteRecoveryClient := draasClient.SiteRecovery()
+	siteRecovery, err := siteRecoveryClient.Get(draasClient.OrgID(), sddcID)
+	if err != nil {
+		// Site recovery has never been activated on this SDDC
+		if isNotFoundError(err) {
+			return "", false, nil
 		}
-		err = resourceSiteRecoveryRead(d, m)
-		if err == nil {
-			return nil
+		return "", false, err
+	}
+	if siteRecovery.SiteRecoveryState == nil {
+		return "", false, nil
+	}
+	switch *siteRecovery.SiteRecoveryState {
+	case draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED, draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATING:
+		// The suffix is empty while the SRM node is being deployed, as well as for the default extension key
+		existingSuffix := getSiteRecoveryExtensionKeySuffix(siteRecovery)
+		if len(existingSuffix) > 0 && existingSuffix != strings.TrimSpace(srmExtensionKeySuffix) {
+			return "", false, fmt.Errorf("site recovery for SDDC %s is activated or being activated with srm_extension_key_suffix %q, "+
+				"which differs from the configured %q. Configure the existing suffix, or deactivate site recovery first",
+				sddcID, existingSuffix, srmExtensionKeySuffix)
 		}
-		return resource.NonRetryableError(err)
-	})
+	}
+	switch *siteRecovery.SiteRecoveryState {
+	case draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED:
+		return "", true, nil
+	case draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATING:
+		activationTask, err := task.GetInProgressDraasTask(draasClient.Wrapper(), sddcID)
+		if err != nil {
+			return "", false, err
+		}
+		if activationTask == nil {
+			return "", false, fmt.Errorf("site recovery for SDDC %s is activating, but no activation task was found", sddcID)
+		}
+		log.Printf("[INFO] Site recovery activation for SDDC %s is already in progress, task ID: %s", sddcID, activationTask.Id)
+		return activationTask.Id, false, nil
+	}
+	return "", false, nil
 }
 
-func resourceSiteRecoveryRead(d *schema.ResourceData, m interface{}) error {
-	connectorWrapper := (m.(*connector.Wrapper)).Connector
+// getExistingSiteRecoveryDeactivation returns the ID of the deactivation task of the site recovery of
+// an SDDC, if site recovery is being deactivated at the moment.
+func getExistingSiteRecoveryDeactivation(draasClient *api.Client, sddcID string) (string, error) {
+	siteRecovery, err := draasClient.SiteRecovery().Get(draasClient.OrgID(), sddcID)
+	if err != nil {
+		// Let the deactivation request report the error
+		return "", nil
+	}
+	if siteRecovery.SiteRecoveryState == nil ||
+		*siteRecovery.SiteRecoveryState != draasmodel.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATING {
+		return "", nil
+	}
+	deactivationTask, err := task.GetInProgressDraasTask(draasClient.Wrapper(), sddcID)
+	if err != nil || deactivationTask == nil {
+		return "", err
+	}
+	log.Printf("[INFO] Site recovery deactivation for SDDC %s is already in progress, task ID: %s", sddcID, deactivationTask.Id)
+	return deactivationTask.Id, nil
+}
+
+// resourceSiteRecoveryImport imports the site recovery of an SDDC by the SDDC ID. The SRM extension
+// key suffix is read from the SRM node deployed upon activation, so that importing does not plan a
+// deactivation and reactivation of site recovery.
+func resourceSiteRecoveryImport(ctx context.Context, d *schema.ResourceData, m interface{}) ([]*schema.ResourceData, error) {
+	sddcID := d.Id()
+	if err := IsValidUUID(sddcID); err != nil {
+		return nil, fmt.Errorf("invalid format for sddc_id : %v", err)
+	}
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	siteRecovery, err := draasClient.SiteRecovery().Get(draasClient.OrgID(), sddcID)
+	if err != nil {
+		if isNotFoundError(err) {
+			return nil, fmt.Errorf("site recovery has never been activated on SDDC %s", sddcID)
+		}
+		return nil, err
+	}
+	if siteRecovery.SiteRecoveryState == nil ||
+		(*siteRecovery.SiteRecoveryState != draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED &&
+			*siteRecovery.SiteRecoveryState != draasmodel.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATING) {
+		return nil, fmt.Errorf("site recovery is not activated on SDDC %s", sddcID)
+	}
+	d.Set("sddc_id", sddcID)
+	if suffix := getSiteRecoveryExtensionKeySuffix(siteRecovery); len(suffix) > 0 {
+		d.Set("srm_extension_key_suffix", suffix)
+	}
+	return []*schema.ResourceData{d}, nil
+}
+
+// getSiteRecoveryExtensionKeySuffix returns the SRM extension key suffix site recovery was activated with,
+// which is the one of its first SRM node. Returns an empty string for the default extension key.
+func getSiteRecoveryExtensionKeySuffix(siteRecovery draasmodel.SiteRecovery) string {
+	if len(siteRecovery.SrmNodes) > 0 && siteRecovery.SrmNodes[0].SrmExtensionKeySuffix != nil {
+		return *siteRecovery.SrmNodes[0].SrmExtensionKeySuffix
+	}
+	return ""
+}
+
+func resourceSiteRecoveryRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	sddcID := d.Id()
 	orgID := (m.(*connector.Wrapper)).OrgID
-	siteRecoveryClient := draas.NewSiteRecoveryClient(connectorWrapper)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	siteRecoveryClient := draasClient.SiteRecovery()
 	siteRecovery, err := siteRecoveryClient.Get(orgID, sddcID)
 	if err != nil {
 
-		return HandleReadError(d, "Site recovery", sddcID, err)
+		return toDiagnostics(HandleReadError(d, "Site recovery", sddcID, err))
 	}
 	d.SetId(siteRecovery.Id)
 	d.Set("site_recovery_state", siteRecovery.SiteRecoveryState)
@@ -138,68 +305,79 @@ func resourceSiteRecoveryRead(d *schema.ResourceData, m interface{}) error {
 	d.Set("user_id", siteRecovery.UserId)
 	d.Set("user_name", siteRecovery.UserName)
 
-	srmExtensionKey := d.Get("srm_extension_key_suffix").(string)
-	srmNodeMap := map[string]string{}
-	for _, SRMNode := range siteRecovery.SrmNodes {
-		if len(strings.TrimSpace(srmExtensionKey)) == 0 {
-			tempStr := strings.Trim(*SRMNode.Hostname, ".")
-			if strings.Contains(tempStr, "-") {
-				srmNodeMap["id"] = *SRMNode.Id
-				srmNodeMap["ip_address"] = *SRMNode.IpAddress
-				srmNodeMap["host_name"] = *SRMNode.Hostname
-				srmNodeMap["state"] = *SRMNode.State
-				srmNodeMap["type"] = *SRMNode.Type_
-				// During tests VmMorefId might be nil
-				if SRMNode.VmMorefId != nil {
-					srmNodeMap["vm_moref_id"] = *SRMNode.VmMorefId
-				}
-				break
-			}
-		} else if strings.Contains(*SRMNode.Hostname, strings.TrimSpace(srmExtensionKey)) {
-			srmNodeMap["id"] = *SRMNode.Id
-			srmNodeMap["ip_address"] = *SRMNode.IpAddress
-			srmNodeMap["host_name"] = *SRMNode.Hostname
-			srmNodeMap["state"] = *SRMNode.State
-			srmNodeMap["type"] = *SRMNode.Type_
-			// During tests VmMorefId might be nil
-			if SRMNode.VmMorefId != nil {
-				srmNodeMap["vm_moref_id"] = *SRMNode.VmMorefId
-			}
+	// The details of the nodes are only partially known, while site recovery is being activated
+	srmExtensionKey := strings.TrimSpace(d.Get("srm_extension_key_suffix").(string))
+	srmNodeMap := map[string]interface{}{}
+	for _, srmNode := range siteRecovery.SrmNodes {
+		if srmNode.Hostname == nil {
+			continue
+		}
+		if (len(srmExtensionKey) == 0 && strings.Contains(strings.Trim(*srmNode.Hostname, "."), "-")) ||
+			(len(srmExtensionKey) > 0 && strings.Contains(*srmNode.Hostname, srmExtensionKey)) {
+			srmNodeMap = flattenSrmNode(srmNode)
 			break
 		}
 	}
 
+	srmNodes := []map[string]interface{}{}
+	for _, srmNode := range siteRecovery.SrmNodes {
+		srmNodes = append(srmNodes, flattenSrmNode(srmNode))
+	}
+
 	vrNodeMap := map[string]string{}
-	// During tests VmMorefId might be nil
-	if siteRecovery.VrNode.VmMorefId != nil {
-		vrNodeMap["vm_moref_id"] = *siteRecovery.VrNode.VmMorefId
-	}
-	vrNodeMap["id"] = *siteRecovery.VrNode.Id
-	vrNodeMap["hostname"] = *siteRecovery.VrNode.Hostname
-	vrNodeMap["type"] = *siteRecovery.VrNode.Type_
-	vrNodeMap["state"] = *siteRecovery.VrNode.State
-	vrNodeMap["ip_address"] = *siteRecovery.VrNode.IpAddress
-	d.Set("sddc_id", *siteRecovery.SddcId)
+	if vrNode := siteRecovery.VrNode; vrNode != nil {
+		// During tests VmMorefId might be nil
+		if vrNode.VmMorefId != nil {
+			vrNodeMap["vm_moref_id"] = *vrNode.VmMorefId
+		}
+		if vrNode.Id != nil {
+			vrNodeMap["id"] = *vrNode.Id
+		}
+		if vrNode.Hostname != nil {
+			vrNodeMap["hostname"] = *vrNode.Hostname
+		}
+		if vrNode.Type_ != nil {
+			vrNodeMap["type"] = *vrNode.Type_
+		}
+		if vrNode.State != nil {
+			vrNodeMap["state"] = *vrNode.State
+		}
+		if vrNode.IpAddress != nil {
+			vrNodeMap["ip_address"] = *vrNode.IpAddress
+		}
+	}
+	if siteRecovery.SddcId != nil {
+		d.Set("sddc_id", *siteRecovery.SddcId)
+	}
 	d.Set("srm_node", srmNodeMap)
+	d.Set("srm_nodes", srmNodes)
 	d.Set("vr_node", vrNodeMap)
 	return nil
 }
 
-func resourceSiteRecoveryDelete(d *schema.ResourceData, m interface{}) error {
-	connectorWrapper := m.(*connector.Wrapper)
-	siteRecoveryClient := draas.NewSiteRecoveryClient(connectorWrapper)
-
+func resourceSiteRecoveryDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	orgID := (m.(*connector.Wrapper)).OrgID
 	sddcID := d.Get("sddc_id").(string)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	connectorWrapper := draasClient.Wrapper()
+	siteRecoveryClient := draasClient.SiteRecovery()
 
-	siteRecoveryDeleteTask, err := siteRecoveryClient.Delete(orgID, sddcID, nil, nil)
+	// Site recovery may already be in the process of deactivation, started by an interrupted apply
+	deactivationTaskID, err := getExistingSiteRecoveryDeactivation(draasClient, sddcID)
 	if err != nil {
-		return HandleDeleteError("Site recovery", sddcID, err)
+		return toDiagnostics(HandleDeleteError("Site recovery", sddcID, err))
 	}
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+	if deactivationTaskID == "" {
+		siteRecoveryDeleteTask, err := siteRecoveryClient.Delete(orgID, sddcID, nil, nil)
+		if err != nil {
+			return toDiagnostics(HandleDeleteError("Site recovery", sddcID, err))
+		}
+		deactivationTaskID = siteRecoveryDeleteTask.Id
+	}
+	return toDiagnostics(task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		taskErr := task.RetryTaskUntilFinished(connectorWrapper,
 			func() (model.Task, error) {
-				return task.GetDraasTask(connectorWrapper, siteRecoveryDeleteTask.Id)
+				return task.GetDraasTask(connectorWrapper, deactivationTaskID)
 			},
 			"error deactivating site recovery for SDDC ",
 			nil)
@@ -208,23 +386,24 @@ func resourceSiteRecoveryDelete(d *schema.ResourceData, m interface{}) error {
 		}
 		d.SetId("")
 		return nil
-	})
+	}))
 }
 
-func resourceSiteRecoveryUpdate(d *schema.ResourceData, m interface{}) error {
+func resourceSiteRecoveryUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	if d.HasChange("srm_extension_key_suffix") {
-		err := resourceSiteRecoveryDelete(d, m)
-		if err != nil {
-			return HandleDeleteError("Site Recovery", d.Get("sddc_id").(string), err)
+		diags := resourceSiteRecoveryDelete(ctx, d, m)
+		if diags.HasError() {
+			return diags
 		}
 
 		// This wait is required after deactivation before activation
-		time.Sleep(15 * time.Minute)
-
-		err = resourceSiteRecoveryCreate(d, m)
-		if err != nil {
-			return HandleCreateError("Site Recovery", err)
+		select {
+		case <-time.After(15 * time.Minute):
+		case <-ctx.Done():
+			return diag.FromErr(ctx.Err())
 		}
+
+		return resourceSiteRecoveryCreate(ctx, d, m)
 	}
 	return nil
 }
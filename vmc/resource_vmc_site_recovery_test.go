@@ -4,8 +4,13 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
 	"testing"
 
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas"
@@ -126,3 +131,146 @@ func testAccVmcSiteRecoveryResourceImportStateIDFunc(resourceName string) resour
 		return rs.Primary.Attributes["sddc_id"], nil
 	}
 }
+
+func TestResourceVmcSiteRecoverySimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "site_recovery_sddc"})
+	d := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id":                  sddcID,
+		"srm_extension_key_suffix": "simulated",
+	})
+
+	err := diagsErr(resourceSiteRecoveryCreate(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED, d.Get("site_recovery_state"))
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED, server.SiteRecoveryState(sddcID))
+	srmNode := d.Get("srm_node").(map[string]interface{})
+	assert.Contains(t, srmNode["host_name"], "simulated")
+	assert.Equal(t, "https://"+srmNode["host_name"].(string)+":5480", srmNode["ui_url"])
+	assert.Equal(t, "https://"+srmNode["host_name"].(string)+"/api/rest/srm/v1", srmNode["api_url"])
+
+	err = diagsErr(resourceSiteRecoveryDelete(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATED, server.SiteRecoveryState(sddcID))
+}
+
+func TestResourceVmcSiteRecoveryAdoptsActivationInProgressSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "site_recovery_sddc"})
+	// Activation started by another workspace, the suffix is not known until the SRM node is deployed
+	activationTaskID := server.StartSiteRecoveryActivation(sddcID, "first")
+	d := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id":                  sddcID,
+		"srm_extension_key_suffix": "first",
+	})
+	err := diagsErr(resourceSiteRecoveryCreate(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED, d.Get("site_recovery_state"))
+	assert.Contains(t, server.Requests(), "GET /vmc/draas/api/orgs/"+simulator.TestOrgID+"/tasks/"+activationTaskID)
+	assert.NotContains(t, server.Requests(), "POST /vmc/draas/api/orgs/"+simulator.TestOrgID+"/sddcs/"+sddcID+"/site-recovery")
+
+	// An activation with a different SRM extension key suffix is not adopted
+	d = schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id":                  sddcID,
+		"srm_extension_key_suffix": "other",
+	})
+	err = diagsErr(resourceSiteRecoveryCreate(context.Background(), d, connectorWrapper))
+	assert.ErrorContains(t, err, `activated or being activated with srm_extension_key_suffix "first", `+
+		`which differs from the configured "other"`)
+	assert.Equal(t, "", d.Id())
+
+	// An already activated site recovery is adopted as well
+	d = schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id":                  sddcID,
+		"srm_extension_key_suffix": "first",
+	})
+	err = diagsErr(resourceSiteRecoveryCreate(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, sddcID, d.Id())
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED, d.Get("site_recovery_state"))
+}
+
+func TestResourceVmcSiteRecoveryResumesInterruptedDeactivationSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "site_recovery_sddc"})
+	siteRecoveryPath := "/vmc/draas/api/orgs/" + simulator.TestOrgID + "/sddcs/" + sddcID + "/site-recovery"
+	d := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), d, connectorWrapper)))
+
+	// Deactivation started by an apply, that was interrupted
+	server.TaskPollsUntilFinished = 2
+	draasClient := api.NewClient(connectorWrapper)
+	deactivationTask, err := draasClient.SiteRecovery().Delete(simulator.TestOrgID, sddcID, nil, nil)
+	assert.NoError(t, err)
+
+	assert.NoError(t, diagsErr(resourceSiteRecoveryDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATED, server.SiteRecoveryState(sddcID))
+	assert.Contains(t, server.Requests(), "GET /vmc/draas/api/orgs/"+simulator.TestOrgID+"/tasks/"+deactivationTask.Id)
+	deactivations := 0
+	for _, request := range server.Requests() {
+		if request == "DELETE "+siteRecoveryPath {
+			deactivations++
+		}
+	}
+	assert.Equal(t, 1, deactivations)
+}
+
+func TestResourceVmcSiteRecoveryImportSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "site_recovery_sddc"})
+	d := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id":                  sddcID,
+		"srm_extension_key_suffix": "manual",
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), d, connectorWrapper)))
+
+	imported := resourceSiteRecovery().Data(nil)
+	imported.SetId(sddcID)
+	results, err := resourceSiteRecoveryImport(context.Background(), imported, connectorWrapper)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.NoError(t, diagsErr(resourceSiteRecoveryRead(context.Background(), results[0], connectorWrapper)))
+	assert.Equal(t, sddcID, results[0].Get("sddc_id"))
+	assert.Equal(t, "manual", results[0].Get("srm_extension_key_suffix"))
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATED, results[0].Get("site_recovery_state"))
+	assert.Contains(t, results[0].Get("srm_node.host_name"), "manual")
+	assert.Len(t, results[0].Get("srm_nodes"), server.SrmNodeCount(sddcID))
+	assert.NotEmpty(t, results[0].Get("vr_node.id"))
+}
+
+func TestResourceVmcSiteRecoveryImportActivatingSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "site_recovery_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+
+	imported := resourceSiteRecovery().Data(nil)
+	imported.SetId(sddcID)
+	results, err := resourceSiteRecoveryImport(context.Background(), imported, connectorWrapper)
+	assert.NoError(t, err)
+	assert.Len(t, results, 1)
+	assert.NoError(t, diagsErr(resourceSiteRecoveryRead(context.Background(), results[0], connectorWrapper)))
+	assert.Equal(t, model.SiteRecovery_SITE_RECOVERY_STATE_ACTIVATING, results[0].Get("site_recovery_state"))
+	assert.Empty(t, results[0].Get("srm_node"))
+	assert.Len(t, results[0].Get("srm_nodes"), 1)
+	assert.Empty(t, results[0].Get("vr_node"))
+}
+
+func TestResourceVmcSiteRecoveryImportNotActivatedSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "site_recovery_sddc"})
+
+	imported := resourceSiteRecovery().Data(nil)
+	imported.SetId(sddcID)
+	_, err := resourceSiteRecoveryImport(context.Background(), imported, connectorWrapper)
+	assert.ErrorContains(t, err, "site recovery has never been activated")
+
+	imported.SetId("not-a-uuid")
+	_, err = resourceSiteRecoveryImport(context.Background(), imported, connectorWrapper)
+	assert.ErrorContains(t, err, "invalid format for sddc_id")
+}
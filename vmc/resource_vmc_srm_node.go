@@ -8,29 +8,28 @@ import (
 	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
 	task "github.com/vmware/terraform-provider-vmc/vmc/task"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+	"log"
 	"strings"
 	"time"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
 
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas"
 	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
 )
 
-// srmNodeCreationLockMutex a mutex that allows only a single srm node per sddc to be created.
-var srmNodeCreationLockMutex = task.KeyedMutex{}
-
 func resourceSrmNode() *schema.Resource {
 	return &schema.Resource{
-		Create: resourceSrmNodeCreate,
-		Read:   resourceSrmNodeRead,
-		Delete: resourceSrmNodeDelete,
+		CreateContext: resourceSrmNodeCreate,
+		ReadContext:   resourceSrmNodeRead,
+		DeleteContext: resourceSrmNodeDelete,
 		Importer: &schema.ResourceImporter{
-			State: func(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+			StateContext: func(_ context.Context, d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
 				idParts := strings.Split(d.Id(), ",")
 				if len(idParts) != 2 || idParts[0] == "" || idParts[1] == "" {
 					return nil, fmt.Errorf("unexpected format of ID (%q), expected id,sddc_id", d.Id())
@@ -51,6 +50,16 @@ func resourceSrmNode() *schema.Resource {
 			Create: schema.DefaultTimeout(30 * time.Minute),
 			Delete: schema.DefaultTimeout(20 * time.Minute),
 		},
+		CustomizeDiff: customizeSrmNodeDiff,
+		// See resourceSddc for changes requiring a new SchemaVersion
+		SchemaVersion: 1,
+		StateUpgraders: []schema.StateUpgrader{
+			{
+				Version: 0,
+				Type:    resourceSrmNodeV0().CoreConfigSchema().ImpliedType(),
+				Upgrade: upgradeSrmNodeStateV0,
+			},
+		},
 		Schema: map[string]*schema.Schema{
 			"sddc_id": {
 				Type:        schema.TypeString,
@@ -65,117 +74,409 @@ func resourceSrmNode() *schema.Resource {
 				ValidateFunc: validation.StringLenBetween(1, 13),
 				Description:  "The custom extension suffix for SRM must contain 13 characters or less, be composed of letters, numbers, ., - characters only. The suffix is appended to com.vmware.vcDr- to form the full extension key. ",
 			},
+			"srm_instance": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The SRM node.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ip_address": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"hostname": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"state": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"type": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"vm_moref_id": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"version": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"ui_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+						"api_url": {
+							Type:     schema.TypeString,
+							Computed: true,
+						},
+					},
+				},
+			},
+			"ui_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the management UI of the SRM appliance.",
+			},
+			"provisioning_phase": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The phase of the deployment of the SRM node, while it is being deployed. Empty once the node is ready.",
+			},
+			"api_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Base URL of the REST API of the SRM appliance.",
+			},
+		},
+	}
+}
+
+// resourceSrmNodeV0 the schema of vmc_srm_node before srm_instance became a typed block.
+func resourceSrmNodeV0() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"sddc_id": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"srm_node_extension_key_suffix": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
 			"srm_instance": {
 				Type:     schema.TypeMap,
 				Computed: true,
 			},
+			"ui_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
+			"api_url": {
+				Type:     schema.TypeString,
+				Computed: true,
+			},
 		},
 	}
 }
 
-func resourceSrmNodeCreate(d *schema.ResourceData, m interface{}) error {
-	err := (m.(*connector.Wrapper)).Authenticate()
+// upgradeSrmNodeStateV0 converts the srm_instance map of the state into a single element list,
+// renaming its host_name key to hostname.
+func upgradeSrmNodeStateV0(_ context.Context, rawState map[string]interface{}, _ interface{}) (map[string]interface{}, error) {
+	srmInstance, ok := rawState["srm_instance"].(map[string]interface{})
+	if !ok || len(srmInstance) == 0 {
+		rawState["srm_instance"] = []interface{}{}
+		return rawState, nil
+	}
+	if hostname, ok := srmInstance["host_name"]; ok {
+		srmInstance["hostname"] = hostname
+		delete(srmInstance, "host_name")
+	}
+	rawState["srm_instance"] = []interface{}{srmInstance}
+	return rawState, nil
+}
+
+// customizeSrmNodeDiff fails the plan of a new SRM node, if the SDDC already has the maximum
+// amount of additional SRM nodes allowed by the service. This is a best-effort check against the
+// SRM nodes existing at plan time: SRM nodes planned alongside it are not counted, so exceeding
+// the limit within a single plan still fails at apply time.
+func customizeSrmNodeDiff(_ context.Context, d *schema.ResourceDiff, m interface{}) error {
+	if len(d.Id()) > 0 || !d.NewValueKnown("sddc_id") || m == nil {
+		return nil
+	}
+	sddcID := d.Get("sddc_id").(string)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	siteRecoveryClient := draasClient.SiteRecovery()
+	siteRecovery, err := siteRecoveryClient.Get(draasClient.OrgID(), sddcID)
 	if err != nil {
-		return fmt.Errorf("authentication error from Cloud Service Provider: %s", err)
+		// Site recovery may not be activated yet, in which case there are no SRM nodes
+		log.Printf("[DEBUG] Skipping SRM node count check for SDDC %s: %v", sddcID, err)
+		return nil
+	}
+	additionalSrmNodes := countAdditionalSrmNodes(siteRecovery.SrmNodes)
+	if additionalSrmNodes >= constants.MaxAdditionalSrmNodes {
+		return fmt.Errorf("SDDC %s already has %d additional SRM nodes, which is the maximum allowed by the service (%d)",
+			sddcID, additionalSrmNodes, constants.MaxAdditionalSrmNodes)
 	}
-	connectorWrapper := m.(*connector.Wrapper)
+	return nil
+}
 
-	siteRecoverySrmNodesClient := draas.NewSiteRecoverySrmNodesClient(connectorWrapper)
+// countAdditionalSrmNodes counts the SRM nodes, that are not being removed, besides the
+// one deployed upon site recovery activation.
+func countAdditionalSrmNodes(srmNodes []draasmodel.SrmNode) int {
+	count := 0
+	for _, srmNode := range srmNodes {
+		if srmNode.Type_ != nil && *srmNode.Type_ != draasmodel.SrmNode_TYPE_SRM {
+			continue
+		}
+		if srmNode.State != nil && (*srmNode.State == draasmodel.SrmNode_STATE_DELETING ||
+			*srmNode.State == draasmodel.SrmNode_STATE_CANCELED) {
+			continue
+		}
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return count - 1
+}
 
+func resourceSrmNodeCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	srmExtensionKeySuffix := d.Get("srm_node_extension_key_suffix").(string)
 	orgID := (m.(*connector.Wrapper)).OrgID
 	sddcID := d.Get("sddc_id").(string)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	connectorWrapper := draasClient.Wrapper()
+
+	siteRecoverySrmNodesClient := draasClient.SiteRecoverySrmNodes()
 
-	unlockFn := srmNodeCreationLockMutex.Lock(sddcID)
 	provisionSrmConfigParam := &draasmodel.ProvisionSrmConfig{
 		SrmExtensionKeySuffix: &srmExtensionKeySuffix,
 	}
 
-	srmNodeCreateTask, err := siteRecoverySrmNodesClient.Post(orgID, sddcID, provisionSrmConfigParam)
-
+	// The SRM node may already be in the process of being provisioned by an interrupted apply, in
+	// which case waiting for its provisioning is resumed instead of provisioning another node
+	srmNodeCreateTask, err := findSrmNodeCreateTask(draasClient, sddcID, srmExtensionKeySuffix)
 	if err != nil {
-		return HandleCreateError("SRM Node", err)
+		return toDiagnostics(HandleCreateError("SRM Node", err))
+	}
+	if srmNodeCreateTask == nil {
+		// Conflicting DRaaS tasks of the SDDC are waited out, so SRM nodes can be created in parallel
+		draasTask, err := submitSrmNodeOperation(ctx, draasClient, sddcID, d.Timeout(schema.TimeoutCreate),
+			func() (draasmodel.Task, error) {
+				return siteRecoverySrmNodesClient.Post(orgID, sddcID, provisionSrmConfigParam)
+			})
+		if err != nil {
+			return toDiagnostics(HandleCreateError("SRM Node", err))
+		}
+		srmNodeCreateTask = &model.Task{Id: draasTask.Id, ResourceId: draasTask.ResourceId}
 	}
 
 	d.SetId(*srmNodeCreateTask.ResourceId)
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
-		taskErr := task.RetryTaskUntilFinished(connectorWrapper,
+	err = task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		return task.RetryTaskUntilFinished(connectorWrapper,
 			func() (model.Task, error) {
 				return task.GetDraasTask(connectorWrapper, srmNodeCreateTask.Id)
 			},
 			"error creating SRM node",
-			func(task model.Task) {
-				unlockFn()
-			})
-		if taskErr != nil {
-			return taskErr
+			nil)
+	})
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	return resourceSrmNodeRead(ctx, d, m)
+}
+
+// findSrmNodeCreateTask looks up the provisioning task of an SRM node of the specified SDDC, that
+// is being deployed with the specified extension key suffix. Returns nil if there is no such node.
+func findSrmNodeCreateTask(draasClient *api.Client, sddcID string, srmExtensionKeySuffix string) (*model.Task, error) {
+	siteRecovery, err := draasClient.SiteRecovery().Get(draasClient.OrgID(), sddcID)
+	if err != nil {
+		// Let the provisioning request report the error
+		return nil, nil
+	}
+	for _, srmNode := range siteRecovery.SrmNodes {
+		if srmNode.Id == nil || srmNode.Hostname == nil || srmNode.State == nil ||
+			*srmNode.State != draasmodel.SiteRecoveryNode_STATE_DEPLOYING {
+			continue
 		}
-		err = resourceSrmNodeRead(d, m)
-		if err == nil {
-			return nil
+		hostName := strings.TrimPrefix(*srmNode.Hostname, constants.SrmPrefix)
+		if strings.Split(hostName, constants.SddcSuffix)[0] != srmExtensionKeySuffix {
+			continue
 		}
-		return resource.NonRetryableError(err)
-	})
+		srmNodeCreateTask, err := task.GetInProgressDraasTask(draasClient.Wrapper(), *srmNode.Id)
+		if err != nil {
+			return nil, err
+		}
+		if srmNodeCreateTask != nil {
+			log.Printf("[INFO] SRM node %s of SDDC %s is already being provisioned, resuming task %s",
+				*srmNode.Id, sddcID, srmNodeCreateTask.Id)
+			return srmNodeCreateTask, nil
+		}
+	}
+	return nil, nil
 }
 
-func resourceSrmNodeRead(d *schema.ResourceData, m interface{}) error {
-	connectorWrapper := (m.(*connector.Wrapper)).Connector
+func resourceSrmNodeRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	orgID := (m.(*connector.Wrapper)).OrgID
 	sddcID := d.Get("sddc_id").(string)
 	srmNodeID := d.Id()
-	siteRecoveryClient := draas.NewSiteRecoveryClient(connectorWrapper)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	siteRecoveryClient := draasClient.SiteRecovery()
 	siteRecovery, err := siteRecoveryClient.Get(orgID, sddcID)
 	if err != nil {
-		return HandleReadError(d, "SRM Node", sddcID, err)
+		return toDiagnostics(HandleReadError(d, "SRM Node", sddcID, err))
 	}
-	srmNodeMap := map[string]string{}
-	d.Set("sddc_id", *siteRecovery.SddcId)
-	for _, SRMNode := range siteRecovery.SrmNodes {
-		if *SRMNode.Id == srmNodeID {
-			srmNodeMap["id"] = *SRMNode.Id
-			srmNodeMap["ip_address"] = *SRMNode.IpAddress
-			srmNodeMap["host_name"] = *SRMNode.Hostname
-			srmNodeMap["state"] = *SRMNode.State
-			srmNodeMap["type"] = *SRMNode.Type_
-			// During tests VmMorefId might be nil
-			if SRMNode.VmMorefId != nil {
-				srmNodeMap["vm_moref_id"] = *SRMNode.VmMorefId
-			}
-			hostName := strings.TrimPrefix(*SRMNode.Hostname, constants.SrmPrefix)
-			partStr := strings.Split(hostName, constants.SddcSuffix)
-			d.Set("srm_node_extension_key_suffix", partStr[0])
+	var srmNode *draasmodel.SrmNode
+	for i := range siteRecovery.SrmNodes {
+		if siteRecovery.SrmNodes[i].Id != nil && *siteRecovery.SrmNodes[i].Id == srmNodeID {
+			srmNode = &siteRecovery.SrmNodes[i]
 			break
 		}
 	}
-	d.Set("srm_instance", srmNodeMap)
+	if srmNode == nil {
+		// The node was removed outside Terraform, e.g. from the VMC console, or together with site recovery
+		log.Printf("[WARN] SRM node %s no longer exists on SDDC %s, removing it from the state", srmNodeID, sddcID)
+		d.SetId("")
+		return nil
+	}
+	d.Set("sddc_id", *siteRecovery.SddcId)
+	// srm_instance follows the format of the vmc_srm_nodes data source, except for the name of the hostname
+	srmNodeMap := flattenSrmNode(*srmNode)
+	if hostname, ok := srmNodeMap["host_name"]; ok {
+		srmNodeMap["hostname"] = hostname
+		delete(srmNodeMap, "host_name")
+	}
+	srmNodeMap["version"] = lookupSrmNodeVersion(draasClient, sddcID, srmNodeID)
+	if srmNode.Hostname != nil {
+		d.Set("ui_url", srmNodeMap["ui_url"])
+		d.Set("api_url", srmNodeMap["api_url"])
+		hostName := strings.TrimPrefix(*srmNode.Hostname, constants.SrmPrefix)
+		partStr := strings.Split(hostName, constants.SddcSuffix)
+		d.Set("srm_node_extension_key_suffix", partStr[0])
+	}
+	d.Set("srm_instance", []interface{}{srmNodeMap})
+	provisioningPhase := ""
+	if srmNode.State != nil && *srmNode.State == draasmodel.SrmNode_STATE_DEPLOYING {
+		provisioningPhase = lookupSrmNodeProvisioningPhase(draasClient, srmNodeID)
+	}
+	d.Set("provisioning_phase", provisioningPhase)
 	return nil
 }
 
-func resourceSrmNodeDelete(d *schema.ResourceData, m interface{}) error {
-	connectorWrapper := m.(*connector.Wrapper)
-	siteRecoverySrmNodesClient := draas.NewSiteRecoverySrmNodesClient(connectorWrapper)
+// lookupSrmNodeProvisioningPhase returns the sub-status of the deployment task of the SRM node, or an
+// empty string if it is not known.
+func lookupSrmNodeProvisioningPhase(draasClient *api.Client, srmNodeID string) string {
+	srmNodeCreateTask, err := task.GetInProgressDraasTask(draasClient.Wrapper(), srmNodeID)
+	if err != nil {
+		log.Printf("[WARN] Unable to look up the deployment task of SRM node %s: %v", srmNodeID, err)
+		return ""
+	}
+	if srmNodeCreateTask == nil {
+		return ""
+	}
+	return task.ProvisioningPhase(*srmNodeCreateTask)
+}
+
+// lookupSrmNodeVersion returns the version of the SRM node, or an empty string if it is not known,
+// e.g. while the node is being deployed.
+func lookupSrmNodeVersion(draasClient *api.Client, sddcID string, srmNodeID string) string {
+	versions, err := draasClient.SiteRecoveryVersions().Get(draasClient.OrgID(), sddcID, nil)
+	if err != nil {
+		log.Printf("[WARN] Unable to look up the version of SRM node %s: %v", srmNodeID, err)
+		return ""
+	}
+	for _, nodeVersion := range versions.NodeVersions {
+		if nodeVersion.NodeId == nil || *nodeVersion.NodeId != srmNodeID {
+			continue
+		}
+		if nodeVersion.FullVersion != nil {
+			return *nodeVersion.FullVersion
+		}
+		if nodeVersion.BuildVersion != nil && nodeVersion.BuildVersion.Version != nil {
+			return *nodeVersion.BuildVersion.Version
+		}
+	}
+	return ""
+}
 
+func resourceSrmNodeDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
 	orgID := (m.(*connector.Wrapper)).OrgID
 	sddcID := d.Get("sddc_id").(string)
-	unlockFn := srmNodeCreationLockMutex.Lock(sddcID)
+	draasClient := api.NewClient(m.(*connector.Wrapper))
+	connectorWrapper := draasClient.Wrapper()
+	siteRecoverySrmNodesClient := draasClient.SiteRecoverySrmNodes()
 	srmNodeID := d.Id()
-	srmNodeDeleteTask, err := siteRecoverySrmNodesClient.Delete(orgID, sddcID, srmNodeID)
+	srmNodeDeleteTask, err := submitSrmNodeOperation(ctx, draasClient, sddcID, d.Timeout(schema.TimeoutDelete),
+		func() (draasmodel.Task, error) {
+			return siteRecoverySrmNodesClient.Delete(orgID, sddcID, srmNodeID)
+		})
 	if err != nil {
-		return HandleDeleteError("SRM Node", sddcID, err)
+		return toDiagnostics(HandleDeleteError("SRM Node", sddcID, err))
 	}
-	return resource.RetryContext(context.Background(), d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
+	return toDiagnostics(task.RetryContext(ctx, connectorWrapper.TaskPoll, d.Timeout(schema.TimeoutDelete), func() *resource.RetryError {
 		taskErr := task.RetryTaskUntilFinished(connectorWrapper,
 			func() (model.Task, error) {
 				return task.GetDraasTask(connectorWrapper, srmNodeDeleteTask.Id)
 			},
 			"failed to delete SRM node",
-			func(task model.Task) {
-				unlockFn()
-			})
+			nil)
 		if taskErr != nil {
 			return taskErr
 		}
 		d.SetId("")
 		return nil
+	}))
+}
+
+// submitSrmNodeOperation submits an SRM node operation to DRaaS. DRaaS rejects node operations
+// while another task is in progress on the site recovery of the SDDC, e.g. while another SRM node
+// is being provisioned, also when the conflicting task was started by another Terraform workspace.
+// In that case the conflicting tasks are polled until they finish and the operation is submitted
+// again, so that all SRM nodes declared in a configuration are provisioned back-to-back.
+func submitSrmNodeOperation(ctx context.Context, draasClient *api.Client, sddcID string, timeout time.Duration,
+	operation func() (draasmodel.Task, error)) (draasmodel.Task, error) {
+	var submittedTask draasmodel.Task
+	var conflictingTaskIDs []string
+	err := task.RetryContext(ctx, draasClient.Wrapper().TaskPoll, timeout, func() *resource.RetryError {
+		if len(conflictingTaskIDs) > 0 {
+			var inProgressTaskIDs []string
+			for _, conflictingTaskID := range conflictingTaskIDs {
+				conflictingTask, err := task.GetDraasTask(draasClient.Wrapper(), conflictingTaskID)
+				if err != nil {
+					return resource.NonRetryableError(err)
+				}
+				if conflictingTask.Status != nil && *conflictingTask.Status == draasmodel.Task_STATUS_STARTED {
+					inProgressTaskIDs = append(inProgressTaskIDs, conflictingTaskID)
+				}
+			}
+			conflictingTaskIDs = inProgressTaskIDs
+			if len(conflictingTaskIDs) > 0 {
+				return resource.RetryableError(fmt.Errorf("waiting for DRaaS tasks %v of SDDC %s to finish", conflictingTaskIDs, sddcID))
+			}
+		}
+		draasTask, err := operation()
+		if err == nil {
+			submittedTask = draasTask
+			return nil
+		}
+		if !isTaskInProgressError(err) {
+			return resource.NonRetryableError(err)
+		}
+		conflictingTasks, listErr := getConflictingSrmNodeTasks(draasClient, sddcID)
+		if listErr != nil {
+			return resource.NonRetryableError(listErr)
+		}
+		for _, conflictingTask := range conflictingTasks {
+			conflictingTaskIDs = append(conflictingTaskIDs, conflictingTask.Id)
+		}
+		log.Printf("[INFO] Another DRaaS task is in progress on SDDC %s, waiting for tasks %v to finish", sddcID, conflictingTaskIDs)
+		return resource.RetryableError(err)
 	})
+	return submittedTask, err
+}
+
+// getConflictingSrmNodeTasks looks up the in progress DRaaS tasks, acting on the site recovery or
+// on any of the nodes of the SDDC.
+func getConflictingSrmNodeTasks(draasClient *api.Client, sddcID string) ([]model.Task, error) {
+	resourceIDs := []string{sddcID}
+	siteRecovery, err := draasClient.SiteRecovery().Get(draasClient.OrgID(), sddcID)
+	if err != nil {
+		return nil, err
+	}
+	for _, srmNode := range siteRecovery.SrmNodes {
+		if srmNode.Id != nil {
+			resourceIDs = append(resourceIDs, *srmNode.Id)
+		}
+	}
+	if siteRecovery.VrNode != nil && siteRecovery.VrNode.Id != nil {
+		resourceIDs = append(resourceIDs, *siteRecovery.VrNode.Id)
+	}
+	return task.GetInProgressDraasTasks(draasClient.Wrapper(), resourceIDs)
 }
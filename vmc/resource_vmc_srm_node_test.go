@@ -4,9 +4,17 @@
 package vmc
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
+	"net/http"
+	"sync"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
@@ -193,3 +201,313 @@ func testAccVmcSrmResourceImportStateIDFunc(resourceName string) resource.Import
 		return fmt.Sprintf("%s,%s", rs.Primary.ID, rs.Primary.Attributes["sddc_id"]), nil
 	}
 }
+
+func TestResourceVmcSrmNodeSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+
+	d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+		"sddc_id":                       sddcID,
+		"srm_node_extension_key_suffix": "second",
+	})
+	err := diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.NotEmpty(t, d.Id())
+	assert.Equal(t, 2, server.SrmNodeCount(sddcID))
+	assert.Equal(t, 1, d.Get("srm_instance.#"))
+	assert.Equal(t, d.Id(), d.Get("srm_instance.0.id"))
+	assert.Equal(t, model.SiteRecoveryNode_STATE_READY, d.Get("srm_instance.0.state"))
+	assert.Equal(t, model.SiteRecoveryNode_TYPE_SRM, d.Get("srm_instance.0.type"))
+	assert.Equal(t, "10.2.224.10", d.Get("srm_instance.0.ip_address"))
+	assert.Equal(t, simulator.SrmVersion, d.Get("srm_instance.0.version"))
+	assert.Equal(t, "second", d.Get("srm_node_extension_key_suffix"))
+	hostname := d.Get("srm_instance.0.hostname").(string)
+	assert.Equal(t, "https://"+hostname+":5480", d.Get("ui_url"))
+	assert.Equal(t, "https://"+hostname+"/api/rest/srm/v1", d.Get("api_url"))
+	assert.Equal(t, d.Get("ui_url"), d.Get("srm_instance.0.ui_url"))
+	assert.Equal(t, d.Get("api_url"), d.Get("srm_instance.0.api_url"))
+
+	err = diagsErr(resourceSrmNodeDelete(context.Background(), d, connectorWrapper))
+	assert.NoError(t, err)
+	assert.Equal(t, "", d.Id())
+	assert.Equal(t, 1, server.SrmNodeCount(sddcID))
+}
+
+func TestResourceVmcSrmNodeDeletedOutsideTerraformSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+	rawConfig := map[string]interface{}{
+		"sddc_id":                       sddcID,
+		"srm_node_extension_key_suffix": "second",
+	}
+	d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, rawConfig)
+	assert.NoError(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+	srmNodeID := d.Id()
+
+	// The node is removed from the console
+	console := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, rawConfig)
+	console.SetId(srmNodeID)
+	assert.NoError(t, diagsErr(resourceSrmNodeDelete(context.Background(), console, connectorWrapper)))
+
+	assert.NoError(t, diagsErr(resourceSrmNodeRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResourceVmcSrmNodeStateUpgradeV0(t *testing.T) {
+	rawState := map[string]interface{}{
+		"id":      "srm-node-id",
+		"sddc_id": "sddc-id",
+		"srm_instance": map[string]interface{}{
+			"id":        "srm-node-id",
+			"host_name": "srm-second.sddc-10-2-0-1.vmc.local",
+			"state":     "READY",
+		},
+	}
+	upgraded, err := upgradeSrmNodeStateV0(context.Background(), rawState, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{map[string]interface{}{
+		"id":       "srm-node-id",
+		"hostname": "srm-second.sddc-10-2-0-1.vmc.local",
+		"state":    "READY",
+	}}, upgraded["srm_instance"])
+
+	upgraded, err = upgradeSrmNodeStateV0(context.Background(), map[string]interface{}{"id": "srm-node-id"}, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, []interface{}{}, upgraded["srm_instance"])
+}
+
+func TestResourceVmcSrmNodeFailuresSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+	srmNodesPath := "/vmc/draas/api/orgs/" + simulator.TestOrgID + "/sddcs/" + sddcID + "/site-recovery/srm-nodes"
+	d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+		"sddc_id":                       sddcID,
+		"srm_node_extension_key_suffix": "second",
+	})
+
+	// Create request rejected
+	server.InjectError(http.MethodPost, srmNodesPath, http.StatusInternalServerError)
+	assert.Error(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+	server.ClearErrors()
+
+	// Create task failed
+	server.TaskFailureMessage = "simulated task failure"
+	assert.Error(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+	server.TaskFailureMessage = ""
+
+	assert.NoError(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+
+	// Delete request rejected
+	server.InjectError(http.MethodDelete, srmNodesPath+"/"+d.Id(), http.StatusInternalServerError)
+	assert.Error(t, diagsErr(resourceSrmNodeDelete(context.Background(), d, connectorWrapper)))
+	server.ClearErrors()
+
+	assert.NoError(t, diagsErr(resourceSrmNodeDelete(context.Background(), d, connectorWrapper)))
+}
+
+func TestResourceVmcSrmNodeWaitsForConflictingTaskSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+
+	// SRM node provisioning started by another workspace
+	draasClient := api.NewClient(connectorWrapper)
+	suffix := "other"
+	conflictingTask, err := draasClient.SiteRecoverySrmNodes().Post(simulator.TestOrgID, sddcID,
+		&model.ProvisionSrmConfig{SrmExtensionKeySuffix: &suffix})
+	assert.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+		"sddc_id":                       sddcID,
+		"srm_node_extension_key_suffix": "second",
+	})
+	assert.NoError(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "second", d.Get("srm_node_extension_key_suffix"))
+	assert.Equal(t, 3, server.SrmNodeCount(sddcID))
+	assert.Contains(t, server.Requests(), "GET /vmc/draas/api/orgs/"+simulator.TestOrgID+"/tasks/"+conflictingTask.Id)
+}
+
+func TestResourceVmcSrmNodeResumesInterruptedCreateSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+
+	// SRM node provisioning started by an apply, that was interrupted
+	server.TaskPollsUntilFinished = 2
+	draasClient := api.NewClient(connectorWrapper)
+	suffix := "second"
+	createTask, err := draasClient.SiteRecoverySrmNodes().Post(simulator.TestOrgID, sddcID,
+		&model.ProvisionSrmConfig{SrmExtensionKeySuffix: &suffix})
+	assert.NoError(t, err)
+
+	d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+		"sddc_id":                       sddcID,
+		"srm_node_extension_key_suffix": suffix,
+	})
+	assert.NoError(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, *createTask.ResourceId, d.Id())
+	assert.Equal(t, 2, server.SrmNodeCount(sddcID))
+	assert.Contains(t, server.Requests(), "GET /vmc/draas/api/orgs/"+simulator.TestOrgID+"/tasks/"+createTask.Id)
+}
+
+func TestResourceVmcSrmNodeConcurrentCreatesSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+
+	suffixes := []string{"node1", "node2", "node3"}
+	errs := make([]error, len(suffixes))
+	var waitGroup sync.WaitGroup
+	for i, suffix := range suffixes {
+		d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+			"sddc_id":                       sddcID,
+			"srm_node_extension_key_suffix": suffix,
+		})
+		waitGroup.Add(1)
+		go func(i int, d *schema.ResourceData) {
+			defer waitGroup.Done()
+			errs[i] = diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper))
+		}(i, d)
+	}
+	waitGroup.Wait()
+	for _, err := range errs {
+		assert.NoError(t, err)
+	}
+	assert.Equal(t, 1+len(suffixes), server.SrmNodeCount(sddcID))
+}
+
+func TestResourceVmcSrmNodeCountLimitSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	newSrmNodeConfig := func(suffix string) *terraform.ResourceConfig {
+		return terraform.NewResourceConfigRaw(map[string]interface{}{
+			"sddc_id":                       sddcID,
+			"srm_node_extension_key_suffix": suffix,
+		})
+	}
+
+	// Site recovery not activated yet
+	_, err := resourceSrmNode().Diff(context.Background(), nil, newSrmNodeConfig("first"), connectorWrapper)
+	assert.NoError(t, err)
+
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+	for i := 0; i < constants.MaxAdditionalSrmNodes; i++ {
+		suffix := fmt.Sprintf("node%d", i)
+		_, err = resourceSrmNode().Diff(context.Background(), nil, newSrmNodeConfig(suffix), connectorWrapper)
+		assert.NoError(t, err)
+		d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+			"sddc_id":                       sddcID,
+			"srm_node_extension_key_suffix": suffix,
+		})
+		assert.NoError(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+	}
+	assert.Equal(t, constants.MaxAdditionalSrmNodes+1, server.SrmNodeCount(sddcID))
+
+	_, err = resourceSrmNode().Diff(context.Background(), nil, newSrmNodeConfig("extra"), connectorWrapper)
+	assert.ErrorContains(t, err, fmt.Sprintf("maximum allowed by the service (%d)", constants.MaxAdditionalSrmNodes))
+
+	// Existing SRM nodes are not affected by the limit
+	state := &terraform.InstanceState{
+		ID: "existing",
+		Attributes: map[string]string{
+			"sddc_id":                       sddcID,
+			"srm_node_extension_key_suffix": "node0",
+		},
+	}
+	_, err = resourceSrmNode().Diff(context.Background(), state, newSrmNodeConfig("node0"), connectorWrapper)
+	assert.NoError(t, err)
+}
+
+func TestResourceVmcSrmNodeCountLimitSamePlanSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	sddcID := server.AddSddc(simulator.SddcConfig{Name: "srm_node_sddc"})
+	server.StartSiteRecoveryActivation(sddcID, "")
+	siteRecoveryData := schema.TestResourceDataRaw(t, resourceSiteRecovery().Schema, map[string]interface{}{
+		"sddc_id": sddcID,
+	})
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+	for i := 0; i < constants.MaxAdditionalSrmNodes-1; i++ {
+		d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+			"sddc_id":                       sddcID,
+			"srm_node_extension_key_suffix": fmt.Sprintf("node%d", i),
+		})
+		assert.NoError(t, diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper)))
+	}
+
+	// Both SRM nodes of the same plan are checked against the existing SRM nodes only
+	suffixes := []string{"last", "extra"}
+	for _, suffix := range suffixes {
+		_, err := resourceSrmNode().Diff(context.Background(), nil, terraform.NewResourceConfigRaw(map[string]interface{}{
+			"sddc_id":                       sddcID,
+			"srm_node_extension_key_suffix": suffix,
+		}), connectorWrapper)
+		assert.NoError(t, err)
+	}
+
+	// The SRM node beyond the limit fails at apply time
+	for i, suffix := range suffixes {
+		d := schema.TestResourceDataRaw(t, resourceSrmNode().Schema, map[string]interface{}{
+			"sddc_id":                       sddcID,
+			"srm_node_extension_key_suffix": suffix,
+		})
+		err := diagsErr(resourceSrmNodeCreate(context.Background(), d, connectorWrapper))
+		if i == 0 {
+			assert.NoError(t, err)
+		} else {
+			assert.ErrorContains(t, err, "Failed to create SRM Node")
+		}
+	}
+	assert.Equal(t, constants.MaxAdditionalSrmNodes+1, server.SrmNodeCount(sddcID))
+}
+
+func TestCountAdditionalSrmNodes(t *testing.T) {
+	srmNode := func(state string, nodeType string) model.SrmNode {
+		return model.SrmNode{State: &state, Type_: &nodeType}
+	}
+	tests := []struct {
+		srmNodes []model.SrmNode
+		expected int
+	}{
+		{srmNodes: nil, expected: 0},
+		{srmNodes: []model.SrmNode{srmNode(model.SrmNode_STATE_READY, model.SrmNode_TYPE_SRM)}, expected: 0},
+		{srmNodes: []model.SrmNode{
+			srmNode(model.SrmNode_STATE_READY, model.SrmNode_TYPE_SRM),
+			srmNode(model.SrmNode_STATE_DEPLOYING, model.SrmNode_TYPE_SRM),
+			srmNode(model.SrmNode_STATE_DELETING, model.SrmNode_TYPE_SRM),
+			srmNode(model.SrmNode_STATE_READY, model.SrmNode_TYPE_VRMS),
+		}, expected: 1},
+	}
+	for _, testCase := range tests {
+		assert.Equal(t, testCase.expected, countAdditionalSrmNodes(testCase.srmNodes))
+	}
+}
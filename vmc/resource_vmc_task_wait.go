@@ -0,0 +1,82 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/task"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func resourceTaskWait() *schema.Resource {
+	taskWaitSchema := taskSchema(true)
+	taskWaitSchema["fail_on_error"] = &schema.Schema{
+		Type:        schema.TypeBool,
+		Optional:    true,
+		ForceNew:    true,
+		Default:     true,
+		Description: "Fail the apply, if the task fails or is canceled. Default: true.",
+	}
+	return &schema.Resource{
+		CreateContext: resourceTaskWaitCreate,
+		ReadContext:   resourceTaskWaitRead,
+		DeleteContext: resourceTaskWaitDelete,
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(120 * time.Minute),
+		},
+		Schema: taskWaitSchema,
+	}
+}
+
+func resourceTaskWaitCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	taskID := d.Get("task_id").(string)
+	var serviceTask model.Task
+	err := task.RetryContext(ctx, m.(*connector.Wrapper).TaskPoll, d.Timeout(schema.TimeoutCreate), func() *resource.RetryError {
+		var err error
+		serviceTask, err = getServiceTask(d, m)
+		if err != nil {
+			return resource.NonRetryableError(HandleCreateError("Task wait", err))
+		}
+		if !isTerminalTaskStatus(serviceTask.Status) {
+			return resource.RetryableError(fmt.Errorf("expected task %s to reach a terminal state", taskID))
+		}
+		return nil
+	})
+	if err != nil {
+		return toDiagnostics(err)
+	}
+	if *serviceTask.Status != model.Task_STATUS_FINISHED && d.Get("fail_on_error").(bool) {
+		errorMessage := ""
+		if serviceTask.ErrorMessage != nil {
+			errorMessage = *serviceTask.ErrorMessage
+		}
+		return toDiagnostics(newAttributeError("task_id", "task %s finished with status %s: %s", taskID, *serviceTask.Status, errorMessage))
+	}
+	d.SetId(taskID)
+	setTaskAttributes(d, serviceTask)
+	return nil
+}
+
+func resourceTaskWaitRead(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	taskID := d.Id()
+	serviceTask, err := getServiceTask(d, m)
+	if err != nil {
+		return toDiagnostics(HandleReadError(d, "Task", taskID, err))
+	}
+	setTaskAttributes(d, serviceTask)
+	return nil
+}
+
+func resourceTaskWaitDelete(_ context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+	// Waiting on a task has no side effects to undo
+	d.SetId("")
+	return nil
+}
@@ -0,0 +1,65 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+func TestResourceVmcTaskWaitSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.TaskPollsUntilFinished = 3
+	taskID := server.StartTask("SDDC-MAINTENANCE", "sddcId")
+
+	d := schema.TestResourceDataRaw(t, resourceTaskWait().Schema, map[string]interface{}{
+		"task_id": taskID,
+	})
+	assert.NoError(t, diagsErr(resourceTaskWaitCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, taskID, d.Id())
+	assert.Equal(t, model.Task_STATUS_FINISHED, d.Get("status"))
+	assert.Equal(t, "SDDC-MAINTENANCE", d.Get("task_type"))
+	assert.Equal(t, "sddcId", d.Get("resource_id"))
+
+	assert.NoError(t, diagsErr(resourceTaskWaitRead(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, taskID, d.Id())
+	assert.NoError(t, diagsErr(resourceTaskWaitDelete(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
+
+func TestResourceVmcTaskWaitFailedTaskSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	server.TaskFailureMessage = "maintenance failed"
+	taskID := server.StartTask("SRM-ACTIVATION", "sddcId")
+
+	d := schema.TestResourceDataRaw(t, resourceTaskWait().Schema, map[string]interface{}{
+		"task_id": taskID,
+		"service": constants.DraasTaskService,
+	})
+	assert.ErrorContains(t, diagsErr(resourceTaskWaitCreate(context.Background(), d, connectorWrapper)), "maintenance failed")
+	assert.Equal(t, "", d.Id())
+
+	d = schema.TestResourceDataRaw(t, resourceTaskWait().Schema, map[string]interface{}{
+		"task_id":       taskID,
+		"service":       constants.DraasTaskService,
+		"fail_on_error": false,
+	})
+	assert.NoError(t, diagsErr(resourceTaskWaitCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, model.Task_STATUS_FAILED, d.Get("status"))
+	assert.Equal(t, "maintenance failed", d.Get("error_message"))
+}
+
+func TestResourceVmcTaskWaitTaskNotFoundSimulator(t *testing.T) {
+	_, connectorWrapper := newTestSimulator(t)
+	d := schema.TestResourceDataRaw(t, resourceTaskWait().Schema, map[string]interface{}{
+		"task_id": "missing-task",
+	})
+	assert.Error(t, diagsErr(resourceTaskWaitCreate(context.Background(), d, connectorWrapper)))
+	assert.Equal(t, "", d.Id())
+}
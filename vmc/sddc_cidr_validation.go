@@ -0,0 +1,150 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"log"
+	"net"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+)
+
+// reservedManagementCidrs the ranges reserved by VMware Cloud on AWS, that the management
+// CIDR (vpc_cidr) of an SDDC cannot overlap with.
+var reservedManagementCidrs = []string{"10.0.0.0/15", "172.31.0.0/16"}
+
+// reservedSddcCidrs the ranges used internally by SDDCs, that none of their networks can overlap with.
+var reservedSddcCidrs = []string{"100.64.0.0/16", "169.254.0.0/16"}
+
+// validateSddcCidrs checks, before an SDDC is created, that its management CIDR (vpc_cidr) and
+// default compute segment (vxlan_subnet) do not overlap with each other, with the reserved ranges
+// or with the CIDR of the VPC of the customer subnets the SDDC is linked to, as the provisioning
+// of the SDDC would fail long after it started otherwise.
+func validateSddcCidrs(d *schema.ResourceDiff, m interface{}) error {
+	if d.Id() != "" && !d.HasChanges("vpc_cidr", "vxlan_subnet", "account_link_sddc_config") {
+		return nil
+	}
+	vpcCidr, err := parseSddcCidr(d, "vpc_cidr")
+	if err != nil {
+		return err
+	}
+	vxlanSubnet, err := parseSddcCidr(d, "vxlan_subnet")
+	if err != nil {
+		return err
+	}
+	if vpcCidr != nil {
+		if err := checkCidrOverlap("vpc_cidr", vpcCidr, append(reservedManagementCidrs, reservedSddcCidrs...),
+			"reserved range"); err != nil {
+			return err
+		}
+	}
+	if vxlanSubnet != nil {
+		if err := checkCidrOverlap("vxlan_subnet", vxlanSubnet, reservedSddcCidrs, "reserved range"); err != nil {
+			return err
+		}
+		if vpcCidr != nil && cidrsOverlap(vpcCidr, vxlanSubnet) {
+			return newAttributeError("vxlan_subnet", "vxlan_subnet %s overlaps with the management CIDR %s of the SDDC",
+				vxlanSubnet, vpcCidr)
+		}
+	}
+	if vpcCidr == nil && vxlanSubnet == nil {
+		return nil
+	}
+	connectedVpcCidrs := lookupConnectedVpcCidrs(d, m)
+	if vpcCidr != nil {
+		if err := checkCidrOverlap("vpc_cidr", vpcCidr, connectedVpcCidrs, "CIDR of the connected VPC"); err != nil {
+			return err
+		}
+	}
+	if vxlanSubnet != nil {
+		return checkCidrOverlap("vxlan_subnet", vxlanSubnet, connectedVpcCidrs, "CIDR of the connected VPC")
+	}
+	return nil
+}
+
+// parseSddcCidr parses the CIDR argument with the specified key, returning nil if it is not set or not yet known.
+func parseSddcCidr(d *schema.ResourceDiff, key string) (*net.IPNet, error) {
+	value := d.Get(key).(string)
+	if !d.NewValueKnown(key) || len(value) == 0 {
+		return nil, nil
+	}
+	_, network, err := net.ParseCIDR(value)
+	if err != nil {
+		return nil, newAttributeError(key, "invalid %s: %v", key, err)
+	}
+	return network, nil
+}
+
+// checkCidrOverlap fails, if the CIDR argument with the specified key overlaps with any of the ranges.
+func checkCidrOverlap(key string, cidr *net.IPNet, ranges []string, description string) error {
+	for _, cidrRange := range ranges {
+		_, network, err := net.ParseCIDR(cidrRange)
+		if err != nil {
+			continue
+		}
+		if cidrsOverlap(cidr, network) {
+			return newAttributeError(key, "%s %s overlaps with the %s %s", key, cidr, description, network)
+		}
+	}
+	return nil
+}
+
+func cidrsOverlap(a *net.IPNet, b *net.IPNet) bool {
+	return a.Contains(b.IP) || b.Contains(a.IP)
+}
+
+// lookupConnectedVpcCidrs returns the CIDRs of the VPCs of the customer subnets in the
+// account_link_sddc_config of the SDDC. The subnets cannot be validated, if they are not yet
+// known or cannot be looked up, in which case the error is left to the creation of the SDDC.
+func lookupConnectedVpcCidrs(d *schema.ResourceDiff, m interface{}) []string {
+	connectorWrapper, ok := m.(*connector.Wrapper)
+	if !ok || !d.NewValueKnown("account_link_sddc_config") || !d.NewValueKnown("region") {
+		return nil
+	}
+	region := d.Get("region").(string)
+	vpcCidrs := []string{}
+	for _, config := range d.Get("account_link_sddc_config").([]interface{}) {
+		c, ok := config.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		accountID := c["connected_account_id"].(string)
+		subnetIDs := map[string]bool{}
+		for _, subnetID := range c["customer_subnet_ids"].([]interface{}) {
+			if subnetID, ok := subnetID.(string); ok && len(subnetID) > 0 {
+				subnetIDs[subnetID] = true
+			}
+		}
+		if len(accountID) == 0 || len(subnetIDs) == 0 {
+			continue
+		}
+		apiClient := api.NewClient(connectorWrapper)
+		forceRefresh := false
+		compatibleSubnets, err := apiClient.CompatibleSubnets().Get(apiClient.OrgID(), accountID, &region,
+			nil, &forceRefresh, nil, nil, nil)
+		if err != nil {
+			log.Printf("[WARN] Unable to look up the subnets of connected account %s, "+
+				"skipping the validation of the SDDC CIDRs against them: %v", accountID, err)
+			continue
+		}
+		for _, vpc := range compatibleSubnets.VpcMap {
+			for _, subnet := range vpc.Subnets {
+				if subnet.SubnetId == nil || !subnetIDs[*subnet.SubnetId] {
+					continue
+				}
+				vpcCidr := subnet.VpcCidrBlock
+				if vpcCidr == nil {
+					vpcCidr = vpc.CidrBlock
+				}
+				if vpcCidr != nil {
+					vpcCidrs = append(vpcCidrs, *vpcCidr)
+				}
+			}
+		}
+	}
+	log.Printf("[DEBUG] CIDRs of the connected VPCs: %v", vpcCidrs)
+	return vpcCidrs
+}
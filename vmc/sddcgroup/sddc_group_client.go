@@ -10,44 +10,39 @@ import (
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
 	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
-	"io"
 	"net/http"
 )
 
-const authnHeader = "csp-auth-token"
-
 type Client interface {
 	connector.Authenticator
 	ValidateCreateSddcGroup(sddcIDs *[]string) error
 	ValidateUpdateSddcGroupMembers(groupID string, sddcIDs *[]string) error
 	GetSddcGroup(groupID string) (sddcGroup DeploymentGroup, error error)
+	ListSddcGroups() (configs []NetworkConnectivityConfig, error error)
 	CreateSddcGroup(name string, description string, sddcIDs *[]string) (groupID string, taskID string, error error)
 	UpdateSddcGroupMembers(groupID string, sddcIDsToAdd *[]string, sddcIDsToRemove *[]string) (taskID string, error error)
+	UpdateVpcAttachment(groupID string, attachment VpcAttachmentAction) (taskID string, error error)
+	UpdateCustomerTransitGatewayAssociation(groupID string,
+		association CustomerTransitGatewayAssociationAction) (taskID string, error error)
 	DeleteSddcGroup(groupID string) (taskID string, error error)
 }
 
-// HTTPClient an interface, that is implemented by the http.DefaultClient,
-// intended to enable stubbing for testing purposes
-type HTTPClient interface {
-	Do(req *http.Request) (*http.Response, error)
-}
-
 type ClientImpl struct {
 	connector  connector.Wrapper
-	httpClient HTTPClient
+	httpClient connector.HTTPClient
 }
 
 func NewSddcGroupClient(wrapper connector.Wrapper) *ClientImpl {
 	copyWrapper := connector.CopyWrapper(wrapper)
 	return &ClientImpl{
 		connector:  *copyWrapper,
-		httpClient: http.DefaultClient,
+		httpClient: copyWrapper.HTTPClient(),
 	}
 }
 
 // newTestSddcGroupClient intended for injecting dummy accessToken and stubbed httpClient for
 // testing purposes.
-func newTestSddcGroupClient(vmcURL string, orgID string, accessToken string, httpClient HTTPClient) *ClientImpl {
+func newTestSddcGroupClient(vmcURL string, orgID string, accessToken string, httpClient connector.HTTPClient) *ClientImpl {
 	testConnector := connector.Wrapper{
 		VmcURL: vmcURL,
 		OrgID:  orgID,
@@ -92,9 +87,9 @@ func (client *ClientImpl) validateCreateUpdateSddcGroupInternal(groupID string,
 	validateCreateURL := client.getBaseURL() + fmt.Sprintf(
 		"/network/%s/core/network-connectivity-configs/validate-members", client.connector.OrgID)
 
-	req := client.createNewRequest(http.MethodPost, validateCreateURL, bytes.NewBuffer(requestPayload))
+	req := client.connector.NewRequest(http.MethodPost, validateCreateURL, bytes.NewBuffer(requestPayload))
 
-	rawResponse, statusCode, err := client.executeRequest(req)
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
 	if err != nil {
 		return err
 	}
@@ -109,8 +104,8 @@ func (client *ClientImpl) GetSddcGroup(groupID string) (*DeploymentGroup,
 	*NetworkConnectivityConfig, error) {
 	getSddcGroupURL := client.getBaseURL() + fmt.Sprintf("/inventory/%s/core/deployment-groups/%s",
 		client.connector.OrgID, groupID)
-	req := client.createNewRequest(http.MethodGet, getSddcGroupURL, nil)
-	rawResponse, statusCode, err := client.executeRequest(req)
+	req := client.connector.NewRequest(http.MethodGet, getSddcGroupURL, nil)
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
 	var group *DeploymentGroup
 	var config *NetworkConnectivityConfig
 	if err != nil {
@@ -135,10 +130,10 @@ func (client *ClientImpl) GetSddcGroup(groupID string) (*DeploymentGroup,
 	}
 	getTraitsURL := client.getBaseURL() + fmt.Sprintf("/network/%s/core/network-connectivity-configs/%s"+
 		"?trait=AwsVpcAttachmentsTrait,AwsDirectConnectGatewayAssociationsTrait,"+
-		"AwsNetworkConnectivityTrait,AwsCustomerTransitGatewayAssociationsTrait",
+		"AwsNetworkConnectivityTrait,AwsCustomerTransitGatewayAssociationsTrait,AwsRealizedSddcConnectivityTrait",
 		client.connector.OrgID, resourceID)
-	req = client.createNewRequest(http.MethodGet, getTraitsURL, nil)
-	rawResponse, statusCode, err = client.executeRequest(req)
+	req = client.connector.NewRequest(http.MethodGet, getTraitsURL, nil)
+	rawResponse, statusCode, err = connector.ExecuteRequest(client.httpClient, req)
 	if err != nil {
 		return group, config, err
 	}
@@ -174,9 +169,9 @@ func (client *ClientImpl) CreateSddcGroup(
 		"/network/%s/core/network-connectivity-configs/create-group-network-connectivity",
 		client.connector.OrgID)
 
-	req := client.createNewRequest(http.MethodPost, createSddcGroupURL, bytes.NewBuffer(requestPayload))
+	req := client.connector.NewRequest(http.MethodPost, createSddcGroupURL, bytes.NewBuffer(requestPayload))
 
-	rawResponse, statusCode, err := client.executeRequest(req)
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
 	if err != nil {
 		return "", "", err
 	}
@@ -223,6 +218,41 @@ func (client *ClientImpl) UpdateSddcGroupMembers(
 	return networkOperationResponse.Config.OperationID, nil
 }
 
+// UpdateVpcAttachment accepts, updates the configured prefixes of, or deletes a VPC attachment
+// of the transit gateway of an SDDC group, depending on the action of the attachment.
+func (client *ClientImpl) UpdateVpcAttachment(groupID string, attachment VpcAttachmentAction) (taskID string, error error) {
+	resourceID, err := client.getResourceIDFromGroupID(groupID)
+	if err != nil {
+		return "", err
+	}
+	config := NewAwsUpdateVpcAttachmentsConfig([]VpcAttachmentAction{attachment})
+	networkOperation := NewNetworkOperation(client.connector.OrgID, resourceID, UpdateVpcAttachmentsNetworkOperationType, *config)
+	networkOperationResponse, err := client.executeNetworkOperation(networkOperation)
+	if err != nil {
+		return "", err
+	}
+	return networkOperationResponse.Config.OperationID, nil
+}
+
+// UpdateCustomerTransitGatewayAssociation associates an external transit gateway with the transit
+// gateway of an SDDC group, updates the configured prefixes of the association or deletes it,
+// depending on the action of the association.
+func (client *ClientImpl) UpdateCustomerTransitGatewayAssociation(groupID string,
+	association CustomerTransitGatewayAssociationAction) (taskID string, error error) {
+	resourceID, err := client.getResourceIDFromGroupID(groupID)
+	if err != nil {
+		return "", err
+	}
+	config := NewAwsUpdateCustomerTransitGatewayAssociationsConfig([]CustomerTransitGatewayAssociationAction{association})
+	networkOperation := NewNetworkOperation(client.connector.OrgID, resourceID,
+		UpdateCustomerTransitGatewayAssociationsNetworkOperationType, *config)
+	networkOperationResponse, err := client.executeNetworkOperation(networkOperation)
+	if err != nil {
+		return "", err
+	}
+	return networkOperationResponse.Config.OperationID, nil
+}
+
 func (client *ClientImpl) DeleteSddcGroup(groupID string) (taskID string, error error) {
 	resourceID, err := client.getResourceIDFromGroupID(groupID)
 	if err != nil {
@@ -237,20 +267,49 @@ func (client *ClientImpl) DeleteSddcGroup(groupID string) (taskID string, error
 	return networkOperationResponse.ID, nil
 }
 
+// ListSddcGroups returns the network connectivity configs of all SDDC groups of the organization,
+// each of which carries the ID and name of its group.
+func (client *ClientImpl) ListSddcGroups() (configs []NetworkConnectivityConfig, error error) {
+	listURL := client.getBaseURL() + fmt.Sprintf(
+		"/network/%s/core/network-connectivity-configs", client.connector.OrgID)
+
+	req := client.connector.NewRequest(http.MethodGet, listURL, nil)
+
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
+	if err != nil {
+		return nil, err
+	}
+	if statusCode == http.StatusOK {
+		err := json.NewDecoder(bytes.NewReader(*rawResponse)).Decode(&configs)
+		if err != nil {
+			return nil, err
+		}
+		return configs, nil
+	}
+	return nil, fmt.Errorf("ListSddcGroups failed with status %d body: %s",
+		statusCode, string(*rawResponse))
+}
+
 func (client *ClientImpl) getResourceIDFromGroupID(groupID string) (resourceID string, error error) {
 	getResourceIDURL := client.getBaseURL() + fmt.Sprintf(
 		"/network/%s/core/network-connectivity-configs?group_id=%s", client.connector.OrgID, groupID)
 
-	req := client.createNewRequest(http.MethodGet, getResourceIDURL, nil)
+	req := client.connector.NewRequest(http.MethodGet, getResourceIDURL, nil)
 
-	rawResponse, statusCode, err := client.executeRequest(req)
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
 	if err != nil {
 		return "", err
 	}
 	var result []NetworkConnectivityConfig
 	if statusCode == http.StatusOK {
 		err := json.NewDecoder(bytes.NewReader(*rawResponse)).Decode(&result)
-		return result[0].ID, err
+		if err != nil {
+			return "", err
+		}
+		if len(result) == 0 {
+			return "", fmt.Errorf("no network connectivity config found for SDDC group %s", groupID)
+		}
+		return result[0].ID, nil
 	}
 	return "", fmt.Errorf("getResourceIDFromGroupID failed with status %d body: %s",
 		statusCode, string(*rawResponse))
@@ -264,9 +323,9 @@ func (client *ClientImpl) executeNetworkOperation(networkOperation *NetworkOpera
 	}
 	networkOperationsURL := client.getNetworkOperationsURL()
 
-	req := client.createNewRequest(http.MethodPost, networkOperationsURL, bytes.NewBuffer(requestPayload))
+	req := client.connector.NewRequest(http.MethodPost, networkOperationsURL, bytes.NewBuffer(requestPayload))
 
-	rawResponse, statusCode, err := client.executeRequest(req)
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
 	if err != nil {
 		return networkOperationResponse, err
 	}
@@ -291,48 +350,10 @@ func (client *ClientImpl) getNetworkOperationsURL() string {
 		"/network/%s/aws/operations", client.connector.OrgID)
 }
 
-// executeRequest Returns the body of the response as byte array pointer, the status code
-// or any error that may have occurred during the Http communication.
-func (client *ClientImpl) executeRequest(
-	request *http.Request) (responseBody *[]byte, statusCode int, error error) {
-	response, err := client.httpClient.Do(request)
-	defer func(Body io.ReadCloser) {
-		err := Body.Close()
-		if err != nil {
-			fmt.Printf("Error closing body of http response")
-		}
-	}(response.Body)
-
-	if err != nil {
-		return nil, response.StatusCode, err
-	}
-	if response.StatusCode == http.StatusUnauthorized {
-		return nil, response.StatusCode, fmt.Errorf("Unauthenticated request ")
-	}
-	if response.StatusCode == http.StatusForbidden {
-		return nil, response.StatusCode, fmt.Errorf("Unauthorized request ")
-	}
-	result, err := io.ReadAll(response.Body)
-	if err != nil {
-		return nil, response.StatusCode, err
-	}
-
-	return &result, response.StatusCode, nil
-}
-
 func (client *ClientImpl) getBaseURL() string {
 	return client.connector.VmcURL + "/api"
 }
 
-func (client *ClientImpl) createNewRequest(method string, URL string, body io.Reader) *http.Request {
-	req, _ := http.NewRequest(method, URL, body)
-	req.Header.Add(authnHeader, client.connector.Connector.SecurityContext().Property(security.ACCESS_TOKEN).(string))
-	if method == http.MethodPost {
-		req.Header.Add("content-type", "application/json")
-	}
-	return req
-}
-
 func validationErrorResponseToString(errorResponse *ValidationErrorResponse) string {
 	errorMessage := ""
 	for _, detail := range errorResponse.Details {
@@ -6,6 +6,7 @@ package sddcgroup
 import (
 	"fmt"
 	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
 	"io"
 	"log"
@@ -54,7 +55,7 @@ func (stub *HTTPClientStub) Do(req *http.Request) (*http.Response, error) {
 	}
 	assert.Equal(stub.t, stub.expectedURL, req.URL.String())
 	assert.Equal(stub.t, stub.expectedMethod, req.Method)
-	assert.Equal(stub.t, req.Header.Get(authnHeader), testAccessToken)
+	assert.Equal(stub.t, req.Header.Get(connector.AuthnHeader), testAccessToken)
 	response := http.Response{
 		StatusCode: stub.responseCode,
 		Body:       io.NopCloser(strings.NewReader(stub.responseJSON)),
@@ -72,7 +73,7 @@ func readAsString(reader io.ReadCloser) string {
 
 func TestValidateCreateSddcGroup(t *testing.T) {
 	type inputStruct struct {
-		httpClientStub HTTPClient
+		httpClientStub connector.HTTPClient
 		sddcIds        *[]string
 	}
 	type test struct {
@@ -136,7 +137,7 @@ func TestValidateCreateSddcGroup(t *testing.T) {
 func TestValidateCreateSddcGroupMembers(t *testing.T) {
 	t.Setenv(constants.VmcURL, testVmcURL)
 	type inputStruct struct {
-		httpClientStub HTTPClient
+		httpClientStub connector.HTTPClient
 		groupID        string
 		sddcIDs        *[]string
 	}
@@ -204,7 +205,7 @@ func TestValidateCreateSddcGroupMembers(t *testing.T) {
 func TestGetSddcGroup(t *testing.T) {
 	t.Setenv(constants.VmcURL, testVmcURL)
 	type inputStruct struct {
-		httpClientStub HTTPClient
+		httpClientStub connector.HTTPClient
 		groupID        string
 	}
 	type outputStruct struct {
@@ -252,7 +253,7 @@ func TestGetSddcGroup(t *testing.T) {
 			outputStruct{
 				sddcGroup:                 nil,
 				networkConnectivityConfig: nil,
-				error:                     fmt.Errorf("Unauthenticated request "),
+				error:                     fmt.Errorf("Unauthenticated request"),
 			},
 		},
 		{
@@ -299,7 +300,9 @@ func TestGetSddcGroup(t *testing.T) {
 						"AwsCustomerTransitGatewayAssociationsTrait\":{\n\"customer_transit_gateway_associations\":[\n{\n\"" +
 						"customer_transit_gateway_id\":\"customer_transit_gateway_id_123\",\n\"customer_transit_gateway_owner\":\"Fett\"," +
 						"\n\"customer_transit_gateway_region\":{\n\"code\":\"us-east-1\"\n},\n\"peering_regions\":[\n{\n\"" +
-						"configured_prefixes\":[\n\"10.20.30.40/24\",\n\"40.30.20.10/24\"\n]\n}\n]\n}\n]\n}\n}\n}",
+						"configured_prefixes\":[\n\"10.20.30.40/24\",\n\"40.30.20.10/24\"\n]\n}\n]\n}\n]\n},\n\"" +
+						"AwsRealizedSddcConnectivityTrait\":{\n\"sddcs\":[\n{\n\"sddc_id\":\"sddcId1\",\n\"state\":\"CONNECTED\"\n},\n{\n\"" +
+						"sddc_id\":\"sddcId2\",\n\"state\":\"CONNECTING\"\n}\n]\n}\n}\n}",
 					t: t,
 				},
 				groupID: "testGroupId",
@@ -386,6 +389,12 @@ func TestGetSddcGroup(t *testing.T) {
 								},
 							},
 						},
+						RealizedSddcs: &AwsRealizedSddcConnectivityTrait{
+							Sddcs: []SddcConnectivity{
+								{SddcID: "sddcId1", State: SddcMemberStateConnected},
+								{SddcID: "sddcId2", State: SddcMemberStateConnecting},
+							},
+						},
 					},
 				},
 				error: nil,
@@ -404,7 +413,7 @@ func TestGetSddcGroup(t *testing.T) {
 func TestCreateSddcGroup(t *testing.T) {
 	t.Setenv(constants.VmcURL, testVmcURL)
 	type inputStruct struct {
-		httpClientStub HTTPClient
+		httpClientStub connector.HTTPClient
 		name           string
 		description    string
 		sddcIDs        *[]string
@@ -518,7 +527,7 @@ func TestCreateSddcGroup(t *testing.T) {
 func TestUpdateSddcGroupMembers(t *testing.T) {
 	t.Setenv(constants.VmcURL, testVmcURL)
 	type inputStruct struct {
-		httpClientStub  HTTPClient
+		httpClientStub  connector.HTTPClient
 		groupID         string
 		sddcIDsToAdd    *[]string
 		sddcIDsToRemove *[]string
@@ -618,7 +627,7 @@ func TestUpdateSddcGroupMembers(t *testing.T) {
 func TestDeleteSddcGroup(t *testing.T) {
 	t.Setenv(constants.VmcURL, testVmcURL)
 	type inputStruct struct {
-		httpClientStub HTTPClient
+		httpClientStub connector.HTTPClient
 		groupID        string
 	}
 	type outputStruct struct {
@@ -700,3 +709,79 @@ func TestDeleteSddcGroup(t *testing.T) {
 		assert.Equal(t, testCase.output.error, err)
 	}
 }
+
+func TestUpdateVpcAttachment(t *testing.T) {
+	t.Setenv(constants.VmcURL, testVmcURL)
+	expectedJSON := "{\"org_id\":\"testOrgID\",\"resource_id\":\"resourceIdDifferentFromGroupId\"," +
+		"\"resource_type\":\"network-connectivity-config\",\"type\":\"UPDATE_VPC_ATTACHMENTS\",\"config\":{" +
+		"\"type\":\"AwsUpdateVpcAttachmentsConfig\",\"attachments\":[{\"action\":\"ACCEPT\"," +
+		"\"attach_id\":\"tgw-attach-123\",\"configured_prefixes\":[\"10.20.0.0/16\"]}]}}"
+	tests := []struct {
+		responseCode int
+		responseJSON string
+		taskID       string
+		error        error
+	}{
+		{
+			responseCode: http.StatusConflict,
+			responseJSON: "{\"status\": 409,\"details\": [{\"validation_error_message\": \"Attachment is not pending acceptance.\"}]}",
+			error:        fmt.Errorf("Attachment is not pending acceptance."),
+		},
+		{
+			responseCode: http.StatusOK,
+			responseJSON: "{\"org_id\":\"testOrgID\",\"type\":\"UPDATE_VPC_ATTACHMENTS\",\"config\":{" +
+				"\"type\":\"AwsUpdateVpcAttachmentsConfig\",\"operation_id\":\"acceptAttachmentTaskId\"}}",
+			taskID: "acceptAttachmentTaskId",
+		},
+	}
+	for _, testCase := range tests {
+		stub := &HTTPClientStub{
+			expectedMethod:                  http.MethodPost,
+			additionalResourceIDRequestJSON: "[{\"id\":\"resourceIdDifferentFromGroupId\"}]",
+			expectedJSON:                    expectedJSON,
+			expectedURL:                     "https://test.vmc.vmware.com/api/network/testOrgID/aws/operations",
+			responseCode:                    testCase.responseCode,
+			responseJSON:                    testCase.responseJSON,
+			t:                               t,
+		}
+		sddcGroupClient := newTestSddcGroupClient(testVmcURL, testOrgID, testAccessToken, stub)
+		taskID, err := sddcGroupClient.UpdateVpcAttachment("testGroupId", VpcAttachmentAction{
+			Action:             AttachmentActionAccept,
+			AttachmentID:       "tgw-attach-123",
+			ConfiguredPrefixes: []string{"10.20.0.0/16"},
+		})
+		assert.Equal(t, testCase.taskID, taskID)
+		assert.Equal(t, testCase.error, err)
+	}
+}
+
+func TestUpdateCustomerTransitGatewayAssociation(t *testing.T) {
+	t.Setenv(constants.VmcURL, testVmcURL)
+	stub := &HTTPClientStub{
+		expectedMethod:                  http.MethodPost,
+		additionalResourceIDRequestJSON: "[{\"id\":\"resourceIdDifferentFromGroupId\"}]",
+		expectedJSON: "{\"org_id\":\"testOrgID\",\"resource_id\":\"resourceIdDifferentFromGroupId\"," +
+			"\"resource_type\":\"network-connectivity-config\",\"type\":\"UPDATE_CUSTOMER_TRANSIT_GATEWAY_ASSOCIATIONS\"," +
+			"\"config\":{\"type\":\"AwsUpdateCustomerTransitGatewayAssociationsConfig\"," +
+			"\"customer_transit_gateway_associations\":[{\"action\":\"ASSOCIATE\",\"customer_transit_gateway_id\":\"tgw-123\"," +
+			"\"customer_transit_gateway_owner\":\"123456789012\",\"customer_transit_gateway_region\":{\"code\":\"us-east-1\"}," +
+			"\"peering_regions\":[{\"configured_prefixes\":[\"10.30.0.0/16\"]}]}]}}",
+		expectedURL:  "https://test.vmc.vmware.com/api/network/testOrgID/aws/operations",
+		responseCode: http.StatusOK,
+		responseJSON: "{\"org_id\":\"testOrgID\",\"type\":\"UPDATE_CUSTOMER_TRANSIT_GATEWAY_ASSOCIATIONS\",\"config\":{" +
+			"\"type\":\"AwsUpdateCustomerTransitGatewayAssociationsConfig\",\"operation_id\":\"associateTgwTaskId\"}}",
+		t: t,
+	}
+	sddcGroupClient := newTestSddcGroupClient(testVmcURL, testOrgID, testAccessToken, stub)
+	taskID, err := sddcGroupClient.UpdateCustomerTransitGatewayAssociation("testGroupId", CustomerTransitGatewayAssociationAction{
+		Action: AttachmentActionAssociate,
+		CustomerTransitGatewayAssociation: CustomerTransitGatewayAssociation{
+			TgwID:          "tgw-123",
+			TgwOwner:       "123456789012",
+			TgwRegion:      TgwRegion{Region: "us-east-1"},
+			PeeringRegions: []PeeringRegions{{ConfiguredPrefixes: []string{"10.30.0.0/16"}}},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "associateTgwTaskId", taskID)
+}
@@ -58,17 +58,46 @@ type CustomerTransitGatewayAssociation struct {
 	TgwID          string           `json:"customer_transit_gateway_id"`
 	TgwOwner       string           `json:"customer_transit_gateway_owner"`
 	TgwRegion      TgwRegion        `json:"customer_transit_gateway_region"`
+	Status         string           `json:"state,omitempty"`
 	PeeringRegions []PeeringRegions `json:"peering_regions"`
 }
 
 type AwsCustomerTransitGatewayAssociationsTrait struct {
 	CustomerTransitGatewayAssociations []CustomerTransitGatewayAssociation `json:"customer_transit_gateway_associations,omitempty"`
 }
+
+// SddcConnectivity the realized state of the connectivity of a member SDDC to the SDDC group.
+type SddcConnectivity struct {
+	SddcID string `json:"sddc_id"`
+	State  string `json:"state"`
+}
+
+const (
+	// SddcMemberStateConnecting the state of an SDDC, that is being attached to the SDDC group
+	SddcMemberStateConnecting = "CONNECTING"
+	// SddcMemberStateConnected the state of an SDDC attached to the SDDC group
+	SddcMemberStateConnected = "CONNECTED"
+	// SddcMemberStateDisconnecting the state of an SDDC, that is being detached from the SDDC group
+	SddcMemberStateDisconnecting = "DISCONNECTING"
+)
+
+const (
+	// AttachmentStatePendingAcceptance the state of an external attachment, that awaits to be accepted
+	AttachmentStatePendingAcceptance = "PENDING_ACCEPTANCE"
+	// AttachmentStateConnected the state of an accepted external attachment
+	AttachmentStateConnected = "CONNECTED"
+)
+
+type AwsRealizedSddcConnectivityTrait struct {
+	Sddcs []SddcConnectivity `json:"sddcs,omitempty"`
+}
+
 type Traits struct {
 	TransitGateway *AwsNetworkConnectivityTrait                `json:"AwsNetworkConnectivityTrait,omitempty"`
 	AwsInfo        *AwsVpcAttachmentsTrait                     `json:"AwsVpcAttachmentsTrait,omitempty"`
 	DxGateway      *AwsDirectConnectGatewayAssociationsTrait   `json:"AwsDirectConnectGatewayAssociationsTrait,omitempty"`
 	ExternalTgw    *AwsCustomerTransitGatewayAssociationsTrait `json:"AwsCustomerTransitGatewayAssociationsTrait,omitempty"`
+	RealizedSddcs  *AwsRealizedSddcConnectivityTrait           `json:"AwsRealizedSddcConnectivityTrait,omitempty"`
 }
 
 type NetworkConnectivityConfigState struct {
@@ -163,10 +192,37 @@ func NewNetworkOperation(
 }
 
 type Config struct {
-	Type          string                  `json:"type"`
-	OperationID   string                  `json:"operation_id,omitempty"`
-	AddMembers    []DeploymentGroupMember `json:"add_members,omitempty"`
-	RemoveMembers []DeploymentGroupMember `json:"remove_members,omitempty"`
+	Type                               string                                    `json:"type"`
+	OperationID                        string                                    `json:"operation_id,omitempty"`
+	AddMembers                         []DeploymentGroupMember                   `json:"add_members,omitempty"`
+	RemoveMembers                      []DeploymentGroupMember                   `json:"remove_members,omitempty"`
+	VpcAttachments                     []VpcAttachmentAction                     `json:"attachments,omitempty"`
+	CustomerTransitGatewayAssociations []CustomerTransitGatewayAssociationAction `json:"customer_transit_gateway_associations,omitempty"`
+}
+
+const (
+	// AttachmentActionAccept accepts a pending VPC attachment
+	AttachmentActionAccept = "ACCEPT"
+	// AttachmentActionAssociate associates an external transit gateway with the SDDC group
+	AttachmentActionAssociate = "ASSOCIATE"
+	// AttachmentActionUpdate updates the configured prefixes of an attachment or association
+	AttachmentActionUpdate = "UPDATE"
+	// AttachmentActionDelete deletes an attachment or association
+	AttachmentActionDelete = "DELETE"
+)
+
+// VpcAttachmentAction an action on a VPC attachment of the transit gateway of an SDDC group.
+type VpcAttachmentAction struct {
+	Action             string   `json:"action"`
+	AttachmentID       string   `json:"attach_id"`
+	ConfiguredPrefixes []string `json:"configured_prefixes,omitempty"`
+}
+
+// CustomerTransitGatewayAssociationAction an action on the association of an external
+// transit gateway with the transit gateway of an SDDC group.
+type CustomerTransitGatewayAssociationAction struct {
+	Action string `json:"action"`
+	CustomerTransitGatewayAssociation
 }
 
 const UpdateMembersNetworkOperationType = "UPDATE_MEMBERS"
@@ -181,6 +237,25 @@ func NewAwsUpdateDeploymentGroupMembersConfig(
 	}
 }
 
+const UpdateVpcAttachmentsNetworkOperationType = "UPDATE_VPC_ATTACHMENTS"
+
+func NewAwsUpdateVpcAttachmentsConfig(attachments []VpcAttachmentAction) *Config {
+	return &Config{
+		Type:           "AwsUpdateVpcAttachmentsConfig",
+		VpcAttachments: attachments,
+	}
+}
+
+const UpdateCustomerTransitGatewayAssociationsNetworkOperationType = "UPDATE_CUSTOMER_TRANSIT_GATEWAY_ASSOCIATIONS"
+
+func NewAwsUpdateCustomerTransitGatewayAssociationsConfig(
+	associations []CustomerTransitGatewayAssociationAction) *Config {
+	return &Config{
+		Type:                               "AwsUpdateCustomerTransitGatewayAssociationsConfig",
+		CustomerTransitGatewayAssociations: associations,
+	}
+}
+
 const DeleteSddcGroupNetworkOperationType = "DELETE_DEPLOYMENT_GROUP"
 
 func NewAwsDeleteDeploymentGroupConfig() *Config {
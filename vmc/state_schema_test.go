@@ -0,0 +1,167 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/hashicorp/go-cty/cty"
+	ctyjson "github.com/hashicorp/go-cty/cty/json"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/stretchr/testify/assert"
+)
+
+var updateStateSchemas = flag.Bool("update-state-schemas", false,
+	"write the state schemas of the current schema versions to testdata/state_schemas")
+
+// versionedResources the resources, whose state schemas are pinned in testdata/state_schemas.
+var versionedResources = map[string]func() *schema.Resource{
+	"vmc_sddc":          resourceSddc,
+	"vmc_site_recovery": resourceSiteRecovery,
+	"vmc_srm_node":      resourceSrmNode,
+}
+
+// TestResourceStateSchemas fails when an attribute of a versioned resource is removed or changes its
+// type without bumping the SchemaVersion, as the state of existing resources could not be read anymore.
+// Such changes need a StateUpgrader from the previous version, whose Type is the pinned state schema.
+// Adding attributes is compatible, run with -update-state-schemas to pin them, which also pins the
+// Types of the StateUpgraders of the previous versions, that are not pinned yet.
+func TestResourceStateSchemas(t *testing.T) {
+	for name, resourceFunc := range versionedResources {
+		resource := resourceFunc()
+		current := resource.CoreConfigSchema().ImpliedType()
+		path := stateSchemaPath(name, resource.SchemaVersion)
+		if *updateStateSchemas {
+			writeStateSchema(t, path, current)
+		}
+		pinned := readStateSchema(t, path)
+		if pinned == cty.NilType {
+			t.Errorf("%s: no state schema is pinned for version %d, run with -update-state-schemas", name,
+				resource.SchemaVersion)
+			continue
+		}
+		for _, incompatibility := range stateSchemaIncompatibilities(name, pinned, current) {
+			t.Errorf("%s changed without bumping its SchemaVersion %d: %s", name, resource.SchemaVersion, incompatibility)
+		}
+
+		upgraders := map[int]schema.StateUpgrader{}
+		for _, upgrader := range resource.StateUpgraders {
+			upgraders[upgrader.Version] = upgrader
+		}
+		for version := 0; version < resource.SchemaVersion; version++ {
+			upgrader, ok := upgraders[version]
+			if !ok {
+				t.Errorf("%s: no StateUpgrader for version %d", name, version)
+				continue
+			}
+			previousPath := stateSchemaPath(name, version)
+			if *updateStateSchemas && readStateSchema(t, previousPath) == cty.NilType {
+				writeStateSchema(t, previousPath, upgrader.Type)
+			}
+			previous := readStateSchema(t, previousPath)
+			if previous != cty.NilType && !previous.Equals(upgrader.Type) {
+				t.Errorf("%s: the Type of the StateUpgrader for version %d differs from its pinned state schema",
+					name, version)
+			}
+		}
+	}
+}
+
+func TestStateSchemaIncompatibilities(t *testing.T) {
+	pinned := cty.Object(map[string]cty.Type{
+		"name":     cty.String,
+		"hosts":    cty.Number,
+		"settings": cty.Map(cty.String),
+		"nodes":    cty.List(cty.Object(map[string]cty.Type{"id": cty.String, "state": cty.String})),
+	})
+	assert.Empty(t, stateSchemaIncompatibilities("vmc_test", pinned, cty.Object(map[string]cty.Type{
+		"name":     cty.String,
+		"hosts":    cty.Number,
+		"settings": cty.Map(cty.String),
+		"nodes": cty.List(cty.Object(map[string]cty.Type{
+			"id": cty.String, "state": cty.String, "type": cty.String})),
+		"added": cty.Bool,
+	})))
+	assert.Equal(t, []string{
+		"vmc_test.hosts was removed",
+		"vmc_test.nodes.state was removed",
+		"vmc_test.settings changed from map of string to list of object",
+	}, stateSchemaIncompatibilities("vmc_test", pinned, cty.Object(map[string]cty.Type{
+		"name":     cty.String,
+		"settings": cty.List(cty.Object(map[string]cty.Type{"key": cty.String})),
+		"nodes":    cty.List(cty.Object(map[string]cty.Type{"id": cty.String})),
+	})))
+}
+
+// stateSchemaIncompatibilities returns the attributes of the pinned state schema, that the current
+// one removes or changes the type of.
+func stateSchemaIncompatibilities(path string, pinned cty.Type, current cty.Type) []string {
+	switch {
+	case pinned.IsObjectType() && current.IsObjectType():
+		var incompatibilities []string
+		currentAttributes := current.AttributeTypes()
+		for name, pinnedType := range pinned.AttributeTypes() {
+			currentType, ok := currentAttributes[name]
+			if !ok {
+				incompatibilities = append(incompatibilities, fmt.Sprintf("%s.%s was removed", path, name))
+				continue
+			}
+			incompatibilities = append(incompatibilities,
+				stateSchemaIncompatibilities(path+"."+name, pinnedType, currentType)...)
+		}
+		sort.Strings(incompatibilities)
+		return incompatibilities
+	case pinned.IsListType() && current.IsListType(), pinned.IsSetType() && current.IsSetType(),
+		pinned.IsMapType() && current.IsMapType():
+		return stateSchemaIncompatibilities(path, pinned.ElementType(), current.ElementType())
+	case pinned.Equals(current):
+		return nil
+	}
+	return []string{fmt.Sprintf("%s changed from %s to %s", path, pinned.FriendlyName(), current.FriendlyName())}
+}
+
+func stateSchemaPath(name string, version int) string {
+	return filepath.Join("testdata", "state_schemas", fmt.Sprintf("%s_v%d.json", name, version))
+}
+
+// readStateSchema returns the state schema pinned in the provided file, cty.NilType if there is none.
+func readStateSchema(t *testing.T, path string) cty.Type {
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cty.NilType
+	}
+	if err != nil {
+		t.Fatalf("error reading %s: %v", path, err)
+	}
+	stateSchema, err := ctyjson.UnmarshalType(content)
+	if err != nil {
+		t.Fatalf("invalid state schema %s: %v", path, err)
+	}
+	return stateSchema
+}
+
+func writeStateSchema(t *testing.T, path string, stateSchema cty.Type) {
+	content, err := ctyjson.MarshalType(stateSchema)
+	if err != nil {
+		t.Fatalf("error marshalling state schema %s: %v", path, err)
+	}
+	var indented bytes.Buffer
+	if err := json.Indent(&indented, content, "", "  "); err != nil {
+		t.Fatalf("error formatting state schema %s: %v", path, err)
+	}
+	indented.WriteByte('\n')
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("error creating %s: %v", filepath.Dir(path), err)
+	}
+	if err := os.WriteFile(path, indented.Bytes(), 0644); err != nil {
+		t.Fatalf("error writing %s: %v", path, err)
+	}
+}
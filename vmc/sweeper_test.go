@@ -0,0 +1,332 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package vmc
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/testing/simulator"
+	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+)
+
+// testResourcePrefix the prefix of the names of the SDDCs, SDDC groups and public IPs created by
+// the acceptance tests. The sweepers only delete the resources, whose names start with it.
+const testResourcePrefix = "terraform_"
+
+// TestMain runs the sweepers, if the tests are invoked with the -sweep flag, e.g.
+// go test ./vmc -v -sweep=us-west-2, and the tests otherwise.
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
+func init() {
+	resource.AddTestSweepers("vmc_sddc", &resource.Sweeper{
+		Name:         "vmc_sddc",
+		Dependencies: []string{"vmc_cluster", "vmc_public_ip", "vmc_sddc_group", "vmc_site_recovery"},
+		F:            sweeperFunc(sweepSddcs),
+	})
+	resource.AddTestSweepers("vmc_cluster", &resource.Sweeper{
+		Name: "vmc_cluster",
+		F:    sweeperFunc(sweepClusters),
+	})
+	resource.AddTestSweepers("vmc_public_ip", &resource.Sweeper{
+		Name: "vmc_public_ip",
+		F:    sweeperFunc(sweepPublicIPs),
+	})
+	resource.AddTestSweepers("vmc_sddc_group", &resource.Sweeper{
+		Name: "vmc_sddc_group",
+		F:    sweeperFunc(sweepSddcGroups),
+	})
+	resource.AddTestSweepers("vmc_site_recovery", &resource.Sweeper{
+		Name: "vmc_site_recovery",
+		F:    sweeperFunc(sweepSiteRecoveries),
+	})
+}
+
+// sweeperFunc adapts a sweep function to the resource.SweeperFunc signature, connecting it to the
+// organization the acceptance tests run in.
+func sweeperFunc(sweep func(connectorWrapper *connector.Wrapper, region string) error) resource.SweeperFunc {
+	return func(region string) error {
+		connectorWrapper, err := newConnectorWrapper(providerConfig{
+			RefreshToken:        os.Getenv(constants.APIToken),
+			ClientID:            os.Getenv(constants.ClientID),
+			ClientSecret:        os.Getenv(constants.ClientSecret),
+			OrgID:               os.Getenv(constants.OrgID),
+			Environment:         os.Getenv(constants.Environment),
+			VmcURL:              os.Getenv(constants.VmcURL),
+			CspURL:              os.Getenv(constants.CspURL),
+			MaxRetries:          constants.DefaultMaxRetries,
+			RetryMinDelay:       constants.DefaultRetryMinDelay,
+			RetryMaxDelay:       constants.DefaultRetryMaxDelay,
+			TaskPollInterval:    constants.DefaultTaskPollInterval,
+			TaskPollMaxInterval: constants.DefaultTaskPollMaxInterval,
+		})
+		if err != nil {
+			return fmt.Errorf("error connecting to organization %s: %w", os.Getenv(constants.OrgID), err)
+		}
+		return sweep(connectorWrapper, region)
+	}
+}
+
+// sweptSddcs returns the SDDCs in the region, that were created by the acceptance tests. All
+// regions are swept, if the region is "all". The existing SDDCs the acceptance tests run
+// against are never swept.
+func sweptSddcs(connectorWrapper *connector.Wrapper, region string) ([]model.Sddc, error) {
+	sddcs, err := api.NewClient(connectorWrapper).Sddcs().List(connectorWrapper.OrgID, nil)
+	if err != nil {
+		return nil, err
+	}
+	preserved := map[string]bool{}
+	for _, key := range []string{constants.TestSddcID, constants.SddcGroupTestSddc1Id, constants.SddcGroupTestSddc2Id} {
+		if sddcID := os.Getenv(key); len(sddcID) > 0 {
+			preserved[sddcID] = true
+		}
+	}
+	region = strings.ReplaceAll(strings.ToUpper(region), "-", "_")
+	var swept []model.Sddc
+	for _, sddc := range sddcs {
+		if preserved[sddc.Id] || sddc.Name == nil || !strings.HasPrefix(*sddc.Name, testResourcePrefix) {
+			continue
+		}
+		if sddc.SddcState != nil && *sddc.SddcState == model.Sddc_SDDC_STATE_DELETED {
+			continue
+		}
+		if region != "ALL" && (sddc.ResourceConfig == nil || sddc.ResourceConfig.Region == nil ||
+			*sddc.ResourceConfig.Region != region) {
+			continue
+		}
+		swept = append(swept, sddc)
+	}
+	return swept, nil
+}
+
+func sweepSddcs(connectorWrapper *connector.Wrapper, region string) error {
+	sddcs, err := sweptSddcs(connectorWrapper, region)
+	if err != nil {
+		return fmt.Errorf("error listing SDDCs: %w", err)
+	}
+	for _, sddc := range sddcs {
+		log.Printf("[INFO] Deleting SDDC %s (%s)", *sddc.Name, sddc.Id)
+		d := resourceSddc().Data(nil)
+		d.SetId(sddc.Id)
+		if err := diagsErr(resourceSddcDelete(context.Background(), d, connectorWrapper)); err != nil {
+			return fmt.Errorf("error deleting SDDC %s: %w", sddc.Id, err)
+		}
+	}
+	return nil
+}
+
+// sweepClusters deletes the clusters added to the SDDCs created by the acceptance tests,
+// the primary clusters are deleted together with the SDDCs.
+func sweepClusters(connectorWrapper *connector.Wrapper, region string) error {
+	sddcs, err := sweptSddcs(connectorWrapper, region)
+	if err != nil {
+		return fmt.Errorf("error listing SDDCs: %w", err)
+	}
+	apiClient := api.NewClient(connectorWrapper)
+	for _, sddc := range sddcs {
+		if sddc.ResourceConfig == nil || len(sddc.ResourceConfig.Clusters) < 2 {
+			continue
+		}
+		primaryCluster, err := apiClient.PrimaryCluster().Get(connectorWrapper.OrgID, sddc.Id)
+		if err != nil {
+			return fmt.Errorf("error getting the primary cluster of SDDC %s: %w", sddc.Id, err)
+		}
+		for _, cluster := range sddc.ResourceConfig.Clusters {
+			if cluster.ClusterId == primaryCluster.ClusterId {
+				continue
+			}
+			log.Printf("[INFO] Deleting cluster %s of SDDC %s", cluster.ClusterId, sddc.Id)
+			d := resourceCluster().Data(nil)
+			d.SetId(cluster.ClusterId)
+			d.Set("sddc_id", sddc.Id)
+			if err := diagsErr(resourceClusterDelete(context.Background(), d, connectorWrapper)); err != nil {
+				return fmt.Errorf("error deleting cluster %s: %w", cluster.ClusterId, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sweepPublicIPs deletes the public IPs allocated by the acceptance tests on the SDDC they run
+// against and on the SDDCs they created.
+func sweepPublicIPs(connectorWrapper *connector.Wrapper, region string) error {
+	sddcs, err := sweptSddcs(connectorWrapper, region)
+	if err != nil {
+		return fmt.Errorf("error listing SDDCs: %w", err)
+	}
+	var nsxtReverseProxyURLs []string
+	if nsxtReverseProxyURL := os.Getenv(constants.NsxtReverseProxyURL); len(nsxtReverseProxyURL) > 0 {
+		nsxtReverseProxyURLs = append(nsxtReverseProxyURLs, nsxtReverseProxyURL)
+	}
+	for _, sddc := range sddcs {
+		if sddc.ResourceConfig != nil && sddc.ResourceConfig.NsxApiPublicEndpointUrl != nil {
+			nsxtReverseProxyURLs = append(nsxtReverseProxyURLs, *sddc.ResourceConfig.NsxApiPublicEndpointUrl)
+		}
+	}
+	for _, nsxtReverseProxyURL := range nsxtReverseProxyURLs {
+		nsxClient, err := api.NewClient(connectorWrapper).ForNsx(nsxtReverseProxyURL)
+		if err != nil {
+			return fmt.Errorf("error connecting to NSX at %s: %w", nsxtReverseProxyURL, err)
+		}
+		publicIPs, err := nsxClient.PublicIps().List(nil, nil, nil, nil, nil)
+		if err != nil {
+			return fmt.Errorf("error listing the public IPs at %s: %w", nsxtReverseProxyURL, err)
+		}
+		for _, publicIP := range publicIPs.Results {
+			if publicIP.Id == nil || publicIP.DisplayName == nil ||
+				!strings.HasPrefix(*publicIP.DisplayName, testResourcePrefix) {
+				continue
+			}
+			log.Printf("[INFO] Deleting public IP %s (%s)", *publicIP.DisplayName, *publicIP.Id)
+			d := resourcePublicIP().Data(nil)
+			d.SetId(*publicIP.Id)
+			d.Set("nsxt_reverse_proxy_url", nsxtReverseProxyURL)
+			if err := diagsErr(resourcePublicIPDelete(context.Background(), d, connectorWrapper)); err != nil {
+				return fmt.Errorf("error deleting public IP %s: %w", *publicIP.Id, err)
+			}
+		}
+	}
+	return nil
+}
+
+// sweepSddcGroups deletes the SDDC groups created by the acceptance tests. SDDC groups are not
+// regional, they are swept regardless of the region.
+func sweepSddcGroups(connectorWrapper *connector.Wrapper, _ string) error {
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
+	if err := sddcGroupsClient.Authenticate(); err != nil {
+		return err
+	}
+	configs, err := sddcGroupsClient.ListSddcGroups()
+	if err != nil {
+		return fmt.Errorf("error listing SDDC groups: %w", err)
+	}
+	for _, config := range configs {
+		if !strings.HasPrefix(config.Name, testResourcePrefix) {
+			continue
+		}
+		log.Printf("[INFO] Deleting SDDC group %s (%s)", config.Name, config.GroupID)
+		d := resourceSddcGroup().Data(nil)
+		d.SetId(config.GroupID)
+		if err := diagsErr(resourceSddcGroupRead(context.Background(), d, connectorWrapper)); err != nil {
+			return fmt.Errorf("error reading SDDC group %s: %w", config.GroupID, err)
+		}
+		if d.Get("deleted").(bool) {
+			continue
+		}
+		if err := diagsErr(resourceSddcGroupDelete(context.Background(), d, connectorWrapper)); err != nil {
+			return fmt.Errorf("error deleting SDDC group %s: %w", config.GroupID, err)
+		}
+	}
+	return nil
+}
+
+// sweepSiteRecoveries deactivates the site recovery activated by the acceptance tests on the SDDC
+// they run against and on the SDDCs they created.
+func sweepSiteRecoveries(connectorWrapper *connector.Wrapper, region string) error {
+	sddcs, err := sweptSddcs(connectorWrapper, region)
+	if err != nil {
+		return fmt.Errorf("error listing SDDCs: %w", err)
+	}
+	var sddcIDs []string
+	if sddcID := os.Getenv(constants.TestSddcID); len(sddcID) > 0 {
+		sddcIDs = append(sddcIDs, sddcID)
+	}
+	for _, sddc := range sddcs {
+		sddcIDs = append(sddcIDs, sddc.Id)
+	}
+	for _, sddcID := range sddcIDs {
+		draasClient := api.NewClient(connectorWrapper)
+		siteRecovery, err := draasClient.SiteRecovery().Get(draasClient.OrgID(), sddcID)
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return fmt.Errorf("error getting the site recovery of SDDC %s: %w", sddcID, err)
+		}
+		if siteRecovery.SiteRecoveryState == nil ||
+			*siteRecovery.SiteRecoveryState == draasmodel.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATED {
+			continue
+		}
+		log.Printf("[INFO] Deactivating site recovery of SDDC %s", sddcID)
+		d := resourceSiteRecovery().Data(nil)
+		d.SetId(sddcID)
+		d.Set("sddc_id", sddcID)
+		if err := diagsErr(resourceSiteRecoveryDelete(context.Background(), d, connectorWrapper)); err != nil {
+			return fmt.Errorf("error deactivating the site recovery of SDDC %s: %w", sddcID, err)
+		}
+	}
+	return nil
+}
+
+func TestSweepersSimulator(t *testing.T) {
+	server, connectorWrapper := newTestSimulator(t)
+	t.Setenv(constants.TestSddcID, server.AddSddc(simulator.SddcConfig{Name: "terraform_long_lived_sddc"}))
+	t.Setenv(constants.NsxtReverseProxyURL, "")
+	testSddcID := server.AddSddc(simulator.SddcConfig{Name: "terraform_test_sddc_swept"})
+	otherRegionSddcID := server.AddSddc(simulator.SddcConfig{Name: "terraform_test_sddc_frankfurt", Region: "EU_CENTRAL_1"})
+	userSddcID := server.AddSddc(simulator.SddcConfig{Name: "production"})
+
+	clusterData := resourceCluster().Data(nil)
+	clusterData.Set("sddc_id", testSddcID)
+	clusterData.Set("num_hosts", 2)
+	assert.NoError(t, diagsErr(resourceClusterCreate(context.Background(), clusterData, connectorWrapper)))
+	for _, publicIP := range []struct{ sddcID, displayName string }{
+		{testSddcID, "terraform_test_public_ip_swept"}, {testSddcID, "kept"}, {userSddcID, "terraform_test_public_ip_kept"},
+	} {
+		publicIPData := resourcePublicIP().Data(nil)
+		publicIPData.Set("nsxt_reverse_proxy_url", server.NsxtReverseProxyURL(publicIP.sddcID))
+		publicIPData.Set("display_name", publicIP.displayName)
+		assert.NoError(t, diagsErr(resourcePublicIPCreate(context.Background(), publicIPData, connectorWrapper)))
+	}
+	siteRecoveryData := resourceSiteRecovery().Data(nil)
+	siteRecoveryData.Set("sddc_id", os.Getenv(constants.TestSddcID))
+	assert.NoError(t, diagsErr(resourceSiteRecoveryCreate(context.Background(), siteRecoveryData, connectorWrapper)))
+	groupIDs := map[string]string{}
+	for _, name := range []string{"terraform_test_sddc_group_swept", "production"} {
+		groupData := resourceSddcGroup().Data(nil)
+		groupData.Set("name", name)
+		groupData.Set("sddc_member_ids", []interface{}{userSddcID})
+		assert.NoError(t, diagsErr(resourceSddcGroupCreate(context.Background(), groupData, connectorWrapper)))
+		groupIDs[name] = groupData.Id()
+	}
+
+	for _, sweep := range []func(*connector.Wrapper, string) error{
+		sweepClusters, sweepPublicIPs, sweepSddcGroups, sweepSiteRecoveries, sweepSddcs} {
+		assert.NoError(t, sweep(connectorWrapper, "us-west-2"))
+	}
+
+	assert.Equal(t, 0, server.ClusterHostCount(testSddcID, clusterData.Id()))
+	assert.Equal(t, 1, server.PublicIPCount(testSddcID))
+	assert.Equal(t, 1, server.PublicIPCount(userSddcID))
+	assert.Equal(t, draasmodel.SiteRecovery_SITE_RECOVERY_STATE_DEACTIVATED, server.SiteRecoveryState(os.Getenv(constants.TestSddcID)))
+	assert.Equal(t, model.Sddc_SDDC_STATE_DELETED, server.SddcState(testSddcID))
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, server.SddcState(os.Getenv(constants.TestSddcID)))
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, server.SddcState(otherRegionSddcID))
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, server.SddcState(userSddcID))
+
+	sddcGroupsClient := api.NewClient(connectorWrapper).SddcGroups()
+	assert.NoError(t, sddcGroupsClient.Authenticate())
+	configs, err := sddcGroupsClient.ListSddcGroups()
+	assert.NoError(t, err)
+	if assert.Len(t, configs, 1) {
+		assert.Equal(t, groupIDs["production"], configs[0].GroupID)
+	}
+
+	// All regions are swept with "all"
+	assert.NoError(t, sweepSddcs(connectorWrapper, "all"))
+	assert.Equal(t, model.Sddc_SDDC_STATE_DELETED, server.SddcState(otherRegionSddcID))
+	assert.Equal(t, model.Sddc_SDDC_STATE_READY, server.SddcState(userSddcID))
+}
@@ -4,24 +4,72 @@
 package task
 
 import (
+	"fmt"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
-	autoscalerapi "github.com/vmware/vsphere-automation-sdk-go/services/vmc/autoscaler/api"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas"
+	"github.com/vmware/terraform-provider-vmc/vmc/internal/api"
+	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs"
 )
 
-// The following functions create API clients and poll for tasks with the provided ID.
-// They are very difficult to unit-test as the API clients don't implement any interface, so
-// passing in (stubbed) API client as a parameter of the functions is not possible.
-// This concern has been raised with the owners of the VMC SDKs.
+// The following functions obtain API clients from the api facade and poll for tasks with
+// the provided ID.
 
 // GetTask returns a model.Task with specified ID
 func GetTask(connectorWrapper *connector.Wrapper, taskID string) (model.Task, error) {
-	tasksClient := orgs.NewTasksClient(connectorWrapper)
+	tasksClient := api.NewClient(connectorWrapper).Tasks()
 	return tasksClient.Get(connectorWrapper.OrgID, taskID)
 }
 
+// GetSubTasks returns the tasks, that were spawned by the task with the specified ID, e.g.
+// the per-host tasks of a cluster creation task.
+func GetSubTasks(connectorWrapper *connector.Wrapper, parentTaskID string) ([]model.Task, error) {
+	tasksClient := api.NewClient(connectorWrapper).Tasks()
+	filter := fmt.Sprintf("(parent_task_id eq '%s')", parentTaskID)
+	tasks, err := tasksClient.List(connectorWrapper.OrgID, &filter)
+	if err != nil {
+		return nil, err
+	}
+	// Do not rely on the service honoring the filter
+	var subTasks []model.Task
+	for _, subTask := range tasks {
+		if subTask.ParentTaskId != nil && *subTask.ParentTaskId == parentTaskID {
+			subTasks = append(subTasks, subTask)
+		}
+	}
+	return subTasks, nil
+}
+
+// GetInProgressTasks looks up the started VMC tasks of the specified type, that act on the resource
+// with the specified ID, e.g. an SDDC creation task started by an interrupted apply.
+func GetInProgressTasks(connectorWrapper *connector.Wrapper, resourceID string, taskType string) ([]model.Task, error) {
+	tasksClient := api.NewClient(connectorWrapper).Tasks()
+	filter := fmt.Sprintf("(resource_id eq '%s')", resourceID)
+	tasks, err := tasksClient.List(connectorWrapper.OrgID, &filter)
+	if err != nil {
+		return nil, err
+	}
+	return findInProgressTasks(tasks, resourceID, taskType), nil
+}
+
+// findInProgressTasks returns the tasks from the list, that are of the specified type, act on the
+// resource with the specified ID and are not yet in a terminal state.
+func findInProgressTasks(tasks []model.Task, resourceID string, taskType string) []model.Task {
+	inProgressTasks := []model.Task{}
+	// Do not rely on the service honoring the filter
+	for _, vmcTask := range tasks {
+		if vmcTask.ResourceId == nil || *vmcTask.ResourceId != resourceID {
+			continue
+		}
+		if vmcTask.TaskType == nil || *vmcTask.TaskType != taskType {
+			continue
+		}
+		if vmcTask.Status != nil && *vmcTask.Status == model.Task_STATUS_STARTED {
+			inProgressTasks = append(inProgressTasks, vmcTask)
+		}
+	}
+	return inProgressTasks
+}
+
 // GetV2Task returns an adapted model.Task with specified ID
 func GetV2Task(connectorWrapper *connector.Wrapper, taskID string) (model.Task, error) {
 	tasksV2Client := NewV2ClientImpl(*connectorWrapper)
@@ -48,7 +96,7 @@ func GetV2Task(connectorWrapper *connector.Wrapper, taskID string) (model.Task,
 
 // GetAutoscalerTask polls autoscalerapi for task with specified ID and converts it to model.Task
 func GetAutoscalerTask(connectorWrapper *connector.Wrapper, taskID string) (model.Task, error) {
-	tasksClient := autoscalerapi.NewAutoscalerClient(connectorWrapper)
+	tasksClient := api.NewClient(connectorWrapper).AutoscalerTasks()
 	autoscalerTask, err := tasksClient.Get(connectorWrapper.OrgID, taskID)
 	// Commented out fields do not exist in the autoscalerapi task
 	return model.Task{
@@ -85,8 +133,72 @@ func GetAutoscalerTask(connectorWrapper *connector.Wrapper, taskID string) (mode
 
 // GetDraasTask polls draas API for task with specified ID and converts it to model.Task
 func GetDraasTask(connectorWrapper *connector.Wrapper, taskID string) (model.Task, error) {
-	tasksClient := draas.NewTaskClient(connectorWrapper)
+	tasksClient := api.NewClient(connectorWrapper).DraasTasks()
 	draasTask, err := tasksClient.Get(connectorWrapper.OrgID, taskID)
+	return convertDraasTask(draasTask), err
+}
+
+// GetInProgressDraasTask looks up a started draas task acting on the resource with the
+// specified ID. Returns nil if no such task is found.
+func GetInProgressDraasTask(connectorWrapper *connector.Wrapper, resourceID string) (*model.Task, error) {
+	tasksClient := api.NewClient(connectorWrapper).DraasTasks()
+	filter := fmt.Sprintf("(resource_id eq '%s')", resourceID)
+	draasTasks, err := tasksClient.List(connectorWrapper.OrgID, &filter)
+	if err != nil {
+		return nil, err
+	}
+	inProgressTask := findInProgressDraasTask(draasTasks, resourceID)
+	if inProgressTask == nil {
+		return nil, nil
+	}
+	convertedTask := convertDraasTask(*inProgressTask)
+	return &convertedTask, nil
+}
+
+// GetInProgressDraasTasks looks up the started draas tasks acting on any of the resources with
+// the specified IDs.
+func GetInProgressDraasTasks(connectorWrapper *connector.Wrapper, resourceIDs []string) ([]model.Task, error) {
+	tasksClient := api.NewClient(connectorWrapper).DraasTasks()
+	filter := fmt.Sprintf("(status eq '%s')", draasmodel.Task_STATUS_STARTED)
+	draasTasks, err := tasksClient.List(connectorWrapper.OrgID, &filter)
+	if err != nil {
+		return nil, err
+	}
+	return findInProgressDraasTasks(draasTasks, resourceIDs), nil
+}
+
+// findInProgressDraasTasks returns the tasks from the list, that act on any of the resources
+// with the specified IDs and are not yet in a terminal state.
+func findInProgressDraasTasks(draasTasks []draasmodel.Task, resourceIDs []string) []model.Task {
+	inProgressTasks := []model.Task{}
+	for _, resourceID := range resourceIDs {
+		for _, draasTask := range draasTasks {
+			if draasTask.ResourceId == nil || *draasTask.ResourceId != resourceID {
+				continue
+			}
+			if draasTask.Status != nil && *draasTask.Status == draasmodel.Task_STATUS_STARTED {
+				inProgressTasks = append(inProgressTasks, convertDraasTask(draasTask))
+			}
+		}
+	}
+	return inProgressTasks
+}
+
+// findInProgressDraasTask returns the first task from the list, that acts on the resource
+// with the specified ID and is not yet in a terminal state.
+func findInProgressDraasTask(draasTasks []draasmodel.Task, resourceID string) *draasmodel.Task {
+	for i, draasTask := range draasTasks {
+		if draasTask.ResourceId == nil || *draasTask.ResourceId != resourceID {
+			continue
+		}
+		if draasTask.Status != nil && *draasTask.Status == draasmodel.Task_STATUS_STARTED {
+			return &draasTasks[i]
+		}
+	}
+	return nil
+}
+
+func convertDraasTask(draasTask draasmodel.Task) model.Task {
 	// Commented out fields do not exist in draas API Task
 	return model.Task{
 		Updated:               draasTask.Updated,
@@ -117,5 +229,5 @@ func GetDraasTask(connectorWrapper *connector.Wrapper, taskID string) (model.Tas
 		//PhaseInProgress
 		ResourceType: draasTask.ResourceType,
 		EndTime:      draasTask.EndTime,
-	}, err
+	}
 }
@@ -0,0 +1,83 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package task
+
+import (
+	"github.com/stretchr/testify/assert"
+	draasmodel "github.com/vmware/vsphere-automation-sdk-go/services/vmc/draas/model"
+	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
+	"testing"
+)
+
+func TestFindInProgressDraasTask(t *testing.T) {
+	sddcID := "sddc-1"
+	otherSddcID := "sddc-2"
+	started := draasmodel.Task_STATUS_STARTED
+	finished := draasmodel.Task_STATUS_FINISHED
+	type test struct {
+		input []draasmodel.Task
+		want  string
+	}
+	tests := []test{
+		{input: nil, want: ""},
+		{input: []draasmodel.Task{{Id: "no-resource", Status: &started}}, want: ""},
+		{input: []draasmodel.Task{{Id: "other-sddc", ResourceId: &otherSddcID, Status: &started}}, want: ""},
+		{input: []draasmodel.Task{{Id: "finished", ResourceId: &sddcID, Status: &finished}}, want: ""},
+		{input: []draasmodel.Task{
+			{Id: "finished", ResourceId: &sddcID, Status: &finished},
+			{Id: "other-sddc", ResourceId: &otherSddcID, Status: &started},
+			{Id: "started", ResourceId: &sddcID, Status: &started},
+		}, want: "started"},
+	}
+	for _, testCase := range tests {
+		got := findInProgressDraasTask(testCase.input, sddcID)
+		if testCase.want == "" {
+			assert.Nil(t, got)
+		} else {
+			assert.Equal(t, testCase.want, got.Id)
+		}
+	}
+}
+
+func TestFindInProgressDraasTasks(t *testing.T) {
+	sddcID := "sddc-1"
+	srmNodeID := "srm-node-1"
+	otherSrmNodeID := "srm-node-2"
+	started := draasmodel.Task_STATUS_STARTED
+	finished := draasmodel.Task_STATUS_FINISHED
+	draasTasks := []draasmodel.Task{
+		{Id: "activation", ResourceId: &sddcID, Status: &finished},
+		{Id: "provision-1", ResourceId: &srmNodeID, Status: &started},
+		{Id: "provision-2", ResourceId: &otherSrmNodeID, Status: &started},
+		{Id: "no-resource", Status: &started},
+	}
+
+	got := findInProgressDraasTasks(draasTasks, []string{sddcID, srmNodeID})
+	assert.Len(t, got, 1)
+	assert.Equal(t, "provision-1", got[0].Id)
+	assert.Empty(t, findInProgressDraasTasks(draasTasks, []string{sddcID}))
+	assert.Empty(t, findInProgressDraasTasks(nil, []string{sddcID}))
+}
+
+func TestFindInProgressTasks(t *testing.T) {
+	sddcID := "sddc-1"
+	otherSddcID := "sddc-2"
+	provision := "SDDC-PROVISION"
+	deletion := "SDDC-DELETE"
+	started := model.Task_STATUS_STARTED
+	finished := model.Task_STATUS_FINISHED
+	tasks := []model.Task{
+		{Id: "finished", ResourceId: &sddcID, TaskType: &provision, Status: &finished},
+		{Id: "other-sddc", ResourceId: &otherSddcID, TaskType: &provision, Status: &started},
+		{Id: "delete", ResourceId: &sddcID, TaskType: &deletion, Status: &started},
+		{Id: "no-type", ResourceId: &sddcID, Status: &started},
+		{Id: "provision", ResourceId: &sddcID, TaskType: &provision, Status: &started},
+	}
+
+	got := findInProgressTasks(tasks, sddcID, provision)
+	assert.Len(t, got, 1)
+	assert.Equal(t, "provision", got[0].Id)
+	assert.Empty(t, findInProgressTasks(tasks, otherSddcID, deletion))
+	assert.Empty(t, findInProgressTasks(nil, sddcID, provision))
+}
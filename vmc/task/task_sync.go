@@ -4,13 +4,17 @@
 package task
 
 import (
+	"context"
 	"fmt"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/vsphere-automation-sdk-go/lib/vapi/std/errors"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
 	"log"
+	"strings"
 	"sync"
+	"time"
 )
 
 // KeyedMutex Mutex that operates multiple locks, based  on a string key.
@@ -19,7 +23,9 @@ type KeyedMutex struct {
 }
 
 // Lock Locks on a key, allowing multiple threads to operate on separate keys. Returns
-// a function, that clients should use to unlock the locks they've obtained.
+// a function, that clients should use to unlock the locks they've obtained. The returned
+// function is safe to be called more than once, so clients can defer it right after locking
+// and still release the lock earlier, e.g. as soon as a task finishes.
 func (keyedMutex *KeyedMutex) Lock(key string) func() {
 	value, _ := keyedMutex.mutexes.LoadOrStore(key, &sync.Mutex{})
 	mutex := value.(*sync.Mutex)
@@ -27,8 +33,9 @@ func (keyedMutex *KeyedMutex) Lock(key string) func() {
 
 	// Encapsulate the access to the underlying mutex, but allow clients to unlock the
 	// mutex they've just locked on.
+	var once sync.Once
 	return func() {
-		mutex.Unlock()
+		once.Do(mutex.Unlock)
 	}
 }
 
@@ -38,6 +45,45 @@ var serviceUnavailableRetries = 0
 // max amount of retries for "service unavailable" errors before giving up
 var maxServiceUnavailableRetries = 20
 
+// RetryContext calls f until it succeeds, fails with a non-retryable error, the timeout elapses or
+// the context is canceled, like resource.RetryContext does. Instead of the fixed cadence of
+// resource.RetryContext, the delay between the calls starts at the poll interval of the config and is
+// doubled after each call, up to its maximum poll interval, with jitter. This spares the API from
+// being polled in lockstep by large parallel applies, as well as multi-hour tasks from being polled
+// needlessly often. Like resource.RetryContext, a *resource.TimeoutError with the last retryable error
+// is returned on timeout. On cancellation the error of the context is returned, wrapped with the last
+// retryable error.
+func RetryContext(ctx context.Context, config connector.TaskPollConfig, timeout time.Duration,
+	f resource.RetryFunc) error {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for poll := 0; ; poll++ {
+		retryErr := f()
+		if retryErr == nil {
+			return nil
+		}
+		if !retryErr.Retryable {
+			return retryErr.Err
+		}
+		lastErr = retryErr.Err
+		delay := config.Delay(poll)
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return &resource.TimeoutError{LastError: lastErr, Timeout: timeout}
+		}
+		if delay > remaining {
+			delay = remaining
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("%w, last error: %v", ctx.Err(), lastErr)
+		case <-timer.C:
+		}
+	}
+}
+
 // RetryTaskUntilFinished function that will poll (using provided task supplier) for a
 // task state until a non-recoverable error is encountered, like task failure or
 // authentication error or until the task finishes. An option to execute a callback after task
@@ -48,18 +94,6 @@ func RetryTaskUntilFinished(authenticator connector.Authenticator,
 	finishCallback func(task model.Task)) *resource.RetryError {
 	task, err := taskSupplier()
 	if err != nil {
-		// Try to reauthenticate (if access token expired)
-		if err.Error() == (errors.Unauthenticated{}.Error()) {
-			log.Printf("Authentication error : %v", errors.Unauthenticated{}.Error())
-			err = authenticator.Authenticate()
-			if err != nil {
-				if finishCallback != nil {
-					finishCallback(task)
-				}
-				return resource.NonRetryableError(fmt.Errorf("authentication error from Cloud Service Provider : %v", err))
-			}
-			return resource.RetryableError(fmt.Errorf("task still in progress"))
-		}
 		// Best-effort resiliency in case of difficulties the VMC service may experience,
 		// during long-running tasks
 		if err.Error() == (errors.ServiceUnavailable{}.Error()) {
@@ -85,6 +119,7 @@ func RetryTaskUntilFinished(authenticator connector.Authenticator,
 	if serviceUnavailableRetries > 0 {
 		serviceUnavailableRetries = 0
 	}
+	logTaskPoll(authenticator, task)
 	if *task.Status == "" {
 		if finishCallback != nil {
 			finishCallback(task)
@@ -96,6 +131,7 @@ func RetryTaskUntilFinished(authenticator connector.Authenticator,
 		}
 		return resource.NonRetryableError(fmt.Errorf("task failed: "+errorMessage+": %s", *task.ErrorMessage))
 	} else if *task.Status != model.Task_STATUS_FINISHED {
+		logTaskProgress(authenticator, task)
 		return resource.RetryableError(fmt.Errorf("expected task type: %s to be finished %s", *task.TaskType, *task.Status))
 	}
 	if finishCallback != nil {
@@ -103,3 +139,144 @@ func RetryTaskUntilFinished(authenticator connector.Authenticator,
 	}
 	return nil
 }
+
+// logTaskPoll logs the status of a polled task, together with its correlation ID, which VMware
+// support needs to look into tasks, that are stuck or failed.
+func logTaskPoll(authenticator connector.Authenticator, task model.Task) {
+	ctx := context.Background()
+	if wrapper, ok := authenticator.(*connector.Wrapper); ok {
+		ctx = wrapper.LogContext()
+	}
+	fields := map[string]interface{}{"task_id": task.Id}
+	for key, value := range map[string]*string{
+		"task_type":          task.TaskType,
+		"task_status":        task.Status,
+		"task_sub_status":    task.SubStatus,
+		"task_phase":         task.PhaseInProgress,
+		"task_resource_id":   task.ResourceId,
+		"correlation_id":     task.CorrelationId,
+		"task_error_message": task.ErrorMessage,
+	} {
+		if value != nil && len(*value) > 0 {
+			fields[key] = *value
+		}
+	}
+	if task.ProgressPercent != nil {
+		fields["task_progress_percent"] = *task.ProgressPercent
+	}
+	tflog.Debug(ctx, "Polled VMC task", fields)
+}
+
+// logTaskProgress logs the progress of a task, that is still in progress, at INFO level, so that
+// operators watching a long-running apply, e.g. an SDDC creation, get feedback on each poll.
+func logTaskProgress(authenticator connector.Authenticator, task model.Task) {
+	ctx := context.Background()
+	if wrapper, ok := authenticator.(*connector.Wrapper); ok {
+		ctx = wrapper.LogContext()
+	}
+	taskType := "VMC"
+	if task.TaskType != nil && len(*task.TaskType) > 0 {
+		taskType = *task.TaskType
+	}
+	tflog.Info(ctx, fmt.Sprintf("Waiting for %s task %s to finish: %s", taskType, task.Id, DescribeProgress(task)),
+		map[string]interface{}{"task_id": task.Id})
+}
+
+// DescribeProgress describes the progress of a task, e.g. "45% complete, phase: DEPLOY_HOSTS,
+// about 80 minutes remaining", from the progress details the task provides.
+func DescribeProgress(task model.Task) string {
+	var details []string
+	if task.ProgressPercent != nil {
+		details = append(details, fmt.Sprintf("%d%% complete", *task.ProgressPercent))
+	}
+	if phase := ProvisioningPhase(task); len(phase) > 0 {
+		details = append(details, "phase: "+phase)
+	}
+	// Negative estimates mean the task cannot estimate its remaining time
+	if task.EstimatedRemainingMinutes != nil && *task.EstimatedRemainingMinutes >= 0 {
+		details = append(details, fmt.Sprintf("about %d minutes remaining", *task.EstimatedRemainingMinutes))
+	}
+	if len(details) == 0 {
+		return "no progress reported"
+	}
+	return strings.Join(details, ", ")
+}
+
+// ProvisioningPhase returns the phase a task is in, falling back to its sub-status for tasks, that do
+// not report their phase, like the DRaaS ones. Returns an empty string if the task reports neither.
+func ProvisioningPhase(task model.Task) string {
+	if task.PhaseInProgress != nil && len(*task.PhaseInProgress) > 0 {
+		return *task.PhaseInProgress
+	}
+	if task.SubStatus != nil {
+		return *task.SubStatus
+	}
+	return ""
+}
+
+// WithSubTaskStatus enriches the outcome of a RetryTaskUntilFinished call with the status of the
+// sub-tasks of the polled task. While the task is in progress the sub-task status is logged, and
+// once the task fails, the failed sub-tasks are added to the error, so that failures like a single
+// host failing provisioning are visible without looking them up in the VMC console.
+func WithSubTaskStatus(retryErr *resource.RetryError, subTasksSupplier func() ([]model.Task, error),
+	taskID string) *resource.RetryError {
+	if retryErr == nil {
+		return nil
+	}
+	subTasks, err := subTasksSupplier()
+	if err != nil {
+		log.Printf("[DEBUG] Unable to get the sub-tasks of task %s: %v", taskID, err)
+		return retryErr
+	}
+	if len(subTasks) == 0 {
+		return retryErr
+	}
+	summary := SummarizeSubTasks(subTasks)
+	if retryErr.Retryable {
+		log.Printf("[INFO] Task %s: %s", taskID, summary)
+		return retryErr
+	}
+	return resource.NonRetryableError(fmt.Errorf("%v\n%s", retryErr.Err, summary))
+}
+
+// SummarizeSubTasks describes the progress of a set of sub-tasks, listing the failed ones.
+func SummarizeSubTasks(subTasks []model.Task) string {
+	var finished, failed, inProgress int
+	var failures []string
+	for _, subTask := range subTasks {
+		status := ""
+		if subTask.Status != nil {
+			status = *subTask.Status
+		}
+		switch status {
+		case model.Task_STATUS_FINISHED:
+			finished++
+		case model.Task_STATUS_FAILED, model.Task_STATUS_CANCELED:
+			failed++
+			failures = append(failures, describeFailedSubTask(subTask, status))
+		default:
+			inProgress++
+		}
+	}
+	summary := fmt.Sprintf("%d of %d sub-tasks failed, %d finished, %d in progress",
+		failed, len(subTasks), finished, inProgress)
+	if len(failures) > 0 {
+		summary += "\n" + strings.Join(failures, "\n")
+	}
+	return summary
+}
+
+func describeFailedSubTask(subTask model.Task, status string) string {
+	description := "  - sub-task " + subTask.Id
+	if subTask.TaskType != nil && len(*subTask.TaskType) > 0 {
+		description += " (" + *subTask.TaskType + ")"
+	}
+	if subTask.ResourceId != nil && len(*subTask.ResourceId) > 0 {
+		description += " on " + *subTask.ResourceId
+	}
+	description += " " + strings.ToLower(status)
+	if subTask.ErrorMessage != nil && len(*subTask.ErrorMessage) > 0 {
+		description += ": " + *subTask.ErrorMessage
+	}
+	return description
+}
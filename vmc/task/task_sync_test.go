@@ -4,6 +4,7 @@
 package task
 
 import (
+	"context"
 	"fmt"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 	"github.com/stretchr/testify/assert"
@@ -14,48 +15,79 @@ import (
 	"time"
 )
 
+// lockInBackground locks the key in a separate goroutine, returning a channel, that receives the unlock
+// function once the lock is obtained.
+func lockInBackground(keyedMutex *KeyedMutex, key string) <-chan func() {
+	obtained := make(chan func(), 1)
+	go func() {
+		obtained <- keyedMutex.Lock(key)
+	}()
+	return obtained
+}
+
+// awaitLock returns the unlock function of the lock, nil if it is not obtained within the timeout.
+func awaitLock(obtained <-chan func(), timeout time.Duration) func() {
+	select {
+	case unlock := <-obtained:
+		return unlock
+	case <-time.After(timeout):
+		return nil
+	}
+}
+
 func TestKeyedMutexLock(t *testing.T) {
 	var keyedMutex = KeyedMutex{}
 	var key1 = "key1"
 	var key2 = "key2"
 
-	lock1Obtained := false
-	lock2Obtained := false
-
 	var unlockFunction = keyedMutex.Lock(key1)
-	// Try to flip the flag 1 in a separate thread
-	go func() {
-		keyedMutex.Lock(key1)
-		lock1Obtained = true
-	}()
-	// Try to flip the flag 2 in a separate thread
-	go func() {
-		keyedMutex.Lock(key2)
-		lock2Obtained = true
-	}()
+	lock1Obtained := lockInBackground(&keyedMutex, key1)
+	lock2Obtained := lockInBackground(&keyedMutex, key2)
 
-	// Give enough time for the separate threads to try to flip the flags
-	time.Sleep(500 * time.Millisecond)
-	assert.False(t, lock1Obtained)
-	assert.True(t, lock2Obtained)
+	// The lock on another key is obtained right away, the one on the locked key is not
+	unlock2 := awaitLock(lock2Obtained, 5*time.Second)
+	assert.NotNil(t, unlock2)
+	assert.Nil(t, awaitLock(lock1Obtained, 200*time.Millisecond))
 	// Test the unlock functionality
 	unlockFunction()
-	time.Sleep(500 * time.Millisecond)
-	assert.True(t, lock1Obtained)
-}
+	unlock1 := awaitLock(lock1Obtained, 5*time.Second)
+	assert.NotNil(t, unlock1)
 
-type AuthenticatorStub struct {
+	if unlock1 != nil {
+		unlock1()
+	}
+	if unlock2 != nil {
+		unlock2()
+	}
 }
 
-func (stub AuthenticatorStub) Authenticate() error {
-	return nil
+func TestKeyedMutexUnlockIsIdempotent(t *testing.T) {
+	var keyedMutex = KeyedMutex{}
+	var key = "key"
+
+	unlockFunction := keyedMutex.Lock(key)
+	unlockFunction()
+	assert.NotPanics(t, unlockFunction)
+
+	// A second unlock from the first holder must not release a lock obtained by someone else
+	secondUnlockFunction := keyedMutex.Lock(key)
+	unlockFunction()
+	lockObtained := lockInBackground(&keyedMutex, key)
+	assert.Nil(t, awaitLock(lockObtained, 200*time.Millisecond))
+	secondUnlockFunction()
+	unlock := awaitLock(lockObtained, 5*time.Second)
+	assert.NotNil(t, unlock)
+
+	if unlock != nil {
+		unlock()
+	}
 }
 
-type BrokenAuthenticatorStub struct {
+type AuthenticatorStub struct {
 }
 
-func (stub BrokenAuthenticatorStub) Authenticate() error {
-	return fmt.Errorf("authentication broken")
+func (stub AuthenticatorStub) Authenticate() error {
+	return nil
 }
 
 func TestRetryTaskUntilFinished(t *testing.T) {
@@ -71,33 +103,19 @@ func TestRetryTaskUntilFinished(t *testing.T) {
 	}
 	var finishCallbackHasBeenCalled = false
 	tests := []test{
-		// Unauthenticated handling - retry authentication
+		// Unauthenticated handling - fail, the access token is refreshed by the transport of the connector
 		{
 			input: inputStruct{
 				connectorWrapper: AuthenticatorStub{},
-				taskSupplier: func() (model.Task, error) {
-					return model.Task{}, errors.Unauthenticated{}
-				},
-				errorMessage: "",
-				finishCallback: func(task model.Task) {
-					assert.Fail(t, "finishCallback should not be called on retrievable errors")
-				},
-			},
-			want: resource.RetryableError(fmt.Errorf("task still in progress")),
-		},
-		// Unauthenticated handling - fail
-		{
-			input: inputStruct{
-				connectorWrapper: BrokenAuthenticatorStub{},
 				taskSupplier: func() (model.Task, error) {
 					return model.Task{Id: "Unauthenticated handling - fail"}, errors.Unauthenticated{}
 				},
-				errorMessage: "",
+				errorMessage: "error polling task",
 				finishCallback: func(task model.Task) {
 					assert.Equal(t, "Unauthenticated handling - fail", task.Id)
 				},
 			},
-			want: resource.NonRetryableError(fmt.Errorf("authentication error from Cloud Service Provider : authentication broken")),
+			want: resource.NonRetryableError(fmt.Errorf("error polling task: %v", errors.Unauthenticated{})),
 		},
 		// Service unavailable retry
 		{
@@ -206,3 +224,90 @@ func TestRetryTaskUntilFinished(t *testing.T) {
 	}
 	assert.Equal(t, finishCallbackHasBeenCalled, true)
 }
+
+func TestRetryContext(t *testing.T) {
+	config := connector.TaskPollConfig{Interval: time.Millisecond, MaxInterval: 4 * time.Millisecond}
+
+	calls := 0
+	err := RetryContext(context.Background(), config, time.Minute, func() *resource.RetryError {
+		calls++
+		if calls < 5 {
+			return resource.RetryableError(fmt.Errorf("task still in progress"))
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, 5, calls)
+
+	err = RetryContext(context.Background(), config, time.Minute, func() *resource.RetryError {
+		return resource.NonRetryableError(fmt.Errorf("task failed"))
+	})
+	assert.EqualError(t, err, "task failed")
+
+	// The last retryable error is returned on timeout and cancellation
+	err = RetryContext(context.Background(), config, 20*time.Millisecond, func() *resource.RetryError {
+		return resource.RetryableError(fmt.Errorf("task still in progress"))
+	})
+	var timeoutErr *resource.TimeoutError
+	if assert.ErrorAs(t, err, &timeoutErr) {
+		assert.EqualError(t, timeoutErr.LastError, "task still in progress")
+		assert.Equal(t, 20*time.Millisecond, timeoutErr.Timeout)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err = RetryContext(ctx, connector.TaskPollConfig{Interval: time.Hour, MaxInterval: time.Hour}, time.Hour,
+		func() *resource.RetryError {
+			return resource.RetryableError(fmt.Errorf("task still in progress"))
+		})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorContains(t, err, "task still in progress")
+}
+
+func TestSummarizeSubTasks(t *testing.T) {
+	newSubTask := func(id string, status string, errorMessage string) model.Task {
+		taskType := "HOST-PROVISION"
+		resourceID := "host-" + id
+		return model.Task{Id: id, Status: &status, TaskType: &taskType, ResourceId: &resourceID, ErrorMessage: &errorMessage}
+	}
+	subTasks := []model.Task{
+		newSubTask("1", model.Task_STATUS_FINISHED, ""),
+		newSubTask("2", model.Task_STATUS_FAILED, "no capacity"),
+		newSubTask("3", model.Task_STATUS_STARTED, ""),
+		newSubTask("4", model.Task_STATUS_FINISHED, ""),
+	}
+	assert.Equal(t, "1 of 4 sub-tasks failed, 2 finished, 1 in progress\n"+
+		"  - sub-task 2 (HOST-PROVISION) on host-2 failed: no capacity", SummarizeSubTasks(subTasks))
+}
+
+func TestWithSubTaskStatus(t *testing.T) {
+	failed := model.Task_STATUS_FAILED
+	subTasks := []model.Task{{Id: "1", Status: &failed}}
+	subTasksSupplier := func() ([]model.Task, error) {
+		return subTasks, nil
+	}
+	brokenSubTasksSupplier := func() ([]model.Task, error) {
+		return nil, fmt.Errorf("sub-tasks unavailable")
+	}
+	taskFailure := resource.NonRetryableError(fmt.Errorf("task failed"))
+	inProgress := resource.RetryableError(fmt.Errorf("task in progress"))
+
+	assert.Nil(t, WithSubTaskStatus(nil, subTasksSupplier, "task"))
+	assert.Equal(t, inProgress, WithSubTaskStatus(inProgress, subTasksSupplier, "task"))
+	assert.Equal(t, taskFailure, WithSubTaskStatus(taskFailure, brokenSubTasksSupplier, "task"))
+	assert.Equal(t, resource.NonRetryableError(fmt.Errorf("task failed\n"+SummarizeSubTasks(subTasks))),
+		WithSubTaskStatus(taskFailure, subTasksSupplier, "task"))
+}
+
+func TestDescribeProgress(t *testing.T) {
+	percent := int64(45)
+	phase := "DEPLOY_HOSTS"
+	subStatus := "DEPLOYING_APPLIANCE"
+	remaining := int64(80)
+	unknownRemaining := int64(-1)
+
+	assert.Equal(t, "no progress reported", DescribeProgress(model.Task{}))
+	assert.Equal(t, "45% complete, phase: DEPLOY_HOSTS, about 80 minutes remaining", DescribeProgress(model.Task{
+		ProgressPercent: &percent, PhaseInProgress: &phase, SubStatus: &subStatus, EstimatedRemainingMinutes: &remaining}))
+	assert.Equal(t, "phase: DEPLOYING_APPLIANCE", DescribeProgress(model.Task{
+		SubStatus: &subStatus, EstimatedRemainingMinutes: &unknownRemaining}))
+}
@@ -47,7 +47,7 @@ func NewV2ClientImpl(wrapper connector.Wrapper) *V2ClientImpl {
 	copyWrapper := connector.CopyWrapper(wrapper)
 	return &V2ClientImpl{
 		connector:  *copyWrapper,
-		HTTPClient: http.DefaultClient,
+		HTTPClient: copyWrapper.HTTPClient(),
 	}
 }
 
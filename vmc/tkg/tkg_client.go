@@ -0,0 +1,115 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+// Package tkg provides a client of the workload control plane (WCP) APIs, that activate
+// Tanzu Kubernetes Grid on the clusters of an SDDC. These APIs are not covered by the VMC SDK.
+package tkg
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
+	"github.com/vmware/vsphere-automation-sdk-go/runtime/security"
+)
+
+type Client interface {
+	connector.Authenticator
+	ValidateNetwork(sddcID string, clusterID string, config NetworkConfig) (taskID string, err error)
+	Enable(sddcID string, clusterID string, config NetworkConfig) (taskID string, err error)
+	Disable(sddcID string, clusterID string) (taskID string, err error)
+}
+
+type ClientImpl struct {
+	connector  connector.Wrapper
+	httpClient connector.HTTPClient
+}
+
+func NewTkgClient(wrapper connector.Wrapper) *ClientImpl {
+	copyWrapper := connector.CopyWrapper(wrapper)
+	return &ClientImpl{
+		connector:  *copyWrapper,
+		httpClient: copyWrapper.HTTPClient(),
+	}
+}
+
+// newTestTkgClient intended for injecting dummy accessToken and stubbed httpClient for
+// testing purposes.
+func newTestTkgClient(vmcURL string, orgID string, accessToken string, httpClient connector.HTTPClient) *ClientImpl {
+	testConnector := connector.Wrapper{
+		VmcURL: vmcURL,
+		OrgID:  orgID,
+	}
+	// Create a dummy connector to house the access token in a security context
+	testConnector.Connector = client.NewConnector("", client.WithHttpClient(&http.Client{}),
+		client.WithSecurityContext(security.NewOauthSecurityContext(accessToken)))
+	return &ClientImpl{
+		connector:  testConnector,
+		httpClient: httpClient,
+	}
+}
+
+// Authenticate grab an access token and set it into the Client instance for later use
+func (client *ClientImpl) Authenticate() error {
+	return client.connector.Authenticate()
+}
+
+// ValidateNetwork starts the validation of the networks of Tanzu Kubernetes Grid against the
+// networks of the SDDC, as required before activating it.
+func (client *ClientImpl) ValidateNetwork(sddcID string, clusterID string, config NetworkConfig) (string, error) {
+	return client.executeOperation(sddcID, clusterID, "validate-network", &config)
+}
+
+// Enable starts the activation of Tanzu Kubernetes Grid on the specified cluster.
+func (client *ClientImpl) Enable(sddcID string, clusterID string, config NetworkConfig) (string, error) {
+	return client.executeOperation(sddcID, clusterID, "enable-wcp", &config)
+}
+
+// Disable starts the deactivation of Tanzu Kubernetes Grid on the specified cluster.
+func (client *ClientImpl) Disable(sddcID string, clusterID string) (string, error) {
+	return client.executeOperation(sddcID, clusterID, "disable-wcp", nil)
+}
+
+// executeOperation sends a WCP operation and returns the ID of the VMC task tracking it.
+func (client *ClientImpl) executeOperation(sddcID string, clusterID string, operation string,
+	config *NetworkConfig) (string, error) {
+	var body io.Reader
+	if config != nil {
+		requestPayload, err := json.Marshal(config)
+		if err != nil {
+			return "", err
+		}
+		body = bytes.NewBuffer(requestPayload)
+	}
+	operationURL := client.getBaseURL() + fmt.Sprintf("/orgs/%s/deployments/%s/clusters/%s/operations/%s",
+		client.connector.OrgID, sddcID, clusterID, operation)
+	req := client.connector.NewRequest(http.MethodPost, operationURL, body)
+
+	rawResponse, statusCode, err := connector.ExecuteRequest(client.httpClient, req)
+	if err != nil {
+		return "", err
+	}
+	if statusCode == http.StatusNotFound {
+		return "", fmt.Errorf("cluster %s of SDDC %s not found", clusterID, sddcID)
+	}
+	if statusCode != http.StatusOK && statusCode != http.StatusAccepted && statusCode != http.StatusCreated {
+		return "", fmt.Errorf("%s response code: %d body: %s", operation, statusCode, string(*rawResponse))
+	}
+	var result Task
+	err = json.NewDecoder(bytes.NewReader(*rawResponse)).Decode(&result)
+	if err != nil {
+		return "", err
+	}
+	if len(result.ID) == 0 {
+		return "", fmt.Errorf("%s response does not contain a task ID", operation)
+	}
+	return result.ID, nil
+}
+
+func (client *ClientImpl) getBaseURL() string {
+	return client.connector.VmcURL + "/api/wcp/v1"
+}
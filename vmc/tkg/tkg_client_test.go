@@ -0,0 +1,123 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package tkg
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/vmware/terraform-provider-vmc/vmc/connector"
+)
+
+const testAccessToken = "testAccessToken"
+const testOrgID = "testOrgID"
+const testVmcURL = "https://test.vmc.vmware.com"
+
+type HTTPClientStub struct {
+	expectedJSON   string
+	expectedURL    string
+	responseJSON   string
+	responseCode   int
+	requestsServed int
+	t              *testing.T
+}
+
+func (stub *HTTPClientStub) Do(req *http.Request) (*http.Response, error) {
+	stub.requestsServed++
+	body := ""
+	if req.Body != nil {
+		bodyBytes, _ := io.ReadAll(req.Body)
+		body = string(bodyBytes)
+	}
+	assert.Equal(stub.t, stub.expectedJSON, body)
+	assert.Equal(stub.t, stub.expectedURL, req.URL.String())
+	assert.Equal(stub.t, http.MethodPost, req.Method)
+	assert.Equal(stub.t, testAccessToken, req.Header.Get(connector.AuthnHeader))
+	return &http.Response{
+		StatusCode: stub.responseCode,
+		Body:       io.NopCloser(strings.NewReader(stub.responseJSON)),
+	}, nil
+}
+
+var testNetworkConfig = NetworkConfig{
+	EgressCidr:    []Cidr{{Address: "10.2.0.0", Prefix: 26}},
+	IngressCidr:   []Cidr{{Address: "10.3.0.0", Prefix: 26}},
+	NamespaceCidr: []Cidr{{Address: "10.4.0.0", Prefix: 20}},
+	ServiceCidr:   Cidr{Address: "10.5.0.0", Prefix: 23},
+}
+
+const testNetworkConfigJSON = `{"egress_cidr":[{"address":"10.2.0.0","prefix":26}],` +
+	`"ingress_cidr":[{"address":"10.3.0.0","prefix":26}],` +
+	`"namespace_cidr":[{"address":"10.4.0.0","prefix":20}],` +
+	`"service_cidr":{"address":"10.5.0.0","prefix":23}}`
+
+func TestOperations(t *testing.T) {
+	operationsURL := testVmcURL + "/api/wcp/v1/orgs/testOrgID/deployments/sddcId/clusters/clusterId/operations/"
+	testCases := []struct {
+		expectedJSON string
+		expectedURL  string
+		operation    func(client *ClientImpl) (string, error)
+	}{
+		{
+			expectedJSON: testNetworkConfigJSON,
+			expectedURL:  operationsURL + "validate-network",
+			operation: func(client *ClientImpl) (string, error) {
+				return client.ValidateNetwork("sddcId", "clusterId", testNetworkConfig)
+			},
+		},
+		{
+			expectedJSON: testNetworkConfigJSON,
+			expectedURL:  operationsURL + "enable-wcp",
+			operation: func(client *ClientImpl) (string, error) {
+				return client.Enable("sddcId", "clusterId", testNetworkConfig)
+			},
+		},
+		{
+			expectedURL: operationsURL + "disable-wcp",
+			operation: func(client *ClientImpl) (string, error) {
+				return client.Disable("sddcId", "clusterId")
+			},
+		},
+	}
+	for _, testCase := range testCases {
+		stub := &HTTPClientStub{
+			expectedJSON: testCase.expectedJSON,
+			expectedURL:  testCase.expectedURL,
+			responseJSON: `{"id": "taskId", "status": "STARTED"}`,
+			responseCode: http.StatusOK,
+			t:            t,
+		}
+		taskID, err := testCase.operation(newTestTkgClient(testVmcURL, testOrgID, testAccessToken, stub))
+		assert.NoError(t, err)
+		assert.Equal(t, "taskId", taskID)
+		assert.Equal(t, 1, stub.requestsServed)
+	}
+}
+
+func TestOperationErrors(t *testing.T) {
+	testCases := []struct {
+		responseCode  int
+		responseJSON  string
+		expectedError string
+	}{
+		{responseCode: http.StatusNotFound, expectedError: "cluster clusterId of SDDC sddcId not found"},
+		{responseCode: http.StatusBadRequest, responseJSON: `{"error_messages": ["overlapping CIDR"]}`,
+			expectedError: "overlapping CIDR"},
+		{responseCode: http.StatusUnauthorized, expectedError: "Unauthenticated request"},
+		{responseCode: http.StatusOK, responseJSON: `{}`, expectedError: "does not contain a task ID"},
+	}
+	for _, testCase := range testCases {
+		stub := &HTTPClientStub{
+			expectedURL:  testVmcURL + "/api/wcp/v1/orgs/testOrgID/deployments/sddcId/clusters/clusterId/operations/disable-wcp",
+			responseJSON: testCase.responseJSON,
+			responseCode: testCase.responseCode,
+			t:            t,
+		}
+		_, err := newTestTkgClient(testVmcURL, testOrgID, testAccessToken, stub).Disable("sddcId", "clusterId")
+		assert.ErrorContains(t, err, testCase.expectedError)
+	}
+}
@@ -0,0 +1,27 @@
+/* Copyright 2023 VMware, Inc.
+   SPDX-License-Identifier: MPL-2.0 */
+
+package tkg
+
+// Cidr a CIDR block, as expected by the workload control plane (WCP) APIs.
+type Cidr struct {
+	Address string `json:"address"`
+	Prefix  int    `json:"prefix"`
+}
+
+// NetworkConfig the networks of Tanzu Kubernetes Grid on an SDDC cluster.
+type NetworkConfig struct {
+	// EgressCidr the CIDR blocks from which the SNAT IPs of the Kubernetes workloads are allotted.
+	EgressCidr []Cidr `json:"egress_cidr"`
+	// IngressCidr the CIDR blocks from which the IPs of the Kubernetes load balancers are allotted.
+	IngressCidr []Cidr `json:"ingress_cidr"`
+	// NamespaceCidr the CIDR blocks from which the IPs of the pods of the vSphere namespaces are allotted.
+	NamespaceCidr []Cidr `json:"namespace_cidr"`
+	// ServiceCidr the CIDR block from which the IPs of the Kubernetes services are allotted.
+	ServiceCidr Cidr `json:"service_cidr"`
+}
+
+// Task the part of the VMC task, returned by the WCP operations, that is needed to track them.
+type Task struct {
+	ID string `json:"id"`
+}
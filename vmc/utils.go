@@ -5,15 +5,16 @@ package vmc
 
 import (
 	"fmt"
-	"github.com/vmware/terraform-provider-vmc/vmc/connector"
 	"github.com/vmware/terraform-provider-vmc/vmc/constants"
+	"log"
 	"net/url"
+	"regexp"
 	"strings"
+	"time"
 
 	"github.com/gofrs/uuid/v5"
-	"github.com/vmware/vsphere-automation-sdk-go/runtime/protocol/client"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
 	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/model"
-	"github.com/vmware/vsphere-automation-sdk-go/services/vmc/orgs"
 )
 
 var storageCapacityMap = map[string]int64{
@@ -24,12 +25,6 @@ var storageCapacityMap = map[string]int64{
 	"35TB": 35007,
 }
 
-func GetSddc(connector client.Connector, orgID string, sddcID string) (model.Sddc, error) {
-	sddcClient := orgs.NewSddcsClient(connector)
-	sddc, err := sddcClient.Get(orgID, sddcID)
-	return sddc, err
-}
-
 func ConvertStorageCapacityToInt(s string) int64 {
 	storageCapacity := storageCapacityMap[s]
 	return storageCapacity
@@ -38,13 +33,15 @@ func ConvertStorageCapacityToInt(s string) int64 {
 // ConvertDeployType Mapping for deployment_type field
 // During refresh/import state, return value of VMC API should be converted to uppercamel case in terraform
 // to maintain consistency
+// Deployment types not known to the provider are returned as is.
 func ConvertDeployType(s string) string {
 	if s == "SINGLE_AZ" {
 		return constants.SingleAvailabilityZone
 	} else if s == "MULTI_AZ" {
 		return constants.MultiAvailabilityZone
 	} else {
-		return ""
+		log.Printf("[WARN] Unknown deployment type %q returned by the VMC API, storing it as is", s)
+		return s
 	}
 }
 
@@ -76,23 +73,110 @@ func expandMsftLicenseConfig(config []interface{}) *model.MsftLicensingConfig {
 	return &licenseConfig
 }
 
-func getNsxtReverseProxyURLConnector(nsxtReverseProxyURL string, wrapper *connector.Wrapper) (client.Connector, error) {
-	if len(nsxtReverseProxyURL) == 0 {
-		return nil, fmt.Errorf("NSX reverse proxy url is required for public IP resource creation")
+// flattenMsftLicenseConfig converts the Microsoft licensing config of a cluster to the format of the
+// microsoft_licensing_config argument, an empty list if the cluster has none.
+func flattenMsftLicenseConfig(licenseConfig *model.MsftLicensingConfig) []interface{} {
+	if licenseConfig == nil {
+		return []interface{}{}
 	}
-	if wrapper == nil {
-		return nil, fmt.Errorf("nil connector.Wrapper provided")
+	licenseConfigMap := map[string]interface{}{}
+	if licenseConfig.MssqlLicensing != nil {
+		licenseConfigMap["mssql_licensing"] = *licenseConfig.MssqlLicensing
 	}
-	nsxtReverseProxyURL = strings.Replace(nsxtReverseProxyURL, constants.SksNSXTManager, "", -1)
-	copyWrapper := connector.CopyWrapper(*wrapper)
-	// The wrapper uses the VmcURL as service URL, so setting it to the NSX URL will
-	// force authentication against the NSX instance
-	copyWrapper.VmcURL = nsxtReverseProxyURL
-	err := copyWrapper.Authenticate()
-	if err != nil {
-		return nil, err
+	if licenseConfig.WindowsLicensing != nil {
+		licenseConfigMap["windows_licensing"] = *licenseConfig.WindowsLicensing
+	}
+	if licenseConfig.AcademicLicense != nil {
+		licenseConfigMap["academic_license"] = *licenseConfig.AcademicLicense
+	}
+	return []interface{}{licenseConfigMap}
+}
+
+// deletionProtectionSchema the deletion_protection argument of the resources, whose deletion cannot be undone.
+func deletionProtectionSchema(resourceType string) *schema.Schema {
+	return &schema.Schema{
+		Type:     schema.TypeBool,
+		Optional: true,
+		Default:  false,
+		Description: fmt.Sprintf("Prevents the %s from being deleted by Terraform. It has to be set to false, "+
+			"and the change applied, before the %s can be destroyed or replaced.", resourceType, resourceType),
+	}
+}
+
+// checkDeletionProtection fails the deletion of a resource, whose deletion_protection is enabled.
+// The setting is taken from the state, so disabling it in the configuration only takes effect after an apply.
+func checkDeletionProtection(d *schema.ResourceData, resourceType string) error {
+	if d.Get("deletion_protection").(bool) {
+		return newAttributeError("deletion_protection", "%s %s cannot be deleted, as its deletion_protection is enabled. "+
+			"Set deletion_protection to false and apply the change before deleting it", resourceType, d.Id())
+	}
+	return nil
+}
+
+// validateStretchedClusterHostCount validates the number of hosts of a stretched cluster, i.e. a cluster
+// of a MultiAZ SDDC. Stretched clusters have the same amount of hosts in each of the two availability
+// zones, so the VMC API only accepts an even number of hosts and adds or removes them in pairs.
+func validateStretchedClusterHostCount(numHosts int) error {
+	if numHosts%2 != 0 {
+		return fmt.Errorf("stretched clusters of %s SDDCs must have an even number of hosts, split evenly across "+
+			"the availability zones, got %d", constants.MultiAvailabilityZone, numHosts)
+	}
+	return nil
+}
+
+// flattenVsanWitness converts the witness node of a stretched cluster into a map. An empty map
+// is returned for clusters, that are not stretched.
+func flattenVsanWitness(witness *model.AwsWitnessEsx) map[string]string {
+	witnessMap := map[string]string{}
+	if witness == nil {
+		return witnessMap
+	}
+	for key, value := range map[string]*string{
+		"esx_id":      witness.EsxId,
+		"name":        witness.Name,
+		"hostname":    witness.Hostname,
+		"state":       witness.EnumState,
+		"instance_id": witness.InstanceId,
+	} {
+		if value != nil {
+			witnessMap[key] = *value
+		}
+	}
+	return witnessMap
+}
+
+// sddcSecurityElem the schema of the sddc_security block of the SDDC resource and data source.
+func sddcSecurityElem() *schema.Resource {
+	return &schema.Resource{
+		Schema: map[string]*schema.Schema{
+			"profile": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "The security profile of the SDDC, PROFILE_DEFAULT or PROFILE_PCI_COMMERCIAL.",
+			},
+			"hardened": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "True if the SDDC is hardened according to its security profile.",
+			},
+		},
+	}
+}
+
+// flattenSddcSecurity converts the security profile of an SDDC to the format of the sddc_security
+// attribute, an empty list for SDDCs, that report none.
+func flattenSddcSecurity(security *model.SddcSecurity) []interface{} {
+	if security == nil {
+		return []interface{}{}
 	}
-	return copyWrapper.Connector, nil
+	securityMap := map[string]interface{}{}
+	if security.Profile != nil {
+		securityMap["profile"] = *security.Profile
+	}
+	if security.Hardened != nil {
+		securityMap["hardened"] = *security.Hardened
+	}
+	return []interface{}{securityMap}
 }
 
 // getHostCountCluster tries to find the amount of hosts on a Cluster in
@@ -110,7 +194,9 @@ func getHostCountCluster(sddc *model.Sddc, clusterID string) int {
 }
 
 // toHostInstanceType converts from the Schema format of the host_instance_type to
-// the possible string values defined in the VMC SDK
+// the possible string values defined in the VMC SDK. Host instance types introduced by the
+// VMC API after the provider was released are passed through, as long as they are in the
+// Schema format (e.g. C6I_METAL) or in the API format (e.g. c6i.metal).
 func toHostInstanceType(userPassedHostInstanceType string) (string, error) {
 	switch userPassedHostInstanceType {
 	case constants.HostInstancetypeI3:
@@ -119,7 +205,99 @@ func toHostInstanceType(userPassedHostInstanceType string) (string, error) {
 		return model.SddcConfig_HOST_INSTANCE_TYPE_I3EN_METAL, nil
 	case constants.HostInstancetypeI4I:
 		return model.SddcConfig_HOST_INSTANCE_TYPE_I4I_METAL, nil
-	default:
-		return "", fmt.Errorf("unknown host instance type: %s", userPassedHostInstanceType)
 	}
+	if apiHostInstanceTypeRegexp.MatchString(userPassedHostInstanceType) {
+		log.Printf("[WARN] Unknown host instance type %s, passing it to the VMC API as is", userPassedHostInstanceType)
+		return userPassedHostInstanceType, nil
+	}
+	if schemaHostInstanceTypeRegexp.MatchString(userPassedHostInstanceType) {
+		// I3EN_METAL -> i3en.metal, M7I_METAL_24XL -> m7i.metal-24xl
+		hostInstanceType := strings.Replace(strings.ToLower(userPassedHostInstanceType), "_", ".", 1)
+		hostInstanceType = strings.ReplaceAll(hostInstanceType, "_", "-")
+		log.Printf("[WARN] Unknown host instance type %s, passing it to the VMC API as %s",
+			userPassedHostInstanceType, hostInstanceType)
+		return hostInstanceType, nil
+	}
+	return "", fmt.Errorf("unknown host instance type: %s", userPassedHostInstanceType)
+}
+
+// fromHostInstanceType converts a host instance type from the API format (e.g. i3en.metal) to
+// the Schema format of the host_instance_type (e.g. I3EN_METAL).
+func fromHostInstanceType(apiHostInstanceType string) string {
+	hostInstanceType := strings.ToUpper(apiHostInstanceType)
+	return strings.NewReplacer(".", "_", "-", "_").Replace(hostInstanceType)
+}
+
+var schemaHostInstanceTypeRegexp = regexp.MustCompile(`^[A-Z0-9]+(_[A-Z0-9]+)+$`)
+var apiHostInstanceTypeRegexp = regexp.MustCompile(`^[a-z0-9]+\.[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validateHostInstanceType validates the host_instance_type field. Values other than the known
+// ones, that are still shaped like a host instance type, produce a warning rather than an error,
+// so that host instance types introduced by the VMC API can be used before the provider knows them.
+func validateHostInstanceType(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+	knownHostInstanceTypes := []string{constants.HostInstancetypeI3, constants.HostInstancetypeI3EN, constants.HostInstancetypeI4I}
+	for _, known := range knownHostInstanceTypes {
+		if v == known {
+			return warnings, errors
+		}
+	}
+	if _, err := toHostInstanceType(v); err != nil {
+		errors = append(errors, fmt.Errorf("expected %s to be one of %v, got %s", k, knownHostInstanceTypes, v))
+		return warnings, errors
+	}
+	warnings = append(warnings, fmt.Sprintf("%s %q is not known to this provider version, passing it to the VMC API as is",
+		k, v))
+	return warnings, errors
+}
+
+// validateKnownStringValue validates that a string field is one of the known values. Unknown values
+// produce a warning rather than an error, so that options introduced by the VMC API can be used
+// before the provider knows them.
+func validateKnownStringValue(knownValues []string) schema.SchemaValidateFunc {
+	return func(i interface{}, k string) (warnings []string, errors []error) {
+		v, ok := i.(string)
+		if !ok {
+			errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+			return warnings, errors
+		}
+		for _, known := range knownValues {
+			if v == known {
+				return warnings, errors
+			}
+		}
+		warnings = append(warnings, fmt.Sprintf("%s %q is not one of the known values %v, passing it to the VMC API as is",
+			k, v, knownValues))
+		return warnings, errors
+	}
+}
+
+// validateDuration validates that a string field is a positive duration, such as "30m" or "12h".
+func validateDuration(i interface{}, k string) (warnings []string, errors []error) {
+	v, ok := i.(string)
+	if !ok {
+		errors = append(errors, fmt.Errorf("expected type of %s to be string", k))
+		return warnings, errors
+	}
+	duration, err := time.ParseDuration(v)
+	if err != nil || duration <= 0 {
+		errors = append(errors, fmt.Errorf("expected %s to be a positive duration, such as 12h, got %s", k, v))
+	}
+	return warnings, errors
+}
+
+// getSrmNodeURLs returns the URLs of the appliance UI and the REST API of an SRM node, derived
+// from its host name, as the DRaaS API does not expose them.
+func getSrmNodeURLs(hostname string) (uiURL string, apiURL string) {
+	hostname = strings.TrimSuffix(strings.TrimSpace(hostname), ".")
+	if len(hostname) == 0 {
+		return "", ""
+	}
+	uiURL = fmt.Sprintf("https://%s:%d", hostname, constants.SrmApplianceUIPort)
+	apiURL = "https://" + hostname + constants.SrmRestAPIPath
+	return uiURL, apiURL
 }
@@ -26,6 +26,10 @@ func TestToHostInstanceType(t *testing.T) {
 		{input: constants.HostInstancetypeI3EN, want: result{converted: model.SddcConfig_HOST_INSTANCE_TYPE_I3EN_METAL, err: nil}},
 		{input: constants.HostInstancetypeI4I, want: result{converted: model.SddcConfig_HOST_INSTANCE_TYPE_I4I_METAL, err: nil}},
 		{input: "RandomString", want: result{converted: "", err: fmt.Errorf("unknown host instance type: RandomString")}},
+		// Host instance types not yet known to the provider are passed through
+		{input: "C6I_METAL", want: result{converted: "c6i.metal", err: nil}},
+		{input: "M7I_METAL_24XL", want: result{converted: "m7i.metal-24xl", err: nil}},
+		{input: "m7i.metal-48xl", want: result{converted: "m7i.metal-48xl", err: nil}},
 	}
 
 	for _, testCase := range tests {
@@ -35,6 +39,79 @@ func TestToHostInstanceType(t *testing.T) {
 	}
 }
 
+func TestFromHostInstanceType(t *testing.T) {
+	assert.Equal(t, constants.HostInstancetypeI3EN, fromHostInstanceType(model.SddcConfig_HOST_INSTANCE_TYPE_I3EN_METAL))
+	assert.Equal(t, constants.HostInstancetypeI4I, fromHostInstanceType(model.SddcConfig_HOST_INSTANCE_TYPE_I4I_METAL))
+	assert.Equal(t, "M7I_METAL_24XL", fromHostInstanceType("m7i.metal-24xl"))
+	for _, hostInstanceType := range []string{"c6i.metal", "m7i.metal-24xl"} {
+		converted, err := toHostInstanceType(fromHostInstanceType(hostInstanceType))
+		assert.NoError(t, err)
+		assert.Equal(t, hostInstanceType, converted)
+	}
+}
+
+func TestValidateHostInstanceType(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectWarning bool
+		expectError   bool
+	}{
+		{input: constants.HostInstancetypeI3},
+		{input: constants.HostInstancetypeI4I},
+		{input: "C6I_METAL", expectWarning: true},
+		{input: "c6i.metal", expectWarning: true},
+		{input: "RandomString", expectError: true},
+	}
+
+	for _, testCase := range tests {
+		warnings, errors := validateHostInstanceType(testCase.input, "host_instance_type")
+		assert.Equal(t, testCase.expectWarning, len(warnings) > 0, testCase.input)
+		assert.Equal(t, testCase.expectError, len(errors) > 0, testCase.input)
+	}
+}
+
+func TestValidateKnownStringValue(t *testing.T) {
+	validateFunc := validateKnownStringValue([]string{constants.CostPolicyType, constants.PerformancePolicyType})
+
+	warnings, errors := validateFunc(constants.CostPolicyType, "edrs_policy_type")
+	assert.Empty(t, warnings)
+	assert.Empty(t, errors)
+
+	warnings, errors = validateFunc("future-policy", "edrs_policy_type")
+	assert.Len(t, warnings, 1)
+	assert.Empty(t, errors)
+
+	_, errors = validateFunc(1, "edrs_policy_type")
+	assert.Len(t, errors, 1)
+}
+
+func TestValidateDuration(t *testing.T) {
+	for _, valid := range []string{"90m", "12h", "1h30m"} {
+		_, errors := validateDuration(valid, "conversion_timeout")
+		assert.Empty(t, errors, valid)
+	}
+	for _, invalid := range []string{"", "12", "-1h", "0s", "twelve hours"} {
+		_, errors := validateDuration(invalid, "conversion_timeout")
+		assert.Len(t, errors, 1, invalid)
+	}
+}
+
+func TestConvertDeployType(t *testing.T) {
+	assert.Equal(t, constants.SingleAvailabilityZone, ConvertDeployType("SINGLE_AZ"))
+	assert.Equal(t, constants.MultiAvailabilityZone, ConvertDeployType("MULTI_AZ"))
+	assert.Equal(t, "FUTURE_AZ", ConvertDeployType("FUTURE_AZ"))
+}
+
+func TestGetSrmNodeURLs(t *testing.T) {
+	uiURL, apiURL := getSrmNodeURLs("srm-second.sddc-52-10-0-1.vmwarevmc.com.")
+	assert.Equal(t, "https://srm-second.sddc-52-10-0-1.vmwarevmc.com:5480", uiURL)
+	assert.Equal(t, "https://srm-second.sddc-52-10-0-1.vmwarevmc.com/api/rest/srm/v1", apiURL)
+
+	uiURL, apiURL = getSrmNodeURLs("")
+	assert.Empty(t, uiURL)
+	assert.Empty(t, apiURL)
+}
+
 func TestGetHostCountOnCluster(t *testing.T) {
 	type inputStruct struct {
 		sddc      *model.Sddc
@@ -96,3 +173,17 @@ func TestGetHostCountOnCluster(t *testing.T) {
 		assert.Equal(t, got, testCase.want)
 	}
 }
+
+func TestValidateStretchedClusterHostCount(t *testing.T) {
+	assert.NoError(t, validateStretchedClusterHostCount(2))
+	assert.NoError(t, validateStretchedClusterHostCount(6))
+	assert.Error(t, validateStretchedClusterHostCount(3))
+}
+
+func TestFlattenVsanWitness(t *testing.T) {
+	assert.Empty(t, flattenVsanWitness(nil))
+	esxID := "witness-id"
+	hostname := "witness.sddc.vmc.local"
+	assert.Equal(t, map[string]string{"esx_id": esxID, "hostname": hostname},
+		flattenVsanWitness(&model.AwsWitnessEsx{EsxId: &esxID, Hostname: &hostname}))
+}